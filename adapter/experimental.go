@@ -9,8 +9,11 @@ import (
 
 	"github.com/sagernet/sing-box/common/urltest"
 	"github.com/sagernet/sing-dns"
+	"github.com/sagernet/sing/common/logger"
 	N "github.com/sagernet/sing/common/network"
 	"github.com/sagernet/sing/common/varbin"
+
+	mdns "github.com/miekg/dns"
 )
 
 type ClashServer interface {
@@ -33,6 +36,10 @@ type CacheFile interface {
 	StoreRDRC() bool
 	dns.RDRCStore
 
+	StoreDNS() bool
+	LoadDNSCache(staleTimeout time.Duration) []SavedDNSCache
+	SaveDNSCacheAsync(message *mdns.Msg, expireAt time.Time, logger logger.Logger)
+
 	LoadMode() string
 	StoreMode(mode string) error
 	LoadSelected(group string) string
@@ -41,6 +48,23 @@ type CacheFile interface {
 	StoreGroupExpand(group string, expand bool) error
 	LoadRuleSet(tag string) *SavedRuleSet
 	SaveRuleSet(tag string, set *SavedRuleSet) error
+
+	StoreConnectionHistory() bool
+	SaveConnectionHistory(connections []SavedConnection) error
+	// LoadConnectionHistory calls yield with connections closed in [since, until], newest first,
+	// zero times are unbounded, iteration stops when yield returns false.
+	LoadConnectionHistory(since time.Time, until time.Time, yield func(content []byte) bool) error
+}
+
+type SavedDNSCache struct {
+	Message  *mdns.Msg
+	ExpireAt time.Time
+}
+
+type SavedConnection struct {
+	ID       [16]byte
+	ClosedAt time.Time
+	Content  []byte
 }
 
 type SavedRuleSet struct {
@@ -95,7 +119,9 @@ func (s *SavedRuleSet) UnmarshalBinary(data []byte) error {
 }
 
 type Tracker interface {
-	Leave()
+	// Leave is called when routing of the connection finished with the error returned by the outbound,
+	// the connection is kept until it is closed if the outbound returned without error.
+	Leave(err error)
 }
 
 type OutboundGroup interface {
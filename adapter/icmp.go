@@ -0,0 +1,30 @@
+package adapter
+
+import (
+	"context"
+	"net/netip"
+
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+var ErrICMPUnsupported = E.New("icmp echo unsupported")
+
+type ICMPEchoRequest struct {
+	Source      netip.Addr
+	Destination netip.Addr
+	Identifier  uint16
+	Sequence    uint16
+	Payload     []byte
+}
+
+// ICMPOutbound is implemented by outbounds able to forward ICMP echo requests.
+// NewICMPEcho blocks until the reply arrives or the context is done,
+// ErrICMPUnsupported tells the caller to use its fallback.
+type ICMPOutbound interface {
+	Outbound
+	NewICMPEcho(ctx context.Context, request *ICMPEchoRequest, metadata InboundContext) error
+}
+
+type ICMPRouter interface {
+	RouteICMPEcho(ctx context.Context, request *ICMPEchoRequest, metadata InboundContext) error
+}
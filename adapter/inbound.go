@@ -24,6 +24,30 @@ type InjectableInbound interface {
 	NewPacketConnection(ctx context.Context, conn N.PacketConn, metadata InboundContext) error
 }
 
+type InboundManager interface {
+	Inbounds() []Inbound
+	Inbound(tag string) (Inbound, bool)
+	AddInbound(options option.Inbound) error
+	RemoveInbound(tag string) error
+}
+
+// UserManagedInbound is a multi-user inbound whose users can be changed at runtime.
+type UserManagedInbound interface {
+	Inbound
+	Users() []InboundUser
+	AddUsers(users []InboundUser) error
+	RemoveUsers(names []string) error
+}
+
+// InboundUser is the protocol-independent form of an inbound user,
+// each inbound uses the credential fields of its own protocol.
+type InboundUser struct {
+	Name     string
+	UUID     string
+	Flow     string
+	Password string
+}
+
 type InboundContext struct {
 	Inbound     string
 	InboundType string
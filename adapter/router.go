@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/netip"
+	"time"
 
 	"github.com/sagernet/sing-box/common/geoip"
 	"github.com/sagernet/sing-dns"
@@ -23,6 +24,10 @@ type Router interface {
 	PostStarter
 	Cleanup() error
 
+	Inbound(tag string) (Inbound, bool)
+	RegisterInbound(inbound Inbound)
+	UnregisterInbound(tag string)
+
 	Outbounds() []Outbound
 	Outbound(tag string) (Outbound, bool)
 	DefaultOutbound(network string) (Outbound, error)
@@ -30,8 +35,10 @@ type Router interface {
 	FakeIPStore() FakeIPStore
 
 	ConnectionRouter
+	ICMPRouter
 
 	GeoIPReader() *geoip.Reader
+	ASNReader() *geoip.Reader
 	LoadGeosite(code string) (Rule, error)
 
 	RuleSet(tag string) (RuleSet, bool)
@@ -117,6 +124,13 @@ type RuleSetMetadata struct {
 	ContainsProcessRule bool
 	ContainsWIFIRule    bool
 	ContainsIPCIDRRule  bool
+	ContainsASNRule     bool
+	Schedules           []ScheduleMatcher
+}
+
+// ScheduleMatcher reports whether the schedule of a rule is active at the time.
+type ScheduleMatcher interface {
+	MatchTime(now time.Time) bool
 }
 
 type RuleSetStartContext interface {
@@ -0,0 +1,88 @@
+package box
+
+import (
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/inbound"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+)
+
+var _ adapter.InboundManager = (*Box)(nil)
+
+func (s *Box) Inbounds() []adapter.Inbound {
+	s.inboundAccess.Lock()
+	defer s.inboundAccess.Unlock()
+	return append([]adapter.Inbound(nil), s.inbounds...)
+}
+
+func (s *Box) Inbound(tag string) (adapter.Inbound, bool) {
+	s.inboundAccess.Lock()
+	defer s.inboundAccess.Unlock()
+	for _, it := range s.inbounds {
+		if it.Tag() == tag {
+			return it, true
+		}
+	}
+	return nil, false
+}
+
+// AddInbound creates and starts an inbound at runtime.
+func (s *Box) AddInbound(options option.Inbound) error {
+	if options.Tag == "" {
+		return E.New("missing inbound tag")
+	}
+	s.inboundAccess.Lock()
+	defer s.inboundAccess.Unlock()
+	if common.Any(s.inbounds, func(it adapter.Inbound) bool {
+		return it.Tag() == options.Tag
+	}) {
+		return E.New("inbound tag ", options.Tag, " duplicated")
+	}
+	in, err := inbound.New(
+		s.ctx,
+		s.router,
+		s.logFactory.NewLogger(F.ToString("inbound/", options.Type, "[", options.Tag, "]")),
+		options.Tag,
+		options,
+		s.platformInterface,
+	)
+	if err != nil {
+		return E.Cause(err, "parse inbound[", options.Tag, "]")
+	}
+	err = in.Start()
+	if err == nil {
+		if lateInbound, isLateInbound := in.(adapter.PostStarter); isLateInbound {
+			err = lateInbound.PostStart()
+		}
+	}
+	if err != nil {
+		in.Close()
+		return E.Cause(err, "initialize inbound/", in.Type(), "[", options.Tag, "]")
+	}
+	s.inbounds = append(s.inbounds, in)
+	s.router.RegisterInbound(in)
+	return nil
+}
+
+// RemoveInbound closes an inbound and removes it at runtime.
+func (s *Box) RemoveInbound(tag string) error {
+	s.inboundAccess.Lock()
+	index := -1
+	for i, it := range s.inbounds {
+		if it.Tag() == tag {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		s.inboundAccess.Unlock()
+		return E.New("inbound not found: ", tag)
+	}
+	in := s.inbounds[index]
+	s.inbounds = append(s.inbounds[:index:index], s.inbounds[index+1:]...)
+	s.router.UnregisterInbound(tag)
+	s.inboundAccess.Unlock()
+	return in.Close()
+}
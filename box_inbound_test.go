@@ -0,0 +1,75 @@
+package box
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sagernet/sing-box/option"
+	F "github.com/sagernet/sing/common/format"
+	"github.com/sagernet/sing/common/json"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newInboundTestBox(t *testing.T) *Box {
+	options, err := json.UnmarshalExtended[option.Options]([]byte(`{"log":{"disabled":true}}`))
+	require.NoError(t, err)
+	instance, err := New(Options{
+		Context: context.Background(),
+		Options: options,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		instance.Close()
+	})
+	return instance
+}
+
+func newInboundTestOptions(t *testing.T, tag string) option.Inbound {
+	options, err := json.UnmarshalExtended[option.Inbound]([]byte(`{"type":"mixed","tag":"` + tag + `","listen":"127.0.0.1"}`))
+	require.NoError(t, err)
+	return options
+}
+
+func TestInboundManagerRouterLookup(t *testing.T) {
+	t.Parallel()
+	instance := newInboundTestBox(t)
+	require.NoError(t, instance.AddInbound(newInboundTestOptions(t, "dynamic")))
+	inbound, loaded := instance.Router().Inbound("dynamic")
+	require.True(t, loaded)
+	require.Equal(t, "dynamic", inbound.Tag())
+	require.Error(t, instance.AddInbound(newInboundTestOptions(t, "dynamic")))
+	require.NoError(t, instance.RemoveInbound("dynamic"))
+	_, loaded = instance.Router().Inbound("dynamic")
+	require.False(t, loaded)
+	require.Error(t, instance.RemoveInbound("dynamic"))
+}
+
+func TestInboundManagerConcurrent(t *testing.T) {
+	t.Parallel()
+	instance := newInboundTestBox(t)
+	const count = 16
+	var group sync.WaitGroup
+	errors := make([]error, count)
+	for i := 0; i < count; i++ {
+		options := newInboundTestOptions(t, F.ToString("inbound", i))
+		group.Add(1)
+		go func(index int) {
+			defer group.Done()
+			errors[index] = instance.AddInbound(options)
+			if errors[index] == nil && index%2 == 0 {
+				errors[index] = instance.RemoveInbound(options.Tag)
+			}
+		}(i)
+	}
+	group.Wait()
+	for _, err := range errors {
+		require.NoError(t, err)
+	}
+	require.Len(t, instance.Inbounds(), count/2)
+	for i := 0; i < count; i++ {
+		_, loaded := instance.Router().Inbound(F.ToString("inbound", i))
+		require.Equal(t, i%2 == 1, loaded)
+	}
+}
@@ -1,17 +1,33 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"os"
 
 	"github.com/sagernet/sing-box"
+	"github.com/sagernet/sing-box/cmd/sing-box/internal/lint"
 	"github.com/sagernet/sing-box/log"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/json"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	commandCheckFlagLint       bool
+	commandCheckFlagLintFormat string
+)
+
 var commandCheck = &cobra.Command{
 	Use:   "check",
 	Short: "Check configuration",
+	Long: `Check configuration.
+
+With --lint, the configuration is also analyzed for problems that do not prevent sing-box from starting:
+shadowed route and DNS rules, unreferenced outbounds and rule-sets, detour cycles,
+invalid selector defaults, DNS bootstrap loops and deprecated fields.
+The command exits with a non-zero status if any problem is found.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := check()
 		if err != nil {
@@ -22,10 +38,15 @@ var commandCheck = &cobra.Command{
 }
 
 func init() {
+	commandCheck.Flags().BoolVar(&commandCheckFlagLint, "lint", false, "Report configuration problems")
+	commandCheck.Flags().StringVar(&commandCheckFlagLintFormat, "lint-format", "text", "Lint output format, available: text, json")
 	mainCommand.AddCommand(commandCheck)
 }
 
 func check() error {
+	if commandCheckFlagLint && commandCheckFlagLintFormat != "text" && commandCheckFlagLintFormat != "json" {
+		return E.New("unknown lint format: ", commandCheckFlagLintFormat)
+	}
 	options, err := readConfigAndMerge()
 	if err != nil {
 		return err
@@ -39,5 +60,50 @@ func check() error {
 		instance.Close()
 	}
 	cancel()
+	if err != nil || !commandCheckFlagLint {
+		return err
+	}
+	findings := lint.Lint(options)
+	err = printLintFindings(findings)
+	if err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return E.New("found ", len(findings), " problem(s)")
+	}
+	return nil
+}
+
+func printLintFindings(findings []lint.Finding) error {
+	var buffer bytes.Buffer
+	switch commandCheckFlagLintFormat {
+	case "json":
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(findings)
+		if err != nil {
+			return err
+		}
+	default:
+		for _, finding := range findings {
+			buffer.WriteString(finding.Path)
+			buffer.WriteString(": ")
+			buffer.WriteString(finding.Level)
+			buffer.WriteString(": ")
+			buffer.WriteString(finding.Message)
+			buffer.WriteString(" [")
+			buffer.WriteString(finding.Code)
+			buffer.WriteString("]\n")
+			if finding.Suggestion != "" {
+				buffer.WriteString("\tsuggestion: ")
+				buffer.WriteString(finding.Suggestion)
+				buffer.WriteString("\n")
+			}
+		}
+	}
+	_, err := os.Stdout.Write(buffer.Bytes())
 	return err
 }
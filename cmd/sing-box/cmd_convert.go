@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sagernet/sing-box/common/sharelink"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/json"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagConvertType   string
+	flagConvertOutput string
+)
+
+var commandConvert = &cobra.Command{
+	Use:   "convert <source-path>",
+	Short: "Convert outbounds between configuration, share links and Clash proxies",
+	Long: `Convert outbounds between configuration, share links and Clash proxies.
+
+The source is detected automatically: a configuration or an outbound list in JSON,
+a Clash configuration with proxies, or share links separated by lines, optionally encoded in base64.
+Use "stdin" as the source path to read from the standard input.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := convertOutbounds(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	mainCommand.AddCommand(commandConvert)
+	commandConvert.Flags().StringVarP(&flagConvertType, "type", "t", "", "Output type, available: json, link, clash (default json, or link for JSON source)")
+	commandConvert.Flags().StringVarP(&flagConvertOutput, "output", "o", "stdout", "Output file")
+}
+
+func convertOutbounds(sourcePath string) error {
+	var (
+		content []byte
+		err     error
+	)
+	if sourcePath == "stdin" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(sourcePath)
+	}
+	if err != nil {
+		return err
+	}
+	outbounds, isJSON, err := readOutbounds(content)
+	if err != nil {
+		return err
+	}
+	outputType := flagConvertType
+	if outputType == "" {
+		if isJSON {
+			outputType = "link"
+		} else {
+			outputType = "json"
+		}
+	}
+	if outputType != "json" {
+		outbounds = common.Filter(outbounds, func(it option.Outbound) bool {
+			return sharelink.Supported(it.Type)
+		})
+		if len(outbounds) == 0 {
+			return E.New("no supported outbounds found")
+		}
+	}
+	var buffer bytes.Buffer
+	switch outputType {
+	case "json":
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(option.Options{Outbounds: outbounds})
+		if err != nil {
+			return E.Cause(err, "encode config")
+		}
+	case "link":
+		for _, outbound := range outbounds {
+			link, err := sharelink.Format(outbound)
+			if err != nil {
+				log.Warn("skip outbound ", outbound.Tag, ": ", err)
+				continue
+			}
+			buffer.WriteString(link)
+			buffer.WriteString("\n")
+		}
+	case "clash":
+		clashContent, err := sharelink.FormatClash(outbounds)
+		if err != nil {
+			return err
+		}
+		buffer.Write(clashContent)
+	default:
+		return E.New("unsupported output type: ", outputType)
+	}
+	if flagConvertOutput == "stdout" {
+		_, err = os.Stdout.Write(buffer.Bytes())
+		return err
+	}
+	return os.WriteFile(flagConvertOutput, buffer.Bytes(), 0o644)
+}
+
+func readOutbounds(content []byte) ([]option.Outbound, bool, error) {
+	trimmedContent := strings.TrimSpace(string(content))
+	switch {
+	case strings.HasPrefix(trimmedContent, "{"):
+		options, err := json.UnmarshalExtended[option.Options](content)
+		if err != nil {
+			return nil, false, E.Cause(err, "decode config")
+		}
+		if len(options.Outbounds) == 0 {
+			return nil, false, E.New("no outbounds found")
+		}
+		return options.Outbounds, true, nil
+	case strings.HasPrefix(trimmedContent, "["):
+		outbounds, err := json.UnmarshalExtended[[]option.Outbound](content)
+		if err != nil {
+			return nil, false, E.Cause(err, "decode outbounds")
+		}
+		return outbounds, true, nil
+	default:
+		outbounds, err := sharelink.ParseContent(trimmedContent)
+		return outbounds, false, err
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	ctlSocketPath string
+	ctlFormat     string
+)
+
+var commandCtl = &cobra.Command{
+	Use:   "ctl",
+	Short: "Control a running sing-box through its command socket",
+}
+
+func init() {
+	commandCtl.PersistentFlags().StringVarP(&ctlSocketPath, "socket", "s", "command.sock", "Command socket path, as set by run --command-socket")
+	commandCtl.PersistentFlags().StringVar(&ctlFormat, "format", "table", "Output format, available: table, json")
+	mainCommand.AddCommand(commandCtl)
+}
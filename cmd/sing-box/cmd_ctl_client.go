@@ -0,0 +1,163 @@
+//go:build with_clash_api
+
+package main
+
+import (
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sagernet/sing-box/experimental/libbox"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/json"
+)
+
+const ctlInterval = time.Second
+
+type ctlHandler struct {
+	connectionState int32
+	logs            chan []string
+	status          chan *libbox.StatusMessage
+	groups          chan []*libbox.OutboundGroup
+	connections     chan []*libbox.Connection
+	clashModes      chan []string
+	currentMode     string
+	disconnected    chan string
+}
+
+func newCtlHandler() *ctlHandler {
+	return &ctlHandler{
+		logs:         make(chan []string, 16),
+		status:       make(chan *libbox.StatusMessage, 1),
+		groups:       make(chan []*libbox.OutboundGroup, 1),
+		connections:  make(chan []*libbox.Connection, 1),
+		clashModes:   make(chan []string, 1),
+		disconnected: make(chan string, 1),
+	}
+}
+
+func (h *ctlHandler) Connected() {
+}
+
+func (h *ctlHandler) Disconnected(message string) {
+	select {
+	case h.disconnected <- message:
+	default:
+	}
+}
+
+func (h *ctlHandler) ClearLogs() {
+}
+
+func (h *ctlHandler) WriteLogs(messageList libbox.StringIterator) {
+	var messages []string
+	for messageList.HasNext() {
+		messages = append(messages, messageList.Next())
+	}
+	h.logs <- messages
+}
+
+func (h *ctlHandler) WriteStatus(message *libbox.StatusMessage) {
+	select {
+	case h.status <- message:
+	default:
+	}
+}
+
+func (h *ctlHandler) WriteGroups(message libbox.OutboundGroupIterator) {
+	var groups []*libbox.OutboundGroup
+	for message.HasNext() {
+		groups = append(groups, message.Next())
+	}
+	select {
+	case h.groups <- groups:
+	default:
+	}
+}
+
+func (h *ctlHandler) InitializeClashMode(modeList libbox.StringIterator, currentMode string) {
+	var modes []string
+	for modeList.HasNext() {
+		modes = append(modes, modeList.Next())
+	}
+	h.currentMode = currentMode
+	h.clashModes <- modes
+}
+
+func (h *ctlHandler) UpdateClashMode(newMode string) {
+}
+
+func (h *ctlHandler) WriteConnections(message *libbox.Connections) {
+	// the message is reused by the client, so it is consumed here
+	message.FilterState(h.connectionState)
+	message.SortByDate()
+	var connections []*libbox.Connection
+	iterator := message.Iterator()
+	for iterator.HasNext() {
+		connections = append(connections, iterator.Next())
+	}
+	select {
+	case h.connections <- connections:
+	default:
+	}
+}
+
+func ctlConnect(command int32, handler *ctlHandler) (*libbox.CommandClient, error) {
+	libbox.SetupCommandSocket(ctlSocketPath)
+	client := libbox.NewCommandClient(handler, &libbox.CommandClientOptions{
+		Command:        command,
+		StatusInterval: int64(ctlInterval),
+	})
+	err := client.Connect()
+	if err != nil {
+		return nil, E.Cause(err, "connect to ", ctlSocketPath)
+	}
+	return client, nil
+}
+
+func ctlStandaloneClient() *libbox.CommandClient {
+	libbox.SetupCommandSocket(ctlSocketPath)
+	return libbox.NewStandaloneCommandClient()
+}
+
+func ctlReceive[T any](handler *ctlHandler, messages <-chan T) (T, error) {
+	select {
+	case message := <-messages:
+		return message, nil
+	case message := <-handler.disconnected:
+		var defaultValue T
+		return defaultValue, E.New("disconnected: ", message)
+	}
+}
+
+func ctlJSON() (bool, error) {
+	switch ctlFormat {
+	case "table":
+		return false, nil
+	case "json":
+		return true, nil
+	default:
+		return false, E.New("unknown output format: ", ctlFormat)
+	}
+}
+
+func ctlWriteJSON(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func ctlWriteTable(header string, writeRows func(writer io.Writer)) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	writer.Write([]byte(header + "\n"))
+	writeRows(writer)
+	writer.Flush()
+}
+
+func ctlFormatTime(unixMilli int64) string {
+	if unixMilli == 0 {
+		return ""
+	}
+	return time.UnixMilli(unixMilli).Format(time.RFC3339)
+}
@@ -0,0 +1,158 @@
+//go:build with_clash_api
+
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/sagernet/sing-box/common/humanize"
+	"github.com/sagernet/sing-box/experimental/libbox"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+
+	"github.com/spf13/cobra"
+)
+
+var ctlConnectionsState string
+
+var commandCtlConnections = &cobra.Command{
+	Use:   "connections",
+	Short: "List connections",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlConnections()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var ctlCloseAll bool
+
+var commandCtlClose = &cobra.Command{
+	Use:   "close [connection id]...",
+	Short: "Close connections",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlClose(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandCtlConnections.Flags().StringVar(&ctlConnectionsState, "state", "active", "Connection state, available: active, closed, all")
+	commandCtlClose.Flags().BoolVar(&ctlCloseAll, "all", false, "Close all connections")
+	commandCtl.AddCommand(commandCtlConnections)
+	commandCtl.AddCommand(commandCtlClose)
+}
+
+type ctlConnectionOutput struct {
+	ID          string   `json:"id"`
+	Inbound     string   `json:"inbound"`
+	InboundType string   `json:"inbound_type"`
+	Network     string   `json:"network"`
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Domain      string   `json:"domain,omitempty"`
+	Protocol    string   `json:"protocol,omitempty"`
+	User        string   `json:"user,omitempty"`
+	Rule        string   `json:"rule,omitempty"`
+	Outbound    string   `json:"outbound"`
+	Chain       []string `json:"chain,omitempty"`
+	Upload      int64    `json:"upload"`
+	Download    int64    `json:"download"`
+	CreatedAt   string   `json:"created_at"`
+	ClosedAt    string   `json:"closed_at,omitempty"`
+}
+
+func ctlConnections() error {
+	outputJSON, err := ctlJSON()
+	if err != nil {
+		return err
+	}
+	handler := newCtlHandler()
+	switch ctlConnectionsState {
+	case "active":
+		handler.connectionState = libbox.ConnectionStateActive
+	case "closed":
+		handler.connectionState = libbox.ConnectionStateClosed
+	case "all":
+		handler.connectionState = libbox.ConnectionStateAll
+	default:
+		return E.New("unknown connection state: ", ctlConnectionsState)
+	}
+	client, err := ctlConnect(libbox.CommandConnections, handler)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+	message, err := ctlReceive(handler, handler.connections)
+	if err != nil {
+		return err
+	}
+	connections := make([]ctlConnectionOutput, 0, len(message))
+	for _, connection := range message {
+		// the chain is reported from the last outbound, like the Clash API
+		chain := common.Reverse(append([]string(nil), connection.ChainList...))
+		connections = append(connections, ctlConnectionOutput{
+			ID:          connection.ID,
+			Inbound:     connection.Inbound,
+			InboundType: connection.InboundType,
+			Network:     connection.Network,
+			Source:      connection.Source,
+			Destination: connection.Destination,
+			Domain:      connection.Domain,
+			Protocol:    connection.Protocol,
+			User:        connection.User,
+			Rule:        connection.Rule,
+			Outbound:    connection.Outbound,
+			Chain:       chain,
+			Upload:      connection.UplinkTotal,
+			Download:    connection.DownlinkTotal,
+			CreatedAt:   ctlFormatTime(connection.CreatedAt),
+			ClosedAt:    ctlFormatTime(connection.ClosedAt),
+		})
+	}
+	if outputJSON {
+		return ctlWriteJSON(connections)
+	}
+	ctlWriteTable("ID\tINBOUND\tNETWORK\tSOURCE\tDESTINATION\tCHAIN\tUPLOAD\tDOWNLOAD", func(writer io.Writer) {
+		for i, connection := range connections {
+			writer.Write([]byte(F.ToString(
+				connection.ID, "\t",
+				connection.Inbound, "\t",
+				connection.Network, "\t",
+				connection.Source, "\t",
+				message[i].DisplayDestination(), "\t",
+				strings.Join(connection.Chain, " -> "), "\t",
+				humanize.Bytes(uint64(connection.Upload)), "\t",
+				humanize.Bytes(uint64(connection.Download)), "\n",
+			)))
+		}
+	})
+	return nil
+}
+
+func ctlClose(connectionIDs []string) error {
+	client := ctlStandaloneClient()
+	if ctlCloseAll {
+		if len(connectionIDs) > 0 {
+			return E.New("connection ids and --all are exclusive")
+		}
+		return client.CloseConnections()
+	}
+	if len(connectionIDs) == 0 {
+		return E.New("missing connection id")
+	}
+	for _, connectionID := range connectionIDs {
+		err := client.CloseConnection(connectionID)
+		if err != nil {
+			return E.Cause(err, "close ", connectionID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+//go:build with_clash_api
+
+package main
+
+import (
+	"io"
+
+	"github.com/sagernet/sing-box/experimental/libbox"
+	"github.com/sagernet/sing-box/log"
+	F "github.com/sagernet/sing/common/format"
+
+	"github.com/spf13/cobra"
+)
+
+var commandCtlGroups = &cobra.Command{
+	Use:   "groups",
+	Short: "List outbound groups",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlGroups()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var commandCtlSelect = &cobra.Command{
+	Use:   "select <group> <outbound>",
+	Short: "Select the outbound of a selector",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlStandaloneClient().SelectOutbound(args[0], args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var commandCtlURLTest = &cobra.Command{
+	Use:   "urltest <group>",
+	Short: "Start URL tests for the outbounds of a group",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlStandaloneClient().URLTest(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandCtl.AddCommand(commandCtlGroups)
+	commandCtl.AddCommand(commandCtlSelect)
+	commandCtl.AddCommand(commandCtlURLTest)
+}
+
+type ctlGroupOutput struct {
+	Tag        string               `json:"tag"`
+	Type       string               `json:"type"`
+	Selectable bool                 `json:"selectable"`
+	Selected   string               `json:"selected"`
+	Items      []ctlGroupItemOutput `json:"items"`
+}
+
+type ctlGroupItemOutput struct {
+	Tag      string `json:"tag"`
+	Type     string `json:"type"`
+	Delay    int32  `json:"delay,omitempty"`
+	TestedAt string `json:"tested_at,omitempty"`
+}
+
+func ctlGroups() error {
+	outputJSON, err := ctlJSON()
+	if err != nil {
+		return err
+	}
+	handler := newCtlHandler()
+	client, err := ctlConnect(libbox.CommandGroup, handler)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+	message, err := ctlReceive(handler, handler.groups)
+	if err != nil {
+		return err
+	}
+	groups := make([]ctlGroupOutput, 0, len(message))
+	for _, group := range message {
+		groupOutput := ctlGroupOutput{
+			Tag:        group.Tag,
+			Type:       group.Type,
+			Selectable: group.Selectable,
+			Selected:   group.Selected,
+		}
+		for _, item := range group.ItemList {
+			itemOutput := ctlGroupItemOutput{
+				Tag:   item.Tag,
+				Type:  item.Type,
+				Delay: item.URLTestDelay,
+			}
+			if item.URLTestTime > 0 {
+				itemOutput.TestedAt = ctlFormatTime(item.URLTestTime * 1000)
+			}
+			groupOutput.Items = append(groupOutput.Items, itemOutput)
+		}
+		groups = append(groups, groupOutput)
+	}
+	if outputJSON {
+		return ctlWriteJSON(groups)
+	}
+	ctlWriteTable("GROUP\tTYPE\tOUTBOUND\tOUTBOUND TYPE\tDELAY", func(writer io.Writer) {
+		for _, group := range groups {
+			for i, item := range group.Items {
+				var groupTag, groupType string
+				if i == 0 {
+					groupTag, groupType = group.Tag, group.Type
+				}
+				outboundTag := item.Tag
+				if item.Tag == group.Selected {
+					outboundTag = "* " + outboundTag
+				} else {
+					outboundTag = "  " + outboundTag
+				}
+				delay := "-"
+				if item.Delay > 0 {
+					delay = F.ToString(item.Delay, "ms")
+				}
+				writer.Write([]byte(F.ToString(groupTag, "\t", groupType, "\t", outboundTag, "\t", item.Type, "\t", delay, "\n")))
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,78 @@
+//go:build with_clash_api
+
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sagernet/sing-box/experimental/libbox"
+	"github.com/sagernet/sing-box/log"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"github.com/spf13/cobra"
+)
+
+var ctlLogsFollow bool
+
+var commandCtlLogs = &cobra.Command{
+	Use:   "logs",
+	Short: "Print recent logs",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlLogs()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandCtlLogs.Flags().BoolVarP(&ctlLogsFollow, "follow", "f", false, "Keep printing new logs")
+	commandCtl.AddCommand(commandCtlLogs)
+}
+
+func ctlLogs() error {
+	outputJSON, err := ctlJSON()
+	if err != nil {
+		return err
+	}
+	handler := newCtlHandler()
+	client, err := ctlConnect(libbox.CommandLog, handler)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+	for {
+		var messages []string
+		if ctlLogsFollow {
+			messages, err = ctlReceive(handler, handler.logs)
+			if err != nil {
+				return err
+			}
+		} else {
+			// saved logs are sent at once after connecting, and nothing is sent if there are none
+			select {
+			case messages = <-handler.logs:
+			case message := <-handler.disconnected:
+				return E.New("disconnected: ", message)
+			case <-time.After(ctlInterval):
+				return nil
+			}
+		}
+		for _, message := range messages {
+			if outputJSON {
+				err = ctlWriteJSON(message)
+			} else {
+				_, err = os.Stdout.WriteString(strings.TrimRight(message, "\n") + "\n")
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if !ctlLogsFollow {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,96 @@
+//go:build with_clash_api
+
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/sagernet/sing-box/experimental/libbox"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+
+	"github.com/spf13/cobra"
+)
+
+var commandCtlMode = &cobra.Command{
+	Use:   "mode [mode]",
+	Short: "Show or set the Clash mode",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlMode(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var commandCtlReload = &cobra.Command{
+	Use:   "reload",
+	Short: "Check the configuration and reload the service",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlStandaloneClient().ServiceReload()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandCtl.AddCommand(commandCtlMode)
+	commandCtl.AddCommand(commandCtlReload)
+}
+
+type ctlModeOutput struct {
+	Mode  string   `json:"mode"`
+	Modes []string `json:"modes"`
+}
+
+func ctlMode(args []string) error {
+	outputJSON, err := ctlJSON()
+	if err != nil {
+		return err
+	}
+	handler := newCtlHandler()
+	client, err := ctlConnect(libbox.CommandClashMode, handler)
+	if err != nil {
+		return err
+	}
+	modeList, err := ctlReceive(handler, handler.clashModes)
+	client.Disconnect()
+	if err != nil {
+		return err
+	}
+	if len(modeList) == 0 {
+		return E.New("Clash mode is not available")
+	}
+	if len(args) > 0 {
+		// the server ignores unknown modes, so they are checked here
+		newMode := common.Find(modeList, func(it string) bool {
+			return strings.EqualFold(it, args[0])
+		})
+		if newMode == "" {
+			return E.New("unknown mode: ", args[0], ", available: ", strings.Join(modeList, ", "))
+		}
+		return ctlStandaloneClient().SetClashMode(newMode)
+	}
+	if outputJSON {
+		return ctlWriteJSON(ctlModeOutput{
+			Mode:  handler.currentMode,
+			Modes: modeList,
+		})
+	}
+	ctlWriteTable("MODE", func(writer io.Writer) {
+		for _, mode := range modeList {
+			if mode == handler.currentMode {
+				writer.Write([]byte(F.ToString("* ", mode, "\n")))
+			} else {
+				writer.Write([]byte(F.ToString("  ", mode, "\n")))
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,141 @@
+//go:build with_clash_api
+
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/sagernet/sing-box/common/humanize"
+	"github.com/sagernet/sing-box/experimental/libbox"
+	"github.com/sagernet/sing-box/log"
+	F "github.com/sagernet/sing/common/format"
+
+	"github.com/spf13/cobra"
+)
+
+var commandCtlStatus = &cobra.Command{
+	Use:   "status",
+	Short: "Show memory, connection and traffic status",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := ctlStatus()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandCtl.AddCommand(commandCtlStatus)
+}
+
+type ctlStatusOutput struct {
+	Memory           int64                      `json:"memory"`
+	Goroutines       int32                      `json:"goroutines"`
+	ConnectionsIn    int32                      `json:"connections_in"`
+	ConnectionsOut   int32                      `json:"connections_out"`
+	TrafficAvailable bool                       `json:"traffic_available"`
+	Uplink           int64                      `json:"uplink"`
+	Downlink         int64                      `json:"downlink"`
+	UplinkTotal      int64                      `json:"uplink_total"`
+	DownlinkTotal    int64                      `json:"downlink_total"`
+	OutboundFailures []ctlOutboundFailureOutput `json:"outbound_failures,omitempty"`
+}
+
+type ctlOutboundFailureOutput struct {
+	Outbound            string `json:"outbound"`
+	Success             int64  `json:"success"`
+	DNS                 int64  `json:"dns"`
+	Refused             int64  `json:"refused"`
+	Unreachable         int64  `json:"unreachable"`
+	Reset               int64  `json:"reset"`
+	Timeout             int64  `json:"timeout"`
+	TLS                 int64  `json:"tls"`
+	Auth                int64  `json:"auth"`
+	Other               int64  `json:"other"`
+	ConsecutiveFailures int64  `json:"consecutive_failures"`
+	LastError           string `json:"last_error,omitempty"`
+	LastFailureAt       string `json:"last_failure_at,omitempty"`
+}
+
+func (o ctlOutboundFailureOutput) failures() int64 {
+	return o.DNS + o.Refused + o.Unreachable + o.Reset + o.Timeout + o.TLS + o.Auth + o.Other
+}
+
+func ctlStatus() error {
+	outputJSON, err := ctlJSON()
+	if err != nil {
+		return err
+	}
+	handler := newCtlHandler()
+	client, err := ctlConnect(libbox.CommandStatus, handler)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect()
+	message, err := ctlReceive(handler, handler.status)
+	if err != nil {
+		return err
+	}
+	status := ctlStatusOutput{
+		Memory:           message.Memory,
+		Goroutines:       message.Goroutines,
+		ConnectionsIn:    message.ConnectionsIn,
+		ConnectionsOut:   message.ConnectionsOut,
+		TrafficAvailable: message.TrafficAvailable,
+		Uplink:           message.Uplink,
+		Downlink:         message.Downlink,
+		UplinkTotal:      message.UplinkTotal,
+		DownlinkTotal:    message.DownlinkTotal,
+	}
+	outboundFailures, err := client.GetOutboundFailures()
+	if err != nil {
+		return err
+	}
+	for outboundFailures.HasNext() {
+		failure := outboundFailures.Next()
+		status.OutboundFailures = append(status.OutboundFailures, ctlOutboundFailureOutput{
+			Outbound:            failure.Outbound,
+			Success:             failure.Success,
+			DNS:                 failure.DNS,
+			Refused:             failure.Refused,
+			Unreachable:         failure.Unreachable,
+			Reset:               failure.Reset,
+			Timeout:             failure.Timeout,
+			TLS:                 failure.TLS,
+			Auth:                failure.Auth,
+			Other:               failure.Other,
+			ConsecutiveFailures: failure.ConsecutiveFailures,
+			LastError:           failure.LastError,
+			LastFailureAt:       ctlFormatTime(failure.LastFailureAt),
+		})
+	}
+	if outputJSON {
+		return ctlWriteJSON(status)
+	}
+	ctlWriteTable("STATUS\tVALUE", func(writer io.Writer) {
+		writer.Write([]byte(F.ToString("memory\t", humanize.MemoryBytes(uint64(status.Memory)), "\n")))
+		writer.Write([]byte(F.ToString("goroutines\t", status.Goroutines, "\n")))
+		writer.Write([]byte(F.ToString("connections\t", status.ConnectionsIn, " in, ", status.ConnectionsOut, " out\n")))
+		if status.TrafficAvailable {
+			writer.Write([]byte(F.ToString("uplink\t", humanize.Bytes(uint64(status.Uplink)), "/s, ", humanize.Bytes(uint64(status.UplinkTotal)), " total\n")))
+			writer.Write([]byte(F.ToString("downlink\t", humanize.Bytes(uint64(status.Downlink)), "/s, ", humanize.Bytes(uint64(status.DownlinkTotal)), " total\n")))
+		}
+	})
+	if len(status.OutboundFailures) > 0 {
+		os.Stdout.WriteString("\n")
+		ctlWriteTable("OUTBOUND\tSUCCESS\tFAILURES\tCONSECUTIVE\tLAST ERROR", func(writer io.Writer) {
+			for _, failure := range status.OutboundFailures {
+				writer.Write([]byte(F.ToString(
+					failure.Outbound, "\t",
+					failure.Success, "\t",
+					failure.failures(), "\t",
+					failure.ConsecutiveFailures, "\t",
+					failure.LastError, "\n",
+				)))
+			}
+		})
+	}
+	return nil
+}
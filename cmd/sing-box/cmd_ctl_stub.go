@@ -0,0 +1,16 @@
+//go:build !with_clash_api
+
+package main
+
+import (
+	"github.com/sagernet/sing-box/log"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	commandCtl.Run = func(cmd *cobra.Command, args []string) {
+		log.Fatal(E.New(`ctl is not included in this build, rebuild with -tags with_clash_api`))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/sagernet/sing-box/common/directive"
 	"github.com/sagernet/sing-box/log"
 	E "github.com/sagernet/sing/common/exceptions"
 	"github.com/sagernet/sing/common/json"
@@ -13,7 +14,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var commandFormatFlagWrite bool
+var (
+	commandFormatFlagWrite   bool
+	commandFormatFlagResolve bool
+)
 
 var commandFormat = &cobra.Command{
 	Use:   "format",
@@ -29,6 +33,7 @@ var commandFormat = &cobra.Command{
 
 func init() {
 	commandFormat.Flags().BoolVarP(&commandFormatFlagWrite, "write", "w", false, "write result to (source) file instead of stdout")
+	commandFormat.Flags().BoolVar(&commandFormatFlagResolve, "resolve", false, "resolve environment variables, secret files and includes instead of preserving them")
 	mainCommand.AddCommand(commandFormat)
 }
 
@@ -38,14 +43,23 @@ func format() error {
 		return err
 	}
 	for _, optionsEntry := range optionsList {
-		optionsEntry.options, err = badjson.Omitempty(optionsEntry.options)
-		if err != nil {
-			return err
+		var content any
+		if !commandFormatFlagResolve && directive.Contains(optionsEntry.content) {
+			// typed options can not hold directives, so only the layout is formatted
+			content, err = directive.Decode(optionsEntry.content)
+			if err != nil {
+				return E.Cause(err, "decode config at ", optionsEntry.path)
+			}
+		} else {
+			content, err = badjson.Omitempty(optionsEntry.options)
+			if err != nil {
+				return err
+			}
 		}
 		buffer := new(bytes.Buffer)
 		encoder := json.NewEncoder(buffer)
 		encoder.SetIndent("", "  ")
-		err = encoder.Encode(optionsEntry.options)
+		err = encoder.Encode(content)
 		if err != nil {
 			return E.Cause(err, "encode config")
 		}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sagernet/sing-box/cmd/sing-box/internal/schema"
+	"github.com/sagernet/sing-box/log"
+
+	"github.com/spf13/cobra"
+)
+
+var flagGenerateSchemaRuleSet bool
+
+var commandGenerateSchema = &cobra.Command{
+	Use:   "schema",
+	Short: "Generate JSON schema of the configuration",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := generateSchema()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandGenerateSchema.Flags().BoolVar(&flagGenerateSchemaRuleSet, "rule-set", false, "Generate JSON schema of source rule-set files")
+	commandGenerate.AddCommand(commandGenerateSchema)
+}
+
+func generateSchema() error {
+	var (
+		jsonSchema *schema.Schema
+		err        error
+	)
+	if flagGenerateSchemaRuleSet {
+		jsonSchema, err = schema.RuleSetSource()
+	} else {
+		jsonSchema, err = schema.Configuration()
+	}
+	if err != nil {
+		return err
+	}
+	return schema.Write(os.Stdout, jsonSchema)
+}
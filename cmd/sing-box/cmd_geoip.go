@@ -1,15 +1,14 @@
 package main
 
 import (
+	"github.com/sagernet/sing-box/common/geoip"
 	"github.com/sagernet/sing-box/log"
-	E "github.com/sagernet/sing/common/exceptions"
 
-	"github.com/oschwald/maxminddb-golang"
 	"github.com/spf13/cobra"
 )
 
 var (
-	geoipReader          *maxminddb.Reader
+	geoipReader          *geoip.Reader
 	commandGeoIPFlagFile string
 )
 
@@ -30,14 +29,10 @@ func init() {
 }
 
 func geoipPreRun() error {
-	reader, err := maxminddb.Open(commandGeoIPFlagFile)
+	reader, _, err := geoip.Open(commandGeoIPFlagFile)
 	if err != nil {
 		return err
 	}
-	if reader.Metadata.DatabaseType != "sing-geoip" {
-		reader.Close()
-		return E.New("incorrect database type, expected sing-geoip, got ", reader.Metadata.DatabaseType)
-	}
 	geoipReader = reader
 	return nil
 }
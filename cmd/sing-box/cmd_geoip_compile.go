@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"net/netip"
+	"os"
+
+	"github.com/sagernet/sing-box/common/geoip"
+	"github.com/sagernet/sing-box/log"
+
+	"github.com/spf13/cobra"
+)
+
+var flagGeoipCompileOutput string
+
+var commandGeoipCompile = &cobra.Command{
+	Use:              "compile <source-directory>",
+	Short:            "Compile geoip database from CIDR lists",
+	Args:             cobra.ExactArgs(1),
+	PersistentPreRun: preRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := geoipCompile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandGeoipCompile.Flags().StringVarP(&flagGeoipCompileOutput, "output", "o", "geoip.db", "Output path")
+	commandGeoip.AddCommand(commandGeoipCompile)
+}
+
+func geoipCompile(sourcePath string) error {
+	codes, err := geoip.ReadSource(sourcePath)
+	if err != nil {
+		return err
+	}
+	return writeGeoipDatabase(flagGeoipCompileOutput, codes)
+}
+
+func writeGeoipDatabase(outputPath string, codes map[string][]netip.Prefix) error {
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(outputFile)
+	err = geoip.Write(writer, codes)
+	if err == nil {
+		err = writer.Flush()
+	}
+	outputFile.Close()
+	if err != nil {
+		os.Remove(outputPath)
+		return err
+	}
+	log.Info("write ", len(codes), " codes to ", outputPath)
+	return nil
+}
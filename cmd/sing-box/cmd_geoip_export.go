@@ -2,17 +2,18 @@ package main
 
 import (
 	"io"
-	"net"
+	"net/netip"
 	"os"
 	"strings"
 
+	"github.com/sagernet/sing-box/common/geoip"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
 	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
 	"github.com/sagernet/sing/common/json"
 
-	"github.com/oschwald/maxminddb-golang"
 	"github.com/spf13/cobra"
 )
 
@@ -21,8 +22,8 @@ var flagGeoipExportOutput string
 const flagGeoipExportDefaultOutput = "geoip-<country>.srs"
 
 var commandGeoipExport = &cobra.Command{
-	Use:   "export <country>",
-	Short: "Export geoip country as rule-set",
+	Use:   "export <country|AS number>",
+	Short: "Export geoip country or autonomous system as rule-set",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		err := geoipExport(args[0])
@@ -38,23 +39,49 @@ func init() {
 }
 
 func geoipExport(countryCode string) error {
-	networks := geoipReader.Networks(maxminddb.SkipAliasedNetworks)
-	countryMap := make(map[string][]*net.IPNet)
 	var (
-		ipNet           *net.IPNet
-		nextCountryCode string
-		err             error
+		ipNets []netip.Prefix
+		err    error
 	)
-	for networks.Next() {
-		ipNet, err = networks.Network(&nextCountryCode)
+	if strings.HasPrefix(strings.ToUpper(countryCode), "AS") {
+		if !geoipReader.HasASN() {
+			return E.New("database has no ASN data: ", geoipReader.DatabaseType())
+		}
+		var asn uint32
+		asn, err = geoip.ParseASN(countryCode)
 		if err != nil {
 			return err
 		}
-		countryMap[nextCountryCode] = append(countryMap[nextCountryCode], ipNet)
-	}
-	ipNets := countryMap[strings.ToLower(countryCode)]
-	if len(ipNets) == 0 {
-		return E.New("country code not found: ", countryCode)
+		err = geoipReader.Networks(func(prefix netip.Prefix, record geoip.Record) bool {
+			if record.ASN == asn {
+				ipNets = append(ipNets, prefix)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if len(ipNets) == 0 {
+			return E.New("ASN not found: ", countryCode)
+		}
+		countryCode = F.ToString("AS", asn)
+	} else {
+		if !geoipReader.HasCountry() {
+			return E.New("database has no country data: ", geoipReader.DatabaseType())
+		}
+		countryCode = strings.ToLower(countryCode)
+		err = geoipReader.Networks(func(prefix netip.Prefix, record geoip.Record) bool {
+			if record.Country == countryCode {
+				ipNets = append(ipNets, prefix)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		if len(ipNets) == 0 {
+			return E.New("country code not found: ", countryCode)
+		}
 	}
 
 	var (
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/sagernet/sing-box/common/geoip"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"github.com/spf13/cobra"
+	"go4.org/netipx"
+)
+
+var flagGeoipImportOutput string
+
+var commandGeoipImport = &cobra.Command{
+	Use:              "import <geoip.dat> [code]",
+	Short:            "Import v2ray geoip.dat as geoip database or rule-set",
+	Args:             cobra.RangeArgs(1, 2),
+	PersistentPreRun: preRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		var code string
+		if len(args) > 1 {
+			code = args[1]
+		}
+		err := geoipImport(args[0], code)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandGeoipImport.Flags().StringVarP(&flagGeoipImportOutput, "output", "o", "", "Output path (default geoip.db, or geoip-<code>.json if code is specified)")
+	commandGeoip.AddCommand(commandGeoipImport)
+}
+
+func geoipImport(sourcePath string, code string) error {
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+	codes, err := geoip.ReadV2Ray(content)
+	if err != nil {
+		return err
+	}
+	outputPath := flagGeoipImportOutput
+	if code == "" {
+		if outputPath == "" {
+			outputPath = "geoip.db"
+		}
+		return writeGeoipDatabase(outputPath, codes)
+	}
+	code = strings.ToLower(code)
+	prefixes, loaded := codes[code]
+	if !loaded {
+		return E.New("code not found: ", code)
+	}
+	var builder netipx.IPSetBuilder
+	for _, prefix := range prefixes {
+		builder.AddPrefix(prefix)
+	}
+	ipSet, err := builder.IPSet()
+	if err != nil {
+		return err
+	}
+	if outputPath == "" {
+		outputPath = "geoip-" + code + ".json"
+	}
+	return writeRuleSet(outputPath, option.DefaultHeadlessRule{
+		IPCIDR: common.Map(ipSet.Prefixes(), netip.Prefix.String),
+	})
+}
@@ -24,7 +24,11 @@ func init() {
 }
 
 func listGeoip() error {
-	for _, code := range geoipReader.Metadata.Languages {
+	codes, err := geoipReader.Codes()
+	if err != nil {
+		return err
+	}
+	for _, code := range codes {
 		os.Stdout.WriteString(code + "\n")
 	}
 	return nil
@@ -6,6 +6,7 @@ import (
 
 	"github.com/sagernet/sing-box/log"
 	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
 	N "github.com/sagernet/sing/common/network"
 
 	"github.com/spf13/cobra"
@@ -36,12 +37,16 @@ func geoipLookup(address string) error {
 		os.Stdout.WriteString("private\n")
 		return nil
 	}
-	var code string
-	_ = geoipReader.Lookup(addr.AsSlice(), &code)
-	if code != "" {
-		os.Stdout.WriteString(code + "\n")
-		return nil
+	if geoipReader.HasCountry() {
+		os.Stdout.WriteString(geoipReader.Lookup(addr) + "\n")
+	}
+	if geoipReader.HasASN() {
+		asn, organization := geoipReader.LookupASN(addr)
+		if asn != 0 {
+			os.Stdout.WriteString(F.ToString("AS", asn, " ", organization) + "\n")
+		} else {
+			os.Stdout.WriteString("unknown AS\n")
+		}
 	}
-	os.Stdout.WriteString("unknown\n")
 	return nil
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/sagernet/sing-box/common/geosite"
+	"github.com/sagernet/sing-box/log"
+
+	"github.com/spf13/cobra"
+)
+
+var flagGeositeCompileOutput string
+
+var commandGeositeCompile = &cobra.Command{
+	Use:              "compile <source-directory>",
+	Short:            "Compile geosite database from domain lists",
+	Args:             cobra.ExactArgs(1),
+	PersistentPreRun: preRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := geositeCompile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandGeositeCompile.Flags().StringVarP(&flagGeositeCompileOutput, "output", "o", "geosite.db", "Output path")
+	commandGeoSite.AddCommand(commandGeositeCompile)
+}
+
+func geositeCompile(sourcePath string) error {
+	source, err := geosite.ReadSource(sourcePath)
+	if err != nil {
+		return err
+	}
+	return writeGeositeDatabase(flagGeositeCompileOutput, geosite.Expand(source))
+}
+
+func writeGeositeDatabase(outputPath string, domains map[string][]geosite.Item) error {
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(outputFile)
+	err = geosite.Write(writer, domains)
+	if err == nil {
+		err = writer.Flush()
+	}
+	outputFile.Close()
+	if err != nil {
+		os.Remove(outputPath)
+		return err
+	}
+	log.Info("write ", len(domains), " codes to ", outputPath)
+	return nil
+}
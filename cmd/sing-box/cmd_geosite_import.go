@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sagernet/sing-box/common/geosite"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/spf13/cobra"
+)
+
+var flagGeositeImportOutput string
+
+var commandGeositeImport = &cobra.Command{
+	Use:              "import <geosite.dat> [code[@attribute]]",
+	Short:            "Import v2ray geosite.dat as geosite database or rule-set",
+	Args:             cobra.RangeArgs(1, 2),
+	PersistentPreRun: preRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		var code string
+		if len(args) > 1 {
+			code = args[1]
+		}
+		err := geositeImport(args[0], code)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandGeositeImport.Flags().StringVarP(&flagGeositeImportOutput, "output", "o", "", "Output path (default geosite.db, or geosite-<code>.json if code is specified)")
+	commandGeoSite.AddCommand(commandGeositeImport)
+}
+
+func geositeImport(sourcePath string, code string) error {
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+	source, err := geosite.ReadV2Ray(content)
+	if err != nil {
+		return err
+	}
+	outputPath := flagGeositeImportOutput
+	if code == "" {
+		if outputPath == "" {
+			outputPath = "geosite.db"
+		}
+		return writeGeositeDatabase(outputPath, geosite.Expand(source))
+	}
+	items, err := geosite.Filter(source, code)
+	if err != nil {
+		return err
+	}
+	if outputPath == "" {
+		outputPath = "geosite-" + code + ".json"
+	}
+	defaultRule := geosite.Compile(items)
+	return writeRuleSet(outputPath, option.DefaultHeadlessRule{
+		Domain:        defaultRule.Domain,
+		DomainSuffix:  defaultRule.DomainSuffix,
+		DomainKeyword: defaultRule.DomainKeyword,
+		DomainRegex:   defaultRule.DomainRegex,
+	})
+}
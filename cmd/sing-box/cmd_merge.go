@@ -6,17 +6,21 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/sagernet/sing-box/common/directive"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
 	"github.com/sagernet/sing/common"
 	E "github.com/sagernet/sing/common/exceptions"
 	"github.com/sagernet/sing/common/json"
+	"github.com/sagernet/sing/common/json/badjson"
 	"github.com/sagernet/sing/common/rw"
 
 	"github.com/spf13/cobra"
 )
 
+var commandMergeFlagPreserve bool
+
 var commandMerge = &cobra.Command{
 	Use:   "merge <output>",
 	Short: "Merge configurations",
@@ -30,22 +34,33 @@ var commandMerge = &cobra.Command{
 }
 
 func init() {
+	commandMerge.Flags().BoolVar(&commandMergeFlagPreserve, "preserve-directives", false, "keep environment variables, secret files and includes unresolved")
 	mainCommand.AddCommand(commandMerge)
 }
 
 func merge(outputPath string) error {
-	mergedOptions, err := readConfigAndMerge()
-	if err != nil {
-		return err
-	}
-	err = mergePathResources(&mergedOptions)
-	if err != nil {
-		return err
+	var content any
+	if commandMergeFlagPreserve {
+		mergedContent, err := mergeUnresolved()
+		if err != nil {
+			return err
+		}
+		content = mergedContent
+	} else {
+		mergedOptions, err := readConfigAndMerge()
+		if err != nil {
+			return err
+		}
+		err = mergePathResources(&mergedOptions)
+		if err != nil {
+			return err
+		}
+		content = mergedOptions
 	}
 	buffer := new(bytes.Buffer)
 	encoder := json.NewEncoder(buffer)
 	encoder.SetIndent("", "  ")
-	err = encoder.Encode(mergedOptions)
+	err := encoder.Encode(content)
 	if err != nil {
 		return E.Cause(err, "encode config")
 	}
@@ -67,6 +82,31 @@ func merge(outputPath string) error {
 	return nil
 }
 
+// mergeUnresolved merges configurations without resolving directives,
+// the configurations are still resolved and decoded to be validated.
+func mergeUnresolved() (json.RawMessage, error) {
+	optionsList, err := readConfig()
+	if err != nil {
+		return nil, err
+	}
+	var mergedMessage json.RawMessage
+	for _, options := range optionsList {
+		value, err := directive.Decode(options.content)
+		if err != nil {
+			return nil, E.Cause(err, "decode config at ", options.path)
+		}
+		rawMessage, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		mergedMessage, err = badjson.MergeJSON(rawMessage, mergedMessage, false)
+		if err != nil {
+			return nil, E.Cause(err, "merge config at ", options.path)
+		}
+	}
+	return mergedMessage, nil
+}
+
 func mergePathResources(options *option.Options) error {
 	for index, inbound := range options.Inbounds {
 		rawOptions, err := inbound.RawOptions()
@@ -1,6 +1,14 @@
 package main
 
 import (
+	"os"
+	"strings"
+
+	"github.com/sagernet/sing-box/common/srs"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common/json"
+
 	"github.com/spf13/cobra"
 )
 
@@ -12,3 +20,42 @@ var commandRuleSet = &cobra.Command{
 func init() {
 	mainCommand.AddCommand(commandRuleSet)
 }
+
+// writeRuleSet writes a single rule rule-set, as binary if the output path ends with `.srs`, or as source otherwise.
+func writeRuleSet(outputPath string, rule option.DefaultHeadlessRule) error {
+	plainRuleSet := option.PlainRuleSet{
+		Rules: []option.HeadlessRule{
+			{
+				Type:           C.RuleTypeDefault,
+				DefaultOptions: rule,
+			},
+		},
+	}
+	if outputPath == "stdout" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(option.PlainRuleSetCompat{
+			Version: C.RuleSetVersion2,
+			Options: plainRuleSet,
+		})
+	}
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(outputPath, ".srs") {
+		err = srs.Write(outputFile, plainRuleSet, C.RuleSetVersion1)
+	} else {
+		encoder := json.NewEncoder(outputFile)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(option.PlainRuleSetCompat{
+			Version: C.RuleSetVersion2,
+			Options: plainRuleSet,
+		})
+	}
+	outputFile.Close()
+	if err != nil {
+		os.Remove(outputPath)
+	}
+	return err
+}
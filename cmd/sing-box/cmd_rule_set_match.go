@@ -68,10 +68,12 @@ func ruleSetMatch(sourcePath string, domain string) error {
 			return err
 		}
 	case C.RuleSetFormatBinary:
-		plainRuleSet, err = srs.Read(bytes.NewReader(content), false)
+		var compat option.PlainRuleSetCompat
+		compat, err = srs.Read(bytes.NewReader(content), false)
 		if err != nil {
 			return err
 		}
+		plainRuleSet = compat.Options
 	default:
 		return E.New("unknown rule-set format: ", flagRuleSetMatchFormat)
 	}
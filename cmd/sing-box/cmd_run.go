@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/sagernet/sing-box"
+	"github.com/sagernet/sing-box/common/directive"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
@@ -34,7 +35,13 @@ var commandRun = &cobra.Command{
 	},
 }
 
+var commandSocketPath string
+
+// reloadRequest is sent by the command server to reload the service like SIGHUP.
+var reloadRequest = make(chan struct{}, 1)
+
 func init() {
+	commandRun.Flags().StringVar(&commandSocketPath, "command-socket", "", "expose the command server for sing-box ctl on the unix socket path")
 	mainCommand.AddCommand(commandRun)
 }
 
@@ -57,7 +64,11 @@ func readConfigAt(path string) (*OptionsEntry, error) {
 	if err != nil {
 		return nil, E.Cause(err, "read config at ", path)
 	}
-	options, err := json.UnmarshalExtended[option.Options](configContent)
+	resolvedContent, err := directive.Resolve(configContent, path)
+	if err != nil {
+		return nil, E.Cause(err, "resolve config at ", path)
+	}
+	options, err := json.UnmarshalExtended[option.Options](resolvedContent)
 	if err != nil {
 		return nil, E.Cause(err, "decode config at ", path)
 	}
@@ -134,7 +145,7 @@ func create() (*box.Box, context.CancelFunc, error) {
 		options.Log.DisableColor = true
 	}
 	ctx, cancel := context.WithCancel(globalCtx)
-	instance, err := box.New(box.Options{
+	instance, err := newInstance(box.Options{
 		Context: ctx,
 		Options: options,
 	})
@@ -163,6 +174,7 @@ func create() (*box.Box, context.CancelFunc, error) {
 		cancel()
 		return nil, nil, E.Cause(err, "start service")
 	}
+	commandServiceStarted()
 	return instance, cancel, nil
 }
 
@@ -170,6 +182,11 @@ func run() error {
 	osSignals := make(chan os.Signal, 1)
 	signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 	defer signal.Stop(osSignals)
+	err := startCommandServer()
+	if err != nil {
+		return err
+	}
+	defer closeCommandServer()
 	for {
 		instance, cancel, err := create()
 		if err != nil {
@@ -177,7 +194,12 @@ func run() error {
 		}
 		runtimeDebug.FreeOSMemory()
 		for {
-			osSignal := <-osSignals
+			var osSignal os.Signal
+			select {
+			case osSignal = <-osSignals:
+			case <-reloadRequest:
+				osSignal = syscall.SIGHUP
+			}
 			if osSignal == syscall.SIGHUP {
 				err = check()
 				if err != nil {
@@ -185,6 +207,7 @@ func run() error {
 					continue
 				}
 			}
+			commandServiceClosing()
 			cancel()
 			closeCtx, closed := context.WithCancel(context.Background())
 			go closeMonitor(closeCtx)
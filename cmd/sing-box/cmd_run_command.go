@@ -0,0 +1,95 @@
+//go:build with_clash_api
+
+package main
+
+import (
+	"github.com/sagernet/sing-box"
+	"github.com/sagernet/sing-box/experimental/libbox"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+var (
+	commandServer  *libbox.CommandServer
+	commandService *libbox.BoxService
+)
+
+func startCommandServer() error {
+	if commandSocketPath == "" {
+		return nil
+	}
+	libbox.SetupCommandSocket(commandSocketPath)
+	server := libbox.NewCommandServer(&commandServerHandler{}, 300)
+	err := server.Start()
+	if err != nil {
+		return E.Cause(err, "start command server")
+	}
+	commandServer = server
+	return nil
+}
+
+func closeCommandServer() {
+	common.Close(commandServer)
+}
+
+func newInstance(options box.Options) (*box.Box, error) {
+	if commandServer == nil {
+		return box.New(options)
+	}
+	options.PlatformLogWriter = (*commandLogWriter)(nil)
+	service, err := libbox.NewServiceWithOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	commandService = service
+	return service.Instance(), nil
+}
+
+func commandServiceStarted() {
+	if commandServer != nil {
+		commandServer.SetService(commandService)
+	}
+}
+
+func commandServiceClosing() {
+	if commandServer != nil {
+		commandServer.SetService(nil)
+		commandService = nil
+	}
+}
+
+type commandServerHandler struct{}
+
+func (h *commandServerHandler) ServiceReload() error {
+	err := check()
+	if err != nil {
+		return err
+	}
+	select {
+	case reloadRequest <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (h *commandServerHandler) PostServiceClose() {
+}
+
+func (h *commandServerHandler) GetSystemProxyStatus() *libbox.SystemProxyStatus {
+	return &libbox.SystemProxyStatus{}
+}
+
+func (h *commandServerHandler) SetSystemProxyEnabled(isEnabled bool) error {
+	return E.New("system proxy is not supported")
+}
+
+type commandLogWriter struct{}
+
+func (w *commandLogWriter) DisableColors() bool {
+	return true
+}
+
+func (w *commandLogWriter) WriteMessage(level log.Level, message string) {
+	commandServer.WriteMessage(message)
+}
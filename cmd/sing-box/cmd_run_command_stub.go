@@ -0,0 +1,28 @@
+//go:build !with_clash_api
+
+package main
+
+import (
+	"github.com/sagernet/sing-box"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+func startCommandServer() error {
+	if commandSocketPath == "" {
+		return nil
+	}
+	return E.New(`command server is not included in this build, rebuild with -tags with_clash_api`)
+}
+
+func closeCommandServer() {
+}
+
+func newInstance(options box.Options) (*box.Box, error) {
+	return box.New(options)
+}
+
+func commandServiceStarted() {
+}
+
+func commandServiceClosing() {
+}
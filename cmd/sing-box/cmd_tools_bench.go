@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sagernet/sing-box"
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/humanize"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+	M "github.com/sagernet/sing/common/metadata"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	commandBenchFlagProtocol   string
+	commandBenchFlagTransport  string
+	commandBenchFlagTLS        string
+	commandBenchFlagMethod     string
+	commandBenchFlagFlow       string
+	commandBenchFlagNetwork    []string
+	commandBenchFlagSize       string
+	commandBenchFlagUDPSize    string
+	commandBenchFlagPacketSize int
+	commandBenchFlagHandshakes int
+)
+
+var commandBench = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark a protocol in-process over loopback",
+	Long: `Benchmark a protocol in-process over loopback.
+
+A matching inbound and outbound are started in the current process,
+bulk TCP and UDP traffic is pushed through the pair to a local sink,
+and throughput, handshake latency, allocations and CPU time per GB are reported.
+CPU time and allocations are measured for the whole process, so both sides are included.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := bench()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	commandBench.Flags().StringVarP(&commandBenchFlagProtocol, "protocol", "p", "vless", "Protocol, available: socks, http, shadowsocks, vmess, vless, trojan, hysteria2, tuic")
+	commandBench.Flags().StringVar(&commandBenchFlagTransport, "transport", "", "V2Ray transport, available: ws, grpc, http, httpupgrade, quic")
+	commandBench.Flags().StringVar(&commandBenchFlagTLS, "tls", "", "TLS mode, available: none, tls, reality (default tls for QUIC based protocols, otherwise none)")
+	commandBench.Flags().StringVar(&commandBenchFlagMethod, "method", "", "Shadowsocks method or VMess security")
+	commandBench.Flags().StringVar(&commandBenchFlagFlow, "flow", "", "VLESS flow")
+	commandBench.Flags().StringSliceVarP(&commandBenchFlagNetwork, "network", "n", []string{"tcp", "udp"}, "Networks to benchmark")
+	commandBench.Flags().StringVar(&commandBenchFlagSize, "size", "256MiB", "Data size of each TCP test")
+	commandBench.Flags().StringVar(&commandBenchFlagUDPSize, "udp-size", "32MiB", "Data size of each UDP test")
+	commandBench.Flags().IntVar(&commandBenchFlagPacketSize, "packet-size", 1200, "UDP packet size")
+	commandBench.Flags().IntVar(&commandBenchFlagHandshakes, "handshakes", 100, "Number of handshake latency samples")
+	commandTools.AddCommand(commandBench)
+}
+
+type benchResult struct {
+	name     string
+	bytes    uint64
+	expected uint64
+	duration time.Duration
+	cpuTime  time.Duration
+	mallocs  uint64
+	alloc    uint64
+}
+
+type benchMeasure struct {
+	startTime time.Time
+	cpuTime   time.Duration
+	memStats  runtime.MemStats
+}
+
+func startBenchMeasure() *benchMeasure {
+	var measure benchMeasure
+	runtime.GC()
+	runtime.ReadMemStats(&measure.memStats)
+	measure.cpuTime = processCPUTime()
+	measure.startTime = time.Now()
+	return &measure
+}
+
+func (m *benchMeasure) finish(name string, bytes uint64, expected uint64) benchResult {
+	duration := time.Since(m.startTime)
+	cpuTime := processCPUTime() - m.cpuTime
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	return benchResult{
+		name:     name,
+		bytes:    bytes,
+		expected: expected,
+		duration: duration,
+		cpuTime:  cpuTime,
+		mallocs:  memStats.Mallocs - m.memStats.Mallocs,
+		alloc:    memStats.TotalAlloc - m.memStats.TotalAlloc,
+	}
+}
+
+func bench() error {
+	tcpSize, err := humanize.ParseBytes(commandBenchFlagSize)
+	if err != nil {
+		return E.Cause(err, "parse size")
+	}
+	udpSize, err := humanize.ParseBytes(commandBenchFlagUDPSize)
+	if err != nil {
+		return E.Cause(err, "parse udp size")
+	}
+	if commandBenchFlagPacketSize < 9 || commandBenchFlagPacketSize > 65507 {
+		return E.New("invalid packet size: ", commandBenchFlagPacketSize)
+	}
+	var benchTCP, benchUDP bool
+	for _, network := range commandBenchFlagNetwork {
+		switch network {
+		case "tcp":
+			benchTCP = true
+		case "udp":
+			benchUDP = true
+		default:
+			return E.New("unknown network: ", network)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, err := newBenchServer(commandBenchFlagPacketSize)
+	if err != nil {
+		return E.Cause(err, "start sink server")
+	}
+	defer server.Close()
+	pair, err := newBenchPair(benchPairOptions{
+		Protocol:  commandBenchFlagProtocol,
+		Transport: commandBenchFlagTransport,
+		TLS:       commandBenchFlagTLS,
+		Method:    commandBenchFlagMethod,
+		Flow:      commandBenchFlagFlow,
+	})
+	if err != nil {
+		return err
+	}
+	defer pair.Close()
+	instance, err := box.New(box.Options{
+		Context: ctx,
+		Options: pair.options,
+	})
+	if err != nil {
+		return E.Cause(err, "create service")
+	}
+	defer instance.Close()
+	err = instance.Start()
+	if err != nil {
+		return E.Cause(err, "start service")
+	}
+	outbound, loaded := instance.Router().Outbound(benchOutboundTag)
+	if !loaded {
+		return E.New("missing bench outbound")
+	}
+	os.Stdout.WriteString(F.ToString("protocol: ", pair.description, "\n"))
+
+	var results []benchResult
+	if benchTCP {
+		latencies, err := benchHandshake(ctx, outbound, server.tcpAddr, commandBenchFlagHandshakes)
+		if err != nil {
+			return E.Cause(err, "benchmark handshake")
+		}
+		printHandshakeLatency(latencies)
+		result, err := benchTCPUpload(ctx, outbound, server.tcpAddr, tcpSize)
+		if err != nil {
+			return E.Cause(err, "benchmark tcp upload")
+		}
+		results = append(results, result)
+		result, err = benchTCPDownload(ctx, outbound, server.tcpAddr, tcpSize)
+		if err != nil {
+			return E.Cause(err, "benchmark tcp download")
+		}
+		results = append(results, result)
+	}
+	if benchUDP {
+		if !pair.supportUDP {
+			log.Warn("udp is not supported by ", commandBenchFlagProtocol, ", skipped")
+		} else {
+			result, err := benchUDPUpload(ctx, outbound, server, udpSize)
+			if err != nil {
+				return E.Cause(err, "benchmark udp upload")
+			}
+			results = append(results, result)
+			result, err = benchUDPDownload(ctx, outbound, server.udpAddr, udpSize)
+			if err != nil {
+				return E.Cause(err, "benchmark udp download")
+			}
+			results = append(results, result)
+		}
+	}
+	if len(results) > 0 {
+		printBenchResults(results)
+	}
+	return nil
+}
+
+func benchHandshake(ctx context.Context, outbound adapter.Outbound, destination M.Socksaddr, samples int) ([]time.Duration, error) {
+	latencies := make([]time.Duration, 0, samples)
+	for i := 0; i < samples; i++ {
+		startTime := time.Now()
+		dialCtx, cancel := context.WithTimeout(ctx, C.TCPTimeout)
+		conn, err := outbound.DialContext(dialCtx, "tcp", destination)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		conn.SetDeadline(time.Now().Add(C.TCPTimeout))
+		err = benchPing(conn)
+		conn.Close()
+		if err != nil {
+			return nil, err
+		}
+		latencies = append(latencies, time.Since(startTime))
+	}
+	return latencies, nil
+}
+
+func printHandshakeLatency(latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	percentile := func(p int) time.Duration {
+		return latencies[(len(latencies)-1)*p/100]
+	}
+	os.Stdout.WriteString(F.ToString(
+		"handshake: ", len(latencies), " samples",
+		", avg ", formatBenchDuration(total/time.Duration(len(latencies))),
+		", p50 ", formatBenchDuration(percentile(50)),
+		", p90 ", formatBenchDuration(percentile(90)),
+		", p99 ", formatBenchDuration(percentile(99)),
+		"\n",
+	))
+}
+
+func printBenchResults(results []benchResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	writer.Write([]byte("TEST\tDATA\tTIME\tTHROUGHPUT\tLOSS\tCPU/GB\tALLOCS/GB\tALLOC/GB\n"))
+	for _, result := range results {
+		var loss string
+		if result.expected > 0 {
+			loss = F.ToString(formatBenchFloat(100*float64(result.expected-result.bytes)/float64(result.expected)), "%")
+		} else {
+			loss = "-"
+		}
+		gigabytes := float64(result.bytes) / humanize.GByte
+		var cpuPerGB, mallocsPerGB, allocPerGB string
+		if gigabytes > 0 {
+			cpuPerGB = formatBenchDuration(time.Duration(float64(result.cpuTime) / gigabytes))
+			mallocsPerGB = F.ToString(uint64(float64(result.mallocs) / gigabytes))
+			allocPerGB = humanize.IBytes(uint64(float64(result.alloc) / gigabytes))
+		} else {
+			cpuPerGB, mallocsPerGB, allocPerGB = "-", "-", "-"
+		}
+		writer.Write([]byte(F.ToString(
+			result.name, "\t",
+			humanize.IBytes(result.bytes), "\t",
+			formatBenchDuration(result.duration), "\t",
+			formatBenchThroughput(result.bytes, result.duration), "\t",
+			loss, "\t",
+			cpuPerGB, "\t",
+			mallocsPerGB, "\t",
+			allocPerGB, "\n",
+		)))
+	}
+	writer.Flush()
+}
+
+func formatBenchDuration(duration time.Duration) string {
+	switch {
+	case duration >= time.Second:
+		return duration.Round(time.Millisecond).String()
+	case duration >= time.Millisecond:
+		return duration.Round(10 * time.Microsecond).String()
+	default:
+		return duration.Round(time.Microsecond).String()
+	}
+}
+
+func formatBenchThroughput(bytes uint64, duration time.Duration) string {
+	if duration <= 0 {
+		return "-"
+	}
+	bitsPerSecond := float64(bytes) * 8 / duration.Seconds()
+	switch {
+	case bitsPerSecond >= 1e9:
+		return F.ToString(formatBenchFloat(bitsPerSecond/1e9), " Gbps")
+	default:
+		return F.ToString(formatBenchFloat(bitsPerSecond/1e6), " Mbps")
+	}
+}
+
+func formatBenchFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func benchPing(conn net.Conn) error {
+	_, err := conn.Write([]byte{benchCommandPing})
+	if err != nil {
+		return err
+	}
+	var response [1]byte
+	_, err = conn.Read(response[:])
+	return err
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"crypto/rand"
+	stdTLS "crypto/tls"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/sagernet/sing-box/common/tls"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	"github.com/sagernet/sing/common/auth"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	benchOutboundTag = "bench"
+	benchServerName  = "bench.sing-box.local"
+	// key pair used by the reality tests in test/
+	benchRealityPrivateKey = "UuMBgl7MXTPx9inmQp2UC7Jcnwc6XYbwDNebonM-FCc"
+	benchRealityPublicKey  = "jNXHt1yRo0vDuchQlIP6Z0ZvjT3KtzVI-T4E7RoLJS0"
+	benchRealityShortID    = "0123456789abcdef"
+)
+
+type benchPairOptions struct {
+	Protocol  string
+	Transport string
+	TLS       string
+	Method    string
+	Flow      string
+}
+
+// benchPair is a matching inbound and outbound connected over loopback.
+type benchPair struct {
+	options     option.Options
+	description string
+	supportUDP  bool
+	closers     []io.Closer
+}
+
+func (p *benchPair) Close() error {
+	closers := make([]any, 0, len(p.closers))
+	for _, closer := range p.closers {
+		closers = append(closers, closer)
+	}
+	return common.Close(closers...)
+}
+
+func newBenchPair(options benchPairOptions) (*benchPair, error) {
+	pair := &benchPair{
+		supportUDP: true,
+	}
+	err := pair.build(options)
+	if err != nil {
+		pair.Close()
+		return nil, err
+	}
+	return pair, nil
+}
+
+func (p *benchPair) build(options benchPairOptions) error {
+	isQUIC := options.Protocol == C.TypeHysteria2 || options.Protocol == C.TypeTUIC || options.Transport == C.V2RayTransportTypeQUIC
+	if options.TLS == "" {
+		if isQUIC {
+			options.TLS = "tls"
+		} else {
+			options.TLS = "none"
+		}
+	}
+	switch options.TLS {
+	case "none":
+		if isQUIC {
+			return E.New("TLS is required by QUIC")
+		}
+	case "tls", "reality":
+		if options.Protocol == C.TypeSOCKS || options.Protocol == C.TypeShadowsocks {
+			return E.New("TLS is not supported by ", options.Protocol)
+		}
+		if options.TLS == "reality" && isQUIC {
+			return E.New("reality is not supported by QUIC")
+		}
+	default:
+		return E.New("unknown TLS mode: ", options.TLS)
+	}
+	if options.Transport != "" {
+		switch options.Protocol {
+		case C.TypeVMess, C.TypeVLESS, C.TypeTrojan:
+		default:
+			return E.New("transport is not supported by ", options.Protocol)
+		}
+	}
+	listenPort, err := pickBenchPort(isQUIC)
+	if err != nil {
+		return err
+	}
+	inboundTLS, outboundTLS, err := p.buildTLS(options.TLS)
+	if err != nil {
+		return err
+	}
+	transport, err := buildBenchTransport(options.Transport)
+	if err != nil {
+		return err
+	}
+	listenOptions := option.ListenOptions{
+		Listen:     option.NewListenAddress(netip.AddrFrom4([4]byte{127, 0, 0, 1})),
+		ListenPort: listenPort,
+	}
+	serverOptions := option.ServerOptions{
+		Server:     "127.0.0.1",
+		ServerPort: listenPort,
+	}
+	inboundTLSContainer := option.InboundTLSOptionsContainer{TLS: inboundTLS}
+	outboundTLSContainer := option.OutboundTLSOptionsContainer{TLS: outboundTLS}
+	userUUID := uuid.Must(uuid.NewV4()).String()
+	password := userUUID
+	inbound := option.Inbound{
+		Type: options.Protocol,
+		Tag:  "bench-in",
+	}
+	outbound := option.Outbound{
+		Type: options.Protocol,
+		Tag:  benchOutboundTag,
+	}
+	var description []string
+	switch options.Protocol {
+	case C.TypeSOCKS:
+		inbound.SocksOptions = option.SocksInboundOptions{
+			ListenOptions: listenOptions,
+			Users:         []auth.User{{Username: "bench", Password: password}},
+		}
+		outbound.SocksOptions = option.SocksOutboundOptions{
+			ServerOptions: serverOptions,
+			Username:      "bench",
+			Password:      password,
+		}
+	case C.TypeHTTP:
+		inbound.HTTPOptions = option.HTTPMixedInboundOptions{
+			ListenOptions:              listenOptions,
+			Users:                      []auth.User{{Username: "bench", Password: password}},
+			InboundTLSOptionsContainer: inboundTLSContainer,
+		}
+		outbound.HTTPOptions = option.HTTPOutboundOptions{
+			ServerOptions:               serverOptions,
+			Username:                    "bench",
+			Password:                    password,
+			OutboundTLSOptionsContainer: outboundTLSContainer,
+		}
+		p.supportUDP = false
+	case C.TypeShadowsocks:
+		method := options.Method
+		if method == "" {
+			method = "2022-blake3-aes-128-gcm"
+		}
+		if strings.HasPrefix(method, "2022-") {
+			keyLength := 32
+			if strings.Contains(method, "aes-128") {
+				keyLength = 16
+			}
+			key := make([]byte, keyLength)
+			_, err = rand.Read(key)
+			if err != nil {
+				return err
+			}
+			password = base64.StdEncoding.EncodeToString(key)
+		}
+		inbound.ShadowsocksOptions = option.ShadowsocksInboundOptions{
+			ListenOptions: listenOptions,
+			Method:        method,
+			Password:      password,
+		}
+		outbound.ShadowsocksOptions = option.ShadowsocksOutboundOptions{
+			ServerOptions: serverOptions,
+			Method:        method,
+			Password:      password,
+		}
+		description = append(description, method)
+	case C.TypeVMess:
+		security := options.Method
+		if security == "" {
+			security = "auto"
+		}
+		inbound.VMessOptions = option.VMessInboundOptions{
+			ListenOptions:              listenOptions,
+			Users:                      []option.VMessUser{{Name: "bench", UUID: userUUID}},
+			InboundTLSOptionsContainer: inboundTLSContainer,
+			Transport:                  transport,
+		}
+		outbound.VMessOptions = option.VMessOutboundOptions{
+			ServerOptions:               serverOptions,
+			UUID:                        userUUID,
+			Security:                    security,
+			OutboundTLSOptionsContainer: outboundTLSContainer,
+			Transport:                   transport,
+		}
+		description = append(description, security)
+	case C.TypeVLESS:
+		inbound.VLESSOptions = option.VLESSInboundOptions{
+			ListenOptions:              listenOptions,
+			Users:                      []option.VLESSUser{{Name: "bench", UUID: userUUID, Flow: options.Flow}},
+			InboundTLSOptionsContainer: inboundTLSContainer,
+			Transport:                  transport,
+		}
+		outbound.VLESSOptions = option.VLESSOutboundOptions{
+			ServerOptions:               serverOptions,
+			UUID:                        userUUID,
+			Flow:                        options.Flow,
+			OutboundTLSOptionsContainer: outboundTLSContainer,
+			Transport:                   transport,
+		}
+		if options.Flow != "" {
+			description = append(description, options.Flow)
+		}
+	case C.TypeTrojan:
+		inbound.TrojanOptions = option.TrojanInboundOptions{
+			ListenOptions:              listenOptions,
+			Users:                      []option.TrojanUser{{Name: "bench", Password: password}},
+			InboundTLSOptionsContainer: inboundTLSContainer,
+			Transport:                  transport,
+		}
+		outbound.TrojanOptions = option.TrojanOutboundOptions{
+			ServerOptions:               serverOptions,
+			Password:                    password,
+			OutboundTLSOptionsContainer: outboundTLSContainer,
+			Transport:                   transport,
+		}
+	case C.TypeHysteria2:
+		inbound.Hysteria2Options = option.Hysteria2InboundOptions{
+			ListenOptions:              listenOptions,
+			Users:                      []option.Hysteria2User{{Name: "bench", Password: password}},
+			InboundTLSOptionsContainer: inboundTLSContainer,
+		}
+		outbound.Hysteria2Options = option.Hysteria2OutboundOptions{
+			ServerOptions:               serverOptions,
+			Password:                    password,
+			OutboundTLSOptionsContainer: outboundTLSContainer,
+		}
+	case C.TypeTUIC:
+		inbound.TUICOptions = option.TUICInboundOptions{
+			ListenOptions:              listenOptions,
+			Users:                      []option.TUICUser{{Name: "bench", UUID: userUUID, Password: password}},
+			InboundTLSOptionsContainer: inboundTLSContainer,
+		}
+		outbound.TUICOptions = option.TUICOutboundOptions{
+			ServerOptions:               serverOptions,
+			UUID:                        userUUID,
+			Password:                    password,
+			OutboundTLSOptionsContainer: outboundTLSContainer,
+		}
+	default:
+		return E.New("unsupported protocol: ", options.Protocol)
+	}
+	if options.Transport != "" {
+		description = append(description, options.Transport)
+	}
+	if options.TLS != "none" {
+		description = append(description, options.TLS)
+	}
+	p.description = options.Protocol
+	if len(description) > 0 {
+		p.description += " (" + strings.Join(description, ", ") + ")"
+	}
+	p.options = option.Options{
+		Log: &option.LogOptions{
+			Level: "warn",
+		},
+		Inbounds: []option.Inbound{inbound},
+		Outbounds: []option.Outbound{
+			{
+				Type: C.TypeDirect,
+				Tag:  "direct",
+			},
+			outbound,
+		},
+	}
+	return nil
+}
+
+func (p *benchPair) buildTLS(mode string) (*option.InboundTLSOptions, *option.OutboundTLSOptions, error) {
+	if mode == "none" {
+		return nil, nil, nil
+	}
+	privateKeyPem, certificatePem, err := tls.GenerateKeyPair(time.Now, benchServerName, time.Now().Add(24*time.Hour))
+	if err != nil {
+		return nil, nil, E.Cause(err, "generate certificate")
+	}
+	if mode == "tls" {
+		return &option.InboundTLSOptions{
+			Enabled:     true,
+			ServerName:  benchServerName,
+			Certificate: []string{string(certificatePem)},
+			Key:         []string{string(privateKeyPem)},
+		}, &option.OutboundTLSOptions{
+			Enabled:     true,
+			ServerName:  benchServerName,
+			Certificate: []string{string(certificatePem)},
+		}, nil
+	}
+	// reality forwards the handshake to a real TLS server, so run one on loopback
+	certificate, err := stdTLS.X509KeyPair(certificatePem, privateKeyPem)
+	if err != nil {
+		return nil, nil, err
+	}
+	handshakeListener, err := stdTLS.Listen("tcp", "127.0.0.1:0", &stdTLS.Config{
+		Certificates: []stdTLS.Certificate{certificate},
+		NextProtos:   []string{"h2", "http/1.1"},
+		MinVersion:   stdTLS.VersionTLS13,
+	})
+	if err != nil {
+		return nil, nil, E.Cause(err, "start reality handshake server")
+	}
+	p.closers = append(p.closers, handshakeListener)
+	go serveBenchHandshake(handshakeListener)
+	handshakePort := uint16(handshakeListener.Addr().(*net.TCPAddr).Port)
+	return &option.InboundTLSOptions{
+		Enabled:    true,
+		ServerName: benchServerName,
+		Reality: &option.InboundRealityOptions{
+			Enabled: true,
+			Handshake: option.InboundRealityHandshakeOptions{
+				ServerOptions: option.ServerOptions{
+					Server:     "127.0.0.1",
+					ServerPort: handshakePort,
+				},
+			},
+			PrivateKey: benchRealityPrivateKey,
+			ShortID:    []string{benchRealityShortID},
+		},
+	}, &option.OutboundTLSOptions{
+		Enabled:    true,
+		ServerName: benchServerName,
+		UTLS: &option.OutboundUTLSOptions{
+			Enabled:     true,
+			Fingerprint: "chrome",
+		},
+		Reality: &option.OutboundRealityOptions{
+			Enabled:   true,
+			PublicKey: benchRealityPublicKey,
+			ShortID:   benchRealityShortID,
+		},
+	}, nil
+}
+
+func serveBenchHandshake(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			io.Copy(io.Discard, conn)
+		}()
+	}
+}
+
+func buildBenchTransport(transportType string) (*option.V2RayTransportOptions, error) {
+	switch transportType {
+	case "":
+		return nil, nil
+	case C.V2RayTransportTypeWebsocket:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeWebsocket,
+			WebsocketOptions: option.V2RayWebsocketOptions{
+				Path: "/bench",
+			},
+		}, nil
+	case C.V2RayTransportTypeGRPC:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeGRPC,
+			GRPCOptions: option.V2RayGRPCOptions{
+				ServiceName: "bench",
+			},
+		}, nil
+	case C.V2RayTransportTypeHTTP:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeHTTP,
+			HTTPOptions: option.V2RayHTTPOptions{
+				Path: "/bench",
+			},
+		}, nil
+	case C.V2RayTransportTypeHTTPUpgrade:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeHTTPUpgrade,
+			HTTPUpgradeOptions: option.V2RayHTTPUpgradeOptions{
+				Path: "/bench",
+			},
+		}, nil
+	case C.V2RayTransportTypeQUIC:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeQUIC,
+		}, nil
+	default:
+		return nil, E.New("unknown transport: ", transportType)
+	}
+}
+
+func pickBenchPort(isQUIC bool) (uint16, error) {
+	if isQUIC {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			return 0, err
+		}
+		defer conn.Close()
+		return uint16(conn.LocalAddr().(*net.UDPAddr).Port), nil
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return uint16(listener.Addr().(*net.TCPAddr).Port), nil
+}
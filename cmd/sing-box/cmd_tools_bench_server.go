@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+)
+
+const (
+	benchCommandPing byte = iota + 1
+	benchCommandUpload
+	benchCommandDownload
+)
+
+const (
+	benchBufferSize     = 32 * 1024
+	benchWindowPackets  = 64
+	benchRequestPackets = 16
+	benchStallTimeout   = 100 * time.Millisecond
+)
+
+// benchServer is the loopback sink that bench traffic is routed to by the direct outbound.
+type benchServer struct {
+	tcpListener net.Listener
+	udpConn     net.PacketConn
+	tcpAddr     M.Socksaddr
+	udpAddr     M.Socksaddr
+	packetSize  int
+	udpReceived atomic.Uint64
+	udpLastRead atomic.Int64
+	udpNotify   chan struct{}
+}
+
+func newBenchServer(packetSize int) (*benchServer, error) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	udpConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		tcpListener.Close()
+		return nil, err
+	}
+	server := &benchServer{
+		tcpListener: tcpListener,
+		udpConn:     udpConn,
+		tcpAddr:     M.SocksaddrFromNet(tcpListener.Addr()),
+		udpAddr:     M.SocksaddrFromNet(udpConn.LocalAddr()),
+		packetSize:  packetSize,
+		udpNotify:   make(chan struct{}, 1),
+	}
+	go server.loopTCP()
+	go server.loopUDP()
+	return server, nil
+}
+
+func (s *benchServer) Close() error {
+	return common.Close(s.tcpListener, s.udpConn)
+}
+
+func (s *benchServer) loopTCP() {
+	for {
+		conn, err := s.tcpListener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleTCP(conn)
+	}
+}
+
+func (s *benchServer) handleTCP(conn net.Conn) {
+	defer conn.Close()
+	var command [1]byte
+	_, err := io.ReadFull(conn, command[:])
+	if err != nil {
+		return
+	}
+	switch command[0] {
+	case benchCommandPing:
+		_, err = conn.Write(command[:])
+	case benchCommandUpload:
+		var size uint64
+		err = binary.Read(conn, binary.BigEndian, &size)
+		if err != nil {
+			return
+		}
+		_, err = io.CopyN(io.Discard, conn, int64(size))
+		if err != nil {
+			return
+		}
+		_, err = conn.Write(command[:])
+	case benchCommandDownload:
+		var size uint64
+		err = binary.Read(conn, binary.BigEndian, &size)
+		if err != nil {
+			return
+		}
+		err = writeBenchData(conn, size)
+	default:
+		return
+	}
+	if err != nil {
+		return
+	}
+	// wait for the client to close the connection, so that no data is discarded by a reset
+	io.Copy(io.Discard, conn)
+}
+
+func (s *benchServer) loopUDP() {
+	buffer := make([]byte, 65535)
+	for {
+		n, addr, err := s.udpConn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+		if n == 0 {
+			continue
+		}
+		switch buffer[0] {
+		case benchCommandUpload:
+			s.udpReceived.Add(uint64(n))
+			s.udpLastRead.Store(time.Now().UnixNano())
+			notifyBenchWindow(s.udpNotify)
+		case benchCommandDownload:
+			if n < 9 {
+				continue
+			}
+			go s.sendPackets(addr, binary.BigEndian.Uint64(buffer[1:9]))
+		}
+	}
+}
+
+func (s *benchServer) sendPackets(addr net.Addr, count uint64) {
+	packet := make([]byte, s.packetSize)
+	for i := uint64(0); i < count; i++ {
+		_, err := s.udpConn.WriteTo(packet, addr)
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeBenchData(writer io.Writer, size uint64) error {
+	buffer := make([]byte, benchBufferSize)
+	for size > 0 {
+		chunk := buffer
+		if size < uint64(len(chunk)) {
+			chunk = chunk[:size]
+		}
+		n, err := writer.Write(chunk)
+		if err != nil {
+			return err
+		}
+		size -= uint64(n)
+	}
+	return nil
+}
+
+func benchTCPUpload(ctx context.Context, outbound adapter.Outbound, destination M.Socksaddr, size uint64) (benchResult, error) {
+	conn, err := outbound.DialContext(ctx, "tcp", destination)
+	if err != nil {
+		return benchResult{}, err
+	}
+	defer conn.Close()
+	measure := startBenchMeasure()
+	header := make([]byte, 9)
+	header[0] = benchCommandUpload
+	binary.BigEndian.PutUint64(header[1:], size)
+	_, err = conn.Write(header)
+	if err != nil {
+		return benchResult{}, err
+	}
+	err = writeBenchData(conn, size)
+	if err != nil {
+		return benchResult{}, err
+	}
+	_, err = io.ReadFull(conn, header[:1])
+	if err != nil {
+		return benchResult{}, E.Cause(err, "read acknowledgement")
+	}
+	return measure.finish("tcp upload", size, 0), nil
+}
+
+func benchTCPDownload(ctx context.Context, outbound adapter.Outbound, destination M.Socksaddr, size uint64) (benchResult, error) {
+	conn, err := outbound.DialContext(ctx, "tcp", destination)
+	if err != nil {
+		return benchResult{}, err
+	}
+	defer conn.Close()
+	measure := startBenchMeasure()
+	header := make([]byte, 9)
+	header[0] = benchCommandDownload
+	binary.BigEndian.PutUint64(header[1:], size)
+	_, err = conn.Write(header)
+	if err != nil {
+		return benchResult{}, err
+	}
+	_, err = io.CopyN(io.Discard, conn, int64(size))
+	if err != nil {
+		return benchResult{}, err
+	}
+	return measure.finish("tcp download", size, 0), nil
+}
+
+func benchUDPUpload(ctx context.Context, outbound adapter.Outbound, server *benchServer, size uint64) (benchResult, error) {
+	conn, err := outbound.ListenPacket(ctx, server.udpAddr)
+	if err != nil {
+		return benchResult{}, err
+	}
+	defer conn.Close()
+	packetSize := uint64(server.packetSize)
+	count := (size + packetSize - 1) / packetSize
+	packet := make([]byte, packetSize)
+	packet[0] = benchCommandUpload
+	destination := server.udpAddr.UDPAddr()
+	server.udpReceived.Store(0)
+	server.udpLastRead.Store(0)
+	window := newBenchWindow(server.udpReceived.Load, server.udpNotify)
+	defer window.Close()
+	measure := startBenchMeasure()
+	var sent uint64
+	for i := uint64(0); i < count; i++ {
+		window.wait(sent, benchWindowPackets*packetSize)
+		_, err = conn.WriteTo(packet, destination)
+		if err != nil {
+			return benchResult{}, err
+		}
+		sent += packetSize
+	}
+	window.wait(sent, 0)
+	result := measure.finish("udp upload", server.udpReceived.Load(), sent)
+	if lastRead := server.udpLastRead.Load(); lastRead > 0 {
+		result.duration = time.Unix(0, lastRead).Sub(measure.startTime)
+	}
+	if result.bytes == 0 {
+		return benchResult{}, E.New("no packets received")
+	}
+	return result, nil
+}
+
+func benchUDPDownload(ctx context.Context, outbound adapter.Outbound, destination M.Socksaddr, size uint64) (benchResult, error) {
+	conn, err := outbound.ListenPacket(ctx, destination)
+	if err != nil {
+		return benchResult{}, err
+	}
+	defer conn.Close()
+	packetSize := uint64(commandBenchFlagPacketSize)
+	count := (size + packetSize - 1) / packetSize
+	var (
+		received atomic.Uint64
+		lastRead atomic.Int64
+	)
+	notify := make(chan struct{}, 1)
+	go func() {
+		buffer := make([]byte, 65535)
+		for {
+			n, _, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			lastRead.Store(time.Now().UnixNano())
+			received.Add(uint64(n))
+			notifyBenchWindow(notify)
+		}
+	}()
+	window := newBenchWindow(received.Load, notify)
+	defer window.Close()
+	request := make([]byte, 9)
+	request[0] = benchCommandDownload
+	udpDestination := destination.UDPAddr()
+	measure := startBenchMeasure()
+	var requested uint64
+	for requested < count*packetSize {
+		window.wait(requested, (benchWindowPackets-benchRequestPackets)*packetSize)
+		batch := count - requested/packetSize
+		if batch > benchRequestPackets {
+			batch = benchRequestPackets
+		}
+		binary.BigEndian.PutUint64(request[1:], batch)
+		_, err = conn.WriteTo(request, udpDestination)
+		if err != nil {
+			return benchResult{}, err
+		}
+		requested += batch * packetSize
+	}
+	window.wait(requested, 0)
+	result := measure.finish("udp download", received.Load(), requested)
+	if lastReadTime := lastRead.Load(); lastReadTime > 0 {
+		result.duration = time.Unix(0, lastReadTime).Sub(measure.startTime)
+	}
+	if result.bytes == 0 {
+		return benchResult{}, E.New("no packets received")
+	}
+	return result, nil
+}
+
+// benchWindow limits the data in flight, so that the measured loss is caused by the protocol
+// instead of overflowing socket buffers.
+type benchWindow struct {
+	received func() uint64
+	notify   <-chan struct{}
+	timer    *time.Timer
+	lost     uint64
+}
+
+func newBenchWindow(received func() uint64, notify <-chan struct{}) *benchWindow {
+	timer := time.NewTimer(benchStallTimeout)
+	timer.Stop()
+	return &benchWindow{
+		received: received,
+		notify:   notify,
+		timer:    timer,
+	}
+}
+
+func notifyBenchWindow(notify chan<- struct{}) {
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
+
+// wait blocks until no more than limit bytes are in flight,
+// data that makes no progress within benchStallTimeout is counted as lost.
+func (w *benchWindow) wait(sent uint64, limit uint64) {
+	for {
+		received := w.received()
+		if received+w.lost+limit >= sent {
+			return
+		}
+		w.timer.Reset(benchStallTimeout)
+		select {
+		case <-w.notify:
+			if !w.timer.Stop() {
+				<-w.timer.C
+			}
+		case <-w.timer.C:
+			if w.received() == received {
+				w.lost = sent - received
+				return
+			}
+		}
+	}
+}
+
+func (w *benchWindow) Close() {
+	w.timer.Stop()
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || windows)
+
+package main
+
+import "time"
+
+func processCPUTime() time.Duration {
+	return 0
+}
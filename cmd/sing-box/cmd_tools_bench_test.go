@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBench(t *testing.T) {
+	commandBenchFlagProtocol = "shadowsocks"
+	commandBenchFlagMethod = "aes-128-gcm"
+	commandBenchFlagNetwork = []string{"tcp", "udp"}
+	commandBenchFlagSize = "1MiB"
+	commandBenchFlagUDPSize = "64KiB"
+	commandBenchFlagHandshakes = 3
+	require.NoError(t, bench())
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+func processCPUTime() time.Duration {
+	var creationTime, exitTime, kernelTime, userTime windows.Filetime
+	err := windows.GetProcessTimes(windows.CurrentProcess(), &creationTime, &exitTime, &kernelTime, &userTime)
+	if err != nil {
+		return 0
+	}
+	return fileTimeDuration(kernelTime) + fileTimeDuration(userTime)
+}
+
+func fileTimeDuration(fileTime windows.Filetime) time.Duration {
+	return time.Duration(uint64(fileTime.HighDateTime)<<32|uint64(fileTime.LowDateTime)) * 100
+}
@@ -0,0 +1,94 @@
+package lint
+
+import (
+	"regexp"
+	"sort"
+
+	F "github.com/sagernet/sing/common/format"
+	"github.com/sagernet/sing/common/json"
+)
+
+type deprecatedField struct {
+	key        string
+	parent     *regexp.Regexp
+	message    string
+	suggestion string
+}
+
+var (
+	inboundPath    = regexp.MustCompile(`^inbounds\[\d+\]$`)
+	rulePath       = regexp.MustCompile(`^(route|dns)\.rules\[\d+\](\.rules\[\d+\])*$`)
+	routePath      = regexp.MustCompile(`^route$`)
+	clashAPIPath   = regexp.MustCompile(`^experimental\.clash_api$`)
+	deprecated1100 = "deprecated in sing-box 1.10.0 and will be removed in sing-box 1.11.0"
+	migrated180    = "migrated to experimental.cache_file in sing-box 1.8.0"
+	deprecated180  = "deprecated in sing-box 1.8.0 and may be removed in the future"
+)
+
+var deprecatedFields = []deprecatedField{
+	{"inet4_address", inboundPath, deprecated1100, "merge into address"},
+	{"inet6_address", inboundPath, deprecated1100, "merge into address"},
+	{"inet4_route_address", inboundPath, deprecated1100, "merge into route_address"},
+	{"inet6_route_address", inboundPath, deprecated1100, "merge into route_address"},
+	{"inet4_route_exclude_address", inboundPath, deprecated1100, "merge into route_exclude_address"},
+	{"inet6_route_exclude_address", inboundPath, deprecated1100, "merge into route_exclude_address"},
+	{"rule_set_ipcidr_match_source", rulePath, deprecated1100, "rename to rule_set_ip_cidr_match_source"},
+	{"geoip", rulePath, deprecated180, "replace with rule_set using a GeoIP rule-set, or ip_is_private for private addresses"},
+	{"source_geoip", rulePath, deprecated180, "replace with rule_set and rule_set_ip_cidr_match_source, or source_ip_is_private for private addresses"},
+	{"geosite", rulePath, deprecated180, "replace with rule_set using a geosite rule-set"},
+	{"geoip", routePath, deprecated180, "remove after migrating rules to rule-sets"},
+	{"geosite", routePath, deprecated180, "remove after migrating rules to rule-sets"},
+	{"cache_file", clashAPIPath, migrated180, "move to experimental.cache_file.path and set experimental.cache_file.enabled"},
+	{"cache_id", clashAPIPath, migrated180, "move to experimental.cache_file.cache_id"},
+	{"store_mode", clashAPIPath, migrated180, "remove and set experimental.cache_file.enabled, the mode is always stored"},
+	{"store_selected", clashAPIPath, migrated180, "remove and set experimental.cache_file.enabled, the selected outbounds are always stored"},
+	{"store_fakeip", clashAPIPath, migrated180, "move to experimental.cache_file.store_fakeip"},
+}
+
+func (l *linter) lintDeprecated() {
+	content := []byte(l.options.RawMessage)
+	if len(content) == 0 {
+		var err error
+		content, err = json.Marshal(l.options)
+		if err != nil {
+			return
+		}
+	}
+	var value any
+	err := json.Unmarshal(content, &value)
+	if err != nil {
+		return
+	}
+	l.walkDeprecated("", value)
+}
+
+func (l *linter) walkDeprecated(path string, value any) {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, field := range deprecatedFields {
+				if field.key == key && field.parent.MatchString(path) {
+					l.report(LevelWarning, CodeDeprecatedField, joinPath(path, key),
+						F.ToString(key, " is ", field.message), field.suggestion)
+				}
+			}
+			l.walkDeprecated(joinPath(path, key), typedValue[key])
+		}
+	case []any:
+		for i, element := range typedValue {
+			l.walkDeprecated(F.ToString(path, "[", i, "]"), element)
+		}
+	}
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,269 @@
+package lint
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	F "github.com/sagernet/sing/common/format"
+	M "github.com/sagernet/sing/common/metadata"
+)
+
+const (
+	matchNo = iota
+	matchMaybe
+	matchYes
+)
+
+// bootstrapEdge means that dialing the DNS server requires a lookup of domain,
+// which is sent to the next DNS server.
+type bootstrapEdge struct {
+	outbound string
+	domain   string
+	next     string
+	definite bool
+}
+
+func (l *linter) lintDNSBootstrap() {
+	if l.options.DNS == nil || len(l.options.DNS.Servers) == 0 {
+		return
+	}
+	serverTags := make([]string, len(l.options.DNS.Servers))
+	for i, server := range l.options.DNS.Servers {
+		tag := server.Tag
+		if tag == "" {
+			tag = F.ToString(i)
+		}
+		serverTags[i] = tag
+	}
+	edges := make(map[string][]bootstrapEdge)
+	for i, server := range l.options.DNS.Servers {
+		if !isDialingDNSServer(server.Address) {
+			continue
+		}
+		detour := server.Detour
+		if detour == "" {
+			detour = l.defaultOutbound()
+		}
+		for _, tag := range l.dialClosure(detour) {
+			outbound := l.outbounds[tag]
+			serverOptions, loaded := takeServerOptions(outbound)
+			if !loaded || !M.ParseSocksaddr(serverOptions.Server).IsFqdn() {
+				continue
+			}
+			// the server address is resolved by the dialer of the outbound, which is tagged as its detour if set
+			lookupOutbound := tag
+			if dialerOptions, loaded := takeDialerOptions(outbound); loaded && dialerOptions.Detour != "" {
+				lookupOutbound = dialerOptions.Detour
+			}
+			domain := strings.ToLower(serverOptions.Server)
+			resolvers, definite := l.resolveDNSServers(domain, lookupOutbound, serverTags)
+			for _, resolver := range resolvers {
+				edges[serverTags[i]] = append(edges[serverTags[i]], bootstrapEdge{
+					outbound: tag,
+					domain:   domain,
+					next:     resolver,
+					definite: definite,
+				})
+			}
+		}
+	}
+	reported := make(map[string]bool)
+	for i, tag := range serverTags {
+		path := findBootstrapLoop(edges, tag)
+		if path == nil {
+			continue
+		}
+		cycle := make([]string, 0, len(path))
+		for _, edge := range path {
+			cycle = append(cycle, edge.next)
+		}
+		key := strings.Join(rotateCycle(cycle), " -> ")
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+		level := LevelError
+		verb := "is resolved by"
+		if !common.All(path, func(it bootstrapEdge) bool {
+			return it.definite
+		}) {
+			level = LevelWarning
+			verb = "may be resolved by"
+		}
+		var steps []string
+		current := tag
+		for _, edge := range path {
+			steps = append(steps, F.ToString("dns server ", current, " dials through outbound ", edge.outbound, ", whose server ", edge.domain, " ", verb, " dns server ", edge.next))
+			current = edge.next
+		}
+		l.report(level, CodeDNSBootstrapLoop, F.ToString("dns.servers[", i, "]"),
+			strings.Join(steps, ", "),
+			F.ToString(`add a DNS rule such as {"outbound": "any", "server": "<server>"} before other rules, where <server> is a DNS server reachable without DNS, or use an IP address as the server of outbound `, path[0].outbound))
+	}
+}
+
+func isDialingDNSServer(address string) bool {
+	switch address {
+	case "local", "fakeip":
+		return false
+	}
+	serverURL, err := url.Parse(address)
+	if err == nil {
+		switch serverURL.Scheme {
+		case "rcode", "dhcp":
+			return false
+		}
+	}
+	return true
+}
+
+// dialClosure returns outbounds that may be used when dialing through the given outbound.
+func (l *linter) dialClosure(tag string) []string {
+	visited := make(map[string]bool)
+	var closure []string
+	var visit func(tag string)
+	visit = func(tag string) {
+		if visited[tag] {
+			return
+		}
+		visited[tag] = true
+		if _, loaded := l.outbounds[tag]; !loaded {
+			return
+		}
+		closure = append(closure, tag)
+		for _, dependency := range l.outboundDependencies(tag) {
+			visit(dependency)
+		}
+	}
+	visit(tag)
+	return closure
+}
+
+// resolveDNSServers returns DNS servers that may answer a lookup made by an outbound dialer,
+// the result is definite if only one server is possible.
+func (l *linter) resolveDNSServers(domain string, outbound string, serverTags []string) ([]string, bool) {
+	var servers []string
+	for _, rule := range l.options.DNS.Rules {
+		var result int
+		if rule.Type == C.RuleTypeDefault && !rule.DefaultOptions.Invert {
+			result = matchBootstrapRule(rule.DefaultOptions, domain, outbound)
+		} else {
+			result = matchMaybe
+		}
+		if result == matchNo {
+			continue
+		}
+		var server string
+		if rule.Type == C.RuleTypeDefault {
+			server = rule.DefaultOptions.Server
+		} else {
+			server = rule.LogicalOptions.Server
+		}
+		if !common.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+		if result == matchYes {
+			return servers, len(servers) == 1
+		}
+	}
+	finalServer := l.options.DNS.Final
+	if finalServer == "" {
+		finalServer = serverTags[0]
+	}
+	if !common.Contains(servers, finalServer) {
+		servers = append(servers, finalServer)
+	}
+	return servers, len(servers) == 1
+}
+
+func matchBootstrapRule(rule option.DefaultDNSRule, domain string, outbound string) int {
+	if len(rule.Outbound) > 0 && !common.Any(rule.Outbound, func(it string) bool {
+		return it == "any" || it == outbound
+	}) {
+		return matchNo
+	}
+	result := matchYes
+	hasDomainItems := len(rule.Domain) > 0 || len(rule.DomainSuffix) > 0 || len(rule.DomainKeyword) > 0 || len(rule.DomainRegex) > 0
+	if hasDomainItems || len(rule.Geosite) > 0 {
+		domainResult := matchDomainItems(rule, domain)
+		if domainResult == matchNo && (len(rule.Geosite) > 0 || len(rule.RuleSet) > 0) {
+			domainResult = matchMaybe
+		}
+		if domainResult == matchNo {
+			return matchNo
+		}
+		result = domainResult
+	}
+	// items that can not be evaluated statically
+	otherItems := rule
+	otherItems.Domain = nil
+	otherItems.DomainSuffix = nil
+	otherItems.DomainKeyword = nil
+	otherItems.DomainRegex = nil
+	otherItems.Outbound = nil
+	otherItems.Invert = false
+	otherItems.Server = ""
+	otherItems.DisableCache = false
+	otherItems.RewriteTTL = nil
+	otherItems.ClientSubnet = nil
+	if !reflect.DeepEqual(otherItems, option.DefaultDNSRule{}) {
+		result = matchMaybe
+	}
+	return result
+}
+
+func matchDomainItems(rule option.DefaultDNSRule, domain string) int {
+	if common.Any(rule.Domain, func(it string) bool {
+		return strings.ToLower(it) == domain
+	}) || common.Any(rule.DomainSuffix, func(it string) bool {
+		return suffixMatches(strings.ToLower(it), domain)
+	}) || common.Any(rule.DomainKeyword, func(it string) bool {
+		return strings.Contains(domain, strings.ToLower(it))
+	}) {
+		return matchYes
+	}
+	result := matchNo
+	for _, expression := range rule.DomainRegex {
+		domainRegex, err := regexp.Compile(expression)
+		if err != nil {
+			result = matchMaybe
+			continue
+		}
+		if domainRegex.MatchString(domain) {
+			return matchYes
+		}
+	}
+	return result
+}
+
+// findBootstrapLoop returns edges leading from the server back to itself.
+func findBootstrapLoop(edges map[string][]bootstrapEdge, server string) []bootstrapEdge {
+	visited := make(map[string]bool)
+	var path []bootstrapEdge
+	var visit func(current string) bool
+	visit = func(current string) bool {
+		for _, edge := range edges[current] {
+			path = append(path, edge)
+			if edge.next == server {
+				return true
+			}
+			if !visited[edge.next] {
+				visited[edge.next] = true
+				if visit(edge.next) {
+					return true
+				}
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+	if visit(server) {
+		return path
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package lint
+
+import (
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	F "github.com/sagernet/sing/common/format"
+)
+
+const (
+	LevelWarning = "warning"
+	LevelError   = "error"
+)
+
+const (
+	CodeShadowedRule           = "shadowed-rule"
+	CodeUnusedOutbound         = "unused-outbound"
+	CodeUnusedRuleSet          = "unused-rule-set"
+	CodeDetourCycle            = "detour-cycle"
+	CodeInvalidSelectorDefault = "invalid-selector-default"
+	CodeDNSBootstrapLoop       = "dns-bootstrap-loop"
+	CodeDeprecatedField        = "deprecated-field"
+)
+
+type Finding struct {
+	Level      string `json:"level"`
+	Code       string `json:"code"`
+	Path       string `json:"path"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Lint reports configuration problems that do not prevent sing-box from starting,
+// but make it behave differently from what the configuration suggests.
+func Lint(options option.Options) []Finding {
+	linter := newLinter(options)
+	linter.lintRules()
+	linter.lintDNSRules()
+	linter.lintReferences()
+	linter.lintDetourCycles()
+	linter.lintSelectors()
+	linter.lintDNSBootstrap()
+	linter.lintDeprecated()
+	return linter.findings
+}
+
+type linter struct {
+	options      option.Options
+	outboundTags []string
+	outbounds    map[string]*option.Outbound
+	outboundPath map[string]string
+	findings     []Finding
+}
+
+func newLinter(options option.Options) *linter {
+	l := &linter{
+		options:      options,
+		outbounds:    make(map[string]*option.Outbound),
+		outboundPath: make(map[string]string),
+	}
+	for i := range options.Outbounds {
+		outbound := &options.Outbounds[i]
+		tag := outbound.Tag
+		if tag == "" {
+			tag = F.ToString(i)
+		}
+		l.outboundTags = append(l.outboundTags, tag)
+		l.outbounds[tag] = outbound
+		l.outboundPath[tag] = F.ToString("outbounds[", i, "]")
+	}
+	return l
+}
+
+func (l *linter) report(level string, code string, path string, message string, suggestion string) {
+	l.findings = append(l.findings, Finding{
+		Level:      level,
+		Code:       code,
+		Path:       path,
+		Message:    message,
+		Suggestion: suggestion,
+	})
+}
+
+func (l *linter) defaultOutbound() string {
+	if l.options.Route != nil && l.options.Route.Final != "" {
+		return l.options.Route.Final
+	}
+	if len(l.outboundTags) > 0 {
+		return l.outboundTags[0]
+	}
+	return ""
+}
+
+// outboundDependencies returns outbounds that the given outbound dials through.
+func (l *linter) outboundDependencies(tag string) []string {
+	outbound, loaded := l.outbounds[tag]
+	if !loaded {
+		return nil
+	}
+	switch outbound.Type {
+	case C.TypeSelector:
+		return outbound.SelectorOptions.Outbounds
+	case C.TypeURLTest:
+		return outbound.URLTestOptions.Outbounds
+	case C.TypeBond:
+		return outbound.BondOptions.Outbounds
+	}
+	dialerOptions, loaded := takeDialerOptions(outbound)
+	if loaded && dialerOptions.Detour != "" {
+		return []string{dialerOptions.Detour}
+	}
+	return nil
+}
+
+func takeDialerOptions(outbound *option.Outbound) (option.DialerOptions, bool) {
+	rawOptions, err := outbound.RawOptions()
+	if err != nil || rawOptions == nil {
+		return option.DialerOptions{}, false
+	}
+	wrapper, isWrapper := rawOptions.(option.DialerOptionsWrapper)
+	if !isWrapper {
+		return option.DialerOptions{}, false
+	}
+	return wrapper.TakeDialerOptions(), true
+}
+
+func takeServerOptions(outbound *option.Outbound) (option.ServerOptions, bool) {
+	rawOptions, err := outbound.RawOptions()
+	if err != nil || rawOptions == nil {
+		return option.ServerOptions{}, false
+	}
+	wrapper, isWrapper := rawOptions.(option.ServerOptionsWrapper)
+	if !isWrapper {
+		return option.ServerOptions{}, false
+	}
+	return wrapper.TakeServerOptions(), true
+}
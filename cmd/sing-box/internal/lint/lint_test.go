@@ -0,0 +1,153 @@
+package lint
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common/json"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lintContent(t *testing.T, content string) []Finding {
+	options, err := json.UnmarshalExtended[option.Options]([]byte(content))
+	require.NoError(t, err)
+	return Lint(options)
+}
+
+func findingPaths(findings []Finding, code string) []string {
+	var paths []string
+	for _, finding := range findings {
+		if finding.Code == code {
+			paths = append(paths, finding.Path)
+		}
+	}
+	return paths
+}
+
+func bootstrapLoops(findings []Finding) []Finding {
+	var loops []Finding
+	for _, finding := range findings {
+		if finding.Code == CodeDNSBootstrapLoop {
+			loops = append(loops, finding)
+		}
+	}
+	return loops
+}
+
+func TestShadowedRules(t *testing.T) {
+	t.Parallel()
+	findings := lintContent(t, `
+{
+  "outbounds": [
+    {"type": "direct", "tag": "direct"},
+    {"type": "direct", "tag": "proxy"}
+  ],
+  "route": {
+    "rules": [
+      {"domain_suffix": "example.org", "outbound": "direct"},
+      {"domain": "www.example.org", "outbound": "proxy"},
+      {"domain_suffix": ".example.com", "outbound": "direct"},
+      {"domain": "example.com", "outbound": "proxy"},
+      {"ip_cidr": "10.0.0.0/8", "port_range": "1000:2000", "outbound": "direct"},
+      {"ip_cidr": ["10.1.0.0/16", "10.2.0.1"], "port": 1500, "network": "tcp", "outbound": "proxy"},
+      {"ip_cidr": "10.3.0.0/16", "port": 3000, "outbound": "proxy"},
+      {"network": "udp", "outbound": "direct"},
+      {"network": "udp", "invert": true, "outbound": "proxy"},
+      {"network": "udp", "rule_set": "private", "outbound": "proxy"},
+      {"rule_set": ["private", "local"], "outbound": "direct"},
+      {"rule_set": "private", "outbound": "proxy"},
+      {"rule_set": "private", "rule_set_ip_cidr_match_source": true, "outbound": "proxy"}
+    ],
+    "rule_set": [
+      {"type": "inline", "tag": "private", "rules": [{"ip_cidr": "10.0.0.0/8"}]},
+      {"type": "inline", "tag": "local", "rules": [{"domain_suffix": "local"}]}
+    ]
+  }
+}`)
+	require.Equal(t, []string{"route.rules[1]", "route.rules[5]", "route.rules[9]", "route.rules[11]"}, findingPaths(findings, CodeShadowedRule))
+}
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+	findings := lintContent(t, `
+{
+  "dns": {
+    "servers": [{"address": "tls://1.1.1.1", "detour": "dns-out"}]
+  },
+  "outbounds": [
+    {"type": "selector", "tag": "select", "outbounds": ["a", "b"], "default": "c"},
+    {"type": "direct", "tag": "a", "detour": "b"},
+    {"type": "direct", "tag": "b", "detour": "a"},
+    {"type": "direct", "tag": "c"},
+    {"type": "direct", "tag": "dns-out"},
+    {"type": "direct", "tag": "download"}
+  ],
+  "route": {
+    "rules": [{"type": "logical", "mode": "or", "rules": [{"rule_set": "used"}, {"network": "tcp"}], "outbound": "select"}],
+    "rule_set": [
+      {"type": "remote", "tag": "used", "format": "binary", "url": "https://example.org/used.srs", "download_detour": "download"},
+      {"type": "remote", "tag": "unused", "format": "binary", "url": "https://example.org/unused.srs", "download_detour": "download"}
+    ]
+  }
+}`)
+	require.Equal(t, []string{"outbounds[3]"}, findingPaths(findings, CodeUnusedOutbound))
+	require.Equal(t, []string{"route.rule_set[1]"}, findingPaths(findings, CodeUnusedRuleSet))
+	require.Equal(t, []string{"outbounds[1]"}, findingPaths(findings, CodeDetourCycle))
+	require.Equal(t, []string{"outbounds[0]"}, findingPaths(findings, CodeInvalidSelectorDefault))
+}
+
+func TestDNSBootstrapLoop(t *testing.T) {
+	t.Parallel()
+	content := `
+{
+  "dns": {
+    "servers": [
+      {"tag": "remote", "address": "https://1.1.1.1/dns-query", "detour": "proxy"},
+      {"tag": "local", "address": "local"}
+    ],
+    "rules": [%s]
+  },
+  "outbounds": [
+    {"type": "shadowsocks", "tag": "proxy", "server": "proxy.example.org", "server_port": 8388, "method": "none"},
+    {"type": "direct", "tag": "direct"}
+  ]
+}`
+	findings := bootstrapLoops(lintContent(t, fmt.Sprintf(content, ``)))
+	require.Len(t, findings, 1)
+	require.Equal(t, LevelError, findings[0].Level)
+	require.Equal(t, "dns.servers[0]", findings[0].Path)
+
+	findings = bootstrapLoops(lintContent(t, fmt.Sprintf(content, `{"outbound": "any", "server": "local"}`)))
+	require.Empty(t, findings)
+
+	findings = bootstrapLoops(lintContent(t, fmt.Sprintf(content, `{"domain_suffix": "example.org", "server": "local"}`)))
+	require.Empty(t, findings)
+
+	findings = bootstrapLoops(lintContent(t, fmt.Sprintf(content, `{"clash_mode": "direct", "server": "local"}`)))
+	require.Len(t, findings, 1)
+	require.Equal(t, LevelWarning, findings[0].Level)
+}
+
+func TestDeprecatedFields(t *testing.T) {
+	t.Parallel()
+	findings := lintContent(t, `
+{
+  "inbounds": [{"type": "tun", "inet4_address": "172.19.0.1/30"}],
+  "outbounds": [{"type": "direct"}],
+  "route": {
+    "rules": [{"type": "logical", "mode": "and", "rules": [{"geosite": "cn"}, {"rule_set_ipcidr_match_source": true}], "outbound": "0"}]
+  },
+  "experimental": {
+    "cache_file": {"enabled": true},
+    "clash_api": {"store_selected": true}
+  }
+}`)
+	require.Equal(t, []string{
+		"experimental.clash_api.store_selected",
+		"inbounds[0].inet4_address",
+		"route.rules[0].rules[0].geosite",
+		"route.rules[0].rules[1].rule_set_ipcidr_match_source",
+	}, findingPaths(findings, CodeDeprecatedField))
+}
@@ -0,0 +1,174 @@
+package lint
+
+import (
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	F "github.com/sagernet/sing/common/format"
+)
+
+func (l *linter) lintReferences() {
+	usedOutbounds := make(map[string]bool)
+	usedRuleSets := make(map[string]bool)
+	useOutbound := func(tag string) {
+		if tag != "" {
+			usedOutbounds[tag] = true
+		}
+	}
+	useOutbound(l.defaultOutbound())
+	for _, tag := range l.outboundTags {
+		for _, dependency := range l.outboundDependencies(tag) {
+			useOutbound(dependency)
+		}
+	}
+	for _, inbound := range l.options.Inbounds {
+		if inbound.Type == C.TypeTor {
+			useOutbound(inbound.TorOptions.Outbound)
+		}
+	}
+	if l.options.Route != nil {
+		var walkRule func(rule option.Rule)
+		walkRule = func(rule option.Rule) {
+			switch rule.Type {
+			case C.RuleTypeDefault:
+				useOutbound(rule.DefaultOptions.Outbound)
+				for _, tag := range rule.DefaultOptions.RuleSet {
+					usedRuleSets[tag] = true
+				}
+			case C.RuleTypeLogical:
+				useOutbound(rule.LogicalOptions.Outbound)
+				for _, subRule := range rule.LogicalOptions.Rules {
+					walkRule(subRule)
+				}
+			}
+		}
+		for _, rule := range l.options.Route.Rules {
+			walkRule(rule)
+		}
+		for _, ruleSet := range l.options.Route.RuleSet {
+			if ruleSet.Type == C.RuleSetTypeRemote {
+				useOutbound(ruleSet.RemoteOptions.DownloadDetour)
+			}
+		}
+		if l.options.Route.GeoIP != nil {
+			useOutbound(l.options.Route.GeoIP.DownloadDetour)
+		}
+		if l.options.Route.Geosite != nil {
+			useOutbound(l.options.Route.Geosite.DownloadDetour)
+		}
+	}
+	if l.options.DNS != nil {
+		for _, server := range l.options.DNS.Servers {
+			useOutbound(server.Detour)
+		}
+		var walkRule func(rule option.DNSRule)
+		walkRule = func(rule option.DNSRule) {
+			switch rule.Type {
+			case C.RuleTypeDefault:
+				for _, tag := range rule.DefaultOptions.RuleSet {
+					usedRuleSets[tag] = true
+				}
+			case C.RuleTypeLogical:
+				for _, subRule := range rule.LogicalOptions.Rules {
+					walkRule(subRule)
+				}
+			}
+		}
+		for _, rule := range l.options.DNS.Rules {
+			walkRule(rule)
+		}
+	}
+	if l.options.NTP != nil {
+		useOutbound(l.options.NTP.Detour)
+	}
+	if l.options.Experimental != nil && l.options.Experimental.ClashAPI != nil {
+		useOutbound(l.options.Experimental.ClashAPI.ExternalUIDownloadDetour)
+	}
+	for _, tag := range l.outboundTags {
+		if !usedOutbounds[tag] {
+			l.report(LevelWarning, CodeUnusedOutbound, l.outboundPath[tag],
+				F.ToString("outbound ", tag, " is never referenced by rules, groups, detours or route.final"),
+				"remove the outbound or reference it")
+		}
+	}
+	if l.options.Route != nil {
+		for i, ruleSet := range l.options.Route.RuleSet {
+			if !usedRuleSets[ruleSet.Tag] {
+				l.report(LevelWarning, CodeUnusedRuleSet, F.ToString("route.rule_set[", i, "]"),
+					F.ToString("rule-set ", ruleSet.Tag, " is never referenced by route or DNS rules"),
+					"remove the rule-set or reference it")
+			}
+		}
+	}
+}
+
+func (l *linter) lintDetourCycles() {
+	const (
+		stateVisiting = iota + 1
+		stateVisited
+	)
+	states := make(map[string]int)
+	reported := make(map[string]bool)
+	var stack []string
+	var visit func(tag string)
+	visit = func(tag string) {
+		states[tag] = stateVisiting
+		stack = append(stack, tag)
+		for _, dependency := range l.outboundDependencies(tag) {
+			if _, loaded := l.outbounds[dependency]; !loaded {
+				continue
+			}
+			switch states[dependency] {
+			case stateVisiting:
+				cycle := rotateCycle(stack[common.Index(stack, func(it string) bool {
+					return it == dependency
+				}):])
+				key := strings.Join(cycle, " -> ")
+				if !reported[key] {
+					reported[key] = true
+					l.report(LevelError, CodeDetourCycle, l.outboundPath[cycle[0]],
+						F.ToString("outbounds depend on each other: ", key, " -> ", cycle[0]),
+						"break the cycle by changing a detour or removing a group member")
+				}
+			case 0:
+				visit(dependency)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		states[tag] = stateVisited
+	}
+	for _, tag := range l.outboundTags {
+		if states[tag] == 0 {
+			visit(tag)
+		}
+	}
+}
+
+// rotateCycle starts the cycle from its smallest tag, so that each cycle is reported once.
+func rotateCycle(cycle []string) []string {
+	start := 0
+	for i, tag := range cycle {
+		if tag < cycle[start] {
+			start = i
+		}
+	}
+	return append(append([]string{}, cycle[start:]...), cycle[:start]...)
+}
+
+func (l *linter) lintSelectors() {
+	for _, tag := range l.outboundTags {
+		outbound := l.outbounds[tag]
+		if outbound.Type != C.TypeSelector {
+			continue
+		}
+		options := outbound.SelectorOptions
+		if options.Default == "" || common.Contains(options.Outbounds, options.Default) {
+			continue
+		}
+		l.report(LevelError, CodeInvalidSelectorDefault, l.outboundPath[tag],
+			F.ToString("default outbound ", options.Default, " of selector ", tag, " is not a member"),
+			F.ToString("add ", options.Default, " to outbounds or set default to one of: ", strings.Join(options.Outbounds, ", ")))
+	}
+}
@@ -0,0 +1,356 @@
+package lint
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	F "github.com/sagernet/sing/common/format"
+)
+
+const (
+	groupSourceAddress      = "source_address"
+	groupSourcePort         = "source_port"
+	groupDestinationAddress = "destination_address"
+	groupDestinationPort    = "destination_port"
+)
+
+const (
+	atomDomain        = "domain"
+	atomDomainSuffix  = "domain_suffix"
+	atomDomainKeyword = "domain_keyword"
+	atomIPCIDR        = "ip_cidr"
+	atomPort          = "port"
+	atomPortRange     = "port_range"
+)
+
+type matchAtom struct {
+	kind  string
+	value string
+}
+
+// ruleMatcher is a static view of a default rule: atoms inside a group are ORed and groups are ANDed,
+// in the same way as the router evaluates them.
+type ruleMatcher struct {
+	groups         map[string][]matchAtom
+	ruleSet        []string
+	ruleSetOptions string
+	hasIPItems     bool
+}
+
+func (l *linter) lintRules() {
+	if l.options.Route == nil {
+		return
+	}
+	matchers := make([]*ruleMatcher, len(l.options.Route.Rules))
+	for i, rule := range l.options.Route.Rules {
+		if rule.Type == C.RuleTypeDefault && !rule.DefaultOptions.Invert {
+			matchers[i] = newRuleMatcher(rule.DefaultOptions)
+		}
+	}
+	l.lintShadowedRules("route.rules", matchers)
+}
+
+func (l *linter) lintDNSRules() {
+	if l.options.DNS == nil {
+		return
+	}
+	matchers := make([]*ruleMatcher, len(l.options.DNS.Rules))
+	for i, rule := range l.options.DNS.Rules {
+		if rule.Type == C.RuleTypeDefault && !rule.DefaultOptions.Invert {
+			matcher := newDNSRuleMatcher(rule.DefaultOptions)
+			// address items of DNS rules are matched against the response, skip them to avoid false positives
+			if !matcher.hasIPItems {
+				matchers[i] = matcher
+			}
+		}
+	}
+	l.lintShadowedRules("dns.rules", matchers)
+}
+
+func (l *linter) lintShadowedRules(path string, matchers []*ruleMatcher) {
+	for j, matcher := range matchers {
+		if matcher == nil {
+			continue
+		}
+		for i := 0; i < j; i++ {
+			if matchers[i] == nil || !matchers[i].covers(matcher) {
+				continue
+			}
+			rulePath := F.ToString(path, "[", j, "]")
+			shadowPath := F.ToString(path, "[", i, "]")
+			l.report(LevelWarning, CodeShadowedRule, rulePath,
+				F.ToString("rule is never matched, everything it matches is already matched by ", shadowPath),
+				F.ToString("remove the rule or move it before ", shadowPath))
+			break
+		}
+	}
+}
+
+func newRuleMatcher(rule option.DefaultRule) *ruleMatcher {
+	m := &ruleMatcher{groups: make(map[string][]matchAtom)}
+	addValues(m, "inbound", rule.Inbound)
+	if rule.IPVersion > 0 {
+		addValues(m, "ip_version", []int{rule.IPVersion})
+	}
+	addValues(m, "network", rule.Network)
+	addValues(m, "auth_user", rule.AuthUser)
+	addValues(m, "protocol", rule.Protocol)
+	addValues(m, "client", rule.Client)
+	m.addDestination(rule.Domain, rule.DomainSuffix, rule.DomainKeyword, rule.DomainRegex, rule.Geosite)
+	m.addAddress(groupDestinationAddress, rule.GeoIP, rule.IPCIDR, rule.IPIsPrivate, rule.IPASN)
+	m.addAddress(groupSourceAddress, rule.SourceGeoIP, rule.SourceIPCIDR, rule.SourceIPIsPrivate, rule.SourceIPASN)
+	m.addPort(groupSourcePort, rule.SourcePort, rule.SourcePortRange)
+	m.addPort(groupDestinationPort, rule.Port, rule.PortRange)
+	addValues(m, "process_name", rule.ProcessName)
+	addValues(m, "process_path", rule.ProcessPath)
+	addValues(m, "process_path_regex", rule.ProcessPathRegex)
+	addValues(m, "package_name", rule.PackageName)
+	addValues(m, "user", rule.User)
+	addValues(m, "user_id", rule.UserID)
+	addValues(m, "cgroup", rule.CGroup)
+	addValues(m, "systemd_unit", rule.SystemdUnit)
+	addValues(m, "container_id", rule.ContainerID)
+	m.addSchedule(rule.Schedule)
+	if rule.ClashMode != "" {
+		addValues(m, "clash_mode", []string{strings.ToLower(rule.ClashMode)})
+	}
+	addValues(m, "wifi_ssid", rule.WIFISSID)
+	addValues(m, "wifi_bssid", rule.WIFIBSSID)
+	m.ruleSet = rule.RuleSet
+	m.ruleSetOptions = F.ToString(rule.RuleSetIPCIDRMatchSource)
+	return m
+}
+
+func newDNSRuleMatcher(rule option.DefaultDNSRule) *ruleMatcher {
+	m := &ruleMatcher{groups: make(map[string][]matchAtom)}
+	addValues(m, "inbound", rule.Inbound)
+	if rule.IPVersion > 0 {
+		addValues(m, "ip_version", []int{rule.IPVersion})
+	}
+	addValues(m, "query_type", common.Map(rule.QueryType, func(it option.DNSQueryType) uint16 {
+		return uint16(it)
+	}))
+	addValues(m, "network", rule.Network)
+	addValues(m, "auth_user", rule.AuthUser)
+	addValues(m, "protocol", rule.Protocol)
+	m.addDestination(rule.Domain, rule.DomainSuffix, rule.DomainKeyword, rule.DomainRegex, rule.Geosite)
+	m.hasIPItems = len(rule.GeoIP) > 0 || len(rule.IPCIDR) > 0 || rule.IPIsPrivate || len(rule.IPASN) > 0
+	m.addAddress(groupSourceAddress, rule.SourceGeoIP, rule.SourceIPCIDR, rule.SourceIPIsPrivate, rule.SourceIPASN)
+	m.addPort(groupSourcePort, rule.SourcePort, rule.SourcePortRange)
+	m.addPort(groupDestinationPort, rule.Port, rule.PortRange)
+	addValues(m, "process_name", rule.ProcessName)
+	addValues(m, "process_path", rule.ProcessPath)
+	addValues(m, "process_path_regex", rule.ProcessPathRegex)
+	addValues(m, "package_name", rule.PackageName)
+	addValues(m, "user", rule.User)
+	addValues(m, "user_id", rule.UserID)
+	addValues(m, "cgroup", rule.CGroup)
+	addValues(m, "systemd_unit", rule.SystemdUnit)
+	addValues(m, "container_id", rule.ContainerID)
+	m.addSchedule(rule.Schedule)
+	addValues(m, "outbound", rule.Outbound)
+	if rule.ClashMode != "" {
+		addValues(m, "clash_mode", []string{strings.ToLower(rule.ClashMode)})
+	}
+	addValues(m, "wifi_ssid", rule.WIFISSID)
+	addValues(m, "wifi_bssid", rule.WIFIBSSID)
+	m.ruleSet = rule.RuleSet
+	m.ruleSetOptions = F.ToString(rule.RuleSetIPCIDRMatchSource, rule.RuleSetIPCIDRAcceptEmpty)
+	return m
+}
+
+func (m *ruleMatcher) add(group string, kind string, value string) {
+	m.groups[group] = append(m.groups[group], matchAtom{kind, value})
+}
+
+func addValues[T any](m *ruleMatcher, group string, values []T) {
+	for _, value := range values {
+		m.add(group, "", F.ToString(value))
+	}
+}
+
+func (m *ruleMatcher) addDestination(domain []string, domainSuffix []string, domainKeyword []string, domainRegex []string, geosite []string) {
+	for _, value := range domain {
+		m.add(groupDestinationAddress, atomDomain, strings.ToLower(value))
+	}
+	for _, value := range domainSuffix {
+		m.add(groupDestinationAddress, atomDomainSuffix, strings.ToLower(value))
+	}
+	for _, value := range domainKeyword {
+		m.add(groupDestinationAddress, atomDomainKeyword, strings.ToLower(value))
+	}
+	for _, value := range domainRegex {
+		m.add(groupDestinationAddress, "domain_regex", value)
+	}
+	for _, value := range geosite {
+		m.add(groupDestinationAddress, "geosite", value)
+	}
+}
+
+func (m *ruleMatcher) addAddress(group string, geoip []string, ipCIDR []string, ipIsPrivate bool, ipASN []uint32) {
+	for _, value := range geoip {
+		m.add(group, "geoip", value)
+	}
+	for _, value := range ipCIDR {
+		prefix, err := parsePrefix(value)
+		if err != nil {
+			m.add(group, "invalid_ip_cidr", value)
+			continue
+		}
+		m.add(group, atomIPCIDR, prefix.String())
+	}
+	if ipIsPrivate {
+		m.add(group, "ip_is_private", "true")
+	}
+	for _, value := range ipASN {
+		m.add(group, "ip_asn", F.ToString(value))
+	}
+}
+
+func (m *ruleMatcher) addPort(group string, port []uint16, portRange []string) {
+	for _, value := range port {
+		m.add(group, atomPort, F.ToString(value))
+	}
+	for _, value := range portRange {
+		m.add(group, atomPortRange, value)
+	}
+}
+
+func (m *ruleMatcher) addSchedule(schedules []option.ScheduleOptions) {
+	for _, schedule := range schedules {
+		m.add("schedule", "", F.ToString(strings.Join(schedule.Weekday, ","), "|", strings.Join(schedule.Time, ","), "|", schedule.Timezone))
+	}
+}
+
+func (m *ruleMatcher) hasAddressGroups() bool {
+	return common.Any([]string{groupSourceAddress, groupSourcePort, groupDestinationAddress, groupDestinationPort}, func(group string) bool {
+		return len(m.groups[group]) > 0
+	})
+}
+
+// covers reports whether everything matched by other is also matched by m.
+func (m *ruleMatcher) covers(other *ruleMatcher) bool {
+	if len(m.ruleSet) > 0 {
+		// rule-set items also satisfy address groups of the rule, so only plain rule-set rules are comparable
+		if m.hasAddressGroups() || len(other.ruleSet) == 0 || m.ruleSetOptions != other.ruleSetOptions {
+			return false
+		}
+		if !common.All(other.ruleSet, func(tag string) bool {
+			return common.Contains(m.ruleSet, tag)
+		}) {
+			return false
+		}
+	} else if len(other.ruleSet) > 0 && m.hasAddressGroups() {
+		return false
+	}
+	for group, atoms := range m.groups {
+		otherAtoms, loaded := other.groups[group]
+		if !loaded {
+			return false
+		}
+		for _, otherAtom := range otherAtoms {
+			if !common.Any(atoms, func(atom matchAtom) bool {
+				return atom.covers(otherAtom)
+			}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (a matchAtom) covers(b matchAtom) bool {
+	if a == b {
+		return true
+	}
+	switch a.kind {
+	case atomDomainSuffix:
+		switch b.kind {
+		case atomDomain:
+			return suffixMatches(a.value, b.value)
+		case atomDomainSuffix:
+			if strings.HasPrefix(b.value, ".") {
+				return suffixMatches(a.value, b.value[1:]) || strings.HasSuffix(b.value, "."+strings.TrimPrefix(a.value, "."))
+			}
+			return suffixMatches(a.value, b.value)
+		}
+	case atomDomainKeyword:
+		switch b.kind {
+		case atomDomain, atomDomainSuffix, atomDomainKeyword:
+			return strings.Contains(strings.TrimPrefix(b.value, "."), a.value)
+		}
+	case atomIPCIDR:
+		if b.kind == atomIPCIDR {
+			prefix := netip.MustParsePrefix(a.value)
+			otherPrefix := netip.MustParsePrefix(b.value)
+			return prefix.Bits() <= otherPrefix.Bits() && prefix.Contains(otherPrefix.Addr())
+		}
+	case atomPort, atomPortRange:
+		start, end, ok := portBounds(a)
+		if !ok {
+			return false
+		}
+		otherStart, otherEnd, ok := portBounds(b)
+		return ok && start <= otherStart && otherEnd <= end
+	}
+	return false
+}
+
+func suffixMatches(suffix string, domain string) bool {
+	if strings.HasPrefix(suffix, ".") {
+		return strings.HasSuffix(domain, suffix)
+	}
+	return domain == suffix || strings.HasSuffix(domain, "."+suffix)
+}
+
+func portBounds(atom matchAtom) (uint16, uint16, bool) {
+	switch atom.kind {
+	case atomPort:
+		port, err := strconv.ParseUint(atom.value, 10, 16)
+		if err != nil {
+			return 0, 0, false
+		}
+		return uint16(port), uint16(port), true
+	case atomPortRange:
+		startString, endString, found := strings.Cut(atom.value, ":")
+		if !found {
+			return 0, 0, false
+		}
+		start, end := uint64(0), uint64(0xFFFF)
+		var err error
+		if startString != "" {
+			start, err = strconv.ParseUint(startString, 10, 16)
+			if err != nil {
+				return 0, 0, false
+			}
+		}
+		if endString != "" {
+			end, err = strconv.ParseUint(endString, 10, 16)
+			if err != nil {
+				return 0, 0, false
+			}
+		}
+		return uint16(start), uint16(end), true
+	default:
+		return 0, 0, false
+	}
+}
+
+func parsePrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,238 @@
+package schema
+
+import (
+	"encoding"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/json"
+)
+
+const Draft07 = "http://json-schema.org/draft-07/schema#"
+
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	Maximum              *uint64            `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Configuration generates the schema of the sing-box configuration.
+func Configuration() (*Schema, error) {
+	return generate(reflect.TypeOf(option.Options{}), "sing-box configuration")
+}
+
+// RuleSetSource generates the schema of source rule-set files.
+func RuleSetSource() (*Schema, error) {
+	return generate(reflect.TypeOf(option.PlainRuleSetCompat{}), "sing-box rule-set source")
+}
+
+// Write writes the schema as indented JSON.
+func Write(writer io.Writer, schema *Schema) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schema)
+}
+
+func generate(rootType reflect.Type, title string) (*Schema, error) {
+	g := &generator{
+		unions:      make(map[reflect.Type]union),
+		names:       make(map[reflect.Type]string),
+		definitions: make(map[string]*Schema),
+	}
+	for unionType, unionTable := range unions {
+		g.unions[unionType] = unionTable
+	}
+	for _, unionType := range proxyUnionTypes {
+		unionTable, err := proxyUnion(unionType)
+		if err != nil {
+			return nil, err
+		}
+		g.unions[unionType] = unionTable
+	}
+	rootRef, err := g.schemaOf(rootType)
+	if err != nil {
+		return nil, err
+	}
+	root := *g.definitions[strings.TrimPrefix(rootRef.Ref, "#/definitions/")]
+	root.Schema = Draft07
+	root.Title = title
+	root.Definitions = g.definitions
+	return &root, nil
+}
+
+type generator struct {
+	unions      map[reflect.Type]union
+	names       map[reflect.Type]string
+	definitions map[string]*Schema
+}
+
+func (g *generator) schemaOf(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if customSchema, loaded := customSchemas[t]; loaded {
+		return customSchema(), nil
+	}
+	if unionTable, loaded := g.unions[t]; loaded {
+		return g.define(t, func() (*Schema, error) {
+			return g.unionSchema(t, unionTable)
+		})
+	}
+	if isListable(t) {
+		itemSchema, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{AnyOf: []*Schema{itemSchema, {Type: "array", Items: itemSchema}}}, nil
+	}
+	pointerType := reflect.PointerTo(t)
+	if _, isUnmarshaler := pointerType.MethodByName("UnmarshalJSON"); isUnmarshaler && !plainStructs[t] {
+		return nil, E.New("missing schema for ", t, ", which has a custom JSON format")
+	}
+	if pointerType.Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
+		return &Schema{Type: "string"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unsignedSchema(t.Bits()), nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// byte slices are accepted both as a base64 string and as an array of bytes
+			return &Schema{AnyOf: []*Schema{{Type: "string"}, {Type: "array", Items: unsignedSchema(8)}}}, nil
+		}
+		itemSchema, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: itemSchema}, nil
+	case reflect.Map:
+		valueSchema, err := g.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: valueSchema}, nil
+	case reflect.Struct:
+		build := func() (*Schema, error) {
+			properties, err := g.properties(t)
+			if err != nil {
+				return nil, err
+			}
+			return &Schema{Type: "object", Properties: properties, AdditionalProperties: false}, nil
+		}
+		if t.Name() == "" {
+			return build()
+		}
+		return g.define(t, build)
+	default:
+		return nil, E.New("unsupported type ", t)
+	}
+}
+
+// define adds the schema of a named type to definitions and returns a reference to it.
+func (g *generator) define(t reflect.Type, build func() (*Schema, error)) (*Schema, error) {
+	name, loaded := g.names[t]
+	if !loaded {
+		name = t.Name()
+		if _, conflict := g.definitions[name]; conflict {
+			name = path.Base(t.PkgPath()) + "." + name
+		}
+		g.names[t] = name
+		// placeholder for recursive types such as logical rules
+		g.definitions[name] = &Schema{}
+		schema, err := build()
+		if err != nil {
+			return nil, E.Cause(err, t.Name())
+		}
+		g.definitions[name] = schema
+	}
+	return &Schema{Ref: "#/definitions/" + name}, nil
+}
+
+// properties returns the properties of a struct, flattening embedded structs like encoding/json.
+func (g *generator) properties(t reflect.Type) (map[string]*Schema, error) {
+	properties := make(map[string]*Schema)
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				embedded = append(embedded, fieldType)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldSchema, err := g.schemaOf(field.Type)
+		if err != nil {
+			return nil, E.Cause(err, name)
+		}
+		properties[name] = fieldSchema
+	}
+	for _, embeddedType := range embedded {
+		embeddedProperties, err := g.properties(embeddedType)
+		if err != nil {
+			return nil, err
+		}
+		for name, propertySchema := range embeddedProperties {
+			if _, loaded := properties[name]; !loaded {
+				properties[name] = propertySchema
+			}
+		}
+	}
+	return properties, nil
+}
+
+func isListable(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.PkgPath() == reflect.TypeOf(option.Listable[string]{}).PkgPath() &&
+		strings.HasPrefix(t.Name(), "Listable[")
+}
+
+func unsignedSchema(bits int) *Schema {
+	minimum := int64(0)
+	schema := &Schema{Type: "integer", Minimum: &minimum}
+	if bits < 64 {
+		maximum := uint64(1)<<bits - 1
+		schema.Maximum = &maximum
+	}
+	return schema
+}
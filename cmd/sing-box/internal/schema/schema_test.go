@@ -0,0 +1,145 @@
+package schema
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common/json"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the generated schema files")
+
+func variantValues(schema *Schema, key string) []any {
+	var values []any
+	for _, variantSchema := range schema.OneOf {
+		keySchema := variantSchema.Properties[key]
+		if keySchema.Const != nil {
+			values = append(values, keySchema.Const)
+		} else {
+			values = append(values, keySchema.Enum...)
+		}
+	}
+	return values
+}
+
+func TestConfiguration(t *testing.T) {
+	t.Parallel()
+	schema, err := Configuration()
+	require.NoError(t, err)
+	require.Equal(t, Draft07, schema.Schema)
+	require.Contains(t, schema.Properties, "$schema")
+	require.Equal(t, "#/definitions/Outbound", schema.Properties["outbounds"].Items.Ref)
+	outboundTypes := variantValues(schema.Definitions["Outbound"], "type")
+	require.Contains(t, outboundTypes, C.TypeShadowsocks)
+	require.Contains(t, outboundTypes, C.TypeBlock)
+	require.NotContains(t, outboundTypes, C.TypeTun)
+	inboundTypes := variantValues(schema.Definitions["Inbound"], "type")
+	require.Contains(t, inboundTypes, C.TypeTun)
+	require.Contains(t, inboundTypes, C.TypeMixed)
+	require.Equal(t, []any{"", C.RuleTypeDefault, C.RuleTypeLogical}, variantValues(schema.Definitions["Rule"], "type"))
+	require.Equal(t, []string{"type"}, schema.Definitions["V2RayTransportOptions"].OneOf[0].Required)
+	_, err = json.Marshal(schema)
+	require.NoError(t, err)
+}
+
+func TestRuleSetSource(t *testing.T) {
+	t.Parallel()
+	schema, err := RuleSetSource()
+	require.NoError(t, err)
+	require.Equal(t, []any{C.RuleSetVersion1, C.RuleSetVersion2, C.RuleSetVersion3}, variantValues(schema, "version"))
+	require.Equal(t, "#/definitions/HeadlessRule", schema.OneOf[0].Properties["rules"].Items.Ref)
+}
+
+type testUnion struct {
+	Type     string `json:"type"`
+	AOptions struct {
+		A string `json:"a"`
+	} `json:"-"`
+	BOptions struct {
+		B string `json:"b"`
+	} `json:"-"`
+}
+
+func TestUnmappedVariant(t *testing.T) {
+	t.Parallel()
+	unionType := reflect.TypeOf(testUnion{})
+	g := &generator{
+		unions: map[reflect.Type]union{
+			unionType: {"type", []variant{{[]any{"a"}, "AOptions"}}},
+		},
+		names:       make(map[reflect.Type]string),
+		definitions: make(map[string]*Schema),
+	}
+	_, err := g.schemaOf(unionType)
+	require.ErrorContains(t, err, "BOptions")
+}
+
+// TestGolden checks that the published schema files are up to date,
+// run with -update to regenerate them.
+func TestGolden(t *testing.T) {
+	for _, testCase := range []struct {
+		path     string
+		generate func() (*Schema, error)
+	}{
+		{"sing-box.schema.json", Configuration},
+		{"rule-set.schema.json", RuleSetSource},
+	} {
+		schema, err := testCase.generate()
+		require.NoError(t, err)
+		var buffer bytes.Buffer
+		require.NoError(t, Write(&buffer, schema))
+		path := filepath.Join("..", "..", "..", "..", "docs", "configuration", testCase.path)
+		if *update {
+			require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0o644))
+			continue
+		}
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, string(content), buffer.String(), testCase.path+" is outdated, run go test with -update")
+	}
+}
+
+// TestProxyTypes checks that every proxy type declared in constant/proxy.go is
+// in proxyTypes and has options in an inbound or an outbound.
+func TestProxyTypes(t *testing.T) {
+	t.Parallel()
+	file, err := parser.ParseFile(token.NewFileSet(), filepath.Join("..", "..", "..", "..", "constant", "proxy.go"), nil, 0)
+	require.NoError(t, err)
+	var declaredTypes []string
+	for _, declaration := range file.Decls {
+		genDecl, isGenDecl := declaration.(*ast.GenDecl)
+		if !isGenDecl || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, name := range valueSpec.Names {
+				if !strings.HasPrefix(name.Name, "Type") {
+					continue
+				}
+				value, err := strconv.Unquote(valueSpec.Values[i].(*ast.BasicLit).Value)
+				require.NoError(t, err)
+				declaredTypes = append(declaredTypes, value)
+			}
+		}
+	}
+	require.ElementsMatch(t, declaredTypes, proxyTypes, "proxyTypes is out of sync with constant/proxy.go")
+	for _, proxyType := range proxyTypes {
+		_, inboundErr := (&option.Inbound{Type: proxyType}).RawOptions()
+		_, outboundErr := (&option.Outbound{Type: proxyType}).RawOptions()
+		require.False(t, inboundErr != nil && outboundErr != nil, "no options for proxy type ", proxyType)
+	}
+}
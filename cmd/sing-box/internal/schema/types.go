@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/sagernet/sing-box/option"
+)
+
+// customSchemas describes option types with a custom JSON format.
+var customSchemas = map[reflect.Type]func() *Schema{
+	reflect.TypeOf(option.ListenAddress{}): stringSchema,
+	reflect.TypeOf(option.AddrPrefix{}):    stringSchema,
+	reflect.TypeOf(option.Duration(0)):     stringSchema,
+	reflect.TypeOf(option.FileMode(0)): func() *Schema {
+		return &Schema{Type: "string", Pattern: "^0?[0-7]{1,3}$"}
+	},
+	reflect.TypeOf(option.NetworkList("")): func() *Schema {
+		networkSchema := &Schema{Enum: []any{"tcp", "udp"}}
+		return &Schema{AnyOf: []*Schema{networkSchema, {Type: "array", Items: networkSchema}}}
+	},
+	reflect.TypeOf(option.DomainStrategy(0)): func() *Schema {
+		return &Schema{Enum: []any{"", "as_is", "prefer_ipv4", "prefer_ipv6", "ipv4_only", "ipv6_only"}}
+	},
+	reflect.TypeOf(option.DNSQueryType(0)): func() *Schema {
+		return &Schema{AnyOf: []*Schema{unsignedSchema(16), {Type: "string"}}}
+	},
+	reflect.TypeOf(option.FwMark(0)): func() *Schema {
+		return &Schema{AnyOf: []*Schema{unsignedSchema(32), {Type: "string"}}}
+	},
+	reflect.TypeOf(option.MemoryBytes(0)):      integerOrString,
+	reflect.TypeOf(option.UDPTimeoutCompat(0)): integerOrString,
+	reflect.TypeOf(option.UDPOverTCPOptions{}): func() *Schema {
+		return &Schema{AnyOf: []*Schema{{Type: "boolean"}, {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"enabled": {Type: "boolean"},
+				"version": unsignedSchema(8),
+			},
+			AdditionalProperties: false,
+		}}}
+	},
+	reflect.TypeOf(option.OnDemandRuleAction(0)): func() *Schema {
+		return &Schema{Enum: []any{"connect", "disconnect", "evaluate_connection", "ignore"}}
+	},
+	reflect.TypeOf(option.OnDemandRuleInterfaceType(0)): func() *Schema {
+		return &Schema{Enum: []any{"any", "wifi", "cellular"}}
+	},
+}
+
+// plainStructs have a custom UnmarshalJSON that decodes them as regular objects.
+var plainStructs = map[reflect.Type]bool{
+	reflect.TypeOf(option.Options{}):        true,
+	reflect.TypeOf(option.DefaultRule{}):    true,
+	reflect.TypeOf(option.DefaultDNSRule{}): true,
+}
+
+func stringSchema() *Schema {
+	return &Schema{Type: "string"}
+}
+
+func integerOrString() *Schema {
+	return &Schema{AnyOf: []*Schema{{Type: "integer"}, {Type: "string"}}}
+}
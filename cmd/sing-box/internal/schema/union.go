@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"reflect"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// union describes an option type that selects one of its `json:"-"` fields by the value of key.
+type union struct {
+	key      string
+	variants []variant
+}
+
+type variant struct {
+	values []any
+	// field is empty for variants without options
+	field string
+}
+
+var unions = map[reflect.Type]union{
+	reflect.TypeOf(option.V2RayTransportOptions{}): {"type", []variant{
+		{[]any{C.V2RayTransportTypeHTTP}, "HTTPOptions"},
+		{[]any{C.V2RayTransportTypeWebsocket}, "WebsocketOptions"},
+		{[]any{C.V2RayTransportTypeQUIC}, "QUICOptions"},
+		{[]any{C.V2RayTransportTypeGRPC}, "GRPCOptions"},
+		{[]any{C.V2RayTransportTypeHTTPUpgrade}, "HTTPUpgradeOptions"},
+	}},
+	reflect.TypeOf(option.Rule{}):         ruleUnion,
+	reflect.TypeOf(option.DNSRule{}):      ruleUnion,
+	reflect.TypeOf(option.HeadlessRule{}): ruleUnion,
+	reflect.TypeOf(option.RuleSet{}): {"type", []variant{
+		{[]any{"", C.RuleSetTypeInline}, "InlineOptions"},
+		{[]any{C.RuleSetTypeLocal}, "LocalOptions"},
+		{[]any{C.RuleSetTypeRemote}, "RemoteOptions"},
+	}},
+	reflect.TypeOf(option.ACMEDNS01ChallengeOptions{}): {"provider", []variant{
+		{[]any{C.DNSProviderAliDNS}, "AliDNSOptions"},
+		{[]any{C.DNSProviderCloudflare}, "CloudflareOptions"},
+	}},
+	reflect.TypeOf(option.PlainRuleSetCompat{}): {"version", []variant{
+		{[]any{C.RuleSetVersion1}, "Options"},
+		{[]any{C.RuleSetVersion2}, "Options"},
+		{[]any{C.RuleSetVersion3}, "Options"},
+	}},
+}
+
+var ruleUnion = union{"type", []variant{
+	{[]any{"", C.RuleTypeDefault}, "DefaultOptions"},
+	{[]any{C.RuleTypeLogical}, "LogicalOptions"},
+}}
+
+// proxyUnionTypes select their options with RawOptions, so variants are discovered from it.
+var proxyUnionTypes = []reflect.Type{
+	reflect.TypeOf(option.Inbound{}),
+	reflect.TypeOf(option.Outbound{}),
+}
+
+var proxyTypes = []string{
+	C.TypeTun,
+	C.TypeRedirect,
+	C.TypeTProxy,
+	C.TypeDirect,
+	C.TypeBlock,
+	C.TypeDNS,
+	C.TypeSOCKS,
+	C.TypeHTTP,
+	C.TypeMixed,
+	C.TypeShadowsocks,
+	C.TypeVMess,
+	C.TypeTrojan,
+	C.TypeNaive,
+	C.TypeWireGuard,
+	C.TypeHysteria,
+	C.TypeTor,
+	C.TypeSSH,
+	C.TypeShadowTLS,
+	C.TypeShadowsocksR,
+	C.TypeVLESS,
+	C.TypeTUIC,
+	C.TypeHysteria2,
+	C.TypeBond,
+	C.TypeSelector,
+	C.TypeURLTest,
+}
+
+type rawOptionsProvider interface {
+	RawOptions() (any, error)
+}
+
+func proxyUnion(t reflect.Type) (union, error) {
+	proxyUnion := union{key: "type"}
+	for _, proxyType := range proxyTypes {
+		value := reflect.New(t)
+		value.Elem().FieldByName("Type").SetString(proxyType)
+		rawOptions, err := value.Interface().(rawOptionsProvider).RawOptions()
+		if err != nil {
+			continue
+		}
+		var fieldName string
+		if rawOptions != nil {
+			for i := 0; i < t.NumField(); i++ {
+				if value.Elem().Field(i).Addr().Interface() == rawOptions {
+					fieldName = t.Field(i).Name
+					break
+				}
+			}
+			if fieldName == "" {
+				return union{}, E.New(t.Name(), ": options of type ", proxyType, " is not a field")
+			}
+		}
+		proxyUnion.variants = append(proxyUnion.variants, variant{[]any{proxyType}, fieldName})
+	}
+	return proxyUnion, nil
+}
+
+// unionSchema generates one variant for each value of the key, consisting of the common fields
+// and the fields of the selected options.
+func (g *generator) unionSchema(t reflect.Type, unionTable union) (*Schema, error) {
+	baseProperties, err := g.properties(t)
+	if err != nil {
+		return nil, err
+	}
+	mappedFields := make(map[string]bool)
+	schema := &Schema{}
+	for _, unionVariant := range unionTable.variants {
+		properties := make(map[string]*Schema, len(baseProperties))
+		for name, propertySchema := range baseProperties {
+			properties[name] = propertySchema
+		}
+		keySchema := &Schema{Enum: unionVariant.values}
+		if len(unionVariant.values) == 1 {
+			keySchema = &Schema{Const: unionVariant.values[0]}
+		}
+		properties[unionTable.key] = keySchema
+		if unionVariant.field != "" {
+			field, loaded := t.FieldByName(unionVariant.field)
+			if !loaded {
+				return nil, E.New("missing variant field ", unionVariant.field)
+			}
+			mappedFields[unionVariant.field] = true
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			variantProperties, err := g.properties(fieldType)
+			if err != nil {
+				return nil, E.Cause(err, unionVariant.field)
+			}
+			for name, propertySchema := range variantProperties {
+				if _, loaded = properties[name]; !loaded {
+					properties[name] = propertySchema
+				}
+			}
+		}
+		variantSchema := &Schema{Type: "object", Properties: properties, AdditionalProperties: false}
+		if !common.Contains(unionVariant.values, any("")) {
+			variantSchema.Required = []string{unionTable.key}
+		}
+		schema.OneOf = append(schema.OneOf, variantSchema)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("json") == "-" && field.Type.Kind() == reflect.Struct && !mappedFields[field.Name] {
+			return nil, E.New("variant field ", field.Name, " is not mapped to any ", unionTable.key)
+		}
+	}
+	return schema, nil
+}
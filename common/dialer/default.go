@@ -101,6 +101,9 @@ func NewDefault(router adapter.Router, options option.DialerOptions) (*DefaultDi
 		udpDialer4 = dialer
 		udpAddr4   string
 	)
+	if !options.Inet4BindAddress.IsIP() || !options.Inet6BindAddress.IsIP() {
+		return nil, E.New("bind address must be an IP address")
+	}
 	if options.Inet4BindAddress != nil {
 		bindAddr := options.Inet4BindAddress.Build()
 		dialer4.LocalAddr = &net.TCPAddr{IP: bindAddr.AsSlice()}
@@ -182,6 +185,12 @@ func (d *DefaultDialer) ListenPacketCompat(network, address string) (net.PacketC
 	return trackPacketConn(d.udpListener.ListenPacket(context.Background(), network, address))
 }
 
+// ListenControl returns the control function applied to listened sockets,
+// so that raw sockets can be bound the same way.
+func (d *DefaultDialer) ListenControl() control.Func {
+	return d.udpListener.Control
+}
+
 func trackConn(conn net.Conn, err error) (net.Conn, error) {
 	if !conntrack.Enabled || err != nil {
 		return conn, err
@@ -0,0 +1,321 @@
+package dialer
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common/bufio"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+)
+
+func NewServer(router adapter.Router, options option.DialerOptions, serverOptions option.ServerOptions) (N.Dialer, error) {
+	dialer, err := New(router, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(serverOptions.ServerAddresses) == 0 && serverOptions.ServerDiscovery == nil {
+		return dialer, nil
+	}
+	return NewServerDialer(router, dialer, serverOptions, time.Duration(options.FallbackDelay))
+}
+
+var _ N.Dialer = (*ServerDialer)(nil)
+
+// ServerDialer expands the configured server into multiple endpoints and dials them Happy-Eyeballs style,
+// preferring endpoints that have not failed recently.
+type ServerDialer struct {
+	dialer        N.Dialer
+	router        adapter.Router
+	server        M.Socksaddr
+	addresses     []M.Socksaddr
+	discoveryType string
+	discovery     *ServiceLookup
+	fallbackDelay time.Duration
+	access        sync.Mutex
+	failures      map[M.Socksaddr]time.Time
+}
+
+func NewServerDialer(router adapter.Router, dialer N.Dialer, options option.ServerOptions, fallbackDelay time.Duration) (*ServerDialer, error) {
+	if fallbackDelay == 0 {
+		fallbackDelay = N.DefaultFallbackDelay
+	}
+	server := options.Build()
+	serverDialer := &ServerDialer{
+		dialer:        dialer,
+		router:        router,
+		server:        server,
+		addresses:     []M.Socksaddr{server},
+		fallbackDelay: fallbackDelay,
+		failures:      make(map[M.Socksaddr]time.Time),
+	}
+	for _, addressString := range options.ServerAddresses {
+		address, err := parseServerAddress(addressString, options.ServerPort)
+		if err != nil {
+			return nil, E.Cause(err, "parse server address: ", addressString)
+		}
+		if !address.IsValid() {
+			return nil, E.New("invalid server address: ", addressString)
+		}
+		serverDialer.addresses = append(serverDialer.addresses, address)
+	}
+	if options.ServerDiscovery != nil {
+		if router == nil {
+			return nil, E.New("server discovery requires a router")
+		}
+		switch options.ServerDiscovery.Type {
+		case C.ServerDiscoverySRV, C.ServerDiscoveryHTTPS:
+		case "":
+			return nil, E.New("missing server discovery type")
+		default:
+			return nil, E.New("unknown server discovery type: ", options.ServerDiscovery.Type)
+		}
+		discoveryDomain := options.ServerDiscovery.Domain
+		if discoveryDomain == "" {
+			if !server.IsFqdn() {
+				return nil, E.New("missing server discovery domain")
+			}
+			discoveryDomain = server.Fqdn
+		}
+		serverDialer.discoveryType = options.ServerDiscovery.Type
+		serverDialer.discovery = NewServiceLookup(router, options.ServerDiscovery.Type, discoveryDomain)
+	}
+	return serverDialer, nil
+}
+
+func parseServerAddress(address string, defaultPort uint16) (M.Socksaddr, error) {
+	if addr, err := netip.ParseAddr(address); err == nil {
+		return M.SocksaddrFrom(addr, defaultPort), nil
+	}
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return M.ParseSocksaddrHostPort(address, defaultPort), nil
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return M.Socksaddr{}, E.Cause(err, "parse port")
+	}
+	return M.ParseSocksaddrHostPort(host, uint16(port)), nil
+}
+
+func (d *ServerDialer) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
+	if destination != d.server {
+		return d.dialer.DialContext(ctx, network, destination)
+	}
+	endpoints := d.endpoints(ctx)
+	if len(endpoints) == 1 {
+		conn, err := d.dialer.DialContext(ctx, network, endpoints[0].address)
+		if err != nil {
+			return nil, err
+		}
+		return endpoints[0].wrapConn(conn), nil
+	}
+	return d.dialParallel(ctx, network, endpoints)
+}
+
+func (d *ServerDialer) ListenPacket(ctx context.Context, destination M.Socksaddr) (net.PacketConn, error) {
+	if destination != d.server {
+		return d.dialer.ListenPacket(ctx, destination)
+	}
+	var errors []error
+	for _, endpoint := range d.endpoints(ctx) {
+		conn, err := d.dialer.ListenPacket(ctx, endpoint.address)
+		if err != nil {
+			d.markFailure(endpoint.address)
+			errors = append(errors, err)
+			continue
+		}
+		d.markSuccess(endpoint.address)
+		if endpoint.address == destination {
+			return conn, nil
+		}
+		return bufio.NewNATPacketConn(bufio.NewPacketConn(conn), endpoint.address, destination), nil
+	}
+	return nil, E.Errors(errors...)
+}
+
+func (d *ServerDialer) Upstream() any {
+	return d.dialer
+}
+
+type serverEndpoint struct {
+	address M.Socksaddr
+	// discovered is set for endpoints from HTTPS records, which carry their own ECH config
+	discovered bool
+	echConfig  []byte
+}
+
+func (e serverEndpoint) wrapConn(conn net.Conn) net.Conn {
+	if !e.discovered {
+		return conn
+	}
+	return &serverConn{Conn: conn, echConfig: e.echConfig}
+}
+
+// ECHConfigConn is implemented by connections dialed to an endpoint discovered from an HTTPS record,
+// the ECH client must use the config of that record instead of looking up the server name.
+type ECHConfigConn interface {
+	ECHConfig() []byte
+}
+
+type serverConn struct {
+	net.Conn
+	echConfig []byte
+}
+
+func (c *serverConn) ECHConfig() []byte {
+	return c.echConfig
+}
+
+func (c *serverConn) Upstream() any {
+	return c.Conn
+}
+
+func (c *serverConn) ReaderReplaceable() bool {
+	return true
+}
+
+func (c *serverConn) WriterReplaceable() bool {
+	return true
+}
+
+type serverDialResult struct {
+	conn     net.Conn
+	endpoint serverEndpoint
+	err      error
+}
+
+func (d *ServerDialer) dialParallel(ctx context.Context, network string, endpoints []serverEndpoint) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan serverDialResult)
+	startDial := func(endpoint serverEndpoint) {
+		go func() {
+			conn, err := d.dialer.DialContext(ctx, network, endpoint.address)
+			select {
+			case results <- serverDialResult{conn, endpoint, err}:
+			case <-ctx.Done():
+				if conn != nil {
+					conn.Close()
+				}
+			}
+		}()
+	}
+	var (
+		errors  []error
+		next    = 1
+		pending = 1
+	)
+	startDial(endpoints[0])
+	fallbackTimer := time.NewTimer(d.fallbackDelay)
+	defer fallbackTimer.Stop()
+	for {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				d.markSuccess(result.endpoint.address)
+				return result.endpoint.wrapConn(result.conn), nil
+			}
+			d.markFailure(result.endpoint.address)
+			errors = append(errors, result.err)
+			if next < len(endpoints) {
+				startDial(endpoints[next])
+				next++
+				pending++
+				if !fallbackTimer.Stop() {
+					select {
+					case <-fallbackTimer.C:
+					default:
+					}
+				}
+				fallbackTimer.Reset(d.fallbackDelay)
+			} else if pending == 0 {
+				return nil, E.Errors(errors...)
+			}
+		case <-fallbackTimer.C:
+			if next < len(endpoints) {
+				startDial(endpoints[next])
+				next++
+				pending++
+				fallbackTimer.Reset(d.fallbackDelay)
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (d *ServerDialer) endpoints(ctx context.Context) []serverEndpoint {
+	var endpoints []serverEndpoint
+	if d.discovery != nil {
+		records, err := d.discovery.Lookup(ctx)
+		// fall back to static addresses on discovery failure
+		if err == nil {
+			for _, record := range records {
+				for _, address := range record.Destinations(d.server.Port) {
+					endpoints = append(endpoints, serverEndpoint{
+						address:    address,
+						discovered: d.discoveryType == C.ServerDiscoveryHTTPS,
+						echConfig:  record.ECHConfig,
+					})
+				}
+			}
+		}
+	}
+	for _, address := range d.addresses {
+		if !containsEndpoint(endpoints, address) {
+			endpoints = append(endpoints, serverEndpoint{address: address})
+		}
+	}
+	return d.sortEndpoints(endpoints)
+}
+
+func (d *ServerDialer) sortEndpoints(endpoints []serverEndpoint) []serverEndpoint {
+	d.access.Lock()
+	defer d.access.Unlock()
+	now := time.Now()
+	available := make([]serverEndpoint, 0, len(endpoints))
+	var failed []serverEndpoint
+	for _, endpoint := range endpoints {
+		failedAt, loaded := d.failures[endpoint.address]
+		if loaded && now.Sub(failedAt) < C.ServerFailureTimeout {
+			failed = append(failed, endpoint)
+		} else {
+			if loaded {
+				delete(d.failures, endpoint.address)
+			}
+			available = append(available, endpoint)
+		}
+	}
+	return append(available, failed...)
+}
+
+func (d *ServerDialer) markFailure(endpoint M.Socksaddr) {
+	d.access.Lock()
+	defer d.access.Unlock()
+	d.failures[endpoint] = time.Now()
+}
+
+func (d *ServerDialer) markSuccess(endpoint M.Socksaddr) {
+	d.access.Lock()
+	defer d.access.Unlock()
+	delete(d.failures, endpoint)
+}
+
+func containsEndpoint(endpoints []serverEndpoint, address M.Socksaddr) bool {
+	for _, it := range endpoints {
+		if it.address == address {
+			return true
+		}
+	}
+	return false
+}
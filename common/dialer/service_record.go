@@ -0,0 +1,313 @@
+package dialer
+
+import (
+	"context"
+	"math/rand"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-dns"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+
+	mDNS "github.com/miekg/dns"
+)
+
+const maxAliasDepth = 8
+
+type ServiceRecord struct {
+	Priority  uint16
+	Weight    uint16
+	Target    string
+	Port      uint16
+	Addresses []netip.Addr
+	ECHConfig []byte
+	TTL       uint32
+}
+
+func (r ServiceRecord) Destinations(defaultPort uint16) []M.Socksaddr {
+	port := r.Port
+	if port == 0 {
+		port = defaultPort
+	}
+	if len(r.Addresses) > 0 {
+		destinations := make([]M.Socksaddr, 0, len(r.Addresses))
+		for _, address := range r.Addresses {
+			destinations = append(destinations, M.SocksaddrFrom(address, port))
+		}
+		return destinations
+	}
+	return []M.Socksaddr{M.ParseSocksaddrHostPort(r.Target, port)}
+}
+
+func LookupSRV(ctx context.Context, router adapter.Router, domain string) ([]ServiceRecord, error) {
+	answer, err := exchangeRecord(ctx, router, domain, mDNS.TypeSRV)
+	if err != nil {
+		return nil, err
+	}
+	var records []ServiceRecord
+	for _, rr := range answer {
+		srv, isSRV := rr.(*mDNS.SRV)
+		if !isSRV {
+			continue
+		}
+		target := mDNS.CanonicalName(srv.Target)
+		if target == "." {
+			// RFC 2782: the service is decidedly not available at this domain
+			continue
+		}
+		records = append(records, ServiceRecord{
+			Priority: srv.Priority,
+			Weight:   srv.Weight,
+			Target:   target[:len(target)-1],
+			Port:     srv.Port,
+			TTL:      srv.Hdr.Ttl,
+		})
+	}
+	if len(records) == 0 {
+		return nil, E.New("no SRV record found for ", domain)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Priority < records[j].Priority
+	})
+	return records, nil
+}
+
+// ShuffleSRV orders records sorted by priority with the weighted random selection of RFC 2782 within each priority.
+func ShuffleSRV(records []ServiceRecord) []ServiceRecord {
+	shuffled := make([]ServiceRecord, 0, len(records))
+	for start := 0; start < len(records); {
+		end := start + 1
+		for end < len(records) && records[end].Priority == records[start].Priority {
+			end++
+		}
+		shuffled = append(shuffled, shuffleByWeight(records[start:end])...)
+		start = end
+	}
+	return shuffled
+}
+
+func shuffleByWeight(records []ServiceRecord) []ServiceRecord {
+	remaining := make([]ServiceRecord, 0, len(records))
+	// records with weight 0 are placed first so they have a very small chance of being selected
+	for _, record := range records {
+		if record.Weight == 0 {
+			remaining = append(remaining, record)
+		}
+	}
+	var sum int
+	for _, record := range records {
+		if record.Weight > 0 {
+			remaining = append(remaining, record)
+			sum += int(record.Weight)
+		}
+	}
+	shuffled := make([]ServiceRecord, 0, len(records))
+	for len(remaining) > 0 {
+		var (
+			target   = rand.Intn(sum + 1)
+			running  int
+			selected int
+		)
+		for i, record := range remaining {
+			running += int(record.Weight)
+			if running >= target {
+				selected = i
+				break
+			}
+		}
+		sum -= int(remaining[selected].Weight)
+		shuffled = append(shuffled, remaining[selected])
+		remaining = append(remaining[:selected], remaining[selected+1:]...)
+	}
+	return shuffled
+}
+
+func LookupHTTPS(ctx context.Context, router adapter.Router, domain string) ([]ServiceRecord, error) {
+	name := domain
+	for depth := 0; depth < maxAliasDepth; depth++ {
+		answer, err := exchangeRecord(ctx, router, name, mDNS.TypeHTTPS)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			records   []ServiceRecord
+			aliasName string
+		)
+		for _, rr := range answer {
+			https, isHTTPS := rr.(*mDNS.HTTPS)
+			if !isHTTPS {
+				continue
+			}
+			target := mDNS.CanonicalName(https.Target)
+			if target == "." {
+				target = name
+			} else {
+				target = target[:len(target)-1]
+			}
+			if https.Priority == 0 {
+				aliasName = target
+				continue
+			}
+			record := ServiceRecord{
+				Priority: https.Priority,
+				Target:   target,
+				TTL:      https.Hdr.Ttl,
+			}
+			for _, value := range https.Value {
+				switch param := value.(type) {
+				case *mDNS.SVCBPort:
+					record.Port = param.Port
+				case *mDNS.SVCBIPv4Hint:
+					for _, ip := range param.Hint {
+						if address, ok := netip.AddrFromSlice(ip); ok {
+							record.Addresses = append(record.Addresses, address.Unmap())
+						}
+					}
+				case *mDNS.SVCBIPv6Hint:
+					for _, ip := range param.Hint {
+						if address, ok := netip.AddrFromSlice(ip); ok {
+							record.Addresses = append(record.Addresses, address)
+						}
+					}
+				case *mDNS.SVCBECHConfig:
+					record.ECHConfig = param.ECH
+				}
+			}
+			records = append(records, record)
+		}
+		if len(records) > 0 {
+			sort.SliceStable(records, func(i, j int) bool {
+				return records[i].Priority < records[j].Priority
+			})
+			return records, nil
+		}
+		if aliasName == "" || aliasName == name {
+			break
+		}
+		name = aliasName
+	}
+	return nil, E.New("no HTTPS record found for ", domain)
+}
+
+func exchangeRecord(ctx context.Context, router adapter.Router, domain string, recordType uint16) ([]mDNS.RR, error) {
+	message := &mDNS.Msg{
+		MsgHdr: mDNS.MsgHdr{
+			RecursionDesired: true,
+		},
+		Question: []mDNS.Question{
+			{
+				Name:   mDNS.Fqdn(domain),
+				Qtype:  recordType,
+				Qclass: mDNS.ClassINET,
+			},
+		},
+	}
+	response, err := router.Exchange(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	if response.Rcode != mDNS.RcodeSuccess {
+		return nil, dns.RCodeError(response.Rcode)
+	}
+	return response.Answer, nil
+}
+
+// ServiceLookup caches discovered service records until the shortest record TTL expires,
+// so a lookup does not run a DNS exchange on every dial. Failed lookups are not cached.
+type ServiceLookup struct {
+	router     adapter.Router
+	recordType string
+	domain     string
+	access     sync.Mutex
+	records    []ServiceRecord
+	expire     time.Time
+	pending    *serviceLookupTask
+}
+
+type serviceLookupTask struct {
+	done    chan struct{}
+	records []ServiceRecord
+	err     error
+}
+
+func NewServiceLookup(router adapter.Router, recordType string, domain string) *ServiceLookup {
+	return &ServiceLookup{
+		router:     router,
+		recordType: recordType,
+		domain:     domain,
+	}
+}
+
+// Lookup returns the cached records, or waits for a lookup shared by all concurrent callers.
+// The exchange is not bound to ctx, so a cancelled caller does not fail the others.
+func (l *ServiceLookup) Lookup(ctx context.Context) ([]ServiceRecord, error) {
+	l.access.Lock()
+	if time.Now().Before(l.expire) {
+		records := l.records
+		l.access.Unlock()
+		return l.result(records), nil
+	}
+	task := l.pending
+	if task == nil {
+		task = &serviceLookupTask{done: make(chan struct{})}
+		l.pending = task
+		go l.exchange(task)
+	}
+	l.access.Unlock()
+	select {
+	case <-task.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if task.err != nil {
+		return nil, task.err
+	}
+	return l.result(task.records), nil
+}
+
+func (l *ServiceLookup) exchange(task *serviceLookupTask) {
+	ctx, cancel := context.WithTimeout(context.Background(), C.DNSTimeout)
+	defer cancel()
+	switch l.recordType {
+	case C.ServerDiscoverySRV:
+		task.records, task.err = LookupSRV(ctx, l.router, l.domain)
+	case C.ServerDiscoveryHTTPS:
+		task.records, task.err = LookupHTTPS(ctx, l.router, l.domain)
+	default:
+		task.err = E.New("unknown server discovery type: ", l.recordType)
+	}
+	l.access.Lock()
+	if task.err == nil {
+		l.records = task.records
+		l.expire = time.Now().Add(recordsTTL(task.records))
+	}
+	l.pending = nil
+	l.access.Unlock()
+	close(task.done)
+}
+
+func (l *ServiceLookup) result(records []ServiceRecord) []ServiceRecord {
+	if l.recordType == C.ServerDiscoverySRV {
+		return ShuffleSRV(records)
+	}
+	return records
+}
+
+func recordsTTL(records []ServiceRecord) time.Duration {
+	ttl := C.ServerDiscoveryMaxTTL
+	for _, record := range records {
+		recordTTL := time.Duration(record.TTL) * time.Second
+		if recordTTL < ttl {
+			ttl = recordTTL
+		}
+	}
+	if ttl < C.ServerDiscoveryMinTTL {
+		ttl = C.ServerDiscoveryMinTTL
+	}
+	return ttl
+}
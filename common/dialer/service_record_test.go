@@ -0,0 +1,168 @@
+package dialer
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+
+	mDNS "github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+type testRouter struct {
+	adapter.Router
+	answer    []mDNS.RR
+	err       error
+	exchanges int
+}
+
+func (r *testRouter) Exchange(ctx context.Context, message *mDNS.Msg) (*mDNS.Msg, error) {
+	r.exchanges++
+	if r.err != nil {
+		return nil, r.err
+	}
+	response := new(mDNS.Msg)
+	response.SetReply(message)
+	for _, rr := range r.answer {
+		if rr.Header().Rrtype == message.Question[0].Qtype {
+			response.Answer = append(response.Answer, rr)
+		}
+	}
+	return response, nil
+}
+
+type testDialer struct {
+	N.Dialer
+	dialed []M.Socksaddr
+}
+
+func (d *testDialer) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
+	d.dialed = append(d.dialed, destination)
+	conn, _ := net.Pipe()
+	return conn, nil
+}
+
+func newTestSRV(priority uint16, weight uint16, target string) *mDNS.SRV {
+	return &mDNS.SRV{
+		Hdr:      mDNS.RR_Header{Name: "_proxy._tcp.example.com.", Rrtype: mDNS.TypeSRV, Class: mDNS.ClassINET, Ttl: 300},
+		Priority: priority,
+		Weight:   weight,
+		Port:     443,
+		Target:   target,
+	}
+}
+
+func newTestHTTPS(priority uint16, address string, echConfig []byte) *mDNS.HTTPS {
+	record := &mDNS.HTTPS{SVCB: mDNS.SVCB{
+		Hdr:      mDNS.RR_Header{Name: "example.com.", Rrtype: mDNS.TypeHTTPS, Class: mDNS.ClassINET, Ttl: 300},
+		Priority: priority,
+		Target:   ".",
+		Value: []mDNS.SVCBKeyValue{
+			&mDNS.SVCBIPv4Hint{Hint: []net.IP{net.ParseIP(address)}},
+		},
+	}}
+	if echConfig != nil {
+		record.Value = append(record.Value, &mDNS.SVCBECHConfig{ECH: echConfig})
+	}
+	return record
+}
+
+func TestShuffleSRV(t *testing.T) {
+	t.Parallel()
+	records := []ServiceRecord{
+		{Priority: 1, Weight: 90, Target: "a"},
+		{Priority: 1, Weight: 10, Target: "b"},
+		{Priority: 1, Weight: 0, Target: "c"},
+		{Priority: 2, Weight: 50, Target: "d"},
+	}
+	first := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		shuffled := ShuffleSRV(records)
+		require.Len(t, shuffled, len(records))
+		require.Equal(t, "d", shuffled[3].Target)
+		first[shuffled[0].Target]++
+	}
+	require.Greater(t, first["a"], first["b"])
+	require.Greater(t, first["b"], 0)
+	require.Less(t, first["c"], first["b"])
+}
+
+func TestServiceLookupCache(t *testing.T) {
+	t.Parallel()
+	router := &testRouter{answer: []mDNS.RR{
+		newTestSRV(2, 0, "backup.example.com."),
+		newTestSRV(1, 10, "primary.example.com."),
+		newTestSRV(1, 0, "."),
+	}}
+	lookup := NewServiceLookup(router, C.ServerDiscoverySRV, "_proxy._tcp.example.com")
+	for i := 0; i < 3; i++ {
+		records, err := lookup.Lookup(context.Background())
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		require.Equal(t, "primary.example.com", records[0].Target)
+		require.Equal(t, "backup.example.com", records[1].Target)
+	}
+	require.Equal(t, 1, router.exchanges)
+}
+
+func TestServiceLookupError(t *testing.T) {
+	t.Parallel()
+	router := &testRouter{err: os.ErrDeadlineExceeded}
+	lookup := NewServiceLookup(router, C.ServerDiscoverySRV, "_proxy._tcp.example.com")
+	_, err := lookup.Lookup(context.Background())
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	router.err = nil
+	router.answer = []mDNS.RR{newTestSRV(1, 0, "primary.example.com.")}
+	records, err := lookup.Lookup(context.Background())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, 2, router.exchanges)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	lookup = NewServiceLookup(router, C.ServerDiscoverySRV, "_proxy._tcp.example.com")
+	_, err = lookup.Lookup(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestServerDialerECHConfig(t *testing.T) {
+	t.Parallel()
+	router := &testRouter{answer: []mDNS.RR{
+		newTestHTTPS(1, "192.0.2.1", []byte("config")),
+		newTestHTTPS(2, "192.0.2.2", nil),
+	}}
+	serverOptions := option.ServerOptions{
+		Server:          "example.com",
+		ServerPort:      443,
+		ServerDiscovery: &option.ServerDiscoveryOptions{Type: C.ServerDiscoveryHTTPS},
+	}
+	upstream := &testDialer{}
+	serverDialer, err := NewServerDialer(router, upstream, serverOptions, 0)
+	require.NoError(t, err)
+	server := serverOptions.Build()
+	conn, err := serverDialer.DialContext(context.Background(), N.NetworkTCP, server)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, M.SocksaddrFrom(netip.MustParseAddr("192.0.2.1"), 443), upstream.dialed[0])
+	echConn, isECHConn := conn.(ECHConfigConn)
+	require.True(t, isECHConn)
+	require.Equal(t, []byte("config"), echConn.ECHConfig())
+
+	serverDialer.markFailure(upstream.dialed[0])
+	conn, err = serverDialer.DialContext(context.Background(), N.NetworkTCP, server)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, M.SocksaddrFrom(netip.MustParseAddr("192.0.2.2"), 443), upstream.dialed[1])
+	echConn, isECHConn = conn.(ECHConfigConn)
+	require.True(t, isECHConn)
+	require.Empty(t, echConn.ECHConfig())
+	require.Equal(t, 1, router.exchanges)
+}
@@ -0,0 +1,277 @@
+package directive
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/json"
+	"github.com/sagernet/sing/common/json/badjson"
+)
+
+const (
+	KeyFile    = "$file"
+	KeyInclude = "$include"
+)
+
+// Contains reports whether the configuration may use directives,
+// so that configurations without them can be processed as is.
+func Contains(content []byte) bool {
+	return bytes.Contains(content, []byte("${")) ||
+		bytes.Contains(content, []byte(`"`+KeyFile+`"`)) ||
+		bytes.Contains(content, []byte(`"`+KeyInclude+`"`))
+}
+
+// Resolve expands environment variables in string values, replaces $file objects with the content of the file,
+// and replaces $include objects with the included configuration fragments.
+// Relative paths are resolved against the directory of the configuration at path.
+func Resolve(content []byte, path string) ([]byte, error) {
+	if !Contains(content) {
+		return content, nil
+	}
+	value, err := Decode(content)
+	if err != nil {
+		return nil, err
+	}
+	r := &resolver{lookupEnv: os.LookupEnv}
+	var directory string
+	if path != "" && path != "stdin" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		r.stack = []string{absPath}
+		directory = filepath.Dir(absPath)
+	}
+	value, err = r.resolve(value, directory)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// Decode decodes a configuration with comments while preserving the order of object keys.
+func Decode(content []byte) (any, error) {
+	filteredContent, err := io.ReadAll(json.NewCommentFilter(bytes.NewReader(content)))
+	if err != nil {
+		return nil, err
+	}
+	return badjson.Decode(filteredContent)
+}
+
+type resolver struct {
+	lookupEnv func(key string) (string, bool)
+	stack     []string
+}
+
+func (r *resolver) resolve(value any, directory string) (any, error) {
+	switch typedValue := value.(type) {
+	case string:
+		return expandEnv(typedValue, r.lookupEnv), nil
+	case badjson.JSONArray:
+		var array badjson.JSONArray
+		for i, element := range typedValue {
+			if object, isObject := element.(*badjson.JSONObject); isObject && object.Size() == 1 && object.ContainsKey(KeyInclude) {
+				// fragments included into an array are spliced into it
+				includedValues, err := r.include(object, directory)
+				if err != nil {
+					return nil, E.Cause(err, "[", i, "]")
+				}
+				for _, includedValue := range includedValues {
+					if includedArray, isArray := includedValue.(badjson.JSONArray); isArray {
+						array = append(array, includedArray...)
+					} else {
+						array = append(array, includedValue)
+					}
+				}
+				continue
+			}
+			resolvedElement, err := r.resolve(element, directory)
+			if err != nil {
+				return nil, E.Cause(err, "[", i, "]")
+			}
+			array = append(array, resolvedElement)
+		}
+		return array, nil
+	case *badjson.JSONObject:
+		if filePath, loaded := typedValue.Get(KeyFile); loaded {
+			if typedValue.Size() != 1 {
+				return nil, E.New(KeyFile, " must be the only key of the object")
+			}
+			return r.readFile(filePath, directory)
+		}
+		var object badjson.JSONObject
+		if typedValue.ContainsKey(KeyInclude) {
+			includedValues, err := r.include(typedValue, directory)
+			if err != nil {
+				return nil, err
+			}
+			if typedValue.Size() == 1 {
+				return joinIncluded(includedValues)
+			}
+			// keys next to $include override keys of the included objects
+			for _, includedValue := range includedValues {
+				includedObject, isObject := includedValue.(*badjson.JSONObject)
+				if !isObject {
+					return nil, E.New("included fragment must be an object when merged with other keys")
+				}
+				object.PutAll(&includedObject.Map)
+			}
+		}
+		for _, entry := range typedValue.Entries() {
+			if entry.Key == KeyInclude {
+				continue
+			}
+			resolvedValue, err := r.resolve(entry.Value, directory)
+			if err != nil {
+				return nil, E.Cause(err, entry.Key)
+			}
+			object.Put(entry.Key, resolvedValue)
+		}
+		return &object, nil
+	default:
+		return value, nil
+	}
+}
+
+func (r *resolver) readFile(rawPath any, directory string) (string, error) {
+	filePath, err := r.resolvePath(rawPath, directory)
+	if err != nil {
+		return "", E.Cause(err, KeyFile)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", E.Cause(err, KeyFile)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func (r *resolver) include(object *badjson.JSONObject, directory string) ([]any, error) {
+	rawPaths, _ := object.Get(KeyInclude)
+	var pathList []any
+	switch paths := rawPaths.(type) {
+	case badjson.JSONArray:
+		pathList = paths
+	default:
+		pathList = []any{paths}
+	}
+	var values []any
+	for _, rawPath := range pathList {
+		includePath, err := r.resolvePath(rawPath, directory)
+		if err != nil {
+			return nil, E.Cause(err, KeyInclude)
+		}
+		if common.Contains(r.stack, includePath) {
+			return nil, E.New(KeyInclude, ": circular include: ", strings.Join(append(r.stack, includePath), " -> "))
+		}
+		content, err := os.ReadFile(includePath)
+		if err != nil {
+			return nil, E.Cause(err, KeyInclude)
+		}
+		value, err := Decode(content)
+		if err != nil {
+			return nil, E.Cause(err, "decode ", includePath)
+		}
+		r.stack = append(r.stack, includePath)
+		value, err = r.resolve(value, filepath.Dir(includePath))
+		r.stack = r.stack[:len(r.stack)-1]
+		if err != nil {
+			return nil, E.Cause(err, "resolve ", includePath)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (r *resolver) resolvePath(rawPath any, directory string) (string, error) {
+	path, isString := rawPath.(string)
+	if !isString || path == "" {
+		return "", E.New("path must be a non-empty string")
+	}
+	path = expandEnv(path, r.lookupEnv)
+	if !filepath.IsAbs(path) && directory != "" {
+		path = filepath.Join(directory, path)
+	}
+	return filepath.Abs(path)
+}
+
+// joinIncluded concatenates included arrays or merges included objects.
+func joinIncluded(values []any) (any, error) {
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	switch values[0].(type) {
+	case badjson.JSONArray:
+		var array badjson.JSONArray
+		for _, value := range values {
+			includedArray, isArray := value.(badjson.JSONArray)
+			if !isArray {
+				return nil, E.New("included fragments must be all arrays or all objects")
+			}
+			array = append(array, includedArray...)
+		}
+		return array, nil
+	case *badjson.JSONObject:
+		var object badjson.JSONObject
+		for _, value := range values {
+			includedObject, isObject := value.(*badjson.JSONObject)
+			if !isObject {
+				return nil, E.New("included fragments must be all arrays or all objects")
+			}
+			object.PutAll(&includedObject.Map)
+		}
+		return &object, nil
+	default:
+		return nil, E.New("included fragments must be all arrays or all objects")
+	}
+}
+
+// expandEnv replaces ${NAME} and ${NAME:-default} with the value of the environment variable,
+// $${ is kept as a literal ${. References to unset variables without a default are kept unchanged,
+// so that existing configurations containing ${ in string values are not altered.
+func expandEnv(value string, lookupEnv func(key string) (string, bool)) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+	var builder strings.Builder
+	for {
+		index := strings.Index(value, "${")
+		if index == -1 {
+			builder.WriteString(value)
+			break
+		}
+		if index > 0 && value[index-1] == '$' {
+			builder.WriteString(value[:index-1])
+			builder.WriteString("${")
+			value = value[index+2:]
+			continue
+		}
+		end := strings.IndexByte(value[index:], '}')
+		if end == -1 {
+			builder.WriteString(value)
+			break
+		}
+		builder.WriteString(value[:index])
+		reference := value[index : index+end+1]
+		value = value[index+end+1:]
+		name, defaultValue, hasDefault := strings.Cut(reference[2:len(reference)-1], ":-")
+		if name == "" {
+			builder.WriteString(reference)
+			continue
+		}
+		envValue, loaded := lookupEnv(name)
+		if !loaded || (hasDefault && envValue == "") {
+			if !hasDefault {
+				builder.WriteString(reference)
+				continue
+			}
+			envValue = defaultValue
+		}
+		builder.WriteString(envValue)
+	}
+	return builder.String()
+}
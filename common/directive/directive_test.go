@@ -0,0 +1,62 @@
+package directive_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sagernet/sing-box/common/directive"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("DIRECTIVE_TEST_PORT", "8388")
+	t.Setenv("DIRECTIVE_TEST_EMPTY", "")
+	directory := t.TempDir()
+	writeFile(t, filepath.Join(directory, "secrets", "password"), "hunter2\n")
+	writeFile(t, filepath.Join(directory, "fragments", "outbounds.json"), `
+// outbounds shared between configurations
+[
+  {"type": "direct", "tag": "direct"},
+  {"type": "shadowsocks", "tag": "ss", "password": {"$file": "../secrets/password"}}
+]`)
+	writeFile(t, filepath.Join(directory, "fragments", "log.json"), `{"level": "info", "timestamp": true}`)
+	configPath := filepath.Join(directory, "config.json")
+	content, err := directive.Resolve([]byte(`{
+  "log": {"$include": "fragments/log.json", "level": "debug"},
+  "outbounds": [
+    {"type": "block", "tag": "block"},
+    {"$include": "fragments/outbounds.json"}
+  ],
+  "route": {"final": "${DIRECTIVE_TEST_MISSING:-ss}", "rules": [{"port_range": "${DIRECTIVE_TEST_PORT}:$${X}", "outbound": "${DIRECTIVE_TEST_EMPTY:-direct}"}]}
+}`), configPath)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "log": {"level": "debug", "timestamp": true},
+  "outbounds": [
+    {"type": "block", "tag": "block"},
+    {"type": "direct", "tag": "direct"},
+    {"type": "shadowsocks", "tag": "ss", "password": "hunter2"}
+  ],
+  "route": {"final": "ss", "rules": [{"port_range": "8388:${X}", "outbound": "direct"}]}
+}`, string(content))
+
+	content, err = directive.Resolve([]byte(`{"route": {"rules": [{"domain_regex": "^a${DIRECTIVE_TEST_MISSING}${}${b$"}]}}`), configPath)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"route": {"rules": [{"domain_regex": "^a${DIRECTIVE_TEST_MISSING}${}${b$"}]}}`, string(content))
+
+	writeFile(t, filepath.Join(directory, "loop.json"), `{"log": {"$include": "config.json"}}`)
+	_, err = directive.Resolve([]byte(`{"log": {"$include": "loop.json"}}`), configPath)
+	require.ErrorContains(t, err, "circular include")
+
+	plainContent := []byte(`{"log": {"level": "info"}}`)
+	content, err = directive.Resolve(plainContent, configPath)
+	require.NoError(t, err)
+	require.Equal(t, plainContent, content)
+}
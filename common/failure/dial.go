@@ -0,0 +1,22 @@
+package failure
+
+import (
+	"context"
+)
+
+type statisticsKey struct{}
+
+// ContextWithStatistics enables recording the dial result of the routed connection.
+func ContextWithStatistics(ctx context.Context, statistics *Statistics) context.Context {
+	return context.WithValue(ctx, (*statisticsKey)(nil), statistics)
+}
+
+// RecordDial counts the result of dialing the outbound for the routed connection, including the protocol handshake.
+// Errors after the connection is established are not counted.
+func RecordDial(ctx context.Context, outbound string, err error) {
+	statistics, loaded := ctx.Value((*statisticsKey)(nil)).(*Statistics)
+	if !loaded {
+		return
+	}
+	statistics.Record(outbound, err)
+}
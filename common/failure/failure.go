@@ -0,0 +1,116 @@
+package failure
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/sagernet/sing-dns"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+const (
+	CategoryDNS         = "dns"
+	CategoryRefused     = "refused"
+	CategoryUnreachable = "unreachable"
+	CategoryReset       = "reset"
+	CategoryTimeout     = "timeout"
+	CategoryTLS         = "tls"
+	CategoryAuth        = "auth"
+	CategoryOther       = "other"
+)
+
+var Categories = []string{
+	CategoryDNS,
+	CategoryRefused,
+	CategoryUnreachable,
+	CategoryReset,
+	CategoryTimeout,
+	CategoryTLS,
+	CategoryAuth,
+	CategoryOther,
+}
+
+type categoryError struct {
+	category string
+	cause    error
+}
+
+func (e *categoryError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *categoryError) Unwrap() error {
+	return e.cause
+}
+
+// DNSError marks err as a failure to resolve the destination.
+func DNSError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &categoryError{CategoryDNS, err}
+}
+
+// TLSError marks err as a failure of the TLS handshake.
+func TLSError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &categoryError{CategoryTLS, err}
+}
+
+// AuthError marks err as a rejection of the credentials by the server.
+func AuthError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &categoryError{CategoryAuth, err}
+}
+
+// Classify returns the failure category of the error a connection finished with,
+// or an empty string if the connection was closed normally.
+func Classify(err error) string {
+	if err == nil {
+		return ""
+	}
+	var marked *categoryError
+	if errors.As(err, &marked) && marked.category == CategoryDNS {
+		return CategoryDNS
+	}
+	switch {
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return CategoryReset
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return CategoryRefused
+	case errors.Is(err, syscall.ENETUNREACH), errors.Is(err, syscall.EHOSTUNREACH):
+		return CategoryUnreachable
+	case E.IsTimeout(err), errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return CategoryTimeout
+	}
+	if marked != nil {
+		return marked.category
+	}
+	var (
+		dnsError   *net.DNSError
+		rcodeError dns.RCodeError
+	)
+	if errors.As(err, &dnsError) || errors.As(err, &rcodeError) {
+		return CategoryDNS
+	}
+	var (
+		unknownAuthorityError x509.UnknownAuthorityError
+		hostnameError         x509.HostnameError
+		invalidError          x509.CertificateInvalidError
+	)
+	if errors.As(err, &unknownAuthorityError) || errors.As(err, &hostnameError) || errors.As(err, &invalidError) {
+		return CategoryTLS
+	}
+	if E.IsClosedOrCanceled(err) {
+		return ""
+	}
+	return CategoryOther
+}
@@ -0,0 +1,80 @@
+package failure
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+	for _, testCase := range []struct {
+		err      error
+		category string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{net.ErrClosed, ""},
+		{context.Canceled, ""},
+		{E.Cause(syscall.ECONNREFUSED, "dial tcp"), CategoryRefused},
+		{&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, CategoryReset},
+		{E.Cause(syscall.EHOSTUNREACH, "dial tcp"), CategoryUnreachable},
+		{os.ErrDeadlineExceeded, CategoryTimeout},
+		{DNSError(E.New("no such host")), CategoryDNS},
+		{DNSError(context.DeadlineExceeded), CategoryDNS},
+		{TLSError(E.New("bad record MAC")), CategoryTLS},
+		{TLSError(syscall.ECONNRESET), CategoryReset},
+		{E.New("socks5: authentication failed"), CategoryOther},
+		{AuthError(E.New("bond: authentication failed")), CategoryAuth},
+		{E.New("unexpected response"), CategoryOther},
+	} {
+		require.Equal(t, testCase.category, Classify(testCase.err), testCase.err)
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	t.Parallel()
+	statistics := NewStatistics()
+	var failures []uint64
+	statistics.RegisterCallback(func(outbound string, consecutiveFailures uint64) {
+		require.Equal(t, "proxy", outbound)
+		failures = append(failures, consecutiveFailures)
+	})
+	statistics.Record("proxy", syscall.ECONNREFUSED)
+	statistics.Record("proxy", os.ErrDeadlineExceeded)
+	statistics.Record("proxy", nil)
+	statistics.Record("proxy", syscall.ECONNREFUSED)
+	require.Equal(t, []uint64{1, 2, 1}, failures)
+	outbound := statistics.Load("proxy")
+	require.NotNil(t, outbound)
+	require.Equal(t, uint64(1), outbound.Success)
+	require.Equal(t, uint64(2), outbound.Failures[CategoryRefused])
+	require.Equal(t, uint64(1), outbound.Failures[CategoryTimeout])
+	require.Equal(t, uint64(1), outbound.ConsecutiveFailures)
+	require.Equal(t, CategoryRefused, outbound.LastFailure)
+	statistics.Reset()
+	require.Nil(t, statistics.Load("proxy"))
+}
+
+func TestRecordDial(t *testing.T) {
+	t.Parallel()
+	statistics := NewStatistics()
+	RecordDial(context.Background(), "proxy", syscall.ECONNREFUSED)
+	require.Nil(t, statistics.Load("proxy"))
+	ctx := ContextWithStatistics(context.Background(), statistics)
+	RecordDial(ctx, "proxy", syscall.ECONNREFUSED)
+	RecordDial(ctx, "proxy", context.Canceled)
+	RecordDial(ctx, "proxy", nil)
+	outbound := statistics.Load("proxy")
+	require.NotNil(t, outbound)
+	require.Equal(t, uint64(1), outbound.Success)
+	require.Equal(t, uint64(1), outbound.Failures[CategoryRefused])
+	require.Equal(t, uint64(0), outbound.ConsecutiveFailures)
+}
@@ -0,0 +1,113 @@
+package failure
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing/common/x/list"
+)
+
+type OutboundStatistics struct {
+	Success             uint64            `json:"success"`
+	Failures            map[string]uint64 `json:"failures"`
+	ConsecutiveFailures uint64            `json:"consecutiveFailures"`
+	LastFailure         string            `json:"lastFailure,omitempty"`
+	LastError           string            `json:"lastError,omitempty"`
+	LastFailureAt       time.Time         `json:"lastFailureAt"`
+}
+
+// FailureCallback is called with the outbound and its consecutive failures after each failure.
+type FailureCallback = func(outbound string, consecutiveFailures uint64)
+
+// Statistics counts connection results per outbound.
+type Statistics struct {
+	access    sync.Mutex
+	outbounds map[string]*OutboundStatistics
+	callbacks list.List[FailureCallback]
+}
+
+func NewStatistics() *Statistics {
+	return &Statistics{
+		outbounds: make(map[string]*OutboundStatistics),
+	}
+}
+
+// Record counts the result of a connection to the outbound and returns its failure category.
+// Canceled connections are not counted.
+func (s *Statistics) Record(outbound string, err error) string {
+	category := Classify(err)
+	if err != nil && category == "" {
+		return ""
+	}
+	s.access.Lock()
+	statistics := s.outbounds[outbound]
+	if statistics == nil {
+		statistics = &OutboundStatistics{Failures: make(map[string]uint64)}
+		s.outbounds[outbound] = statistics
+	}
+	if category == "" {
+		statistics.Success++
+		statistics.ConsecutiveFailures = 0
+		s.access.Unlock()
+		return ""
+	}
+	statistics.Failures[category]++
+	statistics.ConsecutiveFailures++
+	statistics.LastFailure = category
+	statistics.LastError = err.Error()
+	statistics.LastFailureAt = time.Now()
+	consecutiveFailures := statistics.ConsecutiveFailures
+	callbacks := s.callbacks.Array()
+	s.access.Unlock()
+	for _, callback := range callbacks {
+		callback(outbound, consecutiveFailures)
+	}
+	return category
+}
+
+func (s *Statistics) Load(outbound string) *OutboundStatistics {
+	s.access.Lock()
+	defer s.access.Unlock()
+	statistics := s.outbounds[outbound]
+	if statistics == nil {
+		return nil
+	}
+	return statistics.clone()
+}
+
+func (s *Statistics) All() map[string]*OutboundStatistics {
+	s.access.Lock()
+	defer s.access.Unlock()
+	outbounds := make(map[string]*OutboundStatistics, len(s.outbounds))
+	for outbound, statistics := range s.outbounds {
+		outbounds[outbound] = statistics.clone()
+	}
+	return outbounds
+}
+
+func (s *Statistics) Reset() {
+	s.access.Lock()
+	s.outbounds = make(map[string]*OutboundStatistics)
+	s.access.Unlock()
+}
+
+func (s *Statistics) RegisterCallback(callback FailureCallback) *list.Element[FailureCallback] {
+	s.access.Lock()
+	defer s.access.Unlock()
+	return s.callbacks.PushBack(callback)
+}
+
+func (s *Statistics) UnregisterCallback(element *list.Element[FailureCallback]) {
+	s.access.Lock()
+	defer s.access.Unlock()
+	s.callbacks.Remove(element)
+}
+
+func (s *OutboundStatistics) clone() *OutboundStatistics {
+	statistics := *s
+	statistics.Failures = make(map[string]uint64, len(s.Failures))
+	for category, count := range s.Failures {
+		statistics.Failures[category] = count
+	}
+	return &statistics
+}
@@ -1,38 +1,225 @@
 package geoip
 
 import (
+	"net"
 	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
 
 	E "github.com/sagernet/sing/common/exceptions"
 
 	"github.com/oschwald/maxminddb-golang"
 )
 
+const (
+	databaseTypeSing = iota
+	databaseTypeMaxMind
+	databaseTypeIPinfo
+)
+
 type Reader struct {
-	reader *maxminddb.Reader
+	reader       *maxminddb.Reader
+	databaseType int
+	hasCountry   bool
+	hasASN       bool
+}
+
+// Record is the data of a network in any supported database.
+type Record struct {
+	Country        string
+	ASN            uint32
+	ASOrganization string
+}
+
+type maxmindRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	AutonomousSystemNumber       uint32 `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+type ipinfoRecord struct {
+	Country     string `maxminddb:"country"`
+	CountryCode string `maxminddb:"country_code"`
+	ASN         string `maxminddb:"asn"`
+	ASName      string `maxminddb:"as_name"`
+	Name        string `maxminddb:"name"`
 }
 
+// Open opens a sing-geoip, MaxMind GeoIP2/GeoLite2 or IPinfo database.
+// Country codes are only listed for sing-geoip databases.
 func Open(path string) (*Reader, []string, error) {
 	database, err := maxminddb.Open(path)
 	if err != nil {
 		return nil, nil, err
 	}
-	if database.Metadata.DatabaseType != "sing-geoip" {
+	reader := &Reader{reader: database}
+	databaseType := database.Metadata.DatabaseType
+	switch {
+	case databaseType == "sing-geoip":
+		reader.databaseType = databaseTypeSing
+		reader.hasCountry = true
+		return reader, database.Metadata.Languages, nil
+	case strings.HasPrefix(strings.ToLower(databaseType), "ipinfo"):
+		lowerType := strings.ToLower(databaseType)
+		reader.databaseType = databaseTypeIPinfo
+		reader.hasCountry = strings.Contains(lowerType, "country") || strings.Contains(lowerType, "lite")
+		reader.hasASN = strings.Contains(lowerType, "asn") || strings.Contains(lowerType, "lite")
+		if !reader.hasCountry && !reader.hasASN {
+			reader.hasCountry = true
+			reader.hasASN = true
+		}
+	case strings.Contains(databaseType, "ASN"), strings.Contains(databaseType, "ISP"):
+		reader.databaseType = databaseTypeMaxMind
+		reader.hasASN = true
+	case strings.Contains(databaseType, "Country"), strings.Contains(databaseType, "City"), strings.Contains(databaseType, "Enterprise"):
+		reader.databaseType = databaseTypeMaxMind
+		reader.hasCountry = true
+		reader.hasASN = strings.Contains(databaseType, "Enterprise")
+	default:
 		database.Close()
-		return nil, nil, E.New("incorrect database type, expected sing-geoip, got ", database.Metadata.DatabaseType)
+		return nil, nil, E.New("unsupported database type: ", databaseType)
 	}
-	return &Reader{database}, database.Metadata.Languages, nil
+	return reader, nil, nil
+}
+
+func (r *Reader) DatabaseType() string {
+	return r.reader.Metadata.DatabaseType
 }
 
+func (r *Reader) HasCountry() bool {
+	return r.hasCountry
+}
+
+func (r *Reader) HasASN() bool {
+	return r.hasASN
+}
+
+// Lookup returns the lowercase country code of the address, or `unknown`.
 func (r *Reader) Lookup(addr netip.Addr) string {
-	var code string
-	_ = r.reader.Lookup(addr.AsSlice(), &code)
-	if code != "" {
-		return code
+	if r.hasCountry {
+		record, err := r.decode(func(result any) error {
+			return r.reader.Lookup(addr.AsSlice(), result)
+		})
+		if err == nil && record.Country != "" {
+			return record.Country
+		}
 	}
 	return "unknown"
 }
 
+// LookupASN returns the autonomous system number and organization of the address,
+// or zero if not found.
+func (r *Reader) LookupASN(addr netip.Addr) (uint32, string) {
+	if !r.hasASN {
+		return 0, ""
+	}
+	record, err := r.decode(func(result any) error {
+		return r.reader.Lookup(addr.AsSlice(), result)
+	})
+	if err != nil {
+		return 0, ""
+	}
+	return record.ASN, record.ASOrganization
+}
+
+// Networks calls yield for every network in the database until it returns false.
+func (r *Reader) Networks(yield func(prefix netip.Prefix, record Record) bool) error {
+	networks := r.reader.Networks(maxminddb.SkipAliasedNetworks)
+	for networks.Next() {
+		var ipNet *net.IPNet
+		record, err := r.decode(func(result any) error {
+			var nErr error
+			ipNet, nErr = networks.Network(result)
+			return nErr
+		})
+		if err != nil {
+			return err
+		}
+		addr, _ := netip.AddrFromSlice(ipNet.IP)
+		ones, _ := ipNet.Mask.Size()
+		if !yield(netip.PrefixFrom(addr.Unmap(), ones), record) {
+			return nil
+		}
+	}
+	return networks.Err()
+}
+
+// Codes returns all country codes in the database.
+func (r *Reader) Codes() ([]string, error) {
+	if r.databaseType == databaseTypeSing {
+		return r.reader.Metadata.Languages, nil
+	}
+	if !r.hasCountry {
+		return nil, nil
+	}
+	codeMap := make(map[string]bool)
+	err := r.Networks(func(prefix netip.Prefix, record Record) bool {
+		if record.Country != "" {
+			codeMap[record.Country] = true
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(codeMap))
+	for code := range codeMap {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
+
+func (r *Reader) decode(decoder func(result any) error) (Record, error) {
+	switch r.databaseType {
+	case databaseTypeSing:
+		var code string
+		err := decoder(&code)
+		return Record{Country: code}, err
+	case databaseTypeMaxMind:
+		var record maxmindRecord
+		err := decoder(&record)
+		return Record{
+			Country:        strings.ToLower(record.Country.ISOCode),
+			ASN:            record.AutonomousSystemNumber,
+			ASOrganization: record.AutonomousSystemOrganization,
+		}, err
+	default:
+		var record ipinfoRecord
+		err := decoder(&record)
+		country := record.CountryCode
+		if country == "" && len(record.Country) == 2 {
+			country = record.Country
+		}
+		asOrganization := record.ASName
+		if asOrganization == "" {
+			asOrganization = record.Name
+		}
+		asn, _ := ParseASN(record.ASN)
+		return Record{
+			Country:        strings.ToLower(country),
+			ASN:            asn,
+			ASOrganization: asOrganization,
+		}, err
+	}
+}
+
+// ParseASN parses an autonomous system number with or without the `AS` prefix.
+func ParseASN(value string) (uint32, error) {
+	value = strings.TrimSpace(value)
+	if len(value) > 2 && strings.EqualFold(value[:2], "AS") {
+		value = value[2:]
+	}
+	asn, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, E.New("invalid ASN: ", value)
+	}
+	return uint32(asn), nil
+}
+
 func (r *Reader) Close() error {
 	return r.reader.Close()
 }
@@ -0,0 +1,120 @@
+package geoip
+
+import (
+	"bytes"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestDatabase(t *testing.T, databaseType string, networks map[string][]netip.Prefix, writeData func(buffer *bytes.Buffer, key string)) string {
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, writeDatabase(file, databaseType, nil, networks, writeData))
+	require.NoError(t, file.Close())
+	return path
+}
+
+func TestWriteASN(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "asn.mmdb")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, WriteASN(file, map[uint32][]netip.Prefix{
+		13335: {netip.MustParsePrefix("1.1.1.0/24")},
+	}))
+	require.NoError(t, file.Close())
+	reader, _, err := Open(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.True(t, reader.HasASN())
+	asn, _ := reader.LookupASN(netip.MustParseAddr("1.1.1.1"))
+	require.Equal(t, uint32(13335), asn)
+}
+
+var testASNNetworks = map[string][]netip.Prefix{
+	"13335": {netip.MustParsePrefix("1.1.1.0/24"), netip.MustParsePrefix("2606:4700::/32")},
+	"15169": {netip.MustParsePrefix("8.8.8.0/24")},
+}
+
+func TestReaderMaxMindASN(t *testing.T) {
+	t.Parallel()
+	path := writeTestDatabase(t, "GeoLite2-ASN", testASNNetworks, func(buffer *bytes.Buffer, key string) {
+		asn, _ := strconv.ParseUint(key, 10, 32)
+		writeMMDBControl(buffer, mmdbTypeMap, 2)
+		writeMMDBString(buffer, "autonomous_system_number")
+		writeMMDBUint(buffer, mmdbTypeUint32, asn)
+		writeMMDBString(buffer, "autonomous_system_organization")
+		writeMMDBString(buffer, "AS"+key+" Organization")
+	})
+	reader, codes, err := Open(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.Empty(t, codes)
+	require.True(t, reader.HasASN())
+	require.False(t, reader.HasCountry())
+	asn, organization := reader.LookupASN(netip.MustParseAddr("1.1.1.1"))
+	require.Equal(t, uint32(13335), asn)
+	require.Equal(t, "AS13335 Organization", organization)
+	asn, _ = reader.LookupASN(netip.MustParseAddr("2606:4700::1111"))
+	require.Equal(t, uint32(13335), asn)
+	asn, _ = reader.LookupASN(netip.MustParseAddr("8.8.8.8"))
+	require.Equal(t, uint32(15169), asn)
+	asn, _ = reader.LookupASN(netip.MustParseAddr("9.9.9.9"))
+	require.Zero(t, asn)
+	require.Equal(t, "unknown", reader.Lookup(netip.MustParseAddr("1.1.1.1")))
+}
+
+func TestReaderIPinfoLite(t *testing.T) {
+	t.Parallel()
+	path := writeTestDatabase(t, "ipinfo lite.mmdb", testASNNetworks, func(buffer *bytes.Buffer, key string) {
+		writeMMDBControl(buffer, mmdbTypeMap, 3)
+		writeMMDBString(buffer, "asn")
+		writeMMDBString(buffer, "AS"+key)
+		writeMMDBString(buffer, "as_name")
+		writeMMDBString(buffer, "AS"+key+" Organization")
+		writeMMDBString(buffer, "country_code")
+		writeMMDBString(buffer, "US")
+	})
+	reader, _, err := Open(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.True(t, reader.HasASN())
+	require.True(t, reader.HasCountry())
+	asn, organization := reader.LookupASN(netip.MustParseAddr("8.8.8.8"))
+	require.Equal(t, uint32(15169), asn)
+	require.Equal(t, "AS15169 Organization", organization)
+	require.Equal(t, "us", reader.Lookup(netip.MustParseAddr("1.1.1.1")))
+	codes, err := reader.Codes()
+	require.NoError(t, err)
+	require.Equal(t, []string{"us"}, codes)
+}
+
+func TestReaderSingGeoIPHasNoASN(t *testing.T) {
+	t.Parallel()
+	path := writeTestDatabase(t, "sing-geoip", map[string][]netip.Prefix{
+		"cn": {netip.MustParsePrefix("1.0.1.0/24")},
+	}, writeMMDBString)
+	reader, _, err := Open(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.False(t, reader.HasASN())
+	asn, _ := reader.LookupASN(netip.MustParseAddr("1.0.1.1"))
+	require.Zero(t, asn)
+}
+
+func TestParseASN(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{"13335", "AS13335", "as13335", " AS13335 "} {
+		asn, err := ParseASN(value)
+		require.NoError(t, err)
+		require.Equal(t, uint32(13335), asn)
+	}
+	_, err := ParseASN("ASN")
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package geoip
+
+import (
+	"bufio"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// ReadSource reads a directory of CIDR lists, the file name without extension is used as the code.
+func ReadSource(directory string) (map[string][]netip.Prefix, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[string][]netip.Prefix)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		code := strings.ToLower(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))
+		prefixes, err := readSourceFile(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, E.Cause(err, "read ", entry.Name())
+		}
+		codes[code] = append(codes[code], prefixes...)
+	}
+	return codes, nil
+}
+
+func readSourceFile(path string) ([]netip.Prefix, error) {
+	content, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	var prefixes []netip.Prefix
+	scanner := bufio.NewScanner(content)
+	var lineNumber int
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if commentIndex := strings.Index(line, "#"); commentIndex >= 0 {
+			line = line[:commentIndex]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var prefix netip.Prefix
+		if strings.Contains(line, "/") {
+			prefix, err = netip.ParsePrefix(line)
+		} else {
+			var addr netip.Addr
+			addr, err = netip.ParseAddr(line)
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		if err != nil {
+			return nil, E.Cause(err, "line ", lineNumber)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, scanner.Err()
+}
@@ -0,0 +1,92 @@
+package geoip
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/sagernet/sing-box/common/protobuf"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"go4.org/netipx"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ReadV2Ray reads a v2ray/Xray geoip.dat file.
+func ReadV2Ray(content []byte) (map[string][]netip.Prefix, error) {
+	codes := make(map[string][]netip.Prefix)
+	err := protobuf.ConsumeMessage(content, func(number protowire.Number, value []byte) error {
+		if number != 1 {
+			return nil
+		}
+		code, prefixes, err := readV2RayGeoIP(value)
+		if err != nil {
+			return err
+		}
+		codes[code] = append(codes[code], prefixes...)
+		return nil
+	})
+	if err != nil {
+		return nil, E.Cause(err, "read geoip.dat")
+	}
+	return codes, nil
+}
+
+func readV2RayGeoIP(content []byte) (string, []netip.Prefix, error) {
+	var (
+		code         string
+		prefixes     []netip.Prefix
+		inverseMatch bool
+	)
+	err := protobuf.ConsumeFields(content, func(number protowire.Number, fieldType protowire.Type, value []byte, varint uint64) error {
+		switch {
+		case number == 1 && fieldType == protowire.BytesType:
+			code = strings.ToLower(string(value))
+		case number == 2 && fieldType == protowire.BytesType:
+			var (
+				addr netip.Addr
+				bits uint64
+			)
+			err := protobuf.ConsumeFields(value, func(number protowire.Number, fieldType protowire.Type, value []byte, varint uint64) error {
+				switch {
+				case number == 1 && fieldType == protowire.BytesType:
+					var loaded bool
+					addr, loaded = netip.AddrFromSlice(value)
+					if !loaded {
+						return E.New("invalid IP length: ", len(value))
+					}
+				case number == 2 && fieldType == protowire.VarintType:
+					bits = varint
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			prefix := netip.PrefixFrom(addr.Unmap(), int(bits))
+			if !prefix.IsValid() {
+				return E.New("invalid CIDR: ", addr, "/", bits)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+		case number == 3 && fieldType == protowire.VarintType:
+			inverseMatch = varint != 0
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if inverseMatch {
+		var builder netipx.IPSetBuilder
+		builder.AddPrefix(netip.MustParsePrefix("0.0.0.0/0"))
+		builder.AddPrefix(netip.MustParsePrefix("::/0"))
+		for _, prefix := range prefixes {
+			builder.RemovePrefix(prefix)
+		}
+		ipSet, err := builder.IPSet()
+		if err != nil {
+			return "", nil, err
+		}
+		prefixes = ipSet.Prefixes()
+	}
+	return code, prefixes, nil
+}
@@ -0,0 +1,245 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/netip"
+	"sort"
+	"strconv"
+	"time"
+
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"go4.org/netipx"
+)
+
+const (
+	mmdbTypeString = 2
+	mmdbTypeUint16 = 5
+	mmdbTypeUint32 = 6
+	mmdbTypeMap    = 7
+	mmdbTypeUint64 = 9
+	mmdbTypeArray  = 11
+)
+
+var mmdbMetadataStart = []byte("\xAB\xCD\xEFMaxMind.com")
+
+type writerNode struct {
+	children [2]*writerNode
+	data     [2]int
+	index    int
+}
+
+// Write writes a sing-geoip database of the networks of each code.
+// Networks of later codes in lexical order override overlapping ones.
+func Write(writer io.Writer, codes map[string][]netip.Prefix) error {
+	codeList := make([]string, 0, len(codes))
+	for code := range codes {
+		codeList = append(codeList, code)
+	}
+	sort.Strings(codeList)
+	return writeDatabase(writer, "sing-geoip", codeList, codes, writeMMDBString)
+}
+
+// WriteASN writes a database of the networks of each autonomous system number,
+// in the record format of MaxMind GeoLite2 ASN databases.
+func WriteASN(writer io.Writer, asns map[uint32][]netip.Prefix) error {
+	networks := make(map[string][]netip.Prefix, len(asns))
+	for asn, prefixes := range asns {
+		networks[strconv.FormatUint(uint64(asn), 10)] = prefixes
+	}
+	return writeDatabase(writer, "sing-geoip-ASN", nil, networks, func(buffer *bytes.Buffer, key string) {
+		asn, _ := strconv.ParseUint(key, 10, 32)
+		writeMMDBControl(buffer, mmdbTypeMap, 1)
+		writeMMDBString(buffer, "autonomous_system_number")
+		writeMMDBUint(buffer, mmdbTypeUint32, asn)
+	})
+}
+
+// writeDatabase writes a database of the networks of each key, with the data of a key encoded by writeData.
+func writeDatabase(writer io.Writer, databaseType string, languages []string, networks map[string][]netip.Prefix, writeData func(buffer *bytes.Buffer, key string)) error {
+	codeList := make([]string, 0, len(networks))
+	for code := range networks {
+		codeList = append(codeList, code)
+	}
+	sort.Strings(codeList)
+	root := &writerNode{}
+	for codeIndex, code := range codeList {
+		var builder netipx.IPSetBuilder
+		for _, prefix := range networks[code] {
+			builder.AddPrefix(prefix)
+		}
+		ipSet, err := builder.IPSet()
+		if err != nil {
+			return E.Cause(err, "build ", code)
+		}
+		for _, prefix := range ipSet.Prefixes() {
+			root.insert(prefix, codeIndex+1)
+		}
+	}
+
+	var nodes []*writerNode
+	root.collect(&nodes)
+	nodeCount := len(nodes)
+
+	var data bytes.Buffer
+	dataOffset := make([]int, len(codeList))
+	for i, code := range codeList {
+		dataOffset[i] = data.Len()
+		writeData(&data, code)
+	}
+	maxRecord := nodeCount + 16 + data.Len()
+	var recordSize int
+	switch {
+	case maxRecord < 1<<24:
+		recordSize = 24
+	case maxRecord < 1<<28:
+		recordSize = 28
+	default:
+		recordSize = 32
+	}
+	record := func(node *writerNode, bit int) uint32 {
+		if child := node.children[bit]; child != nil {
+			return uint32(child.index)
+		}
+		if node.data[bit] == 0 {
+			return uint32(nodeCount)
+		}
+		return uint32(nodeCount + 16 + dataOffset[node.data[bit]-1])
+	}
+
+	var tree bytes.Buffer
+	tree.Grow(nodeCount * recordSize / 4)
+	for _, node := range nodes {
+		left, right := record(node, 0), record(node, 1)
+		switch recordSize {
+		case 24:
+			tree.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(right >> 16), byte(right >> 8), byte(right)})
+		case 28:
+			tree.Write([]byte{byte(left >> 16), byte(left >> 8), byte(left), byte(left>>24)<<4 | byte(right>>24)&0x0F, byte(right >> 16), byte(right >> 8), byte(right)})
+		default:
+			tree.Write(binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, left), right))
+		}
+	}
+	tree.Write(make([]byte, 16))
+	tree.Write(data.Bytes())
+	tree.Write(mmdbMetadataStart)
+
+	writeMMDBControl(&tree, mmdbTypeMap, 9)
+	writeMMDBString(&tree, "binary_format_major_version")
+	writeMMDBUint(&tree, mmdbTypeUint16, 2)
+	writeMMDBString(&tree, "binary_format_minor_version")
+	writeMMDBUint(&tree, mmdbTypeUint16, 0)
+	writeMMDBString(&tree, "build_epoch")
+	writeMMDBUint(&tree, mmdbTypeUint64, uint64(time.Now().Unix()))
+	writeMMDBString(&tree, "database_type")
+	writeMMDBString(&tree, databaseType)
+	writeMMDBString(&tree, "description")
+	writeMMDBControl(&tree, mmdbTypeMap, 1)
+	writeMMDBString(&tree, "en")
+	writeMMDBString(&tree, databaseType+" database")
+	writeMMDBString(&tree, "ip_version")
+	writeMMDBUint(&tree, mmdbTypeUint16, 6)
+	writeMMDBString(&tree, "languages")
+	writeMMDBControl(&tree, mmdbTypeArray, len(languages))
+	for _, language := range languages {
+		writeMMDBString(&tree, language)
+	}
+	writeMMDBString(&tree, "node_count")
+	writeMMDBUint(&tree, mmdbTypeUint32, uint64(nodeCount))
+	writeMMDBString(&tree, "record_size")
+	writeMMDBUint(&tree, mmdbTypeUint16, uint64(recordSize))
+
+	_, err := writer.Write(tree.Bytes())
+	return err
+}
+
+// insert stores the prefix in the IPv6 tree, IPv4 networks are placed in `::/96`.
+func (n *writerNode) insert(prefix netip.Prefix, data int) {
+	addr := prefix.Addr().As16()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() {
+		addr = [16]byte{}
+		copy(addr[12:], prefix.Addr().AsSlice())
+		bits += 96
+	}
+	if bits == 0 {
+		n.children = [2]*writerNode{}
+		n.data = [2]int{data, data}
+		return
+	}
+	node := n
+	for i := 0; ; i++ {
+		bit := int(addr[i/8]>>(7-i%8)) & 1
+		if i == bits-1 {
+			node.children[bit] = nil
+			node.data[bit] = data
+			return
+		}
+		child := node.children[bit]
+		if child == nil {
+			child = &writerNode{data: [2]int{node.data[bit], node.data[bit]}}
+			node.children[bit] = child
+			node.data[bit] = 0
+		}
+		node = child
+	}
+}
+
+func (n *writerNode) collect(nodes *[]*writerNode) {
+	n.index = len(*nodes)
+	*nodes = append(*nodes, n)
+	for _, child := range n.children {
+		if child != nil {
+			child.collect(nodes)
+		}
+	}
+}
+
+func writeMMDBControl(buffer *bytes.Buffer, dataType int, size int) {
+	var control byte
+	if dataType <= 7 {
+		control = byte(dataType) << 5
+	}
+	switch {
+	case size < 29:
+		control |= byte(size)
+	case size < 29+256:
+		control |= 29
+	case size < 285+65536:
+		control |= 30
+	default:
+		control |= 31
+	}
+	buffer.WriteByte(control)
+	if dataType > 7 {
+		buffer.WriteByte(byte(dataType - 7))
+	}
+	switch {
+	case size < 29:
+	case size < 29+256:
+		buffer.WriteByte(byte(size - 29))
+	case size < 285+65536:
+		size -= 285
+		buffer.Write([]byte{byte(size >> 8), byte(size)})
+	default:
+		size -= 65821
+		buffer.Write([]byte{byte(size >> 16), byte(size >> 8), byte(size)})
+	}
+}
+
+func writeMMDBString(buffer *bytes.Buffer, value string) {
+	writeMMDBControl(buffer, mmdbTypeString, len(value))
+	buffer.WriteString(value)
+}
+
+func writeMMDBUint(buffer *bytes.Buffer, dataType int, value uint64) {
+	var content []byte
+	for value > 0 {
+		content = append([]byte{byte(value)}, content...)
+		value >>= 8
+	}
+	writeMMDBControl(buffer, dataType, len(content))
+	buffer.Write(content)
+}
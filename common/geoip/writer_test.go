@@ -0,0 +1,40 @@
+package geoip_test
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sagernet/sing-box/common/geoip"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "geoip.db")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	err = geoip.Write(file, map[string][]netip.Prefix{
+		"cn": {netip.MustParsePrefix("1.0.1.0/24"), netip.MustParsePrefix("240e::/20")},
+		"us": {netip.MustParsePrefix("8.8.8.0/24"), netip.MustParsePrefix("1.0.1.128/25")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	reader, codes, err := geoip.Open(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.Equal(t, []string{"cn", "us"}, codes)
+	require.Equal(t, "cn", reader.Lookup(netip.MustParseAddr("1.0.1.1")))
+	require.Equal(t, "us", reader.Lookup(netip.MustParseAddr("1.0.1.129")))
+	require.Equal(t, "us", reader.Lookup(netip.MustParseAddr("8.8.8.8")))
+	require.Equal(t, "cn", reader.Lookup(netip.MustParseAddr("240e::1")))
+	require.Equal(t, "unknown", reader.Lookup(netip.MustParseAddr("9.9.9.9")))
+	var prefixes []string
+	require.NoError(t, reader.Networks(func(prefix netip.Prefix, record geoip.Record) bool {
+		prefixes = append(prefixes, record.Country+" "+prefix.String())
+		return true
+	}))
+	require.Equal(t, []string{"cn 1.0.1.0/25", "us 1.0.1.128/25", "us 8.8.8.0/24", "cn 240e::/20"}, prefixes)
+}
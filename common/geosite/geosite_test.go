@@ -7,6 +7,7 @@ import (
 	"github.com/sagernet/sing-box/common/geosite"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 func TestGeosite(t *testing.T) {
@@ -32,3 +33,53 @@ func TestGeosite(t *testing.T) {
 		Value: "example.org",
 	}}, items)
 }
+
+func TestReadV2Ray(t *testing.T) {
+	t.Parallel()
+
+	appendDomain := func(b []byte, domainType uint64, value string, attributes ...string) []byte {
+		var domain []byte
+		domain = protowire.AppendTag(domain, 1, protowire.VarintType)
+		domain = protowire.AppendVarint(domain, domainType)
+		domain = protowire.AppendTag(domain, 2, protowire.BytesType)
+		domain = protowire.AppendString(domain, value)
+		for _, attribute := range attributes {
+			var attributeMessage []byte
+			attributeMessage = protowire.AppendTag(attributeMessage, 1, protowire.BytesType)
+			attributeMessage = protowire.AppendString(attributeMessage, attribute)
+			attributeMessage = protowire.AppendTag(attributeMessage, 2, protowire.VarintType)
+			attributeMessage = protowire.AppendVarint(attributeMessage, 1)
+			domain = protowire.AppendTag(domain, 3, protowire.BytesType)
+			domain = protowire.AppendBytes(domain, attributeMessage)
+		}
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		return protowire.AppendBytes(b, domain)
+	}
+	var site []byte
+	site = protowire.AppendTag(site, 1, protowire.BytesType)
+	site = protowire.AppendString(site, "EXAMPLE")
+	site = appendDomain(site, 2, "example.org")
+	site = appendDomain(site, 3, "ads.example.org", "ads")
+	site = appendDomain(site, 0, "example")
+	var content []byte
+	content = protowire.AppendTag(content, 1, protowire.BytesType)
+	content = protowire.AppendBytes(content, site)
+
+	source, err := geosite.ReadV2Ray(content)
+	require.NoError(t, err)
+	domains := geosite.Expand(source)
+	require.Equal(t, []geosite.Item{
+		{Type: geosite.RuleTypeDomain, Value: "ads.example.org"},
+		{Type: geosite.RuleTypeDomainSuffix, Value: "example.org"},
+		{Type: geosite.RuleTypeDomainKeyword, Value: "example"},
+	}, domains["example"])
+	require.Equal(t, []geosite.Item{
+		{Type: geosite.RuleTypeDomain, Value: "ads.example.org"},
+	}, domains["example@ads"])
+	items, err := geosite.Filter(source, "example@!ads")
+	require.NoError(t, err)
+	require.Equal(t, []geosite.Item{
+		{Type: geosite.RuleTypeDomainSuffix, Value: "example.org"},
+		{Type: geosite.RuleTypeDomainKeyword, Value: "example"},
+	}, items)
+}
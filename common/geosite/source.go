@@ -0,0 +1,235 @@
+package geosite
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// SourceItem is a domain item with v2ray style attributes.
+type SourceItem struct {
+	Item
+	Attributes []string
+}
+
+type sourceInclude struct {
+	code    string
+	include []string
+	exclude []string
+}
+
+type sourceFile struct {
+	items    []SourceItem
+	includes []sourceInclude
+}
+
+// ReadSource reads a directory of domain lists in the v2ray domain-list-community format,
+// the file name is used as the code.
+func ReadSource(directory string) (map[string][]SourceItem, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*sourceFile)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		code := strings.ToLower(strings.TrimSuffix(entry.Name(), ".txt"))
+		file, err := readSourceFile(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, E.Cause(err, "read ", entry.Name())
+		}
+		files[code] = file
+	}
+	codes := make(map[string][]SourceItem)
+	for code := range files {
+		_, err = resolveSource(code, files, codes, make(map[string]bool))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+func readSourceFile(path string) (*sourceFile, error) {
+	content, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	var file sourceFile
+	scanner := bufio.NewScanner(content)
+	var lineNumber int
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if commentIndex := strings.Index(line, "#"); commentIndex >= 0 {
+			line = line[:commentIndex]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var attributes []string
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "@") || len(field) == 1 {
+				return nil, E.New("line ", lineNumber, ": invalid attribute: ", field)
+			}
+			attributes = append(attributes, strings.ToLower(field[1:]))
+		}
+		itemType, value, hasType := strings.Cut(fields[0], ":")
+		if !hasType {
+			itemType, value = "domain", fields[0]
+		}
+		if value == "" {
+			return nil, E.New("line ", lineNumber, ": empty value")
+		}
+		var item SourceItem
+		switch itemType {
+		case "domain":
+			item.Type = RuleTypeDomainSuffix
+			value = strings.ToLower(value)
+		case "full":
+			item.Type = RuleTypeDomain
+			value = strings.ToLower(value)
+		case "keyword":
+			item.Type = RuleTypeDomainKeyword
+		case "regexp":
+			item.Type = RuleTypeDomainRegex
+		case "include":
+			include := sourceInclude{code: strings.ToLower(value)}
+			for _, attribute := range attributes {
+				if strings.HasPrefix(attribute, "-") {
+					include.exclude = append(include.exclude, attribute[1:])
+				} else {
+					include.include = append(include.include, attribute)
+				}
+			}
+			file.includes = append(file.includes, include)
+			continue
+		default:
+			return nil, E.New("line ", lineNumber, ": unknown rule type: ", itemType)
+		}
+		item.Value = value
+		item.Attributes = attributes
+		file.items = append(file.items, item)
+	}
+	return &file, scanner.Err()
+}
+
+func resolveSource(code string, files map[string]*sourceFile, codes map[string][]SourceItem, resolving map[string]bool) ([]SourceItem, error) {
+	if items, loaded := codes[code]; loaded {
+		return items, nil
+	}
+	file, loaded := files[code]
+	if !loaded {
+		return nil, E.New("included list not found: ", code)
+	}
+	if resolving[code] {
+		return nil, E.New("circular include: ", code)
+	}
+	resolving[code] = true
+	items := append([]SourceItem(nil), file.items...)
+	for _, include := range file.includes {
+		includedItems, err := resolveSource(include.code, files, codes, resolving)
+		if err != nil {
+			return nil, E.Cause(err, "resolve ", code)
+		}
+		for _, item := range includedItems {
+			if item.matchAttributes(include.include, include.exclude) {
+				items = append(items, item)
+			}
+		}
+	}
+	delete(resolving, code)
+	codes[code] = items
+	return items, nil
+}
+
+func (i SourceItem) hasAttribute(attribute string) bool {
+	for _, itemAttribute := range i.Attributes {
+		if itemAttribute == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+func (i SourceItem) matchAttributes(include []string, exclude []string) bool {
+	for _, attribute := range include {
+		if !i.hasAttribute(attribute) {
+			return false
+		}
+	}
+	for _, attribute := range exclude {
+		if i.hasAttribute(attribute) {
+			return false
+		}
+	}
+	return true
+}
+
+// Expand converts attributed lists to plain codes,
+// adding `code@attribute` and `code@!attribute` for every attribute in use.
+func Expand(source map[string][]SourceItem) map[string][]Item {
+	domains := make(map[string][]Item)
+	for code, sourceItems := range source {
+		domains[code] = deduplicate(sourceItems, nil, nil)
+		attributeMap := make(map[string]bool)
+		for _, item := range sourceItems {
+			for _, attribute := range item.Attributes {
+				attributeMap[attribute] = true
+			}
+		}
+		for attribute := range attributeMap {
+			domains[code+"@"+attribute] = deduplicate(sourceItems, []string{attribute}, nil)
+			domains[code+"@!"+attribute] = deduplicate(sourceItems, nil, []string{attribute})
+		}
+	}
+	return domains
+}
+
+// Filter returns items of a `code`, `code@attribute` or `code@!attribute` selector.
+func Filter(source map[string][]SourceItem, selector string) ([]Item, error) {
+	code, attribute, hasAttribute := strings.Cut(strings.ToLower(selector), "@")
+	sourceItems, loaded := source[code]
+	if !loaded {
+		return nil, E.New("code not found: ", code)
+	}
+	var items []Item
+	if !hasAttribute {
+		items = deduplicate(sourceItems, nil, nil)
+	} else if strings.HasPrefix(attribute, "!") {
+		items = deduplicate(sourceItems, nil, []string{attribute[1:]})
+	} else {
+		items = deduplicate(sourceItems, []string{attribute}, nil)
+	}
+	if len(items) == 0 {
+		return nil, E.New("no items matched: ", selector)
+	}
+	return items, nil
+}
+
+func deduplicate(sourceItems []SourceItem, include []string, exclude []string) []Item {
+	itemMap := make(map[Item]bool)
+	items := make([]Item, 0, len(sourceItems))
+	for _, sourceItem := range sourceItems {
+		if !sourceItem.matchAttributes(include, exclude) || itemMap[sourceItem.Item] {
+			continue
+		}
+		itemMap[sourceItem.Item] = true
+		items = append(items, sourceItem.Item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Type != items[j].Type {
+			return items[i].Type < items[j].Type
+		}
+		return items[i].Value < items[j].Value
+	})
+	return items
+}
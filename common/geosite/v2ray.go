@@ -0,0 +1,98 @@
+package geosite
+
+import (
+	"strings"
+
+	"github.com/sagernet/sing-box/common/protobuf"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Domain types of the v2ray geosite.dat format.
+const (
+	v2rayDomainPlain = iota
+	v2rayDomainRegex
+	v2rayDomainRoot
+	v2rayDomainFull
+)
+
+// ReadV2Ray reads a v2ray/Xray geosite.dat file.
+func ReadV2Ray(content []byte) (map[string][]SourceItem, error) {
+	codes := make(map[string][]SourceItem)
+	err := protobuf.ConsumeMessage(content, func(number protowire.Number, value []byte) error {
+		if number != 1 {
+			return nil
+		}
+		code, items, err := readV2RayGeoSite(value)
+		if err != nil {
+			return err
+		}
+		codes[code] = append(codes[code], items...)
+		return nil
+	})
+	if err != nil {
+		return nil, E.Cause(err, "read geosite.dat")
+	}
+	return codes, nil
+}
+
+func readV2RayGeoSite(content []byte) (string, []SourceItem, error) {
+	var (
+		code  string
+		items []SourceItem
+	)
+	err := protobuf.ConsumeMessage(content, func(number protowire.Number, value []byte) error {
+		switch number {
+		case 1:
+			code = strings.ToLower(string(value))
+		case 2:
+			item, err := readV2RayDomain(value)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	return code, items, err
+}
+
+func readV2RayDomain(content []byte) (SourceItem, error) {
+	var (
+		item       SourceItem
+		domainType uint64
+	)
+	err := protobuf.ConsumeFields(content, func(number protowire.Number, fieldType protowire.Type, value []byte, varint uint64) error {
+		switch {
+		case number == 1 && fieldType == protowire.VarintType:
+			domainType = varint
+		case number == 2 && fieldType == protowire.BytesType:
+			item.Value = string(value)
+		case number == 3 && fieldType == protowire.BytesType:
+			return protobuf.ConsumeMessage(value, func(number protowire.Number, value []byte) error {
+				if number == 1 {
+					item.Attributes = append(item.Attributes, strings.ToLower(string(value)))
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return item, err
+	}
+	switch domainType {
+	case v2rayDomainPlain:
+		item.Type = RuleTypeDomainKeyword
+	case v2rayDomainRegex:
+		item.Type = RuleTypeDomainRegex
+	case v2rayDomainRoot:
+		item.Type = RuleTypeDomainSuffix
+	case v2rayDomainFull:
+		item.Type = RuleTypeDomain
+	default:
+		return item, E.New("unknown domain type: ", domainType)
+	}
+	return item, nil
+}
@@ -0,0 +1,102 @@
+package listener
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	E "github.com/sagernet/sing/common/exceptions"
+	N "github.com/sagernet/sing/common/network"
+)
+
+const (
+	PrefixUnix    = "unix:"
+	PrefixSystemd = "systemd:"
+)
+
+// Listen listens on a TCP address, a unix socket path prefixed by `unix:`
+// or a socket passed by systemd prefixed by `systemd:`.
+func Listen(ctx context.Context, address string, permissions os.FileMode) (net.Listener, error) {
+	switch {
+	case strings.HasPrefix(address, PrefixUnix):
+		return ListenUnix(ctx, net.ListenConfig{}, strings.TrimPrefix(address, PrefixUnix), permissions)
+	case strings.HasPrefix(address, PrefixSystemd):
+		return ListenSystemd(strings.TrimPrefix(address, PrefixSystemd))
+	default:
+		var listenConfig net.ListenConfig
+		return listenConfig.Listen(ctx, N.NetworkTCP, address)
+	}
+}
+
+// ListenUnix listens on the unix socket path. A stale socket file left by a
+// previous process is removed, and the socket file is removed on close.
+func ListenUnix(ctx context.Context, listenConfig net.ListenConfig, path string, permissions os.FileMode) (net.Listener, error) {
+	err := removeStaleSocket(path)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == 0 {
+		return listenConfig.Listen(ctx, "unix", path)
+	}
+	// the socket is bound in a private directory and moved into place after
+	// its permissions are set, so it is never reachable with the umask mode
+	tempDir, err := os.MkdirTemp(filepath.Dir(path), ".sock-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+	tempPath := filepath.Join(tempDir, "s")
+	listener, err := listenConfig.Listen(ctx, "unix", tempPath)
+	if err != nil {
+		return nil, err
+	}
+	unixListener := listener.(*net.UnixListener)
+	unixListener.SetUnlinkOnClose(false)
+	err = os.Chmod(tempPath, permissions)
+	if err != nil {
+		listener.Close()
+		return nil, E.Cause(err, "set permissions of ", path)
+	}
+	err = os.Rename(tempPath, path)
+	if err != nil {
+		listener.Close()
+		return nil, E.Cause(err, "listen ", path)
+	}
+	return &pathUnixListener{unixListener, path}, nil
+}
+
+type pathUnixListener struct {
+	*net.UnixListener
+	path string
+}
+
+func (l *pathUnixListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: l.path, Net: "unix"}
+}
+
+func (l *pathUnixListener) Close() error {
+	err := l.UnixListener.Close()
+	os.Remove(l.path)
+	return err
+}
+
+func removeStaleSocket(path string) error {
+	fileInfo, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if fileInfo.Mode()&os.ModeSocket == 0 {
+		return E.New("listen ", path, ": file exists and is not a socket")
+	}
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return E.New("listen ", path, ": address already in use")
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,51 @@
+package listener
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenUnix(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := Listen(context.Background(), PrefixUnix+path, 0o600)
+	require.NoError(t, err)
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+	require.Equal(t, path, listener.Addr().String())
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	conn, err := net.Dial("unix", path)
+	require.NoError(t, err)
+	conn.Close()
+	_, err = ListenUnix(context.Background(), net.ListenConfig{}, path, 0)
+	require.ErrorContains(t, err, "address already in use")
+	require.NoError(t, listener.Close())
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestListenUnixStale(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "test.sock")
+	unixListener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	require.NoError(t, err)
+	// leave the socket file behind like a crashed process
+	unixListener.SetUnlinkOnClose(false)
+	require.NoError(t, unixListener.Close())
+	listener, err := ListenUnix(context.Background(), net.ListenConfig{}, path, 0)
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	filePath := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(filePath, nil, 0o644))
+	_, err = ListenUnix(context.Background(), net.ListenConfig{}, filePath, 0)
+	require.ErrorContains(t, err, "not a socket")
+}
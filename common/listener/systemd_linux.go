@@ -0,0 +1,80 @@
+package listener
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// the first file descriptor passed by systemd, see sd_listen_fds(3)
+const systemdListenFDsStart = 3
+
+var (
+	systemdOnce  sync.Once
+	systemdFiles map[string][]*os.File
+	systemdErr   error
+)
+
+// ListenSystemd returns a listener for the socket that systemd passed with
+// the name, which is set by FileDescriptorName= in the socket unit.
+// The passed socket stays open, so that it can be adopted again on reload.
+func ListenSystemd(name string) (net.Listener, error) {
+	systemdOnce.Do(func() {
+		systemdFiles, systemdErr = loadSystemdFiles(systemdListenFDsStart)
+	})
+	if systemdErr != nil {
+		return nil, systemdErr
+	}
+	files := systemdFiles[name]
+	if len(files) == 0 {
+		return nil, E.New("systemd socket not found: ", name)
+	} else if len(files) > 1 {
+		return nil, E.New("multiple systemd sockets named ", name, ", set FileDescriptorName= for each socket")
+	}
+	listener, err := net.FileListener(files[0])
+	if err != nil {
+		return nil, E.Cause(err, "adopt systemd socket ", name)
+	}
+	return listener, nil
+}
+
+// loadSystemdFiles adopts the passed sockets and unsets the environment variables,
+// so that they are not inherited by child processes, see sd_listen_fds(3).
+func loadSystemdFiles(fdStart int) (map[string][]*os.File, error) {
+	listenPID := os.Getenv("LISTEN_PID")
+	listenFDsString := os.Getenv("LISTEN_FDS")
+	listenFDNames := os.Getenv("LISTEN_FDNAMES")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	if listenPID == "" {
+		return nil, E.New("no sockets passed by systemd")
+	}
+	if listenPID != strconv.Itoa(os.Getpid()) {
+		return nil, E.New("sockets passed by systemd are not for this process")
+	}
+	listenFDs, err := strconv.Atoi(listenFDsString)
+	if err != nil || listenFDs <= 0 {
+		return nil, E.New("no sockets passed by systemd")
+	}
+	var names []string
+	if listenFDNames != "" {
+		names = strings.Split(listenFDNames, ":")
+	}
+	files := make(map[string][]*os.File)
+	for i := 0; i < listenFDs; i++ {
+		fd := fdStart + i
+		syscall.CloseOnExec(fd)
+		name := "unknown"
+		if i < len(names) {
+			name = names[i]
+		}
+		files[name] = append(files[name], os.NewFile(uintptr(fd), name))
+	}
+	return files, nil
+}
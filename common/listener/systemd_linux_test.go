@@ -0,0 +1,42 @@
+package listener
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSystemdFiles(t *testing.T) {
+	tcpListener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer tcpListener.Close()
+	file, err := tcpListener.File()
+	require.NoError(t, err)
+	fd, err := syscall.Dup(int(file.Fd()))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDS", "1")
+	t.Setenv("LISTEN_FDNAMES", "test")
+	files, err := loadSystemdFiles(fd)
+	require.NoError(t, err)
+	require.Len(t, files["test"], 1)
+	for _, name := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+		_, loaded := os.LookupEnv(name)
+		require.False(t, loaded, name)
+	}
+	listener, err := net.FileListener(files["test"][0])
+	require.NoError(t, err)
+	defer listener.Close()
+	require.Equal(t, tcpListener.Addr(), listener.Addr())
+	require.NoError(t, files["test"][0].Close())
+
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "1")
+	_, err = loadSystemdFiles(fd)
+	require.ErrorContains(t, err, "not for this process")
+}
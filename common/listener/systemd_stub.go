@@ -0,0 +1,13 @@
+//go:build !linux
+
+package listener
+
+import (
+	"net"
+
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+func ListenSystemd(name string) (net.Listener, error) {
+	return nil, E.New("systemd socket activation is only supported on Linux")
+}
@@ -16,6 +16,9 @@ import (
 
 type Client = mux.Client
 
+// Destination is the destination of connections carrying a multiplexed session.
+var Destination = mux.Destination
+
 func NewClientWithOptions(dialer N.Dialer, logger logger.Logger, options option.OutboundMultiplexOptions) (*Client, error) {
 	if !options.Enabled {
 		return nil, nil
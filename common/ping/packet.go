@@ -0,0 +1,220 @@
+package ping
+
+import (
+	"encoding/binary"
+	"net/netip"
+
+	"github.com/sagernet/sing-box/adapter"
+)
+
+const (
+	ipv4HeaderLength = 20
+	ipv6HeaderLength = 40
+	icmpHeaderLength = 8
+
+	protocolICMP   = 1
+	protocolICMPv6 = 58
+
+	typeEchoReply         = 0
+	typeDestUnreachable   = 3
+	typeEchoRequest       = 8
+	typeV6DestUnreachable = 1
+	typeV6EchoRequest     = 128
+	typeV6EchoReply       = 129
+
+	codeHostUnreachable      = 1
+	codeV6AddressUnreachable = 3
+
+	// RFC 4443 2.4: the error message must not exceed the minimum IPv6 MTU
+	ipv6MinimumMTU = 1280
+)
+
+type message struct {
+	source      netip.Addr
+	destination netip.Addr
+	icmpType    uint8
+	code        uint8
+	body        []byte
+}
+
+func parse(packet []byte) (message, bool) {
+	if len(packet) == 0 {
+		return message{}, false
+	}
+	switch packet[0] >> 4 {
+	case 4:
+		if len(packet) < ipv4HeaderLength {
+			return message{}, false
+		}
+		headerLength := int(packet[0]&0x0f) * 4
+		totalLength := int(binary.BigEndian.Uint16(packet[2:]))
+		if headerLength < ipv4HeaderLength || totalLength < headerLength+icmpHeaderLength || totalLength > len(packet) {
+			return message{}, false
+		}
+		// fragmented messages are left to the stack
+		if binary.BigEndian.Uint16(packet[6:])&0x3fff != 0 || packet[9] != protocolICMP {
+			return message{}, false
+		}
+		return message{
+			source:      netip.AddrFrom4([4]byte(packet[12:16])),
+			destination: netip.AddrFrom4([4]byte(packet[16:20])),
+			icmpType:    packet[headerLength],
+			code:        packet[headerLength+1],
+			body:        packet[headerLength+4 : totalLength],
+		}, true
+	case 6:
+		if len(packet) < ipv6HeaderLength+icmpHeaderLength {
+			return message{}, false
+		}
+		payloadLength := int(binary.BigEndian.Uint16(packet[4:]))
+		if payloadLength < icmpHeaderLength || ipv6HeaderLength+payloadLength > len(packet) || packet[6] != protocolICMPv6 {
+			return message{}, false
+		}
+		return message{
+			source:      netip.AddrFrom16([16]byte(packet[8:24])),
+			destination: netip.AddrFrom16([16]byte(packet[24:40])),
+			icmpType:    packet[ipv6HeaderLength],
+			code:        packet[ipv6HeaderLength+1],
+			body:        packet[ipv6HeaderLength+4 : ipv6HeaderLength+payloadLength],
+		}, true
+	default:
+		return message{}, false
+	}
+}
+
+func (m message) echo() *adapter.ICMPEchoRequest {
+	return &adapter.ICMPEchoRequest{
+		Source:      m.source,
+		Destination: m.destination,
+		Identifier:  binary.BigEndian.Uint16(m.body),
+		Sequence:    binary.BigEndian.Uint16(m.body[2:]),
+		Payload:     append([]byte(nil), m.body[4:]...),
+	}
+}
+
+// ParseEchoRequest parses an IPv4 or IPv6 packet carrying an ICMP echo request.
+func ParseEchoRequest(packet []byte) (*adapter.ICMPEchoRequest, bool) {
+	message, loaded := parse(packet)
+	if !loaded || message.code != 0 {
+		return nil, false
+	}
+	if message.source.Is4() && message.icmpType != typeEchoRequest || message.source.Is6() && message.icmpType != typeV6EchoRequest {
+		return nil, false
+	}
+	return message.echo(), true
+}
+
+// ParseEchoReply parses an IPv4 or IPv6 packet carrying an ICMP echo reply.
+func ParseEchoReply(packet []byte) (*adapter.ICMPEchoRequest, bool) {
+	message, loaded := parse(packet)
+	if !loaded || message.code != 0 {
+		return nil, false
+	}
+	if message.source.Is4() && message.icmpType != typeEchoReply || message.source.Is6() && message.icmpType != typeV6EchoReply {
+		return nil, false
+	}
+	return message.echo(), true
+}
+
+// EncodeEchoRequest builds the IP packet of the request.
+func EncodeEchoRequest(request *adapter.ICMPEchoRequest) []byte {
+	icmpType := uint8(typeEchoRequest)
+	if request.Destination.Is6() {
+		icmpType = typeV6EchoRequest
+	}
+	return encode(request.Source, request.Destination, icmpType, 0, encodeEchoBody(request))
+}
+
+// EncodeEchoReply builds the IP packet answering the request.
+func EncodeEchoReply(request *adapter.ICMPEchoRequest) []byte {
+	icmpType := uint8(typeEchoReply)
+	if request.Destination.Is6() {
+		icmpType = typeV6EchoReply
+	}
+	return encode(request.Destination, request.Source, icmpType, 0, encodeEchoBody(request))
+}
+
+// EncodeUnreachable builds a destination unreachable message for the packet.
+func EncodeUnreachable(packet []byte) []byte {
+	message, loaded := parse(packet)
+	if !loaded {
+		return nil
+	}
+	// the first four bytes of the body are unused
+	body := make([]byte, 4, 4+len(packet))
+	if message.source.Is4() {
+		headerLength := int(packet[0]&0x0f) * 4
+		body = append(body, packet[:headerLength+icmpHeaderLength]...)
+		return encode(message.destination, message.source, typeDestUnreachable, codeHostUnreachable, body)
+	} else {
+		quoteLength := len(packet)
+		if quoteLength > ipv6MinimumMTU-ipv6HeaderLength-icmpHeaderLength {
+			quoteLength = ipv6MinimumMTU - ipv6HeaderLength - icmpHeaderLength
+		}
+		body = append(body, packet[:quoteLength]...)
+		return encode(message.destination, message.source, typeV6DestUnreachable, codeV6AddressUnreachable, body)
+	}
+}
+
+func encodeEchoBody(request *adapter.ICMPEchoRequest) []byte {
+	body := make([]byte, 4+len(request.Payload))
+	binary.BigEndian.PutUint16(body, request.Identifier)
+	binary.BigEndian.PutUint16(body[2:], request.Sequence)
+	copy(body[4:], request.Payload)
+	return body
+}
+
+func encode(source netip.Addr, destination netip.Addr, icmpType uint8, code uint8, body []byte) []byte {
+	if source.Is4() {
+		packet := make([]byte, ipv4HeaderLength+4+len(body))
+		packet[0] = 0x45
+		binary.BigEndian.PutUint16(packet[2:], uint16(len(packet)))
+		packet[8] = 64
+		packet[9] = protocolICMP
+		source4, destination4 := source.As4(), destination.As4()
+		copy(packet[12:], source4[:])
+		copy(packet[16:], destination4[:])
+		binary.BigEndian.PutUint16(packet[10:], ^checksum(0, packet[:ipv4HeaderLength]))
+		icmpMessage := packet[ipv4HeaderLength:]
+		icmpMessage[0] = icmpType
+		icmpMessage[1] = code
+		copy(icmpMessage[4:], body)
+		binary.BigEndian.PutUint16(icmpMessage[2:], ^checksum(0, icmpMessage))
+		return packet
+	}
+	packet := make([]byte, ipv6HeaderLength+4+len(body))
+	packet[0] = 0x60
+	binary.BigEndian.PutUint16(packet[4:], uint16(len(packet)-ipv6HeaderLength))
+	packet[6] = protocolICMPv6
+	packet[7] = 64
+	source16, destination16 := source.As16(), destination.As16()
+	copy(packet[8:], source16[:])
+	copy(packet[24:], destination16[:])
+	icmpMessage := packet[ipv6HeaderLength:]
+	icmpMessage[0] = icmpType
+	icmpMessage[1] = code
+	copy(icmpMessage[4:], body)
+	// RFC 4443 2.3: the checksum covers the IPv6 pseudo-header
+	var pseudoHeader [8]byte
+	binary.BigEndian.PutUint32(pseudoHeader[:], uint32(len(icmpMessage)))
+	pseudoHeader[7] = protocolICMPv6
+	sum := checksum(0, packet[8:ipv6HeaderLength])
+	sum = checksum(uint32(sum), pseudoHeader[:])
+	binary.BigEndian.PutUint16(icmpMessage[2:], ^checksum(uint32(sum), icmpMessage))
+	return packet
+}
+
+func checksum(initial uint32, data []byte) uint16 {
+	sum := initial
+	for len(data) >= 2 {
+		sum += uint32(binary.BigEndian.Uint16(data))
+		data = data[2:]
+	}
+	if len(data) == 1 {
+		sum += uint32(data[0]) << 8
+	}
+	for sum > 0xffff {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return uint16(sum)
+}
@@ -0,0 +1,57 @@
+package ping
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEchoPacket(t *testing.T) {
+	t.Parallel()
+	for _, addresses := range [][2]netip.Addr{
+		{netip.MustParseAddr("172.19.0.1"), netip.MustParseAddr("1.1.1.1")},
+		{netip.MustParseAddr("fdfe:dcba:9876::1"), netip.MustParseAddr("2606:4700::1111")},
+	} {
+		request := &adapter.ICMPEchoRequest{
+			Source:      addresses[0],
+			Destination: addresses[1],
+			Identifier:  0x1234,
+			Sequence:    7,
+			Payload:     []byte("sing-box"),
+		}
+		requestPacket := EncodeEchoRequest(request)
+		parsedRequest, loaded := ParseEchoRequest(requestPacket)
+		require.True(t, loaded)
+		require.Equal(t, request, parsedRequest)
+		_, loaded = ParseEchoReply(requestPacket)
+		require.False(t, loaded)
+
+		replyPacket := EncodeEchoReply(request)
+		reply, loaded := ParseEchoReply(replyPacket)
+		require.True(t, loaded)
+		require.Equal(t, request.Destination, reply.Source)
+		require.Equal(t, request.Source, reply.Destination)
+		require.Equal(t, request.Payload, reply.Payload)
+
+		unreachablePacket := EncodeUnreachable(requestPacket)
+		message, loaded := parse(unreachablePacket)
+		require.True(t, loaded)
+		require.Equal(t, request.Source, message.destination)
+		require.Contains(t, string(message.body), string(requestPacket[:len(requestPacket)-len(request.Payload)]))
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+	packet := EncodeEchoRequest(&adapter.ICMPEchoRequest{
+		Source:      netip.MustParseAddr("192.0.2.1"),
+		Destination: netip.MustParseAddr("192.0.2.2"),
+		Payload:     []byte("odd"),
+	})
+	// a valid checksum sums to all ones
+	require.Equal(t, uint16(0xffff), checksum(0, packet[:ipv4HeaderLength]))
+	require.Equal(t, uint16(0xffff), checksum(0, packet[ipv4HeaderLength:]))
+}
@@ -0,0 +1,14 @@
+//go:build !(linux || darwin)
+
+package ping
+
+import (
+	"context"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing/common/control"
+)
+
+func Exchange(ctx context.Context, controlFunc control.Func, request *adapter.ICMPEchoRequest) error {
+	return adapter.ErrICMPUnsupported
+}
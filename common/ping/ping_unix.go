@@ -0,0 +1,127 @@
+//go:build linux || darwin
+
+package ping
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing/common/control"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+)
+
+// Exchange sends the echo request through an unprivileged ICMP socket,
+// or a raw socket if the former is not permitted, and waits for the reply.
+func Exchange(ctx context.Context, controlFunc control.Func, request *adapter.ICMPEchoRequest) error {
+	conn, isRaw, err := listen(request.Destination.Is6())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	network := "udp4"
+	if request.Destination.Is6() {
+		network = "udp6"
+	}
+	if controlFunc != nil {
+		rawConn, err := conn.(syscall.Conn).SyscallConn()
+		if err != nil {
+			return err
+		}
+		err = controlFunc(network, M.SocksaddrFrom(request.Destination, 0).String(), rawConn)
+		if err != nil {
+			return err
+		}
+	}
+	var destination net.Addr
+	if isRaw {
+		destination = &net.IPAddr{IP: request.Destination.AsSlice()}
+	} else {
+		destination = &net.UDPAddr{IP: request.Destination.AsSlice()}
+	}
+	// the kernel fills in the checksum of ICMPv6 messages and of messages sent
+	// through unprivileged sockets, and replaces the identifier of the latter.
+	echoRequest := EncodeEchoRequest(request)
+	if request.Destination.Is4() {
+		echoRequest = echoRequest[ipv4HeaderLength:]
+	} else {
+		echoRequest = echoRequest[ipv6HeaderLength:]
+	}
+	_, err = conn.WriteTo(echoRequest, destination)
+	if err != nil {
+		return E.Cause(err, "write echo request")
+	}
+	if deadline, loaded := ctx.Deadline(); loaded {
+		conn.SetReadDeadline(deadline)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	replyType := uint8(typeEchoReply)
+	if request.Destination.Is6() {
+		replyType = typeV6EchoReply
+	}
+	buffer := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if M.SocksaddrFromNet(addr).Addr != request.Destination {
+			continue
+		}
+		message := buffer[:n]
+		// raw IPv4 sockets, and unprivileged sockets on darwin, include the IP header
+		if request.Destination.Is4() && n >= ipv4HeaderLength && message[0]>>4 == 4 {
+			message = message[int(message[0]&0x0f)*4:]
+		}
+		if len(message) < icmpHeaderLength || message[0] != replyType || message[1] != 0 {
+			continue
+		}
+		if isRaw && binary.BigEndian.Uint16(message[4:]) != request.Identifier {
+			continue
+		}
+		if binary.BigEndian.Uint16(message[6:]) != request.Sequence {
+			continue
+		}
+		return nil
+	}
+}
+
+func listen(isIPv6 bool) (net.PacketConn, bool, error) {
+	family, protocol := syscall.AF_INET, syscall.IPPROTO_ICMP
+	if isIPv6 {
+		family, protocol = syscall.AF_INET6, syscall.IPPROTO_ICMPV6
+	}
+	isRaw := false
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM, protocol)
+	if err == syscall.EACCES || err == syscall.EPERM || err == syscall.EPROTONOSUPPORT {
+		isRaw = true
+		fd, err = syscall.Socket(family, syscall.SOCK_RAW, protocol)
+	}
+	if err != nil {
+		return nil, false, E.Cause(os.NewSyscallError("socket", err), "create icmp socket")
+	}
+	syscall.CloseOnExec(fd)
+	file := os.NewFile(uintptr(fd), "icmp")
+	conn, err := net.FilePacketConn(file)
+	file.Close()
+	if err != nil {
+		return nil, false, err
+	}
+	return conn, isRaw, nil
+}
@@ -0,0 +1,83 @@
+//go:build linux && !android
+
+package process
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+var systemdUnitSuffixes = []string{".service", ".scope"}
+
+func resolveProcessCGroup(pid string) (string, error) {
+	content, err := os.ReadFile(path.Join(pathProc, pid, "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	var systemdPath string
+	for _, line := range strings.Split(string(content), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			// cgroup v2 unified hierarchy
+			return parts[2], nil
+		} else if parts[1] == "name=systemd" {
+			// cgroup v1 or hybrid hierarchy
+			systemdPath = parts[2]
+		}
+	}
+	if systemdPath != "" {
+		return systemdPath, nil
+	}
+	return "", E.New("cgroup path not found")
+}
+
+func cgroupSystemdUnit(cgroupPath string) string {
+	components := strings.Split(cgroupPath, "/")
+	for i := len(components) - 1; i >= 0; i-- {
+		for _, suffix := range systemdUnitSuffixes {
+			if strings.HasSuffix(components[i], suffix) {
+				return components[i]
+			}
+		}
+	}
+	return ""
+}
+
+// cgroupContainerID extracts the container ID from cgroup paths created by
+// Docker, containerd, CRI-O and Podman, with both systemd and cgroupfs drivers, e.g.
+//
+//	/system.slice/docker-<id>.scope
+//	/docker/<id>
+//	/kubepods.slice/.../cri-containerd-<id>.scope
+//	/machine.slice/libpod-<id>.scope
+func cgroupContainerID(cgroupPath string) string {
+	components := strings.Split(cgroupPath, "/")
+	for i := len(components) - 1; i >= 0; i-- {
+		component := strings.TrimSuffix(components[i], ".scope")
+		if index := strings.LastIndexAny(component, "-:"); index != -1 {
+			component = component[index+1:]
+		}
+		if isContainerID(component) {
+			return component
+		}
+	}
+	return ""
+}
+
+func isContainerID(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,23 @@
+//go:build linux && !android
+
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCGroupParse(t *testing.T) {
+	t.Parallel()
+	const containerID = "4d5b9a3c7e21f0a8b6c4d2e0f1a3b5c7d9e1f3a5b7c9d1e3f5a7b9c1d3e5f7a9"
+	require.Equal(t, "docker-"+containerID+".scope", cgroupSystemdUnit("/system.slice/docker-"+containerID+".scope"))
+	require.Equal(t, "nginx.service", cgroupSystemdUnit("/system.slice/nginx.service"))
+	require.Equal(t, "app-foo.scope", cgroupSystemdUnit("/user.slice/user-1000.slice/user@1000.service/app.slice/app-foo.scope"))
+	require.Equal(t, "", cgroupSystemdUnit("/docker/"+containerID))
+	require.Equal(t, containerID, cgroupContainerID("/system.slice/docker-"+containerID+".scope"))
+	require.Equal(t, containerID, cgroupContainerID("/docker/"+containerID))
+	require.Equal(t, containerID, cgroupContainerID("/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1.slice/cri-containerd-"+containerID+".scope"))
+	require.Equal(t, containerID, cgroupContainerID("/machine.slice/libpod-"+containerID+".scope"))
+	require.Equal(t, "", cgroupContainerID("/system.slice/nginx.service"))
+}
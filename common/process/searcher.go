@@ -27,6 +27,9 @@ type Info struct {
 	PackageName string
 	User        string
 	UserId      int32
+	CGroup      string
+	SystemdUnit string
+	ContainerID string
 }
 
 func FindProcessInfo(searcher Searcher, ctx context.Context, network string, source netip.AddrPort, destination netip.AddrPort) (*Info, error) {
@@ -24,12 +24,23 @@ func (s *linuxSearcher) FindProcessInfo(ctx context.Context, network string, sou
 	if err != nil {
 		return nil, err
 	}
-	processPath, err := resolveProcessNameByProcSearch(inode, uid)
+	info := &Info{
+		UserId: int32(uid),
+	}
+	pid, processPath, err := resolveProcessByProcSearch(inode, uid)
 	if err != nil {
 		s.logger.DebugContext(ctx, "find process path: ", err)
 	}
-	return &Info{
-		UserId:      int32(uid),
-		ProcessPath: processPath,
-	}, nil
+	info.ProcessPath = processPath
+	if pid != "" {
+		cgroupPath, err := resolveProcessCGroup(pid)
+		if err != nil {
+			s.logger.DebugContext(ctx, "find process cgroup: ", err)
+		} else {
+			info.CGroup = cgroupPath
+			info.SystemdUnit = cgroupSystemdUnit(cgroupPath)
+			info.ContainerID = cgroupContainerID(cgroupPath)
+		}
+	}
+	return info, nil
 }
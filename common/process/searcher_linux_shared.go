@@ -150,10 +150,10 @@ func unpackSocketDiagResponse(msg *syscall.NetlinkMessage) (inode, uid uint32) {
 	return
 }
 
-func resolveProcessNameByProcSearch(inode, uid uint32) (string, error) {
+func resolveProcessByProcSearch(inode, uid uint32) (pid string, processPath string, err error) {
 	files, err := os.ReadDir(pathProc)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	buffer := make([]byte, syscall.PathMax)
@@ -166,7 +166,7 @@ func resolveProcessNameByProcSearch(inode, uid uint32) (string, error) {
 
 		info, err := f.Info()
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 		if info.Sys().(*syscall.Stat_t).Uid != uid {
 			continue
@@ -187,12 +187,13 @@ func resolveProcessNameByProcSearch(inode, uid uint32) (string, error) {
 			}
 
 			if bytes.Equal(buffer[:n], socket) {
-				return os.Readlink(path.Join(processPath, "exe"))
+				executablePath, err := os.Readlink(path.Join(processPath, "exe"))
+				return f.Name(), executablePath, err
 			}
 		}
 	}
 
-	return "", fmt.Errorf("process of uid(%d),inode(%d) not found", uid, inode)
+	return "", "", fmt.Errorf("process of uid(%d),inode(%d) not found", uid, inode)
 }
 
 func isPid(s string) bool {
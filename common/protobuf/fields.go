@@ -0,0 +1,48 @@
+package protobuf
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ConsumeFields calls handler for every field of a protobuf message, value is set for
+// length-delimited fields and varint for varint fields, other fields are skipped.
+func ConsumeFields(content []byte, handler func(number protowire.Number, fieldType protowire.Type, value []byte, varint uint64) error) error {
+	for len(content) > 0 {
+		number, fieldType, n := protowire.ConsumeTag(content)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		content = content[n:]
+		var (
+			value  []byte
+			varint uint64
+		)
+		switch fieldType {
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(content)
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(content)
+		default:
+			n = protowire.ConsumeFieldValue(number, fieldType, content)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		content = content[n:]
+		err := handler(number, fieldType, value, varint)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConsumeMessage calls handler for every length-delimited field of a protobuf message.
+func ConsumeMessage(content []byte, handler func(number protowire.Number, value []byte) error) error {
+	return ConsumeFields(content, func(number protowire.Number, fieldType protowire.Type, value []byte, varint uint64) error {
+		if fieldType != protowire.BytesType {
+			return nil
+		}
+		return handler(number, value)
+	})
+}
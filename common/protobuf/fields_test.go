@@ -0,0 +1,47 @@
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestConsumeFields(t *testing.T) {
+	t.Parallel()
+	var content []byte
+	content = protowire.AppendTag(content, 1, protowire.BytesType)
+	content = protowire.AppendString(content, "cn")
+	content = protowire.AppendTag(content, 2, protowire.VarintType)
+	content = protowire.AppendVarint(content, 24)
+	content = protowire.AppendTag(content, 3, protowire.Fixed32Type)
+	content = protowire.AppendFixed32(content, 1)
+	content = protowire.AppendTag(content, 4, protowire.BytesType)
+	content = protowire.AppendBytes(content, nil)
+	var fields []protowire.Number
+	err := ConsumeFields(content, func(number protowire.Number, fieldType protowire.Type, value []byte, varint uint64) error {
+		fields = append(fields, number)
+		switch number {
+		case 1:
+			require.Equal(t, "cn", string(value))
+		case 2:
+			require.Equal(t, uint64(24), varint)
+		case 4:
+			require.Equal(t, protowire.BytesType, fieldType)
+			require.Empty(t, value)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []protowire.Number{1, 2, 3, 4}, fields)
+	fields = nil
+	err = ConsumeMessage(content, func(number protowire.Number, value []byte) error {
+		fields = append(fields, number)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []protowire.Number{1, 4}, fields)
+	require.Error(t, ConsumeFields(content[:len(content)-3], func(protowire.Number, protowire.Type, []byte, uint64) error {
+		return nil
+	}))
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+
+	"github.com/sagernet/sing/common/buf"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+)
+
+// Shaper holds the limiters applied to a connection.
+// Upload is traffic read from the client, download is traffic written to the client.
+type Shaper struct {
+	Upload   []*Limiter
+	Download []*Limiter
+	Priority int
+}
+
+func (s *Shaper) IsEmpty() bool {
+	return len(s.Upload) == 0 && len(s.Download) == 0
+}
+
+func (s *Shaper) waitUpload(ctx context.Context, n int) error {
+	return waitAll(ctx, s.Upload, n, s.Priority)
+}
+
+func (s *Shaper) waitDownload(ctx context.Context, n int) error {
+	return waitAll(ctx, s.Download, n, s.Priority)
+}
+
+func waitAll(ctx context.Context, limiters []*Limiter, n int, priority int) error {
+	for _, limiter := range limiters {
+		err := limiter.WaitN(ctx, n, priority)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewConn(ctx context.Context, conn net.Conn, shaper Shaper) net.Conn {
+	return &Conn{Conn: conn, ctx: ctx, shaper: shaper}
+}
+
+type Conn struct {
+	net.Conn
+	ctx    context.Context
+	shaper Shaper
+}
+
+func (c *Conn) Read(p []byte) (n int, err error) {
+	n, err = c.Conn.Read(p)
+	if n > 0 {
+		waitErr := c.shaper.waitUpload(c.ctx, n)
+		if err == nil {
+			err = waitErr
+		}
+	}
+	return
+}
+
+func (c *Conn) Write(p []byte) (n int, err error) {
+	err = c.shaper.waitDownload(c.ctx, len(p))
+	if err != nil {
+		return
+	}
+	return c.Conn.Write(p)
+}
+
+func (c *Conn) Upstream() any {
+	return c.Conn
+}
+
+func NewPacketConn(ctx context.Context, conn N.PacketConn, shaper Shaper) N.PacketConn {
+	return &PacketConn{PacketConn: conn, ctx: ctx, shaper: shaper}
+}
+
+type PacketConn struct {
+	N.PacketConn
+	ctx    context.Context
+	shaper Shaper
+}
+
+func (c *PacketConn) ReadPacket(buffer *buf.Buffer) (destination M.Socksaddr, err error) {
+	destination, err = c.PacketConn.ReadPacket(buffer)
+	if err != nil {
+		return
+	}
+	err = c.shaper.waitUpload(c.ctx, buffer.Len())
+	return
+}
+
+func (c *PacketConn) WritePacket(buffer *buf.Buffer, destination M.Socksaddr) error {
+	err := c.shaper.waitDownload(c.ctx, buffer.Len())
+	if err != nil {
+		buffer.Release()
+		return err
+	}
+	return c.PacketConn.WritePacket(buffer, destination)
+}
+
+func (c *PacketConn) Upstream() any {
+	return c.PacketConn
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	minBurst     = 64 * 1024
+	burstWindow  = 100 * time.Millisecond
+	yieldTimeout = 5 * time.Millisecond
+)
+
+// Limiter is a token bucket shared by all connections attached to it.
+//
+// Waiters with a lower priority yield to higher priority waiters,
+// so interactive traffic is not queued behind bulk transfers.
+type Limiter struct {
+	access   sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	last     time.Time
+	waiting  map[int]int
+	priority []int
+}
+
+func NewLimiter(bytesPerSecond uint64) *Limiter {
+	burst := float64(bytesPerSecond) * burstWindow.Seconds()
+	if burst < minBurst {
+		burst = minBurst
+	}
+	return &Limiter{
+		rate:    float64(bytesPerSecond),
+		burst:   burst,
+		tokens:  burst,
+		last:    time.Now(),
+		waiting: make(map[int]int),
+	}
+}
+
+func (l *Limiter) WaitN(ctx context.Context, n int, priority int) error {
+	for n > 0 {
+		chunk := n
+		if float64(chunk) > l.burst {
+			chunk = int(l.burst)
+		}
+		err := l.wait(ctx, chunk, priority)
+		if err != nil {
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+func (l *Limiter) wait(ctx context.Context, n int, priority int) error {
+	var registered bool
+	defer func() {
+		if registered {
+			l.access.Lock()
+			l.unregister(priority)
+			l.access.Unlock()
+		}
+	}()
+	for {
+		l.access.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+		preempted := l.hasHigherWaiter(priority)
+		if !preempted && l.tokens >= float64(n) {
+			l.tokens -= float64(n)
+			l.access.Unlock()
+			return nil
+		}
+		if !registered {
+			l.register(priority)
+			registered = true
+		}
+		var delay time.Duration
+		if preempted {
+			delay = yieldTimeout
+		} else {
+			delay = time.Duration((float64(n) - l.tokens) / l.rate * float64(time.Second))
+			if delay < time.Millisecond {
+				delay = time.Millisecond
+			}
+		}
+		l.access.Unlock()
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *Limiter) register(priority int) {
+	if l.waiting[priority] == 0 {
+		l.priority = append(l.priority, priority)
+	}
+	l.waiting[priority]++
+}
+
+func (l *Limiter) unregister(priority int) {
+	l.waiting[priority]--
+	if l.waiting[priority] > 0 {
+		return
+	}
+	delete(l.waiting, priority)
+	for i, it := range l.priority {
+		if it == priority {
+			l.priority = append(l.priority[:i], l.priority[i+1:]...)
+			break
+		}
+	}
+}
+
+func (l *Limiter) hasHigherWaiter(priority int) bool {
+	for _, it := range l.priority {
+		if it > priority {
+			return true
+		}
+	}
+	return false
+}
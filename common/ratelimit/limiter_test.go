@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterRate(t *testing.T) {
+	t.Parallel()
+	limiter := NewLimiter(1024 * 1024)
+	start := time.Now()
+	// the first burst is free, the rest is paced at the rate
+	require.NoError(t, limiter.WaitN(context.Background(), 1024*1024/10+256*1024, 0))
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestLimiterCancel(t *testing.T) {
+	t.Parallel()
+	limiter := NewLimiter(1024)
+	require.NoError(t, limiter.WaitN(context.Background(), minBurst, 0))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, limiter.WaitN(ctx, minBurst, 0), context.DeadlineExceeded)
+	require.Empty(t, limiter.priority)
+}
+
+func TestLimiterPriority(t *testing.T) {
+	t.Parallel()
+	limiter := NewLimiter(1024 * 1024)
+	limiter.register(10)
+	require.True(t, limiter.hasHigherWaiter(0))
+	require.False(t, limiter.hasHigherWaiter(10))
+	limiter.unregister(10)
+	require.False(t, limiter.hasHigherWaiter(0))
+}
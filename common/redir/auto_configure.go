@@ -0,0 +1,28 @@
+package redir
+
+import (
+	"net/netip"
+
+	"go4.org/netipx"
+)
+
+const (
+	DefaultAutoConfigureMark       = 0x2025
+	DefaultAutoConfigureTableIndex = 2025
+	DefaultAutoConfigureRuleIndex  = 9100
+)
+
+type AutoConfigureOptions struct {
+	TableName  string
+	TProxy     bool
+	Network    []string
+	ListenPort uint16
+	EnableIPv6 bool
+	// OutputMark marks the traffic of sing-box itself, local traffic is only proxied if set.
+	OutputMark        uint32
+	TProxyMark        uint32
+	TableIndex        int
+	RuleIndex         int
+	ExcludeAddress    []netip.Prefix
+	ExcludeAddressSet *[]*netipx.IPSet
+}
@@ -0,0 +1,594 @@
+package redir
+
+import (
+	"net/netip"
+	"sync"
+
+	"github.com/sagernet/netlink"
+	"github.com/sagernet/nftables"
+	"github.com/sagernet/nftables/binaryutil"
+	"github.com/sagernet/nftables/expr"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	N "github.com/sagernet/sing/common/network"
+
+	"go4.org/netipx"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	excludeAddressSetID4   = 1
+	excludeAddressSetName4 = "inet4_exclude_address_set"
+	excludeAddressSetID6   = 2
+	excludeAddressSetName6 = "inet6_exclude_address_set"
+)
+
+type AutoConfigure struct {
+	options         AutoConfigureOptions
+	started         bool
+	tableReserved   bool
+	policyReserved  bool
+	installedRules  []*netlink.Rule
+	installedRoutes []*netlink.Route
+}
+
+// policyAccess guards the table names and the marks and indices used by the tproxy policy routing
+// of all instances in the process, so that an inbound never removes the configuration of another.
+var (
+	policyAccess    sync.Mutex
+	policyTableName = make(map[string]bool)
+	policyMarks     = make(map[uint32]bool)
+	policyTables    = make(map[int]bool)
+	policyRuleIndex = make(map[int]bool)
+)
+
+func NewAutoConfigure(options AutoConfigureOptions) (*AutoConfigure, error) {
+	if options.TableName == "" {
+		return nil, E.New("missing table name")
+	}
+	if options.ListenPort == 0 {
+		return nil, E.New("missing listen port")
+	}
+	return &AutoConfigure{options: options}, nil
+}
+
+// reservePolicy reserves the table name and allocates the mark and indices of the policy routing.
+// Unset values are allocated from the defaults upwards, explicitly set values must not be used by another instance.
+func (c *AutoConfigure) reservePolicy() error {
+	policyAccess.Lock()
+	defer policyAccess.Unlock()
+	if policyTableName[c.options.TableName] {
+		return E.New("nftables table ", c.options.TableName, " is already used by another inbound")
+	}
+	if !c.options.TProxy {
+		policyTableName[c.options.TableName] = true
+		c.tableReserved = true
+		return nil
+	}
+	mark, tableIndex, ruleIndex := c.options.TProxyMark, c.options.TableIndex, c.options.RuleIndex
+	if mark == 0 {
+		for mark = DefaultAutoConfigureMark; policyMarks[mark]; mark++ {
+		}
+	} else if policyMarks[mark] {
+		return E.New("auto_configure_mark ", mark, " is already used by another inbound")
+	}
+	if tableIndex == 0 {
+		for tableIndex = DefaultAutoConfigureTableIndex; policyTables[tableIndex]; tableIndex++ {
+		}
+	} else if policyTables[tableIndex] {
+		return E.New("iproute2_table_index ", tableIndex, " is already used by another inbound")
+	}
+	if ruleIndex == 0 {
+		for ruleIndex = DefaultAutoConfigureRuleIndex; policyRuleIndex[ruleIndex]; ruleIndex++ {
+		}
+	} else if policyRuleIndex[ruleIndex] {
+		return E.New("iproute2_rule_index ", ruleIndex, " is already used by another inbound")
+	}
+	policyTableName[c.options.TableName] = true
+	c.tableReserved = true
+	policyMarks[mark] = true
+	policyTables[tableIndex] = true
+	policyRuleIndex[ruleIndex] = true
+	c.options.TProxyMark, c.options.TableIndex, c.options.RuleIndex = mark, tableIndex, ruleIndex
+	c.policyReserved = true
+	return nil
+}
+
+func (c *AutoConfigure) releasePolicy() {
+	policyAccess.Lock()
+	defer policyAccess.Unlock()
+	if c.tableReserved {
+		delete(policyTableName, c.options.TableName)
+		c.tableReserved = false
+	}
+	if !c.policyReserved {
+		return
+	}
+	delete(policyMarks, c.options.TProxyMark)
+	delete(policyTables, c.options.TableIndex)
+	delete(policyRuleIndex, c.options.RuleIndex)
+	c.policyReserved = false
+}
+
+func (c *AutoConfigure) Start() error {
+	err := c.reservePolicy()
+	if err != nil {
+		return err
+	}
+	// removes the table left by an unclean exit, the table name is unique to the inbound
+	c.cleanupTable()
+	nft, err := nftables.New()
+	if err != nil {
+		return err
+	}
+	defer nft.CloseLasting()
+	table := nft.AddTable(&nftables.Table{
+		Name:   c.options.TableName,
+		Family: nftables.TableFamilyINet,
+	})
+	err = c.createExcludeAddressSets(nft, table, false)
+	if err != nil {
+		return err
+	}
+	if c.options.TProxy {
+		chainPreRouting := nft.AddChain(&nftables.Chain{
+			Name:     "prerouting",
+			Table:    table,
+			Hooknum:  nftables.ChainHookPrerouting,
+			Priority: nftables.ChainPriorityMangle,
+			Type:     nftables.ChainTypeFilter,
+		})
+		c.createExcludeRules(nft, table, chainPreRouting)
+		c.createTProxyRules(nft, table, chainPreRouting)
+		if c.options.OutputMark != 0 {
+			chainOutput := nft.AddChain(&nftables.Chain{
+				Name:     "output",
+				Table:    table,
+				Hooknum:  nftables.ChainHookOutput,
+				Priority: nftables.ChainPriorityMangle,
+				Type:     nftables.ChainTypeRoute,
+			})
+			c.createExcludeRules(nft, table, chainOutput)
+			c.createRerouteRules(nft, table, chainOutput)
+		}
+	} else {
+		chainPreRouting := nft.AddChain(&nftables.Chain{
+			Name:     "prerouting",
+			Table:    table,
+			Hooknum:  nftables.ChainHookPrerouting,
+			Priority: nftables.ChainPriorityNATDest,
+			Type:     nftables.ChainTypeNAT,
+		})
+		c.createExcludeRules(nft, table, chainPreRouting)
+		c.createRedirectRule(nft, table, chainPreRouting)
+		if c.options.OutputMark != 0 {
+			chainOutput := nft.AddChain(&nftables.Chain{
+				Name:     "output",
+				Table:    table,
+				Hooknum:  nftables.ChainHookOutput,
+				Priority: nftables.ChainPriorityNATDest,
+				Type:     nftables.ChainTypeNAT,
+			})
+			c.createExcludeRules(nft, table, chainOutput)
+			c.createRedirectRule(nft, table, chainOutput)
+		}
+	}
+	err = nft.Flush()
+	if err != nil {
+		return E.Cause(err, "configure nftables")
+	}
+	c.started = true
+	if c.options.TProxy {
+		err = c.setupPolicyRouting()
+		if err != nil {
+			return E.Cause(err, "configure policy routing")
+		}
+	}
+	return nil
+}
+
+func (c *AutoConfigure) UpdateExcludeAddressSet() error {
+	if !c.started {
+		return nil
+	}
+	nft, err := nftables.New()
+	if err != nil {
+		return err
+	}
+	defer nft.CloseLasting()
+	table, err := nft.ListTableOfFamily(c.options.TableName, nftables.TableFamilyINet)
+	if err != nil {
+		return err
+	}
+	err = c.createExcludeAddressSets(nft, table, true)
+	if err != nil {
+		return err
+	}
+	return nft.Flush()
+}
+
+func (c *AutoConfigure) Close() error {
+	// policy routing is removed even if Start failed after installing part of it
+	err := c.cleanupPolicyRouting()
+	c.releasePolicy()
+	if !c.started {
+		return err
+	}
+	c.started = false
+	return E.Errors(c.cleanupTable(), err)
+}
+
+func (c *AutoConfigure) cleanupTable() error {
+	nft, err := nftables.New()
+	if err != nil {
+		return err
+	}
+	defer nft.CloseLasting()
+	table, err := nft.ListTableOfFamily(c.options.TableName, nftables.TableFamilyINet)
+	if err != nil {
+		return nil
+	}
+	nft.DelTable(table)
+	err = nft.Flush()
+	if err != nil {
+		return E.Cause(err, "remove nftables table")
+	}
+	return nil
+}
+
+// cleanupPolicyRouting removes only the rules and routes installed by this instance.
+func (c *AutoConfigure) cleanupPolicyRouting() error {
+	var errors []error
+	for _, rule := range c.installedRules {
+		err := netlink.RuleDel(rule)
+		if err != nil && !E.IsMulti(err, unix.ENOENT) {
+			errors = append(errors, E.Cause(err, "remove rule"))
+		}
+	}
+	c.installedRules = nil
+	for _, route := range c.installedRoutes {
+		err := netlink.RouteDel(route)
+		if err != nil && !E.IsMulti(err, unix.ESRCH) {
+			errors = append(errors, E.Cause(err, "remove route ", route.Dst))
+		}
+	}
+	c.installedRoutes = nil
+	return E.Errors(errors...)
+}
+
+func (c *AutoConfigure) policyRules() []*netlink.Rule {
+	families := []int{unix.AF_INET}
+	if c.options.EnableIPv6 {
+		families = append(families, unix.AF_INET6)
+	}
+	return common.Map(families, func(family int) *netlink.Rule {
+		rule := netlink.NewRule()
+		rule.Priority = c.options.RuleIndex
+		rule.Family = family
+		rule.Mark = c.options.TProxyMark
+		rule.MarkSet = true
+		rule.Table = c.options.TableIndex
+		return rule
+	})
+}
+
+func (c *AutoConfigure) policyRoutes() []*netlink.Route {
+	loopback, err := netlink.LinkByName("lo")
+	if err != nil {
+		return nil
+	}
+	destinations := []netip.Prefix{netip.PrefixFrom(netip.IPv4Unspecified(), 0)}
+	if c.options.EnableIPv6 {
+		destinations = append(destinations, netip.PrefixFrom(netip.IPv6Unspecified(), 0))
+	}
+	return common.Map(destinations, func(destination netip.Prefix) *netlink.Route {
+		return &netlink.Route{
+			LinkIndex: loopback.Attrs().Index,
+			Dst:       netipx.PrefixIPNet(destination),
+			Table:     c.options.TableIndex,
+			Type:      unix.RTN_LOCAL,
+			Scope:     netlink.SCOPE_HOST,
+		}
+	})
+}
+
+// setupPolicyRouting delivers packets marked by the tproxy rules to the local host,
+// like `ip rule add fwmark <mark> lookup <table>` and `ip route add local default dev lo table <table>`.
+// Identical rules and routes left by an unclean exit are taken over.
+func (c *AutoConfigure) setupPolicyRouting() error {
+	for _, route := range c.policyRoutes() {
+		err := netlink.RouteReplace(route)
+		if err != nil {
+			return E.Cause(err, "add route ", route.Dst)
+		}
+		c.installedRoutes = append(c.installedRoutes, route)
+	}
+	for _, rule := range c.policyRules() {
+		err := netlink.RuleAdd(rule)
+		if err != nil && !E.IsMulti(err, unix.EEXIST) {
+			return E.Cause(err, "add rule")
+		}
+		c.installedRules = append(c.installedRules, rule)
+	}
+	return nil
+}
+
+func (c *AutoConfigure) createExcludeAddressSets(nft *nftables.Conn, table *nftables.Table, update bool) error {
+	var builder netipx.IPSetBuilder
+	for _, prefix := range c.options.ExcludeAddress {
+		builder.AddPrefix(prefix)
+	}
+	if c.options.ExcludeAddressSet != nil {
+		for _, ipSet := range *c.options.ExcludeAddressSet {
+			builder.AddSet(ipSet)
+		}
+	}
+	excludeAddress, err := builder.IPSet()
+	if err != nil {
+		return err
+	}
+	err = createIPSet(nft, table, excludeAddressSetID4, excludeAddressSetName4, nftables.TableFamilyIPv4, excludeAddress, update)
+	if err != nil {
+		return err
+	}
+	if c.options.EnableIPv6 {
+		err = createIPSet(nft, table, excludeAddressSetID6, excludeAddressSetName6, nftables.TableFamilyIPv6, excludeAddress, update)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createIPSet(nft *nftables.Conn, table *nftables.Table, id uint32, name string, family nftables.TableFamily, ipSet *netipx.IPSet, update bool) error {
+	var elements []nftables.SetElement
+	for _, ipRange := range ipSet.Ranges() {
+		if (family == nftables.TableFamilyIPv4) != ipRange.From().Is4() {
+			continue
+		}
+		elements = append(elements, nftables.SetElement{
+			Key: ipRange.From().AsSlice(),
+		})
+		if endAddr := ipRange.To().Next(); endAddr.IsValid() {
+			elements = append(elements, nftables.SetElement{
+				Key:         endAddr.AsSlice(),
+				IntervalEnd: true,
+			})
+		}
+	}
+	keyType := nftables.TypeIPAddr
+	if family == nftables.TableFamilyIPv6 {
+		keyType = nftables.TypeIP6Addr
+	}
+	set := &nftables.Set{
+		Table:    table,
+		ID:       id,
+		Name:     name,
+		Interval: true,
+		KeyType:  keyType,
+	}
+	if update {
+		nft.FlushSet(set)
+	} else {
+		err := nft.AddSet(set, nil)
+		if err != nil {
+			return err
+		}
+	}
+	if len(elements) == 0 {
+		return nil
+	}
+	return nft.SetAddElements(set, elements)
+}
+
+// createExcludeRules lets through traffic to the local host, broadcast and multicast traffic,
+// traffic to excluded addresses, and in output chains, traffic of sing-box itself.
+func (c *AutoConfigure) createExcludeRules(nft *nftables.Conn, table *nftables.Table, chain *nftables.Chain) {
+	if chain.Hooknum == nftables.ChainHookOutput {
+		nft.AddRule(&nftables.Rule{
+			Table: table,
+			Chain: chain,
+			Exprs: []expr.Any{
+				&expr.Meta{Key: expr.MetaKeyMARK, Register: 1},
+				&expr.Cmp{
+					Op:       expr.CmpOpEq,
+					Register: 1,
+					Data:     binaryutil.NativeEndian.PutUint32(c.options.OutputMark),
+				},
+				&expr.Counter{},
+				&expr.Verdict{Kind: expr.VerdictReturn},
+			},
+		})
+	}
+	for _, addressType := range []uint32{unix.RTN_LOCAL, unix.RTN_BROADCAST, unix.RTN_MULTICAST} {
+		nft.AddRule(&nftables.Rule{
+			Table: table,
+			Chain: chain,
+			Exprs: []expr.Any{
+				&expr.Fib{
+					Register:       1,
+					FlagDADDR:      true,
+					ResultADDRTYPE: true,
+				},
+				&expr.Cmp{
+					Op:       expr.CmpOpEq,
+					Register: 1,
+					Data:     binaryutil.NativeEndian.PutUint32(addressType),
+				},
+				&expr.Counter{},
+				&expr.Verdict{Kind: expr.VerdictReturn},
+			},
+		})
+	}
+	c.createExcludeSetRule(nft, table, chain, excludeAddressSetID4, excludeAddressSetName4, nftables.TableFamilyIPv4)
+	if c.options.EnableIPv6 {
+		c.createExcludeSetRule(nft, table, chain, excludeAddressSetID6, excludeAddressSetName6, nftables.TableFamilyIPv6)
+	} else {
+		nft.AddRule(&nftables.Rule{
+			Table: table,
+			Chain: chain,
+			Exprs: append(matchFamily(nftables.TableFamilyIPv6),
+				&expr.Verdict{Kind: expr.VerdictReturn},
+			),
+		})
+	}
+}
+
+func (c *AutoConfigure) createExcludeSetRule(nft *nftables.Conn, table *nftables.Table, chain *nftables.Chain, id uint32, name string, family nftables.TableFamily) {
+	exprs := matchFamily(family)
+	if family == nftables.TableFamilyIPv4 {
+		exprs = append(exprs, &expr.Payload{
+			OperationType: expr.PayloadLoad,
+			DestRegister:  1,
+			Base:          expr.PayloadBaseNetworkHeader,
+			Offset:        16,
+			Len:           4,
+		})
+	} else {
+		exprs = append(exprs, &expr.Payload{
+			OperationType: expr.PayloadLoad,
+			DestRegister:  1,
+			Base:          expr.PayloadBaseNetworkHeader,
+			Offset:        24,
+			Len:           16,
+		})
+	}
+	nft.AddRule(&nftables.Rule{
+		Table: table,
+		Chain: chain,
+		Exprs: append(exprs,
+			&expr.Lookup{
+				SourceRegister: 1,
+				SetID:          id,
+				SetName:        name,
+			},
+			&expr.Counter{},
+			&expr.Verdict{Kind: expr.VerdictReturn},
+		),
+	})
+}
+
+func (c *AutoConfigure) createRedirectRule(nft *nftables.Conn, table *nftables.Table, chain *nftables.Chain) {
+	nft.AddRule(&nftables.Rule{
+		Table: table,
+		Chain: chain,
+		Exprs: append(matchProtocol(unix.IPPROTO_TCP),
+			&expr.Counter{},
+			&expr.Immediate{
+				Register: 1,
+				Data:     binaryutil.BigEndian.PutUint16(c.options.ListenPort),
+			},
+			&expr.Redir{
+				RegisterProtoMin: 1,
+				Flags:            unix.NF_NAT_RANGE_PROTO_SPECIFIED,
+			},
+		),
+	})
+}
+
+func (c *AutoConfigure) createTProxyRules(nft *nftables.Conn, table *nftables.Table, chain *nftables.Chain) {
+	families := []nftables.TableFamily{nftables.TableFamilyIPv4}
+	if c.options.EnableIPv6 {
+		families = append(families, nftables.TableFamilyIPv6)
+	}
+	for _, family := range families {
+		for _, protocol := range c.protocols() {
+			nft.AddRule(&nftables.Rule{
+				Table: table,
+				Chain: chain,
+				Exprs: append(append(matchFamily(family), matchProtocol(protocol)...),
+					&expr.Immediate{
+						Register: 1,
+						Data:     binaryutil.BigEndian.PutUint16(c.options.ListenPort),
+					},
+					&expr.TProxy{
+						Family:      byte(family),
+						TableFamily: byte(nftables.TableFamilyINet),
+						RegPort:     1,
+					},
+					&expr.Immediate{
+						Register: 1,
+						Data:     binaryutil.NativeEndian.PutUint32(c.options.TProxyMark),
+					},
+					&expr.Meta{
+						Key:            expr.MetaKeyMARK,
+						Register:       1,
+						SourceRegister: true,
+					},
+					&expr.Counter{},
+					&expr.Verdict{Kind: expr.VerdictAccept},
+				),
+			})
+		}
+	}
+}
+
+// createRerouteRules marks local traffic to be routed back to the prerouting chain through the loopback interface,
+// replies of connections accepted by the tproxy listener are left alone.
+func (c *AutoConfigure) createRerouteRules(nft *nftables.Conn, table *nftables.Table, chain *nftables.Chain) {
+	nft.AddRule(&nftables.Rule{
+		Table: table,
+		Chain: chain,
+		Exprs: []expr.Any{
+			&expr.Ct{Key: expr.CtKeyDIRECTION, Register: 1},
+			&expr.Cmp{
+				Op:       expr.CmpOpEq,
+				Register: 1,
+				// IP_CT_DIR_REPLY
+				Data: []byte{1},
+			},
+			&expr.Verdict{Kind: expr.VerdictReturn},
+		},
+	})
+	for _, protocol := range c.protocols() {
+		nft.AddRule(&nftables.Rule{
+			Table: table,
+			Chain: chain,
+			Exprs: append(matchProtocol(protocol),
+				&expr.Immediate{
+					Register: 1,
+					Data:     binaryutil.NativeEndian.PutUint32(c.options.TProxyMark),
+				},
+				&expr.Meta{
+					Key:            expr.MetaKeyMARK,
+					Register:       1,
+					SourceRegister: true,
+				},
+				&expr.Counter{},
+			),
+		})
+	}
+}
+
+func (c *AutoConfigure) protocols() []byte {
+	var protocols []byte
+	if len(c.options.Network) == 0 || common.Contains(c.options.Network, N.NetworkTCP) {
+		protocols = append(protocols, unix.IPPROTO_TCP)
+	}
+	if len(c.options.Network) == 0 || common.Contains(c.options.Network, N.NetworkUDP) {
+		protocols = append(protocols, unix.IPPROTO_UDP)
+	}
+	return protocols
+}
+
+func matchFamily(family nftables.TableFamily) []expr.Any {
+	return []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyNFPROTO, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     []byte{byte(family)},
+		},
+	}
+}
+
+func matchProtocol(protocol byte) []expr.Any {
+	return []expr.Any{
+		&expr.Meta{Key: expr.MetaKeyL4PROTO, Register: 1},
+		&expr.Cmp{
+			Op:       expr.CmpOpEq,
+			Register: 1,
+			Data:     []byte{protocol},
+		},
+	}
+}
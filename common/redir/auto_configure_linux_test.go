@@ -0,0 +1,39 @@
+package redir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservePolicy(t *testing.T) {
+	first, err := NewAutoConfigure(AutoConfigureOptions{TableName: "first", TProxy: true, ListenPort: 1})
+	require.NoError(t, err)
+	require.NoError(t, first.reservePolicy())
+	defer first.releasePolicy()
+	require.Equal(t, uint32(DefaultAutoConfigureMark), first.options.TProxyMark)
+	require.Equal(t, DefaultAutoConfigureTableIndex, first.options.TableIndex)
+	require.Equal(t, DefaultAutoConfigureRuleIndex, first.options.RuleIndex)
+
+	second, err := NewAutoConfigure(AutoConfigureOptions{TableName: "second", TProxy: true, ListenPort: 2})
+	require.NoError(t, err)
+	require.NoError(t, second.reservePolicy())
+	require.Equal(t, uint32(DefaultAutoConfigureMark+1), second.options.TProxyMark)
+	require.Equal(t, DefaultAutoConfigureTableIndex+1, second.options.TableIndex)
+	require.Equal(t, DefaultAutoConfigureRuleIndex+1, second.options.RuleIndex)
+	second.releasePolicy()
+
+	conflict, err := NewAutoConfigure(AutoConfigureOptions{TableName: "conflict", TProxy: true, ListenPort: 3, TableIndex: DefaultAutoConfigureTableIndex})
+	require.NoError(t, err)
+	require.ErrorContains(t, conflict.reservePolicy(), "iproute2_table_index")
+
+	duplicate, err := NewAutoConfigure(AutoConfigureOptions{TableName: "first", ListenPort: 4})
+	require.NoError(t, err)
+	require.ErrorContains(t, duplicate.reservePolicy(), "already used")
+
+	reused, err := NewAutoConfigure(AutoConfigureOptions{TableName: "reused", TProxy: true, ListenPort: 5})
+	require.NoError(t, err)
+	require.NoError(t, reused.reservePolicy())
+	defer reused.releasePolicy()
+	require.Equal(t, DefaultAutoConfigureTableIndex+1, reused.options.TableIndex)
+}
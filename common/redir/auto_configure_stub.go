@@ -0,0 +1,25 @@
+//go:build !linux
+
+package redir
+
+import (
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+type AutoConfigure struct{}
+
+func NewAutoConfigure(options AutoConfigureOptions) (*AutoConfigure, error) {
+	return nil, E.New("auto_configure is only supported on Linux")
+}
+
+func (c *AutoConfigure) Start() error {
+	return nil
+}
+
+func (c *AutoConfigure) UpdateExcludeAddressSet() error {
+	return nil
+}
+
+func (c *AutoConfigure) Close() error {
+	return nil
+}
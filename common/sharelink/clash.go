@@ -0,0 +1,648 @@
+package sharelink
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+
+	"gopkg.in/yaml.v3"
+)
+
+type clashConfig struct {
+	Proxies []clashProxy `yaml:"proxies"`
+}
+
+type clashProxy struct {
+	Name   string      `yaml:"name"`
+	Type   string      `yaml:"type"`
+	Server string      `yaml:"server"`
+	Port   clashNumber `yaml:"port"`
+	UDP    bool        `yaml:"udp,omitempty"`
+
+	Password string      `yaml:"password,omitempty"`
+	Cipher   string      `yaml:"cipher,omitempty"`
+	UUID     string      `yaml:"uuid,omitempty"`
+	AlterID  clashNumber `yaml:"alterId,omitempty"`
+	Flow     string      `yaml:"flow,omitempty"`
+
+	PacketEncoding string         `yaml:"packet-encoding,omitempty"`
+	Plugin         string         `yaml:"plugin,omitempty"`
+	PluginOptions  map[string]any `yaml:"plugin-opts,omitempty"`
+
+	TLS               bool              `yaml:"tls,omitempty"`
+	SkipCertVerify    bool              `yaml:"skip-cert-verify,omitempty"`
+	ServerName        string            `yaml:"servername,omitempty"`
+	SNI               string            `yaml:"sni,omitempty"`
+	ALPN              []string          `yaml:"alpn,omitempty"`
+	ClientFingerprint string            `yaml:"client-fingerprint,omitempty"`
+	RealityOptions    *clashRealityOpts `yaml:"reality-opts,omitempty"`
+
+	Network     string         `yaml:"network,omitempty"`
+	WSOptions   *clashWSOpts   `yaml:"ws-opts,omitempty"`
+	GRPCOptions *clashGRPCOpts `yaml:"grpc-opts,omitempty"`
+	H2Options   *clashH2Opts   `yaml:"h2-opts,omitempty"`
+
+	Ports        string      `yaml:"ports,omitempty"`
+	Up           clashNumber `yaml:"up,omitempty"`
+	Down         clashNumber `yaml:"down,omitempty"`
+	Obfs         string      `yaml:"obfs,omitempty"`
+	ObfsPassword string      `yaml:"obfs-password,omitempty"`
+
+	CongestionController string `yaml:"congestion-controller,omitempty"`
+	UDPRelayMode         string `yaml:"udp-relay-mode,omitempty"`
+	ReduceRTT            bool   `yaml:"reduce-rtt,omitempty"`
+	DisableSNI           bool   `yaml:"disable-sni,omitempty"`
+	HeartbeatInterval    int    `yaml:"heartbeat-interval,omitempty"`
+
+	IP           string        `yaml:"ip,omitempty"`
+	IPv6         string        `yaml:"ipv6,omitempty"`
+	PrivateKey   string        `yaml:"private-key,omitempty"`
+	PublicKey    string        `yaml:"public-key,omitempty"`
+	PreSharedKey string        `yaml:"pre-shared-key,omitempty"`
+	Reserved     clashReserved `yaml:"reserved,omitempty"`
+	MTU          uint32        `yaml:"mtu,omitempty"`
+}
+
+type clashRealityOpts struct {
+	PublicKey string `yaml:"public-key"`
+	ShortID   string `yaml:"short-id,omitempty"`
+}
+
+type clashWSOpts struct {
+	Path                string            `yaml:"path,omitempty"`
+	Headers             map[string]string `yaml:"headers,omitempty"`
+	MaxEarlyData        uint32            `yaml:"max-early-data,omitempty"`
+	EarlyDataHeaderName string            `yaml:"early-data-header-name,omitempty"`
+}
+
+type clashGRPCOpts struct {
+	ServiceName string `yaml:"grpc-service-name,omitempty"`
+}
+
+type clashH2Opts struct {
+	Host []string `yaml:"host,omitempty"`
+	Path string   `yaml:"path,omitempty"`
+}
+
+// clashNumber accepts both numbers and strings, and is written as a number if possible.
+type clashNumber string
+
+func (n clashNumber) MarshalYAML() (any, error) {
+	if value, err := strconv.Atoi(string(n)); err == nil {
+		return value, nil
+	}
+	return string(n), nil
+}
+
+func (n clashNumber) Int() (int, error) {
+	if n == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(string(n))
+}
+
+// clashReserved accepts a list of bytes or a base64 string.
+type clashReserved []uint8
+
+func (r *clashReserved) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		content, err := base64.StdEncoding.DecodeString(node.Value)
+		if err != nil {
+			return E.Cause(err, "decode reserved")
+		}
+		*r = content
+		return nil
+	}
+	var reserved []uint8
+	err := node.Decode(&reserved)
+	if err != nil {
+		return err
+	}
+	*r = reserved
+	return nil
+}
+
+// ParseClash parses outbounds from the proxies list of a Clash configuration.
+func ParseClash(content []byte) ([]option.Outbound, error) {
+	var config clashConfig
+	err := yaml.Unmarshal(content, &config)
+	if err != nil {
+		return nil, E.Cause(err, "decode clash config")
+	}
+	if len(config.Proxies) == 0 {
+		return nil, E.New("no proxies found")
+	}
+	outbounds := make([]option.Outbound, 0, len(config.Proxies))
+	for index, proxy := range config.Proxies {
+		outbound, err := proxy.build()
+		if err != nil {
+			if proxy.Name != "" {
+				return nil, E.Cause(err, "parse proxy ", proxy.Name)
+			}
+			return nil, E.Cause(err, "parse proxy[", index, "]")
+		}
+		outbounds = append(outbounds, outbound)
+	}
+	return uniqueTags(outbounds), nil
+}
+
+// FormatClash formats outbounds as a Clash proxies list.
+func FormatClash(outbounds []option.Outbound) ([]byte, error) {
+	var config clashConfig
+	for _, outbound := range outbounds {
+		proxy, err := newClashProxy(outbound)
+		if err != nil {
+			return nil, E.Cause(err, "format outbound ", outbound.Tag)
+		}
+		config.Proxies = append(config.Proxies, proxy)
+	}
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	err := encoder.Encode(config)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (p *clashProxy) build() (option.Outbound, error) {
+	server, err := parseServer(p.Server, string(p.Port))
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	outbound := option.Outbound{
+		Tag: p.Name,
+	}
+	switch p.Type {
+	case "ss":
+		outbound.Type = C.TypeShadowsocks
+		outbound.ShadowsocksOptions = option.ShadowsocksOutboundOptions{
+			ServerOptions: server,
+			Method:        p.Cipher,
+			Password:      p.Password,
+		}
+		outbound.ShadowsocksOptions.Plugin, outbound.ShadowsocksOptions.PluginOptions, err = p.buildPlugin()
+	case "vmess":
+		outbound.Type = C.TypeVMess
+		outbound.VMessOptions = option.VMessOutboundOptions{
+			ServerOptions: server,
+			UUID:          p.UUID,
+			Security:      p.Cipher,
+		}
+		if outbound.VMessOptions.Security == "" {
+			outbound.VMessOptions.Security = "auto"
+		}
+		outbound.VMessOptions.AlterId, err = p.AlterID.Int()
+		if err != nil {
+			return option.Outbound{}, E.New("invalid alterId: ", p.AlterID)
+		}
+		outbound.VMessOptions.PacketEncoding = p.PacketEncoding
+		if p.TLS {
+			outbound.VMessOptions.TLS = p.buildTLS(p.ServerName)
+		}
+		outbound.VMessOptions.Transport, err = p.buildTransport()
+	case "vless":
+		outbound.Type = C.TypeVLESS
+		outbound.VLESSOptions = option.VLESSOutboundOptions{
+			ServerOptions: server,
+			UUID:          p.UUID,
+			Flow:          p.Flow,
+		}
+		if p.PacketEncoding != "" {
+			packetEncoding := p.PacketEncoding
+			outbound.VLESSOptions.PacketEncoding = &packetEncoding
+		}
+		if p.TLS {
+			outbound.VLESSOptions.TLS = p.buildTLS(p.ServerName)
+		}
+		outbound.VLESSOptions.Transport, err = p.buildTransport()
+	case "trojan":
+		outbound.Type = C.TypeTrojan
+		outbound.TrojanOptions = option.TrojanOutboundOptions{
+			ServerOptions: server,
+			Password:      p.Password,
+			OutboundTLSOptionsContainer: option.OutboundTLSOptionsContainer{
+				TLS: p.buildTLS(p.SNI),
+			},
+		}
+		outbound.TrojanOptions.Transport, err = p.buildTransport()
+	case "hysteria2":
+		outbound.Type = C.TypeHysteria2
+		outbound.Hysteria2Options = option.Hysteria2OutboundOptions{
+			ServerOptions: server,
+			Password:      p.Password,
+			OutboundTLSOptionsContainer: option.OutboundTLSOptionsContainer{
+				TLS: p.buildTLS(p.SNI),
+			},
+		}
+		for _, portRange := range splitList(p.Ports) {
+			start, end, isRange := strings.Cut(portRange, "-")
+			if !isRange {
+				end = start
+			}
+			outbound.Hysteria2Options.ServerPorts = append(outbound.Hysteria2Options.ServerPorts, start+":"+end)
+		}
+		if p.Obfs != "" {
+			outbound.Hysteria2Options.Obfs = &option.Hysteria2Obfs{
+				Type:     p.Obfs,
+				Password: p.ObfsPassword,
+			}
+		}
+		outbound.Hysteria2Options.UpMbps, err = parseClashBandwidth(p.Up)
+		if err == nil {
+			outbound.Hysteria2Options.DownMbps, err = parseClashBandwidth(p.Down)
+		}
+	case "tuic":
+		outbound.Type = C.TypeTUIC
+		outbound.TUICOptions = option.TUICOutboundOptions{
+			ServerOptions:     server,
+			UUID:              p.UUID,
+			Password:          p.Password,
+			CongestionControl: p.CongestionController,
+			UDPRelayMode:      p.UDPRelayMode,
+			ZeroRTTHandshake:  p.ReduceRTT,
+			Heartbeat:         option.Duration(time.Duration(p.HeartbeatInterval) * time.Millisecond),
+			OutboundTLSOptionsContainer: option.OutboundTLSOptionsContainer{
+				TLS: p.buildTLS(p.SNI),
+			},
+		}
+		outbound.TUICOptions.TLS.DisableSNI = p.DisableSNI
+	case "wireguard":
+		outbound.Type = C.TypeWireGuard
+		outbound.WireGuardOptions = option.WireGuardOutboundOptions{
+			ServerOptions: server,
+			PrivateKey:    p.PrivateKey,
+			PeerPublicKey: p.PublicKey,
+			PreSharedKey:  p.PreSharedKey,
+			Reserved:      p.Reserved,
+			MTU:           p.MTU,
+		}
+		for _, address := range []string{p.IP, p.IPv6} {
+			if address == "" {
+				continue
+			}
+			var prefix netip.Prefix
+			if strings.Contains(address, "/") {
+				prefix, err = netip.ParsePrefix(address)
+			} else {
+				var addr netip.Addr
+				addr, err = netip.ParseAddr(address)
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+			if err != nil {
+				return option.Outbound{}, E.Cause(err, "parse address")
+			}
+			outbound.WireGuardOptions.LocalAddress = append(outbound.WireGuardOptions.LocalAddress, prefix)
+		}
+	default:
+		return option.Outbound{}, E.New("unsupported proxy type: ", p.Type)
+	}
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	return outbound, nil
+}
+
+func (p *clashProxy) buildTLS(serverName string) *option.OutboundTLSOptions {
+	tlsOptions := &option.OutboundTLSOptions{
+		Enabled:    true,
+		ServerName: serverName,
+		Insecure:   p.SkipCertVerify,
+		ALPN:       p.ALPN,
+	}
+	if p.ClientFingerprint != "" {
+		tlsOptions.UTLS = &option.OutboundUTLSOptions{
+			Enabled:     true,
+			Fingerprint: p.ClientFingerprint,
+		}
+	}
+	if p.RealityOptions != nil {
+		tlsOptions.Reality = &option.OutboundRealityOptions{
+			Enabled:   true,
+			PublicKey: p.RealityOptions.PublicKey,
+			ShortID:   p.RealityOptions.ShortID,
+		}
+		if tlsOptions.UTLS == nil {
+			tlsOptions.UTLS = &option.OutboundUTLSOptions{
+				Enabled:     true,
+				Fingerprint: "chrome",
+			}
+		}
+	}
+	return tlsOptions
+}
+
+func (p *clashProxy) buildTransport() (*option.V2RayTransportOptions, error) {
+	switch p.Network {
+	case "", "tcp":
+		return nil, nil
+	case "ws":
+		options := option.V2RayWebsocketOptions{}
+		if p.WSOptions != nil {
+			options.Path, options.MaxEarlyData = parseEarlyData(p.WSOptions.Path)
+			if p.WSOptions.MaxEarlyData > 0 {
+				options.MaxEarlyData = p.WSOptions.MaxEarlyData
+				options.EarlyDataHeaderName = p.WSOptions.EarlyDataHeaderName
+			} else if options.MaxEarlyData > 0 {
+				options.EarlyDataHeaderName = earlyDataHeaderName
+			}
+			if len(p.WSOptions.Headers) > 0 {
+				options.Headers = make(option.HTTPHeader)
+				for key, value := range p.WSOptions.Headers {
+					options.Headers[key] = option.Listable[string]{value}
+				}
+			}
+		}
+		return &option.V2RayTransportOptions{
+			Type:             C.V2RayTransportTypeWebsocket,
+			WebsocketOptions: options,
+		}, nil
+	case "grpc":
+		options := option.V2RayGRPCOptions{}
+		if p.GRPCOptions != nil {
+			options.ServiceName = p.GRPCOptions.ServiceName
+		}
+		return &option.V2RayTransportOptions{
+			Type:        C.V2RayTransportTypeGRPC,
+			GRPCOptions: options,
+		}, nil
+	case "h2":
+		options := option.V2RayHTTPOptions{}
+		if p.H2Options != nil {
+			options.Host = p.H2Options.Host
+			options.Path = p.H2Options.Path
+		}
+		return &option.V2RayTransportOptions{
+			Type:        C.V2RayTransportTypeHTTP,
+			HTTPOptions: options,
+		}, nil
+	default:
+		return nil, E.New("unsupported network: ", p.Network)
+	}
+}
+
+// buildPlugin converts Clash obfs and v2ray-plugin options to SIP003 plugin options.
+func (p *clashProxy) buildPlugin() (string, string, error) {
+	var pluginOptions []string
+	pluginOption := func(name string) string {
+		return F.ToString(p.PluginOptions[name])
+	}
+	switch p.Plugin {
+	case "":
+		return "", "", nil
+	case "obfs":
+		pluginOptions = append(pluginOptions, "obfs="+pluginOption("mode"))
+		if host := pluginOption("host"); host != "" {
+			pluginOptions = append(pluginOptions, "obfs-host="+host)
+		}
+		return "obfs-local", strings.Join(pluginOptions, ";"), nil
+	case "v2ray-plugin":
+		pluginOptions = append(pluginOptions, "mode="+pluginOption("mode"))
+		if pluginOption("tls") == "true" {
+			pluginOptions = append(pluginOptions, "tls")
+		}
+		if host := pluginOption("host"); host != "" {
+			pluginOptions = append(pluginOptions, "host="+host)
+		}
+		if path := pluginOption("path"); path != "" {
+			pluginOptions = append(pluginOptions, "path="+path)
+		}
+		if pluginOption("mux") == "true" {
+			pluginOptions = append(pluginOptions, "mux=1")
+		}
+		return "v2ray-plugin", strings.Join(pluginOptions, ";"), nil
+	default:
+		return "", "", E.New("unsupported plugin: ", p.Plugin)
+	}
+}
+
+func parseClashBandwidth(value clashNumber) (int, error) {
+	bandwidth := strings.TrimSpace(strings.TrimSuffix(strings.ToLower(string(value)), "mbps"))
+	if bandwidth == "" {
+		return 0, nil
+	}
+	mbps, err := strconv.Atoi(bandwidth)
+	if err != nil {
+		return 0, E.New("invalid bandwidth: ", value)
+	}
+	return mbps, nil
+}
+
+func newClashProxy(outbound option.Outbound) (clashProxy, error) {
+	var (
+		proxy clashProxy
+		err   error
+	)
+	proxy.Name = outbound.Tag
+	switch outbound.Type {
+	case C.TypeShadowsocks:
+		options := outbound.ShadowsocksOptions
+		proxy.Type = "ss"
+		proxy.setServer(options.ServerOptions)
+		proxy.Cipher = options.Method
+		proxy.Password = options.Password
+		err = proxy.setPlugin(options.Plugin, options.PluginOptions)
+	case C.TypeVMess:
+		options := outbound.VMessOptions
+		proxy.Type = "vmess"
+		proxy.setServer(options.ServerOptions)
+		proxy.UUID = options.UUID
+		proxy.AlterID = clashNumber(strconv.Itoa(options.AlterId))
+		proxy.Cipher = options.Security
+		proxy.PacketEncoding = options.PacketEncoding
+		proxy.TLS = proxy.setTLS(options.TLS, &proxy.ServerName)
+		err = proxy.setTransport(options.Transport)
+	case C.TypeVLESS:
+		options := outbound.VLESSOptions
+		proxy.Type = "vless"
+		proxy.setServer(options.ServerOptions)
+		proxy.UUID = options.UUID
+		proxy.Flow = options.Flow
+		if options.PacketEncoding != nil {
+			proxy.PacketEncoding = *options.PacketEncoding
+		}
+		proxy.TLS = proxy.setTLS(options.TLS, &proxy.ServerName)
+		err = proxy.setTransport(options.Transport)
+	case C.TypeTrojan:
+		options := outbound.TrojanOptions
+		proxy.Type = "trojan"
+		proxy.setServer(options.ServerOptions)
+		proxy.Password = options.Password
+		proxy.setTLS(options.TLS, &proxy.SNI)
+		err = proxy.setTransport(options.Transport)
+	case C.TypeHysteria2:
+		options := outbound.Hysteria2Options
+		proxy.Type = "hysteria2"
+		proxy.setServer(options.ServerOptions)
+		proxy.Password = options.Password
+		var ports []string
+		for _, portRange := range options.ServerPorts {
+			start, end, _ := strings.Cut(portRange, ":")
+			if start == end || end == "" {
+				ports = append(ports, start)
+			} else {
+				ports = append(ports, start+"-"+end)
+			}
+		}
+		proxy.Ports = strings.Join(ports, ",")
+		if options.Obfs != nil {
+			proxy.Obfs = options.Obfs.Type
+			proxy.ObfsPassword = options.Obfs.Password
+		}
+		if options.UpMbps > 0 {
+			proxy.Up = clashNumber(F.ToString(options.UpMbps, " Mbps"))
+		}
+		if options.DownMbps > 0 {
+			proxy.Down = clashNumber(F.ToString(options.DownMbps, " Mbps"))
+		}
+		proxy.setTLS(options.TLS, &proxy.SNI)
+	case C.TypeTUIC:
+		options := outbound.TUICOptions
+		proxy.Type = "tuic"
+		proxy.setServer(options.ServerOptions)
+		proxy.UUID = options.UUID
+		proxy.Password = options.Password
+		proxy.CongestionController = options.CongestionControl
+		proxy.UDPRelayMode = options.UDPRelayMode
+		proxy.ReduceRTT = options.ZeroRTTHandshake
+		proxy.HeartbeatInterval = int(time.Duration(options.Heartbeat).Milliseconds())
+		proxy.setTLS(options.TLS, &proxy.SNI)
+		if options.TLS != nil {
+			proxy.DisableSNI = options.TLS.DisableSNI
+		}
+	case C.TypeWireGuard:
+		options := outbound.WireGuardOptions
+		if len(options.Peers) > 0 {
+			return clashProxy{}, E.New("multiple peers are not supported")
+		}
+		proxy.Type = "wireguard"
+		proxy.setServer(options.ServerOptions)
+		proxy.PrivateKey = options.PrivateKey
+		proxy.PublicKey = options.PeerPublicKey
+		proxy.PreSharedKey = options.PreSharedKey
+		proxy.Reserved = options.Reserved
+		proxy.MTU = options.MTU
+		for _, prefix := range options.LocalAddress {
+			if prefix.Addr().Is4() && proxy.IP == "" {
+				proxy.IP = prefix.Addr().String()
+			} else if prefix.Addr().Is6() && proxy.IPv6 == "" {
+				proxy.IPv6 = prefix.Addr().String()
+			}
+		}
+	default:
+		return clashProxy{}, E.New("unsupported outbound type: ", outbound.Type)
+	}
+	if err != nil {
+		return clashProxy{}, err
+	}
+	return proxy, nil
+}
+
+func (p *clashProxy) setServer(server option.ServerOptions) {
+	p.Server = server.Server
+	p.Port = clashNumber(strconv.Itoa(int(server.ServerPort)))
+	p.UDP = true
+}
+
+func (p *clashProxy) setTLS(tlsOptions *option.OutboundTLSOptions, serverName *string) bool {
+	if tlsOptions == nil || !tlsOptions.Enabled {
+		return false
+	}
+	*serverName = tlsOptions.ServerName
+	p.SkipCertVerify = tlsOptions.Insecure
+	p.ALPN = tlsOptions.ALPN
+	if tlsOptions.UTLS != nil && tlsOptions.UTLS.Enabled {
+		p.ClientFingerprint = tlsOptions.UTLS.Fingerprint
+	}
+	if tlsOptions.Reality != nil && tlsOptions.Reality.Enabled {
+		p.RealityOptions = &clashRealityOpts{
+			PublicKey: tlsOptions.Reality.PublicKey,
+			ShortID:   tlsOptions.Reality.ShortID,
+		}
+	}
+	return true
+}
+
+func (p *clashProxy) setTransport(transport *option.V2RayTransportOptions) error {
+	if transport == nil {
+		return nil
+	}
+	switch transport.Type {
+	case C.V2RayTransportTypeWebsocket:
+		p.Network = "ws"
+		p.WSOptions = &clashWSOpts{
+			Path:                transport.WebsocketOptions.Path,
+			MaxEarlyData:        transport.WebsocketOptions.MaxEarlyData,
+			EarlyDataHeaderName: transport.WebsocketOptions.EarlyDataHeaderName,
+		}
+		if len(transport.WebsocketOptions.Headers) > 0 {
+			p.WSOptions.Headers = make(map[string]string)
+			for key, values := range transport.WebsocketOptions.Headers {
+				if len(values) > 0 {
+					p.WSOptions.Headers[key] = values[0]
+				}
+			}
+		}
+	case C.V2RayTransportTypeGRPC:
+		p.Network = "grpc"
+		p.GRPCOptions = &clashGRPCOpts{
+			ServiceName: transport.GRPCOptions.ServiceName,
+		}
+	case C.V2RayTransportTypeHTTP:
+		p.Network = "h2"
+		p.H2Options = &clashH2Opts{
+			Host: transport.HTTPOptions.Host,
+			Path: transport.HTTPOptions.Path,
+		}
+	default:
+		return E.New("unsupported transport type: ", transport.Type)
+	}
+	return nil
+}
+
+func (p *clashProxy) setPlugin(plugin string, pluginOptions string) error {
+	if plugin == "" {
+		return nil
+	}
+	options := make(map[string]any)
+	for _, pluginOption := range strings.Split(pluginOptions, ";") {
+		key, value, _ := strings.Cut(pluginOption, "=")
+		switch plugin {
+		case "obfs-local":
+			switch key {
+			case "obfs":
+				options["mode"] = value
+			case "obfs-host":
+				options["host"] = value
+			}
+		case "v2ray-plugin":
+			switch key {
+			case "mode", "host", "path":
+				options[key] = value
+			case "tls":
+				options["tls"] = true
+			case "mux":
+				options["mux"] = value != "0"
+			}
+		}
+	}
+	switch plugin {
+	case "obfs-local":
+		p.Plugin = "obfs"
+	case "v2ray-plugin":
+		p.Plugin = "v2ray-plugin"
+	default:
+		return E.New("unsupported plugin: ", plugin)
+	}
+	p.PluginOptions = options
+	return nil
+}
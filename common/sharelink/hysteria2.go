@@ -0,0 +1,108 @@
+package sharelink
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// parseHysteria2 parses a hysteria2 link, port hopping lists like `443,5000-6000` are not valid URL ports
+// so the host is replaced before parsing.
+func parseHysteria2(link string) (option.Outbound, error) {
+	scheme, content, _ := strings.Cut(link, "://")
+	authorityEnd := strings.IndexAny(content, "/?#")
+	if authorityEnd < 0 {
+		authorityEnd = len(content)
+	}
+	authority := content[:authorityEnd]
+	userInfo, host, hasUser := strings.Cut(authority, "@")
+	if !hasUser {
+		host = userInfo
+		userInfo = ""
+	}
+	var serverPorts []string
+	portIndex := strings.LastIndexByte(host, ':')
+	if portIndex < 0 || strings.HasSuffix(host, "]") {
+		host += ":443"
+	} else if ports := host[portIndex+1:]; strings.ContainsAny(ports, ",-") {
+		for _, portRange := range splitList(ports) {
+			if strings.Contains(portRange, "-") {
+				serverPorts = append(serverPorts, strings.Replace(portRange, "-", ":", 1))
+			} else {
+				serverPorts = append(serverPorts, portRange+":"+portRange)
+			}
+		}
+		firstPort, _, _ := strings.Cut(ports, ",")
+		firstPort, _, _ = strings.Cut(firstPort, "-")
+		host = host[:portIndex+1] + firstPort
+	}
+	if hasUser {
+		host = userInfo + "@" + host
+	}
+	linkURL, server, err := parseURL(scheme + "://" + host + content[authorityEnd:])
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	query := linkURL.Query()
+	options := option.Hysteria2OutboundOptions{
+		ServerOptions: server,
+		ServerPorts:   serverPorts,
+		Password:      linkURL.User.Username(),
+	}
+	if password, loaded := linkURL.User.Password(); loaded {
+		options.Password += ":" + password
+	}
+	if obfs := query.Get("obfs"); obfs != "" {
+		if obfs != "salamander" {
+			return option.Outbound{}, E.New("unsupported obfs: ", obfs)
+		}
+		options.Obfs = &option.Hysteria2Obfs{
+			Type:     obfs,
+			Password: query.Get("obfs-password"),
+		}
+	}
+	options.TLS = &option.OutboundTLSOptions{
+		Enabled:    true,
+		ServerName: query.Get("sni"),
+		Insecure:   isTrue(query.Get("insecure")),
+	}
+	return option.Outbound{
+		Type:             C.TypeHysteria2,
+		Tag:              linkURL.Fragment,
+		Hysteria2Options: options,
+	}, nil
+}
+
+func formatHysteria2(tag string, options option.Hysteria2OutboundOptions) (string, error) {
+	query := url.Values{}
+	if options.Obfs != nil && options.Obfs.Type != "" {
+		query.Set("obfs", options.Obfs.Type)
+		query.Set("obfs-password", options.Obfs.Password)
+	}
+	if options.TLS != nil {
+		if options.TLS.ServerName != "" {
+			query.Set("sni", options.TLS.ServerName)
+		}
+		if options.TLS.Insecure {
+			query.Set("insecure", "1")
+		}
+	}
+	host := formatHost(options.ServerOptions)
+	if len(options.ServerPorts) > 0 {
+		ports := make([]string, 0, len(options.ServerPorts))
+		for _, portRange := range options.ServerPorts {
+			start, end, _ := strings.Cut(portRange, ":")
+			if start == end || end == "" {
+				ports = append(ports, start)
+			} else {
+				ports = append(ports, start+"-"+end)
+			}
+		}
+		host = strings.TrimSuffix(host, strconv.Itoa(int(options.ServerPort))) + strings.Join(ports, ",")
+	}
+	return buildLink("hysteria2", url.User(options.Password), host, query, tag), nil
+}
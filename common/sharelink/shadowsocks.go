@@ -0,0 +1,81 @@
+package sharelink
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// parseShadowsocks parses a SIP002 link, or the legacy link with the whole content encoded in base64.
+func parseShadowsocks(link string) (option.Outbound, error) {
+	content := link[len("ss://"):]
+	var tag string
+	if fragmentIndex := strings.IndexByte(content, '#'); fragmentIndex >= 0 {
+		tag, _ = url.PathUnescape(content[fragmentIndex+1:])
+		content = content[:fragmentIndex]
+	}
+	if !strings.Contains(content, "@") {
+		decoded, err := decodeBase64(content)
+		if err != nil {
+			return option.Outbound{}, err
+		}
+		content = string(decoded)
+	}
+	linkURL, server, err := parseURL("ss://" + content)
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	var method, password string
+	if userPassword, loaded := linkURL.User.Password(); loaded {
+		method = linkURL.User.Username()
+		password = userPassword
+	} else {
+		userInfo, err := decodeBase64(linkURL.User.Username())
+		if err != nil {
+			return option.Outbound{}, E.Cause(err, "decode user info")
+		}
+		var found bool
+		method, password, found = strings.Cut(string(userInfo), ":")
+		if !found {
+			return option.Outbound{}, E.New("missing password")
+		}
+	}
+	options := option.ShadowsocksOutboundOptions{
+		ServerOptions: server,
+		Method:        method,
+		Password:      password,
+	}
+	if plugin := linkURL.Query().Get("plugin"); plugin != "" {
+		options.Plugin, options.PluginOptions, _ = strings.Cut(plugin, ";")
+		if options.Plugin == "simple-obfs" {
+			options.Plugin = "obfs-local"
+		}
+	}
+	return option.Outbound{
+		Type:               C.TypeShadowsocks,
+		Tag:                tag,
+		ShadowsocksOptions: options,
+	}, nil
+}
+
+func formatShadowsocks(tag string, options option.ShadowsocksOutboundOptions) (string, error) {
+	var user *url.Userinfo
+	if strings.HasPrefix(options.Method, "2022-") {
+		user = url.UserPassword(options.Method, options.Password)
+	} else {
+		user = url.User(base64.RawURLEncoding.EncodeToString([]byte(options.Method + ":" + options.Password)))
+	}
+	query := url.Values{}
+	if options.Plugin != "" {
+		plugin := options.Plugin
+		if options.PluginOptions != "" {
+			plugin += ";" + options.PluginOptions
+		}
+		query.Set("plugin", plugin)
+	}
+	return buildLink("ss", user, formatHost(options.ServerOptions), query, tag), nil
+}
@@ -0,0 +1,202 @@
+package sharelink
+
+import (
+	"encoding/base64"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+)
+
+// Parse parses a vmess, vless, ss, trojan, hysteria2, tuic or wireguard share link.
+func Parse(link string) (option.Outbound, error) {
+	link = strings.TrimSpace(link)
+	scheme, _, loaded := strings.Cut(link, "://")
+	if !loaded {
+		return option.Outbound{}, E.New("invalid share link: missing scheme")
+	}
+	var (
+		outbound option.Outbound
+		err      error
+	)
+	switch strings.ToLower(scheme) {
+	case "vmess":
+		outbound, err = parseVMess(link)
+	case "vless":
+		outbound, err = parseVLESS(link)
+	case "ss":
+		outbound, err = parseShadowsocks(link)
+	case "trojan":
+		outbound, err = parseTrojan(link)
+	case "hysteria2", "hy2":
+		outbound, err = parseHysteria2(link)
+	case "tuic":
+		outbound, err = parseTUIC(link)
+	case "wireguard", "wg":
+		outbound, err = parseWireGuard(link)
+	default:
+		return option.Outbound{}, E.New("unsupported share link scheme: ", scheme)
+	}
+	if err != nil {
+		return option.Outbound{}, E.Cause(err, "parse ", scheme, " link")
+	}
+	return outbound, nil
+}
+
+// ParseContent parses a Clash proxies list, or share links separated by lines,
+// optionally encoded in base64 as subscriptions usually are.
+func ParseContent(content string) ([]option.Outbound, error) {
+	content = strings.TrimSpace(content)
+	if isClash(content) {
+		return ParseClash([]byte(content))
+	}
+	if !strings.Contains(content, "://") {
+		decoded, err := decodeBase64(content)
+		if err != nil {
+			return nil, E.New("unknown content format")
+		}
+		content = string(decoded)
+	}
+	var outbounds []option.Outbound
+	for index, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		outbound, err := Parse(line)
+		if err != nil {
+			return nil, E.Cause(err, "line ", index+1)
+		}
+		outbounds = append(outbounds, outbound)
+	}
+	if len(outbounds) == 0 {
+		return nil, E.New("no share links found")
+	}
+	return uniqueTags(outbounds), nil
+}
+
+// Format formats an outbound as a share link.
+func Format(outbound option.Outbound) (string, error) {
+	switch outbound.Type {
+	case C.TypeVMess:
+		return formatVMess(outbound.Tag, outbound.VMessOptions)
+	case C.TypeVLESS:
+		return formatVLESS(outbound.Tag, outbound.VLESSOptions)
+	case C.TypeShadowsocks:
+		return formatShadowsocks(outbound.Tag, outbound.ShadowsocksOptions)
+	case C.TypeTrojan:
+		return formatTrojan(outbound.Tag, outbound.TrojanOptions)
+	case C.TypeHysteria2:
+		return formatHysteria2(outbound.Tag, outbound.Hysteria2Options)
+	case C.TypeTUIC:
+		return formatTUIC(outbound.Tag, outbound.TUICOptions)
+	case C.TypeWireGuard:
+		return formatWireGuard(outbound.Tag, outbound.WireGuardOptions)
+	default:
+		return "", E.New("unsupported outbound type for share link: ", outbound.Type)
+	}
+}
+
+// Supported returns whether the outbound type can be converted to share links and Clash proxies.
+func Supported(outboundType string) bool {
+	switch outboundType {
+	case C.TypeVMess, C.TypeVLESS, C.TypeShadowsocks, C.TypeTrojan, C.TypeHysteria2, C.TypeTUIC, C.TypeWireGuard:
+		return true
+	default:
+		return false
+	}
+}
+
+func isClash(content string) bool {
+	return strings.HasPrefix(content, "proxies:") || strings.Contains(content, "\nproxies:")
+}
+
+func decodeBase64(content string) ([]byte, error) {
+	content = strings.Join(strings.Fields(content), "")
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		decoded, err := encoding.DecodeString(content)
+		if err == nil {
+			return decoded, nil
+		}
+	}
+	return nil, E.New("invalid base64 content")
+}
+
+func uniqueTags(outbounds []option.Outbound) []option.Outbound {
+	tagCount := make(map[string]int)
+	for index := range outbounds {
+		outbound := &outbounds[index]
+		if outbound.Tag == "" {
+			outbound.Tag = outbound.Type
+		}
+		tagCount[outbound.Tag]++
+		if count := tagCount[outbound.Tag]; count > 1 {
+			outbound.Tag = F.ToString(outbound.Tag, " (", count, ")")
+		}
+	}
+	return outbounds
+}
+
+func parseServer(host string, port string) (option.ServerOptions, error) {
+	if port == "" {
+		return option.ServerOptions{}, E.New("missing server port")
+	}
+	serverPort, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return option.ServerOptions{}, E.New("invalid server port: ", port)
+	}
+	if host == "" {
+		return option.ServerOptions{}, E.New("missing server address")
+	}
+	return option.ServerOptions{
+		Server:     host,
+		ServerPort: uint16(serverPort),
+	}, nil
+}
+
+func formatHost(server option.ServerOptions) string {
+	return net.JoinHostPort(server.Server, strconv.Itoa(int(server.ServerPort)))
+}
+
+func parseURL(link string) (*url.URL, option.ServerOptions, error) {
+	linkURL, err := url.Parse(link)
+	if err != nil {
+		return nil, option.ServerOptions{}, err
+	}
+	server, err := parseServer(linkURL.Hostname(), linkURL.Port())
+	if err != nil {
+		return nil, option.ServerOptions{}, err
+	}
+	return linkURL, server, nil
+}
+
+func isTrue(value string) bool {
+	return value == "1" || strings.EqualFold(value, "true")
+}
+
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func buildLink(scheme string, user *url.Userinfo, host string, query url.Values, tag string) string {
+	linkURL := url.URL{
+		Scheme:   scheme,
+		User:     user,
+		Host:     host,
+		RawQuery: query.Encode(),
+		Fragment: tag,
+	}
+	return linkURL.String()
+}
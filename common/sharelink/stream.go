@@ -0,0 +1,222 @@
+package sharelink
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+const earlyDataHeaderName = "Sec-WebSocket-Protocol"
+
+// parseStreamTLS reads TLS parameters in the Xray share link format.
+func parseStreamTLS(query url.Values, defaultSecurity string) (*option.OutboundTLSOptions, error) {
+	security := query.Get("security")
+	if security == "" {
+		security = defaultSecurity
+	}
+	switch security {
+	case "", "none":
+		return nil, nil
+	case "tls", "xtls", "reality":
+	default:
+		return nil, E.New("unsupported security: ", security)
+	}
+	tlsOptions := &option.OutboundTLSOptions{
+		Enabled:    true,
+		ServerName: query.Get("sni"),
+		Insecure:   isTrue(query.Get("allowInsecure")) || isTrue(query.Get("insecure")),
+		ALPN:       splitList(query.Get("alpn")),
+	}
+	if fingerprint := query.Get("fp"); fingerprint != "" {
+		tlsOptions.UTLS = &option.OutboundUTLSOptions{
+			Enabled:     true,
+			Fingerprint: fingerprint,
+		}
+	}
+	if security == "reality" {
+		tlsOptions.Reality = &option.OutboundRealityOptions{
+			Enabled:   true,
+			PublicKey: query.Get("pbk"),
+			ShortID:   query.Get("sid"),
+		}
+		if tlsOptions.UTLS == nil {
+			tlsOptions.UTLS = &option.OutboundUTLSOptions{
+				Enabled:     true,
+				Fingerprint: "chrome",
+			}
+		}
+	}
+	return tlsOptions, nil
+}
+
+func formatStreamTLS(query url.Values, tlsOptions *option.OutboundTLSOptions) {
+	if tlsOptions == nil || !tlsOptions.Enabled {
+		query.Set("security", "none")
+		return
+	}
+	if tlsOptions.Reality != nil && tlsOptions.Reality.Enabled {
+		query.Set("security", "reality")
+		query.Set("pbk", tlsOptions.Reality.PublicKey)
+		if tlsOptions.Reality.ShortID != "" {
+			query.Set("sid", tlsOptions.Reality.ShortID)
+		}
+	} else {
+		query.Set("security", "tls")
+	}
+	if tlsOptions.ServerName != "" {
+		query.Set("sni", tlsOptions.ServerName)
+	}
+	if tlsOptions.Insecure {
+		query.Set("allowInsecure", "1")
+	}
+	if len(tlsOptions.ALPN) > 0 {
+		query.Set("alpn", strings.Join(tlsOptions.ALPN, ","))
+	}
+	if tlsOptions.UTLS != nil && tlsOptions.UTLS.Enabled && tlsOptions.UTLS.Fingerprint != "" {
+		query.Set("fp", tlsOptions.UTLS.Fingerprint)
+	}
+}
+
+// parseStreamTransport reads transport parameters in the Xray share link format.
+func parseStreamTransport(transportType string, headerType string, host string, path string) (*option.V2RayTransportOptions, error) {
+	switch transportType {
+	case "", "tcp", "raw":
+		if headerType != "" && headerType != "none" {
+			return nil, E.New("unsupported tcp header type: ", headerType)
+		}
+		return nil, nil
+	case C.V2RayTransportTypeWebsocket:
+		options := option.V2RayWebsocketOptions{}
+		if host != "" {
+			options.Headers = option.HTTPHeader{"Host": {host}}
+		}
+		options.Path, options.MaxEarlyData = parseEarlyData(path)
+		if options.MaxEarlyData > 0 {
+			options.EarlyDataHeaderName = earlyDataHeaderName
+		}
+		return &option.V2RayTransportOptions{
+			Type:             C.V2RayTransportTypeWebsocket,
+			WebsocketOptions: options,
+		}, nil
+	case C.V2RayTransportTypeHTTPUpgrade:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeHTTPUpgrade,
+			HTTPUpgradeOptions: option.V2RayHTTPUpgradeOptions{
+				Host: host,
+				Path: path,
+			},
+		}, nil
+	case C.V2RayTransportTypeGRPC:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeGRPC,
+			GRPCOptions: option.V2RayGRPCOptions{
+				ServiceName: path,
+			},
+		}, nil
+	case C.V2RayTransportTypeHTTP, "h2":
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeHTTP,
+			HTTPOptions: option.V2RayHTTPOptions{
+				Host: splitList(host),
+				Path: path,
+			},
+		}, nil
+	case C.V2RayTransportTypeQUIC:
+		return &option.V2RayTransportOptions{
+			Type: C.V2RayTransportTypeQUIC,
+		}, nil
+	default:
+		return nil, E.New("unsupported transport type: ", transportType)
+	}
+}
+
+// formatStreamTransport returns the type, host and path of a transport in the Xray share link format.
+func formatStreamTransport(transport *option.V2RayTransportOptions) (transportType string, host string, path string) {
+	if transport == nil {
+		return "tcp", "", ""
+	}
+	switch transport.Type {
+	case C.V2RayTransportTypeWebsocket:
+		path = transport.WebsocketOptions.Path
+		if transport.WebsocketOptions.MaxEarlyData > 0 && transport.WebsocketOptions.EarlyDataHeaderName == earlyDataHeaderName {
+			path = formatEarlyData(path, transport.WebsocketOptions.MaxEarlyData)
+		}
+		return C.V2RayTransportTypeWebsocket, headerValue(transport.WebsocketOptions.Headers, "Host"), path
+	case C.V2RayTransportTypeHTTPUpgrade:
+		return C.V2RayTransportTypeHTTPUpgrade, transport.HTTPUpgradeOptions.Host, transport.HTTPUpgradeOptions.Path
+	case C.V2RayTransportTypeGRPC:
+		return C.V2RayTransportTypeGRPC, "", transport.GRPCOptions.ServiceName
+	case C.V2RayTransportTypeHTTP:
+		return C.V2RayTransportTypeHTTP, strings.Join(transport.HTTPOptions.Host, ","), transport.HTTPOptions.Path
+	default:
+		return transport.Type, "", ""
+	}
+}
+
+func parseStreamQuery(query url.Values, defaultSecurity string) (*option.OutboundTLSOptions, *option.V2RayTransportOptions, error) {
+	tlsOptions, err := parseStreamTLS(query, defaultSecurity)
+	if err != nil {
+		return nil, nil, err
+	}
+	path := query.Get("path")
+	if query.Get("type") == C.V2RayTransportTypeGRPC {
+		path = query.Get("serviceName")
+	}
+	transport, err := parseStreamTransport(query.Get("type"), query.Get("headerType"), query.Get("host"), path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tlsOptions, transport, nil
+}
+
+func formatStreamQuery(query url.Values, tlsOptions *option.OutboundTLSOptions, transport *option.V2RayTransportOptions) {
+	formatStreamTLS(query, tlsOptions)
+	transportType, host, path := formatStreamTransport(transport)
+	query.Set("type", transportType)
+	if host != "" {
+		query.Set("host", host)
+	}
+	if path != "" {
+		if transportType == C.V2RayTransportTypeGRPC {
+			query.Set("serviceName", path)
+		} else {
+			query.Set("path", path)
+		}
+	}
+}
+
+// parseEarlyData extracts the `ed` parameter used by Xray for websocket early data.
+func parseEarlyData(path string) (string, uint32) {
+	pathURL, err := url.Parse(path)
+	if err != nil {
+		return path, 0
+	}
+	query := pathURL.Query()
+	earlyData, err := strconv.ParseUint(query.Get("ed"), 10, 32)
+	if err != nil {
+		return path, 0
+	}
+	query.Del("ed")
+	pathURL.RawQuery = query.Encode()
+	return pathURL.String(), uint32(earlyData)
+}
+
+func formatEarlyData(path string, maxEarlyData uint32) string {
+	if strings.Contains(path, "?") {
+		return path + "&ed=" + strconv.FormatUint(uint64(maxEarlyData), 10)
+	}
+	return path + "?ed=" + strconv.FormatUint(uint64(maxEarlyData), 10)
+}
+
+func headerValue(headers option.HTTPHeader, name string) string {
+	for key, values := range headers {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package sharelink
+
+import (
+	"net/url"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+func parseTrojan(link string) (option.Outbound, error) {
+	linkURL, server, err := parseURL(link)
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	options := option.TrojanOutboundOptions{
+		ServerOptions: server,
+		Password:      linkURL.User.Username(),
+	}
+	if options.Password == "" {
+		return option.Outbound{}, E.New("missing password")
+	}
+	options.TLS, options.Transport, err = parseStreamQuery(linkURL.Query(), "tls")
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	return option.Outbound{
+		Type:          C.TypeTrojan,
+		Tag:           linkURL.Fragment,
+		TrojanOptions: options,
+	}, nil
+}
+
+func formatTrojan(tag string, options option.TrojanOutboundOptions) (string, error) {
+	query := url.Values{}
+	formatStreamQuery(query, options.TLS, options.Transport)
+	return buildLink("trojan", url.User(options.Password), formatHost(options.ServerOptions), query, tag), nil
+}
@@ -0,0 +1,65 @@
+package sharelink
+
+import (
+	"net/url"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+func parseTUIC(link string) (option.Outbound, error) {
+	linkURL, server, err := parseURL(link)
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	query := linkURL.Query()
+	options := option.TUICOutboundOptions{
+		ServerOptions:     server,
+		UUID:              linkURL.User.Username(),
+		CongestionControl: query.Get("congestion_control"),
+		UDPRelayMode:      query.Get("udp_relay_mode"),
+	}
+	options.Password, _ = linkURL.User.Password()
+	if options.UUID == "" {
+		return option.Outbound{}, E.New("missing uuid")
+	}
+	options.TLS = &option.OutboundTLSOptions{
+		Enabled:    true,
+		ServerName: query.Get("sni"),
+		DisableSNI: isTrue(query.Get("disable_sni")),
+		Insecure:   isTrue(query.Get("allow_insecure")) || isTrue(query.Get("insecure")),
+		ALPN:       splitList(query.Get("alpn")),
+	}
+	return option.Outbound{
+		Type:        C.TypeTUIC,
+		Tag:         linkURL.Fragment,
+		TUICOptions: options,
+	}, nil
+}
+
+func formatTUIC(tag string, options option.TUICOutboundOptions) (string, error) {
+	query := url.Values{}
+	if options.CongestionControl != "" {
+		query.Set("congestion_control", options.CongestionControl)
+	}
+	if options.UDPRelayMode != "" {
+		query.Set("udp_relay_mode", options.UDPRelayMode)
+	}
+	if options.TLS != nil {
+		if options.TLS.ServerName != "" {
+			query.Set("sni", options.TLS.ServerName)
+		}
+		if options.TLS.DisableSNI {
+			query.Set("disable_sni", "1")
+		}
+		if options.TLS.Insecure {
+			query.Set("allow_insecure", "1")
+		}
+		if len(options.TLS.ALPN) > 0 {
+			query.Set("alpn", strings.Join(options.TLS.ALPN, ","))
+		}
+	}
+	return buildLink("tuic", url.UserPassword(options.UUID, options.Password), formatHost(options.ServerOptions), query, tag), nil
+}
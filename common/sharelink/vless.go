@@ -0,0 +1,53 @@
+package sharelink
+
+import (
+	"net/url"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+func parseVLESS(link string) (option.Outbound, error) {
+	linkURL, server, err := parseURL(link)
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	query := linkURL.Query()
+	if encryption := query.Get("encryption"); encryption != "" && encryption != "none" {
+		return option.Outbound{}, E.New("unsupported encryption: ", encryption)
+	}
+	options := option.VLESSOutboundOptions{
+		ServerOptions: server,
+		UUID:          linkURL.User.Username(),
+		Flow:          query.Get("flow"),
+	}
+	if options.UUID == "" {
+		return option.Outbound{}, E.New("missing uuid")
+	}
+	if packetEncoding := query.Get("packetEncoding"); packetEncoding != "" {
+		options.PacketEncoding = &packetEncoding
+	}
+	options.TLS, options.Transport, err = parseStreamQuery(query, "none")
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	return option.Outbound{
+		Type:         C.TypeVLESS,
+		Tag:          linkURL.Fragment,
+		VLESSOptions: options,
+	}, nil
+}
+
+func formatVLESS(tag string, options option.VLESSOutboundOptions) (string, error) {
+	query := url.Values{}
+	query.Set("encryption", "none")
+	if options.Flow != "" {
+		query.Set("flow", options.Flow)
+	}
+	if options.PacketEncoding != nil && *options.PacketEncoding != "" {
+		query.Set("packetEncoding", *options.PacketEncoding)
+	}
+	formatStreamQuery(query, options.TLS, options.Transport)
+	return buildLink("vless", url.User(options.UUID), formatHost(options.ServerOptions), query, tag), nil
+}
@@ -0,0 +1,125 @@
+package sharelink
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+	"github.com/sagernet/sing/common/json"
+)
+
+// vmessLink is the v2rayN vmess share link format, values may be strings or numbers.
+type vmessLink map[string]any
+
+func (l vmessLink) get(key string) string {
+	value, loaded := l[key]
+	if !loaded || value == nil {
+		return ""
+	}
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	default:
+		return F.ToString(typedValue)
+	}
+}
+
+func parseVMess(link string) (option.Outbound, error) {
+	content, err := decodeBase64(strings.TrimPrefix(link[len("vmess://"):], "/"))
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	var vmess vmessLink
+	err = json.Unmarshal(content, &vmess)
+	if err != nil {
+		return option.Outbound{}, E.Cause(err, "decode vmess json")
+	}
+	server, err := parseServer(vmess.get("add"), vmess.get("port"))
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	options := option.VMessOutboundOptions{
+		ServerOptions: server,
+		UUID:          vmess.get("id"),
+		Security:      vmess.get("scy"),
+	}
+	if options.UUID == "" {
+		return option.Outbound{}, E.New("missing uuid")
+	}
+	if options.Security == "" {
+		options.Security = "auto"
+	}
+	if alterID := vmess.get("aid"); alterID != "" {
+		options.AlterId, err = strconv.Atoi(alterID)
+		if err != nil {
+			return option.Outbound{}, E.New("invalid alter id: ", alterID)
+		}
+	}
+	security := vmess.get("tls")
+	if security != "" && security != "none" {
+		options.TLS = &option.OutboundTLSOptions{
+			Enabled:    true,
+			ServerName: vmess.get("sni"),
+			ALPN:       splitList(vmess.get("alpn")),
+			Insecure:   isTrue(vmess.get("allowInsecure")) || isTrue(vmess.get("skip-cert-verify")),
+		}
+		if fingerprint := vmess.get("fp"); fingerprint != "" {
+			options.TLS.UTLS = &option.OutboundUTLSOptions{
+				Enabled:     true,
+				Fingerprint: fingerprint,
+			}
+		}
+	}
+	options.Transport, err = parseStreamTransport(vmess.get("net"), vmess.get("type"), vmess.get("host"), vmess.get("path"))
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	return option.Outbound{
+		Type:         C.TypeVMess,
+		Tag:          vmess.get("ps"),
+		VMessOptions: options,
+	}, nil
+}
+
+func formatVMess(tag string, options option.VMessOutboundOptions) (string, error) {
+	transportType, host, path := formatStreamTransport(options.Transport)
+	vmess := vmessLink{
+		"v":    "2",
+		"ps":   tag,
+		"add":  options.Server,
+		"port": strconv.Itoa(int(options.ServerPort)),
+		"id":   options.UUID,
+		"aid":  strconv.Itoa(options.AlterId),
+		"scy":  options.Security,
+		"net":  transportType,
+		"type": "none",
+		"host": host,
+		"path": path,
+		"tls":  "",
+	}
+	if options.TLS != nil && options.TLS.Enabled {
+		if options.TLS.Reality != nil && options.TLS.Reality.Enabled {
+			return "", E.New("reality is not supported in vmess share links")
+		}
+		vmess["tls"] = "tls"
+		vmess["sni"] = options.TLS.ServerName
+		vmess["alpn"] = strings.Join(options.TLS.ALPN, ",")
+		if options.TLS.UTLS != nil && options.TLS.UTLS.Enabled {
+			vmess["fp"] = options.TLS.UTLS.Fingerprint
+		}
+		if options.TLS.Insecure {
+			vmess["allowInsecure"] = "1"
+		}
+	}
+	content, err := json.Marshal(vmess)
+	if err != nil {
+		return "", err
+	}
+	return "vmess://" + base64.StdEncoding.EncodeToString(content), nil
+}
@@ -0,0 +1,99 @@
+package sharelink
+
+import (
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+func parseWireGuard(link string) (option.Outbound, error) {
+	linkURL, server, err := parseURL(link)
+	if err != nil {
+		return option.Outbound{}, err
+	}
+	query := linkURL.Query()
+	options := option.WireGuardOutboundOptions{
+		ServerOptions: server,
+		PrivateKey:    linkURL.User.Username(),
+		PeerPublicKey: queryKey(query, "publickey"),
+		PreSharedKey:  queryKey(query, "presharedkey"),
+	}
+	if options.PrivateKey == "" {
+		return option.Outbound{}, E.New("missing private key")
+	}
+	if options.PeerPublicKey == "" {
+		return option.Outbound{}, E.New("missing public key")
+	}
+	for _, address := range splitList(query.Get("address")) {
+		var prefix netip.Prefix
+		if strings.Contains(address, "/") {
+			prefix, err = netip.ParsePrefix(address)
+		} else {
+			var addr netip.Addr
+			addr, err = netip.ParseAddr(address)
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		if err != nil {
+			return option.Outbound{}, E.Cause(err, "parse address")
+		}
+		options.LocalAddress = append(options.LocalAddress, prefix)
+	}
+	for _, value := range splitList(query.Get("reserved")) {
+		reserved, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return option.Outbound{}, E.New("invalid reserved: ", value)
+		}
+		options.Reserved = append(options.Reserved, uint8(reserved))
+	}
+	if mtu := query.Get("mtu"); mtu != "" {
+		mtuValue, err := strconv.ParseUint(mtu, 10, 32)
+		if err != nil {
+			return option.Outbound{}, E.New("invalid mtu: ", mtu)
+		}
+		options.MTU = uint32(mtuValue)
+	}
+	return option.Outbound{
+		Type:             C.TypeWireGuard,
+		Tag:              linkURL.Fragment,
+		WireGuardOptions: options,
+	}, nil
+}
+
+func formatWireGuard(tag string, options option.WireGuardOutboundOptions) (string, error) {
+	if len(options.Peers) > 0 {
+		return "", E.New("multiple peers are not supported in wireguard share links")
+	}
+	query := url.Values{}
+	query.Set("publickey", options.PeerPublicKey)
+	if options.PreSharedKey != "" {
+		query.Set("presharedkey", options.PreSharedKey)
+	}
+	if len(options.LocalAddress) > 0 {
+		addresses := make([]string, 0, len(options.LocalAddress))
+		for _, prefix := range options.LocalAddress {
+			addresses = append(addresses, prefix.String())
+		}
+		query.Set("address", strings.Join(addresses, ","))
+	}
+	if len(options.Reserved) > 0 {
+		reserved := make([]string, 0, len(options.Reserved))
+		for _, value := range options.Reserved {
+			reserved = append(reserved, strconv.Itoa(int(value)))
+		}
+		query.Set("reserved", strings.Join(reserved, ","))
+	}
+	if options.MTU > 0 {
+		query.Set("mtu", strconv.FormatUint(uint64(options.MTU), 10))
+	}
+	return buildLink("wireguard", url.User(options.PrivateKey), formatHost(options.ServerOptions), query, tag), nil
+}
+
+// queryKey reads a base64 key, which is often left unescaped so `+` would be decoded as a space.
+func queryKey(query url.Values, name string) string {
+	return strings.ReplaceAll(query.Get(name), " ", "+")
+}
@@ -38,10 +38,16 @@ const (
 	ruleItemWIFIBSSID
 	ruleItemAdGuardDomain
 	ruleItemProcessPathRegex
+	ruleItemCGroup
+	ruleItemSystemdUnit
+	ruleItemContainerID
+	ruleItemSchedule
+	ruleItemSourceIPASN
+	ruleItemIPASN
 	ruleItemFinal uint8 = 0xFF
 )
 
-func Read(reader io.Reader, recover bool) (ruleSet option.PlainRuleSet, err error) {
+func Read(reader io.Reader, recover bool) (ruleSetCompat option.PlainRuleSetCompat, err error) {
 	var magicBytes [3]byte
 	_, err = io.ReadFull(reader, magicBytes[:])
 	if err != nil {
@@ -54,10 +60,11 @@ func Read(reader io.Reader, recover bool) (ruleSet option.PlainRuleSet, err erro
 	var version uint8
 	err = binary.Read(reader, binary.BigEndian, &version)
 	if err != nil {
-		return ruleSet, err
+		return
 	}
-	if version > C.RuleSetVersion2 {
-		return ruleSet, E.New("unsupported version: ", version)
+	if version > C.RuleSetVersionCurrent {
+		err = E.New("unsupported version: ", version)
+		return
 	}
 	compressReader, err := zlib.NewReader(reader)
 	if err != nil {
@@ -68,28 +75,29 @@ func Read(reader io.Reader, recover bool) (ruleSet option.PlainRuleSet, err erro
 	if err != nil {
 		return
 	}
-	ruleSet.Rules = make([]option.HeadlessRule, length)
+	ruleSet := option.PlainRuleSet{
+		Rules: make([]option.HeadlessRule, length),
+	}
 	for i := uint64(0); i < length; i++ {
-		ruleSet.Rules[i], err = readRule(bReader, recover)
+		ruleSet.Rules[i], err = readRule(bReader, version, recover)
 		if err != nil {
 			err = E.Cause(err, "read rule[", i, "]")
 			return
 		}
 	}
+	ruleSetCompat.Version = int(version)
+	ruleSetCompat.Options = ruleSet
 	return
 }
 
-func Write(writer io.Writer, ruleSet option.PlainRuleSet, generateUnstable bool) error {
+func Write(writer io.Writer, ruleSet option.PlainRuleSet, version uint8) error {
+	if version < C.RuleSetVersion1 || version > C.RuleSetVersionCurrent {
+		return E.New("unsupported version: ", version)
+	}
 	_, err := writer.Write(MagicBytes[:])
 	if err != nil {
 		return err
 	}
-	var version uint8
-	if generateUnstable {
-		version = C.RuleSetVersion2
-	} else {
-		version = C.RuleSetVersion1
-	}
 	err = binary.Write(writer, binary.BigEndian, version)
 	if err != nil {
 		return err
@@ -104,7 +112,7 @@ func Write(writer io.Writer, ruleSet option.PlainRuleSet, generateUnstable bool)
 		return err
 	}
 	for _, rule := range ruleSet.Rules {
-		err = writeRule(bWriter, rule, generateUnstable)
+		err = writeRule(bWriter, rule, version)
 		if err != nil {
 			return err
 		}
@@ -116,7 +124,7 @@ func Write(writer io.Writer, ruleSet option.PlainRuleSet, generateUnstable bool)
 	return compressWriter.Close()
 }
 
-func readRule(reader varbin.Reader, recover bool) (rule option.HeadlessRule, err error) {
+func readRule(reader varbin.Reader, version uint8, recover bool) (rule option.HeadlessRule, err error) {
 	var ruleType uint8
 	err = binary.Read(reader, binary.BigEndian, &ruleType)
 	if err != nil {
@@ -125,28 +133,28 @@ func readRule(reader varbin.Reader, recover bool) (rule option.HeadlessRule, err
 	switch ruleType {
 	case 0:
 		rule.Type = C.RuleTypeDefault
-		rule.DefaultOptions, err = readDefaultRule(reader, recover)
+		rule.DefaultOptions, err = readDefaultRule(reader, version, recover)
 	case 1:
 		rule.Type = C.RuleTypeLogical
-		rule.LogicalOptions, err = readLogicalRule(reader, recover)
+		rule.LogicalOptions, err = readLogicalRule(reader, version, recover)
 	default:
 		err = E.New("unknown rule type: ", ruleType)
 	}
 	return
 }
 
-func writeRule(writer varbin.Writer, rule option.HeadlessRule, generateUnstable bool) error {
+func writeRule(writer varbin.Writer, rule option.HeadlessRule, version uint8) error {
 	switch rule.Type {
 	case C.RuleTypeDefault:
-		return writeDefaultRule(writer, rule.DefaultOptions, generateUnstable)
+		return writeDefaultRule(writer, rule.DefaultOptions, version)
 	case C.RuleTypeLogical:
-		return writeLogicalRule(writer, rule.LogicalOptions, generateUnstable)
+		return writeLogicalRule(writer, rule.LogicalOptions, version)
 	default:
 		panic("unknown rule type: " + rule.Type)
 	}
 }
 
-func readDefaultRule(reader varbin.Reader, recover bool) (rule option.DefaultHeadlessRule, err error) {
+func readDefaultRule(reader varbin.Reader, version uint8, recover bool) (rule option.DefaultHeadlessRule, err error) {
 	var lastItemType uint8
 	for {
 		var itemType uint8
@@ -155,6 +163,13 @@ func readDefaultRule(reader varbin.Reader, recover bool) (rule option.DefaultHea
 			return
 		}
 		switch itemType {
+		case ruleItemCGroup, ruleItemSystemdUnit, ruleItemContainerID, ruleItemSchedule, ruleItemSourceIPASN, ruleItemIPASN:
+			if version < C.RuleSetVersion3 {
+				err = E.New("rule item type ", itemType, " is only supported in version 3 or later")
+				return
+			}
+		}
+		switch itemType {
 		case ruleItemQueryType:
 			var rawQueryType []uint16
 			rawQueryType, err = readRuleItemUint16(reader)
@@ -212,6 +227,18 @@ func readDefaultRule(reader varbin.Reader, recover bool) (rule option.DefaultHea
 			rule.ProcessPathRegex, err = readRuleItemString(reader)
 		case ruleItemPackageName:
 			rule.PackageName, err = readRuleItemString(reader)
+		case ruleItemCGroup:
+			rule.CGroup, err = readRuleItemString(reader)
+		case ruleItemSystemdUnit:
+			rule.SystemdUnit, err = readRuleItemString(reader)
+		case ruleItemContainerID:
+			rule.ContainerID, err = readRuleItemString(reader)
+		case ruleItemSchedule:
+			rule.Schedule, err = varbin.ReadValue[[]option.ScheduleOptions](reader, binary.BigEndian)
+		case ruleItemSourceIPASN:
+			rule.SourceIPASN, err = readRuleItemUint32(reader)
+		case ruleItemIPASN:
+			rule.IPASN, err = readRuleItemUint32(reader)
 		case ruleItemWIFISSID:
 			rule.WIFISSID, err = readRuleItemString(reader)
 		case ruleItemWIFIBSSID:
@@ -240,7 +267,7 @@ func readDefaultRule(reader varbin.Reader, recover bool) (rule option.DefaultHea
 	}
 }
 
-func writeDefaultRule(writer varbin.Writer, rule option.DefaultHeadlessRule, generateUnstable bool) error {
+func writeDefaultRule(writer varbin.Writer, rule option.DefaultHeadlessRule, version uint8) error {
 	err := binary.Write(writer, binary.BigEndian, uint8(0))
 	if err != nil {
 		return err
@@ -264,7 +291,7 @@ func writeDefaultRule(writer varbin.Writer, rule option.DefaultHeadlessRule, gen
 		if err != nil {
 			return err
 		}
-		err = domain.NewMatcher(rule.Domain, rule.DomainSuffix, !generateUnstable).Write(writer)
+		err = domain.NewMatcher(rule.Domain, rule.DomainSuffix, version == C.RuleSetVersion1).Write(writer)
 		if err != nil {
 			return err
 		}
@@ -341,6 +368,64 @@ func writeDefaultRule(writer varbin.Writer, rule option.DefaultHeadlessRule, gen
 			return err
 		}
 	}
+	if len(rule.CGroup) > 0 {
+		if version < C.RuleSetVersion3 {
+			return E.New("`cgroup` rule item is only supported in version 3 or later")
+		}
+		err = writeRuleItemString(writer, ruleItemCGroup, rule.CGroup)
+		if err != nil {
+			return err
+		}
+	}
+	if len(rule.SystemdUnit) > 0 {
+		if version < C.RuleSetVersion3 {
+			return E.New("`systemd_unit` rule item is only supported in version 3 or later")
+		}
+		err = writeRuleItemString(writer, ruleItemSystemdUnit, rule.SystemdUnit)
+		if err != nil {
+			return err
+		}
+	}
+	if len(rule.ContainerID) > 0 {
+		if version < C.RuleSetVersion3 {
+			return E.New("`container_id` rule item is only supported in version 3 or later")
+		}
+		err = writeRuleItemString(writer, ruleItemContainerID, rule.ContainerID)
+		if err != nil {
+			return err
+		}
+	}
+	if len(rule.Schedule) > 0 {
+		if version < C.RuleSetVersion3 {
+			return E.New("`schedule` rule item is only supported in version 3 or later")
+		}
+		err = writer.WriteByte(ruleItemSchedule)
+		if err != nil {
+			return err
+		}
+		err = varbin.Write(writer, binary.BigEndian, []option.ScheduleOptions(rule.Schedule))
+		if err != nil {
+			return err
+		}
+	}
+	if len(rule.SourceIPASN) > 0 {
+		if version < C.RuleSetVersion3 {
+			return E.New("`source_ip_asn` rule item is only supported in version 3 or later")
+		}
+		err = writeRuleItemUint32(writer, ruleItemSourceIPASN, rule.SourceIPASN)
+		if err != nil {
+			return err
+		}
+	}
+	if len(rule.IPASN) > 0 {
+		if version < C.RuleSetVersion3 {
+			return E.New("`ip_asn` rule item is only supported in version 3 or later")
+		}
+		err = writeRuleItemUint32(writer, ruleItemIPASN, rule.IPASN)
+		if err != nil {
+			return err
+		}
+	}
 	if len(rule.WIFISSID) > 0 {
 		err = writeRuleItemString(writer, ruleItemWIFISSID, rule.WIFISSID)
 		if err != nil {
@@ -398,6 +483,18 @@ func writeRuleItemUint16(writer varbin.Writer, itemType uint8, value []uint16) e
 	return varbin.Write(writer, binary.BigEndian, value)
 }
 
+func readRuleItemUint32(reader varbin.Reader) ([]uint32, error) {
+	return varbin.ReadValue[[]uint32](reader, binary.BigEndian)
+}
+
+func writeRuleItemUint32(writer varbin.Writer, itemType uint8, value []uint32) error {
+	err := writer.WriteByte(itemType)
+	if err != nil {
+		return err
+	}
+	return varbin.Write(writer, binary.BigEndian, value)
+}
+
 func writeRuleItemCIDR(writer varbin.Writer, itemType uint8, value []string) error {
 	var builder netipx.IPSetBuilder
 	for i, prefixString := range value {
@@ -424,7 +521,7 @@ func writeRuleItemCIDR(writer varbin.Writer, itemType uint8, value []string) err
 	return writeIPSet(writer, ipSet)
 }
 
-func readLogicalRule(reader varbin.Reader, recovery bool) (logicalRule option.LogicalHeadlessRule, err error) {
+func readLogicalRule(reader varbin.Reader, version uint8, recovery bool) (logicalRule option.LogicalHeadlessRule, err error) {
 	mode, err := reader.ReadByte()
 	if err != nil {
 		return
@@ -444,7 +541,7 @@ func readLogicalRule(reader varbin.Reader, recovery bool) (logicalRule option.Lo
 	}
 	logicalRule.Rules = make([]option.HeadlessRule, length)
 	for i := uint64(0); i < length; i++ {
-		logicalRule.Rules[i], err = readRule(reader, recovery)
+		logicalRule.Rules[i], err = readRule(reader, version, recovery)
 		if err != nil {
 			err = E.Cause(err, "read logical rule [", i, "]")
 			return
@@ -457,7 +554,7 @@ func readLogicalRule(reader varbin.Reader, recovery bool) (logicalRule option.Lo
 	return
 }
 
-func writeLogicalRule(writer varbin.Writer, logicalRule option.LogicalHeadlessRule, generateUnstable bool) error {
+func writeLogicalRule(writer varbin.Writer, logicalRule option.LogicalHeadlessRule, version uint8) error {
 	err := binary.Write(writer, binary.BigEndian, uint8(1))
 	if err != nil {
 		return err
@@ -478,7 +575,7 @@ func writeLogicalRule(writer varbin.Writer, logicalRule option.LogicalHeadlessRu
 		return err
 	}
 	for _, rule := range logicalRule.Rules {
-		err = writeRule(writer, rule, generateUnstable)
+		err = writeRule(writer, rule, version)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,63 @@
+package srs
+
+import (
+	"bytes"
+	"testing"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleSetVersion(t *testing.T) {
+	t.Parallel()
+	ruleSet := option.PlainRuleSet{
+		Rules: []option.HeadlessRule{{
+			Type: C.RuleTypeDefault,
+			DefaultOptions: option.DefaultHeadlessRule{
+				Domain: []string{"example.com"},
+				IPASN:  []uint32{13335},
+			},
+		}},
+	}
+	var buffer bytes.Buffer
+	require.Error(t, Write(&buffer, ruleSet, C.RuleSetVersion2))
+	buffer.Reset()
+	require.NoError(t, Write(&buffer, ruleSet, C.RuleSetVersion3))
+	content := buffer.Bytes()
+	compat, err := Read(bytes.NewReader(content), true)
+	require.NoError(t, err)
+	require.Equal(t, C.RuleSetVersion3, compat.Version)
+	require.Equal(t, []uint32{13335}, []uint32(compat.Options.Rules[0].DefaultOptions.IPASN))
+
+	downgraded := append([]byte(nil), content...)
+	downgraded[len(MagicBytes)] = C.RuleSetVersion2
+	_, err = Read(bytes.NewReader(downgraded), false)
+	require.ErrorContains(t, err, "version 3")
+
+	upgraded := append([]byte(nil), content...)
+	upgraded[len(MagicBytes)] = C.RuleSetVersionCurrent + 1
+	_, err = Read(bytes.NewReader(upgraded), false)
+	require.ErrorContains(t, err, "unsupported version")
+}
+
+func TestRuleSetVersionLogical(t *testing.T) {
+	t.Parallel()
+	ruleSet := option.PlainRuleSet{
+		Rules: []option.HeadlessRule{{
+			Type: C.RuleTypeLogical,
+			LogicalOptions: option.LogicalHeadlessRule{
+				Mode: C.LogicalTypeAnd,
+				Rules: []option.HeadlessRule{{
+					Type: C.RuleTypeDefault,
+					DefaultOptions: option.DefaultHeadlessRule{
+						CGroup: []string{"/system.slice"},
+					},
+				}},
+			},
+		}},
+	}
+	require.Error(t, Write(&bytes.Buffer{}, ruleSet, C.RuleSetVersion1))
+	require.NoError(t, Write(&bytes.Buffer{}, ruleSet, C.RuleSetVersion3))
+}
@@ -0,0 +1,114 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+const (
+	ClientAuthenticationNone          = "no"
+	ClientAuthenticationRequest       = "request"
+	ClientAuthenticationRequire       = "require"
+	ClientAuthenticationVerifyIfGiven = "verify_if_given"
+	ClientAuthenticationVerify        = "verify"
+)
+
+func parseClientAuthentication(options option.InboundTLSOptions) (tls.ClientAuthType, *x509.CertPool, error) {
+	var clientAuth tls.ClientAuthType
+	switch options.ClientAuthentication {
+	case "", ClientAuthenticationNone:
+		return tls.NoClientCert, nil, nil
+	case ClientAuthenticationRequest:
+		clientAuth = tls.RequestClientCert
+	case ClientAuthenticationRequire:
+		clientAuth = tls.RequireAnyClientCert
+	case ClientAuthenticationVerifyIfGiven:
+		clientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthenticationVerify:
+		clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return 0, nil, E.New("unknown client_authentication: ", options.ClientAuthentication)
+	}
+	var certificate []byte
+	if len(options.ClientCertificate) > 0 {
+		certificate = []byte(strings.Join(options.ClientCertificate, "\n"))
+	} else if options.ClientCertificatePath != "" {
+		content, err := os.ReadFile(options.ClientCertificatePath)
+		if err != nil {
+			return 0, nil, E.Cause(err, "read client_certificate")
+		}
+		certificate = content
+	}
+	if len(certificate) == 0 {
+		if clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert {
+			return 0, nil, E.New("missing client_certificate")
+		}
+		return clientAuth, nil, nil
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(certificate) {
+		return 0, nil, E.New("failed to parse client_certificate:\n\n", string(certificate))
+	}
+	return clientAuth, certPool, nil
+}
+
+func loadClientKeyPair(options option.OutboundTLSOptions) (certificate []byte, key []byte, err error) {
+	if len(options.ClientCertificate) > 0 {
+		certificate = []byte(strings.Join(options.ClientCertificate, "\n"))
+	} else if options.ClientCertificatePath != "" {
+		certificate, err = os.ReadFile(options.ClientCertificatePath)
+		if err != nil {
+			return nil, nil, E.Cause(err, "read client_certificate")
+		}
+	}
+	if len(options.ClientKey) > 0 {
+		key = []byte(strings.Join(options.ClientKey, "\n"))
+	} else if options.ClientKeyPath != "" {
+		key, err = os.ReadFile(options.ClientKeyPath)
+		if err != nil {
+			return nil, nil, E.Cause(err, "read client_key")
+		}
+	}
+	if certificate == nil && key != nil {
+		return nil, nil, E.New("missing client_certificate")
+	} else if certificate != nil && key == nil {
+		return nil, nil, E.New("missing client_key")
+	}
+	return
+}
+
+// ClientCertificateUser returns the common name, or the first subject alternative name,
+// of the verified client certificate of the connection.
+func ClientCertificateUser(conn net.Conn) string {
+	tlsConn, isTLS := common.Cast[Conn](conn)
+	if !isTLS {
+		return ""
+	}
+	return ClientCertificateUserFromState(tlsConn.ConnectionState())
+}
+
+func ClientCertificateUserFromState(state ConnectionState) string {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	certificate := state.VerifiedChains[0][0]
+	switch {
+	case certificate.Subject.CommonName != "":
+		return certificate.Subject.CommonName
+	case len(certificate.DNSNames) > 0:
+		return certificate.DNSNames[0]
+	case len(certificate.EmailAddresses) > 0:
+		return certificate.EmailAddresses[0]
+	case len(certificate.URIs) > 0:
+		return certificate.URIs[0].String()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,85 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"testing"
+
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestCertificate(certificate *testCertificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.certificate.Raw}))
+}
+
+func TestParseClientAuthentication(t *testing.T) {
+	t.Parallel()
+	root := newTestCertificate(t, "root", true, nil)
+	clientAuth, certPool, err := parseClientAuthentication(option.InboundTLSOptions{})
+	require.NoError(t, err)
+	require.Equal(t, tls.NoClientCert, clientAuth)
+	require.Nil(t, certPool)
+
+	clientAuth, certPool, err = parseClientAuthentication(option.InboundTLSOptions{ClientAuthentication: ClientAuthenticationRequire})
+	require.NoError(t, err)
+	require.Equal(t, tls.RequireAnyClientCert, clientAuth)
+	require.Nil(t, certPool)
+
+	clientAuth, certPool, err = parseClientAuthentication(option.InboundTLSOptions{
+		ClientAuthentication: ClientAuthenticationVerify,
+		ClientCertificate:    []string{encodeTestCertificate(root)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, tls.RequireAndVerifyClientCert, clientAuth)
+	require.NotNil(t, certPool)
+
+	_, _, err = parseClientAuthentication(option.InboundTLSOptions{ClientAuthentication: ClientAuthenticationVerifyIfGiven})
+	require.ErrorContains(t, err, "missing client_certificate")
+	_, _, err = parseClientAuthentication(option.InboundTLSOptions{
+		ClientAuthentication: ClientAuthenticationVerify,
+		ClientCertificate:    []string{"invalid"},
+	})
+	require.ErrorContains(t, err, "failed to parse client_certificate")
+	_, _, err = parseClientAuthentication(option.InboundTLSOptions{ClientAuthentication: "always"})
+	require.ErrorContains(t, err, "unknown client_authentication")
+}
+
+func TestLoadClientKeyPair(t *testing.T) {
+	t.Parallel()
+	certificate, key, err := loadClientKeyPair(option.OutboundTLSOptions{})
+	require.NoError(t, err)
+	require.Nil(t, certificate)
+	require.Nil(t, key)
+	_, _, err = loadClientKeyPair(option.OutboundTLSOptions{ClientCertificate: []string{"certificate"}})
+	require.ErrorContains(t, err, "missing client_key")
+	_, _, err = loadClientKeyPair(option.OutboundTLSOptions{ClientKey: []string{"key"}})
+	require.ErrorContains(t, err, "missing client_certificate")
+	certificate, key, err = loadClientKeyPair(option.OutboundTLSOptions{
+		ClientCertificate: []string{"line1", "line2"},
+		ClientKey:         []string{"key"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "line1\nline2", string(certificate))
+	require.Equal(t, "key", string(key))
+}
+
+func TestClientCertificateUser(t *testing.T) {
+	t.Parallel()
+	stateOf := func(certificate *x509.Certificate) ConnectionState {
+		return ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+	}
+	require.Equal(t, "", ClientCertificateUserFromState(ConnectionState{}))
+	require.Equal(t, "", ClientCertificateUserFromState(ConnectionState{
+		PeerCertificates: []*x509.Certificate{newTestCertificate(t, "unverified", false, nil).certificate},
+	}))
+	require.Equal(t, "alice", ClientCertificateUserFromState(stateOf(newTestCertificate(t, "alice", false, nil).certificate)))
+	require.Equal(t, "bob.example.com", ClientCertificateUserFromState(stateOf(&x509.Certificate{DNSNames: []string{"bob.example.com"}})))
+	require.Equal(t, "carol@example.com", ClientCertificateUserFromState(stateOf(&x509.Certificate{EmailAddresses: []string{"carol@example.com"}})))
+	require.Equal(t, "spiffe://example.com/dave", ClientCertificateUserFromState(stateOf(&x509.Certificate{
+		URIs: []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/dave"}},
+	})))
+}
@@ -6,21 +6,22 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/base64"
 	"encoding/pem"
 	"net"
 	"net/netip"
 	"os"
 	"strings"
+	"sync"
 
 	cftls "github.com/sagernet/cloudflare-tls"
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/dialer"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
-	"github.com/sagernet/sing-dns"
+	"github.com/sagernet/sing/common"
 	E "github.com/sagernet/sing/common/exceptions"
 	"github.com/sagernet/sing/common/ntp"
-
-	mDNS "github.com/miekg/dns"
 )
 
 type echClientConfig struct {
@@ -48,7 +49,20 @@ func (c *echClientConfig) Config() (*STDConfig, error) {
 }
 
 func (c *echClientConfig) Client(conn net.Conn) (Conn, error) {
-	return &echConnWrapper{cftls.Client(conn, c.config)}, nil
+	config := c.config
+	if echConn, isECHConn := common.Cast[dialer.ECHConfigConn](conn); isECHConn && config.ClientECHConfigs == nil {
+		// use the ECH config published with the discovered endpoint actually dialed
+		config = config.Clone()
+		config.GetClientECHConfigs = nil
+		if echConfig := echConn.ECHConfig(); len(echConfig) > 0 {
+			echConfigs, err := cftls.UnmarshalECHConfigs(echConfig)
+			if err != nil {
+				return nil, E.Cause(err, "parse ECH configs")
+			}
+			config.ClientECHConfigs = echConfigs
+		}
+	}
+	return &echConnWrapper{cftls.Client(conn, config)}, nil
 }
 
 func (c *echClientConfig) Clone() Config {
@@ -83,7 +97,7 @@ func (c *echConnWrapper) Upstream() any {
 	return c.Conn
 }
 
-func NewECHClient(ctx context.Context, serverAddress string, options option.OutboundTLSOptions) (Config, error) {
+func NewECHClient(ctx context.Context, logger log.Logger, serverAddress string, options option.OutboundTLSOptions) (Config, error) {
 	var serverName string
 	if options.ServerName != "" {
 		serverName = options.ServerName
@@ -92,7 +106,11 @@ func NewECHClient(ctx context.Context, serverAddress string, options option.Outb
 			serverName = serverAddress
 		}
 	}
-	if serverName == "" && !options.Insecure {
+	pins, err := newPublicKeyPins(logger, serverName, options)
+	if err != nil {
+		return nil, err
+	}
+	if serverName == "" && !options.Insecure && !pins.enforced() {
 		return nil, E.New("missing server_name or insecure=true")
 	}
 
@@ -119,6 +137,13 @@ func NewECHClient(ctx context.Context, serverAddress string, options option.Outb
 			return err
 		}
 	}
+	if pins != nil {
+		if pins.enforced() {
+			tlsConfig.InsecureSkipVerify = true
+			tlsConfig.VerifyConnection = nil
+		}
+		tlsConfig.VerifyPeerCertificate = pins.VerifyPeerCertificate
+	}
 	if len(options.ALPN) > 0 {
 		tlsConfig.NextProtos = options.ALPN
 	}
@@ -165,6 +190,17 @@ func NewECHClient(ctx context.Context, serverAddress string, options option.Outb
 		}
 		tlsConfig.RootCAs = certPool
 	}
+	clientCertificate, clientKey, err := loadClientKeyPair(options)
+	if err != nil {
+		return nil, err
+	}
+	if clientCertificate != nil {
+		keyPair, err := cftls.X509KeyPair(clientCertificate, clientKey)
+		if err != nil {
+			return nil, E.Cause(err, "parse client x509 key pair")
+		}
+		tlsConfig.Certificates = []cftls.Certificate{keyPair}
+	}
 
 	// ECH Config
 
@@ -200,40 +236,25 @@ func NewECHClient(ctx context.Context, serverAddress string, options option.Outb
 }
 
 func fetchECHClientConfig(ctx context.Context) func(_ context.Context, serverName string) ([]cftls.ECHConfig, error) {
+	var (
+		access  sync.Mutex
+		lookups = make(map[string]*dialer.ServiceLookup)
+	)
 	return func(_ context.Context, serverName string) ([]cftls.ECHConfig, error) {
-		message := &mDNS.Msg{
-			MsgHdr: mDNS.MsgHdr{
-				RecursionDesired: true,
-			},
-			Question: []mDNS.Question{
-				{
-					Name:   serverName + ".",
-					Qtype:  mDNS.TypeHTTPS,
-					Qclass: mDNS.ClassINET,
-				},
-			},
-		}
-		response, err := adapter.RouterFromContext(ctx).Exchange(ctx, message)
+		access.Lock()
+		lookup, loaded := lookups[serverName]
+		if !loaded {
+			lookup = dialer.NewServiceLookup(adapter.RouterFromContext(ctx), C.ServerDiscoveryHTTPS, serverName)
+			lookups[serverName] = lookup
+		}
+		access.Unlock()
+		records, err := lookup.Lookup(ctx)
 		if err != nil {
 			return nil, err
 		}
-		if response.Rcode != mDNS.RcodeSuccess {
-			return nil, dns.RCodeError(response.Rcode)
-		}
-		for _, rr := range response.Answer {
-			switch resource := rr.(type) {
-			case *mDNS.HTTPS:
-				for _, value := range resource.Value {
-					if value.Key().String() == "ech" {
-						echConfig, err := base64.StdEncoding.DecodeString(value.String())
-						if err != nil {
-							return nil, E.Cause(err, "decode ECH config")
-						}
-						return cftls.UnmarshalECHConfigs(echConfig)
-					}
-				}
-			default:
-				return nil, E.New("unknown resource record type: ", resource.Header().Rrtype)
+		for _, record := range records {
+			if len(record.ECHConfig) > 0 {
+				return cftls.UnmarshalECHConfigs(record.ECHConfig)
 			}
 		}
 		return nil, E.New("no ECH config found")
@@ -229,6 +229,13 @@ func NewECHServer(ctx context.Context, logger log.Logger, options option.Inbound
 	}
 	tlsConfig.Certificates = []cftls.Certificate{keyPair}
 
+	clientAuth, clientCAs, err := parseClientAuthentication(options)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientAuth = cftls.ClientAuthType(clientAuth)
+	tlsConfig.ClientCAs = clientCAs
+
 	var echKey []byte
 	if len(options.ECH.Key) > 0 {
 		echKey = []byte(strings.Join(options.ECH.Key, "\n"))
@@ -16,7 +16,7 @@ func NewECHServer(ctx context.Context, logger log.Logger, options option.Inbound
 	return nil, errECHNotIncluded
 }
 
-func NewECHClient(ctx context.Context, serverAddress string, options option.OutboundTLSOptions) (Config, error) {
+func NewECHClient(ctx context.Context, logger log.Logger, serverAddress string, options option.OutboundTLSOptions) (Config, error) {
 	return nil, errECHNotIncluded
 }
 
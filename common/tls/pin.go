@@ -0,0 +1,111 @@
+package tls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// publicKeyPins verifies server certificates by the SHA-256 hash of their SubjectPublicKeyInfo.
+type publicKeyPins struct {
+	logger     log.Logger
+	serverName string
+	hashes     [][]byte
+	reportOnly bool
+}
+
+func newPublicKeyPins(logger log.Logger, serverName string, options option.OutboundTLSOptions) (*publicKeyPins, error) {
+	if len(options.CertificatePublicKeySHA256) == 0 {
+		if options.CertificatePublicKeySHA256ReportOnly {
+			return nil, E.New("missing certificate_public_key_sha256")
+		}
+		return nil, nil
+	}
+	pins := &publicKeyPins{
+		logger:     logger,
+		serverName: serverName,
+		reportOnly: options.CertificatePublicKeySHA256ReportOnly,
+	}
+	for _, pin := range options.CertificatePublicKeySHA256 {
+		hash, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil {
+			return nil, E.Cause(err, "decode certificate_public_key_sha256: ", pin)
+		}
+		if len(hash) != sha256.Size {
+			return nil, E.New("invalid certificate_public_key_sha256: ", pin)
+		}
+		pins.hashes = append(pins.hashes, hash)
+	}
+	return pins, nil
+}
+
+// enforced reports whether pins replace the regular certificate verification.
+func (p *publicKeyPins) enforced() bool {
+	return p != nil && !p.reportOnly
+}
+
+// VerifyPeerCertificate accepts the chain if the leaf certificate is pinned, or if the leaf is issued
+// for the server name by a pinned certificate presented in the chain.
+func (p *publicKeyPins) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	err := p.verify(rawCerts)
+	if err != nil && p.reportOnly {
+		p.logger.Warn(err)
+		return nil
+	}
+	return err
+}
+
+func (p *publicKeyPins) verify(rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return E.New("missing certificate of ", p.serverName)
+	}
+	certificates := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, rawCert := range rawCerts {
+		certificate, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if p.match(certificates[0]) {
+		return nil
+	}
+	for i, certificate := range certificates[1:] {
+		if !p.match(certificate) {
+			continue
+		}
+		if p.serverName == "" {
+			return E.New("missing server_name to verify certificate issued by a pinned certificate")
+		}
+		verifyOptions := x509.VerifyOptions{
+			DNSName:       p.serverName,
+			Roots:         x509.NewCertPool(),
+			Intermediates: x509.NewCertPool(),
+		}
+		verifyOptions.Roots.AddCert(certificate)
+		for _, intermediate := range certificates[1 : i+1] {
+			verifyOptions.Intermediates.AddCert(intermediate)
+		}
+		_, err := certificates[0].Verify(verifyOptions)
+		if err != nil {
+			return E.Cause(err, "verify certificate of ", p.serverName, " by pinned certificate")
+		}
+		return nil
+	}
+	return E.New("certificate public key of ", p.serverName, " does not match any pin")
+}
+
+func (p *publicKeyPins) match(certificate *x509.Certificate) bool {
+	hash := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+	for _, pin := range p.hashes {
+		if bytes.Equal(hash[:], pin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/sagernet/sing-box/log"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, commonName string, isCA bool, issuer *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.DNSNames = []string{commonName}
+	}
+	parent, parentKey := template, key
+	if issuer != nil {
+		parent, parentKey = issuer.certificate, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCertificate{certificate, key}
+}
+
+func newTestPins(serverName string, certificates ...*testCertificate) *publicKeyPins {
+	pins := &publicKeyPins{
+		logger:     log.NewNOPFactory().Logger(),
+		serverName: serverName,
+	}
+	for _, certificate := range certificates {
+		hash := sha256.Sum256(certificate.certificate.RawSubjectPublicKeyInfo)
+		pins.hashes = append(pins.hashes, hash[:])
+	}
+	return pins
+}
+
+func rawChain(certificates ...*testCertificate) [][]byte {
+	var rawCerts [][]byte
+	for _, certificate := range certificates {
+		rawCerts = append(rawCerts, certificate.certificate.Raw)
+	}
+	return rawCerts
+}
+
+func TestPublicKeyPinLeaf(t *testing.T) {
+	t.Parallel()
+	leaf := newTestCertificate(t, "example.com", false, nil)
+	other := newTestCertificate(t, "example.com", false, nil)
+	require.NoError(t, newTestPins("example.com", leaf).VerifyPeerCertificate(rawChain(leaf), nil))
+	require.Error(t, newTestPins("example.com", other).VerifyPeerCertificate(rawChain(leaf), nil))
+}
+
+func TestPublicKeyPinIntermediate(t *testing.T) {
+	t.Parallel()
+	root := newTestCertificate(t, "root", true, nil)
+	intermediate := newTestCertificate(t, "intermediate", true, root)
+	leaf := newTestCertificate(t, "example.com", false, intermediate)
+	chain := rawChain(leaf, intermediate, root)
+	require.NoError(t, newTestPins("example.com", intermediate).VerifyPeerCertificate(chain, nil))
+	require.NoError(t, newTestPins("example.com", root).VerifyPeerCertificate(chain, nil))
+	require.Error(t, newTestPins("example.org", intermediate).VerifyPeerCertificate(chain, nil))
+	require.Error(t, newTestPins("", intermediate).VerifyPeerCertificate(chain, nil))
+}
+
+func TestPublicKeyPinForgedLeaf(t *testing.T) {
+	t.Parallel()
+	root := newTestCertificate(t, "root", true, nil)
+	pinned := newTestCertificate(t, "example.com", false, root)
+	forged := newTestCertificate(t, "example.com", false, nil)
+	pins := newTestPins("example.com", pinned)
+	require.Error(t, pins.VerifyPeerCertificate(rawChain(forged, pinned), nil))
+	forgedCA := newTestCertificate(t, "root", true, nil)
+	forgedByCA := newTestCertificate(t, "example.com", false, forgedCA)
+	require.Error(t, newTestPins("example.com", root).VerifyPeerCertificate(rawChain(forgedByCA, root), nil))
+}
+
+func TestPublicKeyPinReportOnly(t *testing.T) {
+	t.Parallel()
+	leaf := newTestCertificate(t, "example.com", false, nil)
+	other := newTestCertificate(t, "example.com", false, nil)
+	pins := newTestPins("example.com", other)
+	pins.reportOnly = true
+	require.NoError(t, pins.VerifyPeerCertificate(rawChain(leaf), nil))
+}
@@ -27,6 +27,7 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
 	"github.com/sagernet/sing/common/debug"
 	E "github.com/sagernet/sing/common/exceptions"
@@ -43,14 +44,22 @@ type RealityClientConfig struct {
 	uClient   *UTLSClientConfig
 	publicKey []byte
 	shortID   [8]byte
+	pins      *publicKeyPins
 }
 
-func NewRealityClient(ctx context.Context, serverAddress string, options option.OutboundTLSOptions) (*RealityClientConfig, error) {
+func NewRealityClient(ctx context.Context, logger log.Logger, serverAddress string, options option.OutboundTLSOptions) (*RealityClientConfig, error) {
 	if options.UTLS == nil || !options.UTLS.Enabled {
 		return nil, E.New("uTLS is required by reality client")
 	}
+	if len(options.ClientCertificate) > 0 || options.ClientCertificatePath != "" {
+		return nil, E.New("client_certificate is unavailable in reality")
+	}
 
-	uClient, err := NewUTLSClient(ctx, serverAddress, options)
+	uClient, err := NewUTLSClient(ctx, logger, serverAddress, options)
+	if err != nil {
+		return nil, err
+	}
+	pins, err := newPublicKeyPins(logger, uClient.ServerName(), options)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +79,7 @@ func NewRealityClient(ctx context.Context, serverAddress string, options option.
 	if decodedLen > 8 {
 		return nil, E.New("invalid short_id")
 	}
-	return &RealityClientConfig{uClient, publicKey, shortID}, nil
+	return &RealityClientConfig{uClient, publicKey, shortID, pins}, nil
 }
 
 func (e *RealityClientConfig) ServerName() string {
@@ -100,6 +109,7 @@ func (e *RealityClientConfig) Client(conn net.Conn) (Conn, error) {
 func (e *RealityClientConfig) ClientHandshake(ctx context.Context, conn net.Conn) (aTLS.Conn, error) {
 	verifier := &realityVerifier{
 		serverName: e.uClient.ServerName(),
+		pins:       e.pins,
 	}
 	uConfig := e.uClient.config.Clone()
 	uConfig.InsecureSkipVerify = true
@@ -216,12 +226,14 @@ func (e *RealityClientConfig) Clone() Config {
 		e.uClient.Clone().(*UTLSClientConfig),
 		e.publicKey,
 		e.shortID,
+		e.pins,
 	}
 }
 
 type realityVerifier struct {
 	*utls.UConn
 	serverName string
+	pins       *publicKeyPins
 	authKey    []byte
 	verified   bool
 }
@@ -237,6 +249,9 @@ func (c *realityVerifier) VerifyPeerCertificate(rawCerts [][]byte, verifiedChain
 			return nil
 		}
 	}
+	if c.pins.enforced() {
+		return c.pins.VerifyPeerCertificate(rawCerts, verifiedChains)
+	}
 	opts := x509.VerifyOptions{
 		DNSName:       c.serverName,
 		Intermediates: x509.NewCertPool(),
@@ -247,5 +262,8 @@ func (c *realityVerifier) VerifyPeerCertificate(rawCerts [][]byte, verifiedChain
 	if _, err := certs[0].Verify(opts); err != nil {
 		return err
 	}
+	if c.pins != nil {
+		return c.pins.VerifyPeerCertificate(rawCerts, verifiedChains)
+	}
 	return nil
 }
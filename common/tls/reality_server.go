@@ -34,6 +34,9 @@ func NewRealityServer(ctx context.Context, logger log.Logger, options option.Inb
 	if options.ACME != nil && len(options.ACME.Domain) > 0 {
 		return nil, E.New("acme is unavailable in reality")
 	}
+	if options.ClientAuthentication != "" && options.ClientAuthentication != ClientAuthenticationNone {
+		return nil, E.New("client_authentication is unavailable in reality")
+	}
 	tlsConfig.Time = ntp.TimeFuncFromContext(ctx)
 	if options.ServerName != "" {
 		tlsConfig.ServerName = options.ServerName
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
 	E "github.com/sagernet/sing/common/exceptions"
 	"github.com/sagernet/sing/common/ntp"
@@ -46,7 +47,7 @@ func (s *STDClientConfig) Clone() Config {
 	return &STDClientConfig{s.config.Clone()}
 }
 
-func NewSTDClient(ctx context.Context, serverAddress string, options option.OutboundTLSOptions) (Config, error) {
+func NewSTDClient(ctx context.Context, logger log.Logger, serverAddress string, options option.OutboundTLSOptions) (Config, error) {
 	var serverName string
 	if options.ServerName != "" {
 		serverName = options.ServerName
@@ -55,7 +56,11 @@ func NewSTDClient(ctx context.Context, serverAddress string, options option.Outb
 			serverName = serverAddress
 		}
 	}
-	if serverName == "" && !options.Insecure {
+	pins, err := newPublicKeyPins(logger, serverName, options)
+	if err != nil {
+		return nil, err
+	}
+	if serverName == "" && !options.Insecure && !pins.enforced() {
 		return nil, E.New("missing server_name or insecure=true")
 	}
 
@@ -82,6 +87,13 @@ func NewSTDClient(ctx context.Context, serverAddress string, options option.Outb
 			return err
 		}
 	}
+	if pins != nil {
+		if pins.enforced() {
+			tlsConfig.InsecureSkipVerify = true
+			tlsConfig.VerifyConnection = nil
+		}
+		tlsConfig.VerifyPeerCertificate = pins.VerifyPeerCertificate
+	}
 	if len(options.ALPN) > 0 {
 		tlsConfig.NextProtos = options.ALPN
 	}
@@ -128,5 +140,16 @@ func NewSTDClient(ctx context.Context, serverAddress string, options option.Outb
 		}
 		tlsConfig.RootCAs = certPool
 	}
+	clientCertificate, clientKey, err := loadClientKeyPair(options)
+	if err != nil {
+		return nil, err
+	}
+	if clientCertificate != nil {
+		keyPair, err := tls.X509KeyPair(clientCertificate, clientKey)
+		if err != nil {
+			return nil, E.Cause(err, "parse client x509 key pair")
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
 	return &STDClientConfig{&tlsConfig}, nil
 }
@@ -234,6 +234,10 @@ func NewSTDServer(ctx context.Context, logger log.Logger, options option.Inbound
 			tlsConfig.Certificates = []tls.Certificate{keyPair}
 		}
 	}
+	tlsConfig.ClientAuth, tlsConfig.ClientCAs, err = parseClientAuthentication(options)
+	if err != nil {
+		return nil, err
+	}
 	return &STDServerConfig{
 		config:          tlsConfig,
 		logger:          logger,
@@ -12,6 +12,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
 	E "github.com/sagernet/sing/common/exceptions"
 	"github.com/sagernet/sing/common/ntp"
@@ -114,7 +115,7 @@ func (c *utlsALPNWrapper) HandshakeContext(ctx context.Context) error {
 	return c.UConn.HandshakeContext(ctx)
 }
 
-func NewUTLSClient(ctx context.Context, serverAddress string, options option.OutboundTLSOptions) (*UTLSClientConfig, error) {
+func NewUTLSClient(ctx context.Context, logger log.Logger, serverAddress string, options option.OutboundTLSOptions) (*UTLSClientConfig, error) {
 	var serverName string
 	if options.ServerName != "" {
 		serverName = options.ServerName
@@ -123,7 +124,11 @@ func NewUTLSClient(ctx context.Context, serverAddress string, options option.Out
 			serverName = serverAddress
 		}
 	}
-	if serverName == "" && !options.Insecure {
+	pins, err := newPublicKeyPins(logger, serverName, options)
+	if err != nil {
+		return nil, err
+	}
+	if serverName == "" && !options.Insecure && !pins.enforced() {
 		return nil, E.New("missing server_name or insecure=true")
 	}
 
@@ -139,6 +144,12 @@ func NewUTLSClient(ctx context.Context, serverAddress string, options option.Out
 	} else if options.DisableSNI {
 		return nil, E.New("disable_sni is unsupported in uTLS")
 	}
+	if pins != nil {
+		if pins.enforced() {
+			tlsConfig.InsecureSkipVerify = true
+		}
+		tlsConfig.VerifyPeerCertificate = pins.VerifyPeerCertificate
+	}
 	if len(options.ALPN) > 0 {
 		tlsConfig.NextProtos = options.ALPN
 	}
@@ -185,6 +196,17 @@ func NewUTLSClient(ctx context.Context, serverAddress string, options option.Out
 		}
 		tlsConfig.RootCAs = certPool
 	}
+	clientCertificate, clientKey, err := loadClientKeyPair(options)
+	if err != nil {
+		return nil, err
+	}
+	if clientCertificate != nil {
+		keyPair, err := utls.X509KeyPair(clientCertificate, clientKey)
+		if err != nil {
+			return nil, E.Cause(err, "parse client x509 key pair")
+		}
+		tlsConfig.Certificates = []utls.Certificate{keyPair}
+	}
 	id, err := uTLSClientHelloID(options.UTLS.Fingerprint)
 	if err != nil {
 		return nil, err
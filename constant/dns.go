@@ -4,3 +4,8 @@ const (
 	DNSProviderAliDNS     = "alidns"
 	DNSProviderCloudflare = "cloudflare"
 )
+
+const (
+	ServerDiscoverySRV   = "srv"
+	ServerDiscoveryHTTPS = "https"
+)
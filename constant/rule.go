@@ -21,4 +21,13 @@ const (
 const (
 	RuleSetVersion1 = 1 + iota
 	RuleSetVersion2
+	RuleSetVersion3
+	RuleSetVersionCurrent = RuleSetVersion3
+)
+
+const NetworkICMP = "icmp"
+
+const (
+	ICMPFallbackReply       = "reply"
+	ICMPFallbackUnreachable = "unreachable"
 )
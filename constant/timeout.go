@@ -17,4 +17,14 @@ const (
 	StopTimeout                = 5 * time.Second
 	FatalStopTimeout           = 10 * time.Second
 	FakeIPMetadataSaveInterval = 10 * time.Second
+	ServerFailureTimeout       = 10 * time.Minute
+	ServerDiscoveryMinTTL      = 30 * time.Second
+	ServerDiscoveryMaxTTL      = time.Hour
+	BondLinkTimeout            = 30 * time.Second
+	DNSServeStaleTimeout       = 72 * time.Hour
+	DNSCacheSaveInterval       = time.Second
+	DNSCachePruneInterval      = time.Hour
+	DefaultHopInterval         = 30 * time.Second
+	SSHLoginTimeout            = 2 * time.Minute
+	ICMPTimeout                = 10 * time.Second
 )
@@ -15,6 +15,7 @@ import (
 	"github.com/sagernet/sing-box/option"
 	"github.com/sagernet/sing/common"
 	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/logger"
 	"github.com/sagernet/sing/service/filemanager"
 )
 
@@ -30,6 +31,8 @@ var (
 		string(bucketMode),
 		string(bucketRuleSet),
 		string(bucketRDRC),
+		string(bucketDNS),
+		string(bucketConnection),
 	}
 
 	cacheIDDefault = []byte("default")
@@ -38,20 +41,29 @@ var (
 var _ adapter.CacheFile = (*CacheFile)(nil)
 
 type CacheFile struct {
-	ctx               context.Context
-	path              string
-	cacheID           []byte
-	storeFakeIP       bool
-	storeRDRC         bool
-	rdrcTimeout       time.Duration
-	DB                *bbolt.DB
-	saveMetadataTimer *time.Timer
-	saveFakeIPAccess  sync.RWMutex
-	saveDomain        map[netip.Addr]string
-	saveAddress4      map[string]netip.Addr
-	saveAddress6      map[string]netip.Addr
-	saveRDRCAccess    sync.RWMutex
-	saveRDRC          map[saveRDRCCacheKey]bool
+	ctx                      context.Context
+	path                     string
+	cacheID                  []byte
+	storeFakeIP              bool
+	storeRDRC                bool
+	rdrcTimeout              time.Duration
+	storeDNS                 bool
+	storeConnectionHistory   bool
+	connectionHistoryTimeout time.Duration
+	DB                       *bbolt.DB
+	saveMetadataTimer        *time.Timer
+	saveFakeIPAccess         sync.RWMutex
+	saveDomain               map[netip.Addr]string
+	saveAddress4             map[string]netip.Addr
+	saveAddress6             map[string]netip.Addr
+	saveRDRCAccess           sync.RWMutex
+	saveRDRC                 map[saveRDRCCacheKey]bool
+	saveDNSAccess            sync.Mutex
+	saveDNS                  map[string][]byte
+	saveDNSTimer             *time.Timer
+	saveDNSLogger            logger.Logger
+	dnsStaleTimeout          time.Duration
+	dnsPrunedAt              time.Time
 }
 
 type saveRDRCCacheKey struct {
@@ -79,17 +91,29 @@ func New(ctx context.Context, options option.CacheFileOptions) *CacheFile {
 			rdrcTimeout = 7 * 24 * time.Hour
 		}
 	}
+	var connectionHistoryTimeout time.Duration
+	if options.StoreConnectionHistory {
+		if options.ConnectionHistoryTimeout > 0 {
+			connectionHistoryTimeout = time.Duration(options.ConnectionHistoryTimeout)
+		} else {
+			connectionHistoryTimeout = 24 * time.Hour
+		}
+	}
 	return &CacheFile{
-		ctx:          ctx,
-		path:         filemanager.BasePath(ctx, path),
-		cacheID:      cacheIDBytes,
-		storeFakeIP:  options.StoreFakeIP,
-		storeRDRC:    options.StoreRDRC,
-		rdrcTimeout:  rdrcTimeout,
-		saveDomain:   make(map[netip.Addr]string),
-		saveAddress4: make(map[string]netip.Addr),
-		saveAddress6: make(map[string]netip.Addr),
-		saveRDRC:     make(map[saveRDRCCacheKey]bool),
+		ctx:                      ctx,
+		path:                     filemanager.BasePath(ctx, path),
+		cacheID:                  cacheIDBytes,
+		storeFakeIP:              options.StoreFakeIP,
+		storeRDRC:                options.StoreRDRC,
+		rdrcTimeout:              rdrcTimeout,
+		storeDNS:                 options.StoreDNS,
+		storeConnectionHistory:   options.StoreConnectionHistory,
+		connectionHistoryTimeout: connectionHistoryTimeout,
+		saveDomain:               make(map[netip.Addr]string),
+		saveAddress4:             make(map[string]netip.Addr),
+		saveAddress6:             make(map[string]netip.Addr),
+		saveRDRC:                 make(map[saveRDRCCacheKey]bool),
+		saveDNS:                  make(map[string][]byte),
 	}
 }
 
@@ -163,6 +187,7 @@ func (c *CacheFile) Close() error {
 	if c.DB == nil {
 		return nil
 	}
+	c.flushDNSCacheOnClose()
 	return c.DB.Close()
 }
 
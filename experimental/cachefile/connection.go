@@ -0,0 +1,90 @@
+package cachefile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/sagernet/bbolt"
+	"github.com/sagernet/sing-box/adapter"
+)
+
+var bucketConnection = []byte("connection_history")
+
+func (c *CacheFile) StoreConnectionHistory() bool {
+	return c.storeConnectionHistory
+}
+
+// connectionKey orders saved connections by close time.
+func connectionKey(closedAt time.Time, id [16]byte) []byte {
+	key := make([]byte, 8+len(id))
+	binary.BigEndian.PutUint64(key, uint64(closedAt.UnixNano()))
+	copy(key[8:], id[:])
+	return key
+}
+
+// SaveConnectionHistory saves closed connections and deletes the ones
+// closed longer than connection_history_timeout ago.
+func (c *CacheFile) SaveConnectionHistory(connections []adapter.SavedConnection) error {
+	return c.DB.Batch(func(tx *bbolt.Tx) error {
+		bucket, err := c.createBucket(tx, bucketConnection)
+		if err != nil {
+			return err
+		}
+		for _, connection := range connections {
+			err = bucket.Put(connectionKey(connection.ClosedAt, connection.ID), connection.Content)
+			if err != nil {
+				return err
+			}
+		}
+		expireKey := connectionKey(time.Now().Add(-c.connectionHistoryTimeout), [16]byte{})
+		// deleting with the cursor while iterating skips elements
+		var expiredKeys [][]byte
+		cursor := bucket.Cursor()
+		for key, _ := cursor.First(); key != nil && bytes.Compare(key, expireKey) < 0; key, _ = cursor.Next() {
+			expiredKeys = append(expiredKeys, append([]byte(nil), key...))
+		}
+		for _, key := range expiredKeys {
+			err = bucket.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *CacheFile) LoadConnectionHistory(since time.Time, until time.Time, yield func(content []byte) bool) error {
+	return c.DB.View(func(tx *bbolt.Tx) error {
+		bucket := c.bucket(tx, bucketConnection)
+		if bucket == nil {
+			return nil
+		}
+		var sinceKey []byte
+		if !since.IsZero() {
+			sinceKey = connectionKey(since, [16]byte{})
+		}
+		cursor := bucket.Cursor()
+		var key, value []byte
+		if until.IsZero() {
+			key, value = cursor.Last()
+		} else {
+			// seek to the first key after until
+			key, value = cursor.Seek(connectionKey(until.Add(time.Nanosecond), [16]byte{}))
+			if key == nil {
+				key, value = cursor.Last()
+			} else {
+				key, value = cursor.Prev()
+			}
+		}
+		for ; key != nil; key, value = cursor.Prev() {
+			if sinceKey != nil && bytes.Compare(key, sinceKey) < 0 {
+				break
+			}
+			if !yield(value) {
+				break
+			}
+		}
+		return nil
+	})
+}
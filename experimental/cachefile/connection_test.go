@@ -0,0 +1,51 @@
+package cachefile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSavedConnection(id byte, closedAt time.Time) adapter.SavedConnection {
+	return adapter.SavedConnection{
+		ID:       [16]byte{id},
+		ClosedAt: closedAt,
+		Content:  []byte{id},
+	}
+}
+
+func loadTestConnectionHistory(t *testing.T, cacheFile *CacheFile, since time.Time, until time.Time) []byte {
+	var contents []byte
+	require.NoError(t, cacheFile.LoadConnectionHistory(since, until, func(content []byte) bool {
+		contents = append(contents, content...)
+		return true
+	}))
+	return contents
+}
+
+func TestConnectionHistory(t *testing.T) {
+	t.Parallel()
+	cacheFile := newTestCacheFile(t, option.CacheFileOptions{
+		StoreConnectionHistory:   true,
+		ConnectionHistoryTimeout: option.Duration(time.Hour),
+	})
+	now := time.Now()
+	var connections []adapter.SavedConnection
+	// enough expired connections to span multiple pages
+	for i := 0; i < 5000; i++ {
+		connections = append(connections, newTestSavedConnection(byte(i%10), now.Add(-2*time.Hour).Add(time.Duration(i)*time.Millisecond)))
+	}
+	connections = append(connections,
+		newTestSavedConnection(10, now.Add(-3*time.Minute)),
+		newTestSavedConnection(11, now.Add(-2*time.Minute)),
+		newTestSavedConnection(12, now.Add(-time.Minute)),
+	)
+	require.NoError(t, cacheFile.SaveConnectionHistory(connections))
+	require.Equal(t, []byte{12, 11, 10}, loadTestConnectionHistory(t, cacheFile, time.Time{}, time.Time{}))
+	require.Equal(t, []byte{12, 11}, loadTestConnectionHistory(t, cacheFile, now.Add(-2*time.Minute), time.Time{}))
+	require.Equal(t, []byte{11, 10}, loadTestConnectionHistory(t, cacheFile, time.Time{}, now.Add(-2*time.Minute)))
+}
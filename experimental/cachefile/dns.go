@@ -0,0 +1,185 @@
+package cachefile
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/sagernet/bbolt"
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing/common/logger"
+
+	mDNS "github.com/miekg/dns"
+)
+
+var bucketDNS = []byte("dns_cache")
+
+func (c *CacheFile) StoreDNS() bool {
+	return c.storeDNS
+}
+
+func dnsCacheKey(question mDNS.Question) []byte {
+	key := make([]byte, 4+len(question.Name))
+	binary.BigEndian.PutUint16(key, question.Qtype)
+	binary.BigEndian.PutUint16(key[2:], question.Qclass)
+	copy(key[4:], question.Name)
+	return key
+}
+
+// LoadDNSCache returns all saved DNS responses and deletes the ones
+// that expired longer than staleTimeout ago.
+func (c *CacheFile) LoadDNSCache(staleTimeout time.Duration) []adapter.SavedDNSCache {
+	var (
+		cacheList  []adapter.SavedDNSCache
+		deleteKeys [][]byte
+	)
+	timeNow := time.Now()
+	c.saveDNSAccess.Lock()
+	c.dnsStaleTimeout = staleTimeout
+	c.dnsPrunedAt = timeNow
+	c.saveDNSAccess.Unlock()
+	c.DB.View(func(tx *bbolt.Tx) error {
+		bucket := c.bucket(tx, bucketDNS)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if len(v) < 8 {
+				deleteKeys = append(deleteKeys, append([]byte(nil), k...))
+				return nil
+			}
+			expireAt := time.Unix(int64(binary.BigEndian.Uint64(v)), 0)
+			if timeNow.After(expireAt.Add(staleTimeout)) {
+				deleteKeys = append(deleteKeys, append([]byte(nil), k...))
+				return nil
+			}
+			var message mDNS.Msg
+			err := message.Unpack(v[8:])
+			if err != nil || len(message.Question) != 1 {
+				deleteKeys = append(deleteKeys, append([]byte(nil), k...))
+				return nil
+			}
+			cacheList = append(cacheList, adapter.SavedDNSCache{
+				Message:  &message,
+				ExpireAt: expireAt,
+			})
+			return nil
+		})
+	})
+	if len(deleteKeys) > 0 {
+		c.DB.Update(func(tx *bbolt.Tx) error {
+			bucket := c.bucket(tx, bucketDNS)
+			if bucket == nil {
+				return nil
+			}
+			for _, key := range deleteKeys {
+				bucket.Delete(key)
+			}
+			return nil
+		})
+	}
+	return cacheList
+}
+
+func dnsCacheValue(message *mDNS.Msg, expireAt time.Time) ([]byte, error) {
+	content, err := message.Pack()
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, 8+len(content))
+	binary.BigEndian.PutUint64(value, uint64(expireAt.Unix()))
+	copy(value[8:], content)
+	return value, nil
+}
+
+func (c *CacheFile) SaveDNSCache(message *mDNS.Msg, expireAt time.Time) error {
+	value, err := dnsCacheValue(message, expireAt)
+	if err != nil {
+		return err
+	}
+	return c.saveDNSCache(map[string][]byte{string(dnsCacheKey(message.Question[0])): value}, false, 0)
+}
+
+// SaveDNSCacheAsync queues the response, queued responses are written
+// in one transaction after DNSCacheSaveInterval.
+func (c *CacheFile) SaveDNSCacheAsync(message *mDNS.Msg, expireAt time.Time, logger logger.Logger) {
+	value, err := dnsCacheValue(message, expireAt)
+	if err != nil {
+		logger.Warn("save DNS cache: ", err)
+		return
+	}
+	c.saveDNSAccess.Lock()
+	c.saveDNS[string(dnsCacheKey(message.Question[0]))] = value
+	c.saveDNSLogger = logger
+	if c.saveDNSTimer == nil {
+		c.saveDNSTimer = time.AfterFunc(C.DNSCacheSaveInterval, c.flushDNSCache)
+	}
+	c.saveDNSAccess.Unlock()
+}
+
+func (c *CacheFile) flushDNSCache() {
+	c.saveDNSAccess.Lock()
+	pending := c.saveDNS
+	c.saveDNS = make(map[string][]byte)
+	c.saveDNSTimer = nil
+	logger := c.saveDNSLogger
+	// expired entries are also pruned while running, not only when the cache is loaded
+	prune := !c.dnsPrunedAt.IsZero() && time.Since(c.dnsPrunedAt) >= C.DNSCachePruneInterval
+	if prune {
+		c.dnsPrunedAt = time.Now()
+	}
+	staleTimeout := c.dnsStaleTimeout
+	c.saveDNSAccess.Unlock()
+	if len(pending) == 0 && !prune {
+		return
+	}
+	err := c.saveDNSCache(pending, prune, staleTimeout)
+	if err != nil && logger != nil {
+		logger.Warn("save DNS cache: ", err)
+	}
+}
+
+func (c *CacheFile) flushDNSCacheOnClose() {
+	c.saveDNSAccess.Lock()
+	pending := c.saveDNSTimer != nil && c.saveDNSTimer.Stop()
+	c.saveDNSAccess.Unlock()
+	if pending {
+		c.flushDNSCache()
+	}
+}
+
+func (c *CacheFile) saveDNSCache(pending map[string][]byte, prune bool, staleTimeout time.Duration) error {
+	return c.DB.Update(func(tx *bbolt.Tx) error {
+		bucket, err := c.createBucket(tx, bucketDNS)
+		if err != nil {
+			return err
+		}
+		for key, value := range pending {
+			err = bucket.Put([]byte(key), value)
+			if err != nil {
+				return err
+			}
+		}
+		if !prune {
+			return nil
+		}
+		var deleteKeys [][]byte
+		timeNow := time.Now()
+		err = bucket.ForEach(func(k, v []byte) error {
+			if len(v) < 8 || timeNow.After(time.Unix(int64(binary.BigEndian.Uint64(v)), 0).Add(staleTimeout)) {
+				deleteKeys = append(deleteKeys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range deleteKeys {
+			err = bucket.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
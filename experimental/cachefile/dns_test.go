@@ -0,0 +1,75 @@
+package cachefile
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sagernet/bbolt"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+
+	mDNS "github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCacheFile(t *testing.T, options option.CacheFileOptions) *CacheFile {
+	options.Path = filepath.Join(t.TempDir(), "cache.db")
+	cacheFile := New(context.Background(), options)
+	require.NoError(t, cacheFile.PreStart())
+	t.Cleanup(func() {
+		cacheFile.Close()
+	})
+	return cacheFile
+}
+
+func newTestDNSMessage(name string) *mDNS.Msg {
+	return &mDNS.Msg{
+		MsgHdr:   mDNS.MsgHdr{Response: true},
+		Question: []mDNS.Question{{Name: name, Qtype: mDNS.TypeA, Qclass: mDNS.ClassINET}},
+		Answer: []mDNS.RR{&mDNS.A{
+			Hdr: mDNS.RR_Header{Name: name, Rrtype: mDNS.TypeA, Class: mDNS.ClassINET, Ttl: 60},
+			A:   net.IPv4(1, 1, 1, 1),
+		}},
+	}
+}
+
+func TestDNSCacheBatch(t *testing.T) {
+	t.Parallel()
+	cacheFile := newTestCacheFile(t, option.CacheFileOptions{StoreDNS: true})
+	require.Empty(t, cacheFile.LoadDNSCache(0))
+	logger := log.NewNOPFactory().Logger()
+	expireAt := time.Now().Add(time.Minute)
+	cacheFile.SaveDNSCacheAsync(newTestDNSMessage("a.example."), expireAt, logger)
+	cacheFile.SaveDNSCacheAsync(newTestDNSMessage("b.example."), expireAt, logger)
+	cacheFile.SaveDNSCacheAsync(newTestDNSMessage("a.example."), expireAt, logger)
+	cacheFile.saveDNSAccess.Lock()
+	require.Len(t, cacheFile.saveDNS, 2)
+	require.NotNil(t, cacheFile.saveDNSTimer)
+	cacheFile.saveDNSAccess.Unlock()
+	cacheFile.flushDNSCacheOnClose()
+	require.Len(t, cacheFile.LoadDNSCache(0), 2)
+}
+
+func TestDNSCachePrune(t *testing.T) {
+	t.Parallel()
+	cacheFile := newTestCacheFile(t, option.CacheFileOptions{StoreDNS: true})
+	require.NoError(t, cacheFile.SaveDNSCache(newTestDNSMessage("expired.example."), time.Now().Add(-time.Hour)))
+	require.Empty(t, cacheFile.LoadDNSCache(time.Minute))
+	require.NoError(t, cacheFile.SaveDNSCache(newTestDNSMessage("expired.example."), time.Now().Add(-time.Hour)))
+	cacheFile.saveDNSAccess.Lock()
+	cacheFile.dnsPrunedAt = time.Now().Add(-2 * time.Hour)
+	cacheFile.saveDNSAccess.Unlock()
+	cacheFile.SaveDNSCacheAsync(newTestDNSMessage("valid.example."), time.Now().Add(time.Minute), log.NewNOPFactory().Logger())
+	cacheFile.flushDNSCacheOnClose()
+	var keys int
+	require.NoError(t, cacheFile.DB.View(func(tx *bbolt.Tx) error {
+		return cacheFile.bucket(tx, bucketDNS).ForEach(func(k, v []byte) error {
+			keys++
+			return nil
+		})
+	}))
+	require.Equal(t, 1, keys)
+}
@@ -20,6 +20,7 @@ import (
 func connectionRouter(router adapter.Router, trafficManager *trafficontrol.Manager) http.Handler {
 	r := chi.NewRouter()
 	r.Get("/", getConnections(trafficManager))
+	r.Get("/history", getConnectionHistory(trafficManager))
 	r.Delete("/", closeAllConnections(router, trafficManager))
 	r.Delete("/{id}", closeConnection(trafficManager))
 	return r
@@ -81,7 +82,7 @@ func closeConnection(trafficManager *trafficontrol.Manager) func(w http.Response
 		snapshot := trafficManager.Snapshot()
 		for _, c := range snapshot.Connections {
 			if id == c.Metadata().ID {
-				c.Close()
+				c.Kill()
 				break
 			}
 		}
@@ -93,9 +94,64 @@ func closeAllConnections(router adapter.Router, trafficManager *trafficontrol.Ma
 	return func(w http.ResponseWriter, r *http.Request) {
 		snapshot := trafficManager.Snapshot()
 		for _, c := range snapshot.Connections {
-			c.Close()
+			c.Kill()
 		}
 		router.ResetNetwork()
 		render.NoContent(w, r)
 	}
 }
+
+func getConnectionHistory(trafficManager *trafficontrol.Manager) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := trafficontrol.ConnectionFilter{
+			Host:     query.Get("host"),
+			Rule:     query.Get("rule"),
+			Outbound: query.Get("outbound"),
+			Process:  query.Get("process"),
+			Network:  query.Get("network"),
+			Reason:   query.Get("reason"),
+			Failure:  query.Get("failure"),
+		}
+		var err error
+		filter.Since, err = parseHistoryTime(query.Get("since"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, newError(err.Error()))
+			return
+		}
+		filter.Until, err = parseHistoryTime(query.Get("until"))
+		if err != nil {
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, newError(err.Error()))
+			return
+		}
+		limit := 1000
+		if limitStr := query.Get("limit"); limitStr != "" {
+			limit, err = strconv.Atoi(limitStr)
+			if err != nil || limit < 0 {
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, ErrBadRequest)
+				return
+			}
+		}
+		connections := trafficManager.ConnectionHistory(&filter, limit)
+		if connections == nil {
+			connections = []*trafficontrol.ConnectionRecord{}
+		}
+		render.JSON(w, r, render.M{
+			"connections": connections,
+		})
+	}
+}
+
+// parseHistoryTime parses a RFC 3339 time or an Unix timestamp in milliseconds.
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if milliseconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(milliseconds), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,42 @@
+package clashapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sagernet/sing-box/common/failure"
+	"github.com/sagernet/sing/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+func failureRouter(ctx context.Context) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/", getFailures(ctx))
+	r.Delete("/", resetFailures(ctx))
+	return r
+}
+
+func getFailures(ctx context.Context) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		outbounds := make(map[string]*failure.OutboundStatistics)
+		statistics := service.PtrFromContext[failure.Statistics](ctx)
+		if statistics != nil {
+			outbounds = statistics.All()
+		}
+		render.JSON(w, r, render.M{
+			"outbounds": outbounds,
+		})
+	}
+}
+
+func resetFailures(ctx context.Context) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statistics := service.PtrFromContext[failure.Statistics](ctx)
+		if statistics != nil {
+			statistics.Reset()
+		}
+		render.NoContent(w, r)
+	}
+}
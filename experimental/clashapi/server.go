@@ -14,6 +14,7 @@ import (
 
 	"github.com/sagernet/cors"
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/listener"
 	"github.com/sagernet/sing-box/common/urltest"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/experimental"
@@ -51,6 +52,7 @@ type Server struct {
 	modeUpdateHook chan<- struct{}
 
 	externalController       bool
+	externalControllerMode   os.FileMode
 	externalUI               string
 	externalUIDownloadURL    string
 	externalUIDownloadDetour string
@@ -70,6 +72,7 @@ func NewServer(ctx context.Context, router adapter.Router, logFactory log.Observ
 		trafficManager:           trafficManager,
 		modeList:                 options.ModeList,
 		externalController:       options.ExternalController != "",
+		externalControllerMode:   options.ExternalControllerUnixPermissions.Build(),
 		externalUIDownloadURL:    options.ExternalUIDownloadURL,
 		externalUIDownloadDetour: options.ExternalUIDownloadDetour,
 	}
@@ -118,6 +121,7 @@ func NewServer(ctx context.Context, router adapter.Router, logFactory log.Observ
 		r.Mount("/profile", profileRouter())
 		r.Mount("/cache", cacheRouter(ctx))
 		r.Mount("/dns", dnsRouter(router))
+		r.Mount("/failures", failureRouter(ctx))
 
 		server.setupMetaAPI(r)
 	})
@@ -143,6 +147,9 @@ func (s *Server) PreStart() error {
 		}) {
 			s.mode = mode
 		}
+		if cacheFile.StoreConnectionHistory() {
+			s.trafficManager.SetCacheFile(cacheFile, s.logger)
+		}
 	}
 	return nil
 }
@@ -151,11 +158,11 @@ func (s *Server) Start() error {
 	if s.externalController {
 		s.checkAndDownloadExternalUI()
 		var (
-			listener net.Listener
-			err      error
+			tcpListener net.Listener
+			err         error
 		)
 		for i := 0; i < 3; i++ {
-			listener, err = net.Listen("tcp", s.httpServer.Addr)
+			tcpListener, err = listener.Listen(s.ctx, s.httpServer.Addr, s.externalControllerMode)
 			if runtime.GOOS == "android" && errors.Is(err, syscall.EADDRINUSE) {
 				time.Sleep(100 * time.Millisecond)
 				continue
@@ -165,9 +172,9 @@ func (s *Server) Start() error {
 		if err != nil {
 			return E.Cause(err, "external controller listen error")
 		}
-		s.logger.Info("restful api listening at ", listener.Addr())
+		s.logger.Info("restful api listening at ", tcpListener.Addr())
 		go func() {
-			err = s.httpServer.Serve(listener)
+			err = s.httpServer.Serve(tcpListener)
 			if err != nil && !errors.Is(err, http.ErrServerClosed) {
 				s.logger.Error("external controller serve error: ", err)
 			}
@@ -5,16 +5,21 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/failure"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/experimental/clashapi/compatible"
 	"github.com/sagernet/sing/common"
 	"github.com/sagernet/sing/common/atomic"
 	"github.com/sagernet/sing/common/json"
+	"github.com/sagernet/sing/common/logger"
 	"github.com/sagernet/sing/common/x/list"
 
 	"github.com/gofrs/uuid/v5"
 )
 
+const closedConnectionsSize = 1000
+
 type Manager struct {
 	uploadTemp    atomic.Int64
 	downloadTemp  atomic.Int64
@@ -26,6 +31,9 @@ type Manager struct {
 	connections             compatible.Map[uuid.UUID, Tracker]
 	closedConnectionsAccess sync.Mutex
 	closedConnections       list.List[TrackerMetadata]
+	cacheFile               adapter.CacheFile
+	logger                  logger.Logger
+	saveConnections         []TrackerMetadata
 	ticker                  *time.Ticker
 	done                    chan struct{}
 	// process     *process.Process
@@ -46,17 +54,33 @@ func (m *Manager) Join(c Tracker) {
 	m.connections.Store(c.Metadata().ID, c)
 }
 
-func (m *Manager) Leave(c Tracker) {
+// SetCacheFile enables saving closed connections to the cache file.
+func (m *Manager) SetCacheFile(cacheFile adapter.CacheFile, logger logger.Logger) {
+	m.cacheFile = cacheFile
+	m.logger = logger
+}
+
+func (m *Manager) Leave(c Tracker, reason string, err error) {
 	metadata := c.Metadata()
 	_, loaded := m.connections.LoadAndDelete(metadata.ID)
 	if loaded {
 		metadata.ClosedAt = time.Now()
+		metadata.CloseReason = reason
+		if err != nil && reason != CloseReasonEOF && reason != CloseReasonKilled {
+			metadata.CloseError = err.Error()
+		}
+		if reason != CloseReasonKilled {
+			metadata.Failure = failure.Classify(err)
+		}
 		m.closedConnectionsAccess.Lock()
 		defer m.closedConnectionsAccess.Unlock()
-		if m.closedConnections.Len() >= 1000 {
+		if m.closedConnections.Len() >= closedConnectionsSize {
 			m.closedConnections.PopFront()
 		}
 		m.closedConnections.PushBack(metadata)
+		if m.cacheFile != nil {
+			m.saveConnections = append(m.saveConnections, metadata)
+		}
 	}
 }
 
@@ -97,6 +121,76 @@ func (m *Manager) ClosedConnections() []TrackerMetadata {
 	return m.closedConnections.Array()
 }
 
+// ConnectionHistory returns closed connections matching the filter, newest first.
+// Connections saved in the cache file are included if enabled.
+func (m *Manager) ConnectionHistory(filter *ConnectionFilter, limit int) []*ConnectionRecord {
+	var (
+		records []*ConnectionRecord
+		oldest  time.Time
+	)
+	m.closedConnectionsAccess.Lock()
+	for element := m.closedConnections.Back(); element != nil; element = element.Prev() {
+		oldest = element.Value.ClosedAt
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+		record := element.Value.Record()
+		if filter.Match(record) {
+			records = append(records, record)
+		}
+	}
+	m.closedConnectionsAccess.Unlock()
+	if m.cacheFile == nil || limit > 0 && len(records) >= limit {
+		return records
+	}
+	until := filter.Until
+	if !oldest.IsZero() && (until.IsZero() || oldest.Before(until)) {
+		// connections in memory are also saved
+		until = oldest.Add(-time.Nanosecond)
+	}
+	err := m.cacheFile.LoadConnectionHistory(filter.Since, until, func(content []byte) bool {
+		var record ConnectionRecord
+		err := json.Unmarshal(content, &record)
+		if err != nil {
+			return true
+		}
+		if filter.Match(&record) {
+			records = append(records, &record)
+		}
+		return limit <= 0 || len(records) < limit
+	})
+	if err != nil {
+		m.logger.Warn("load connection history: ", err)
+	}
+	return records
+}
+
+func (m *Manager) saveConnectionHistory() {
+	m.closedConnectionsAccess.Lock()
+	connections := m.saveConnections
+	m.saveConnections = nil
+	m.closedConnectionsAccess.Unlock()
+	if len(connections) == 0 {
+		return
+	}
+	savedConnections := make([]adapter.SavedConnection, 0, len(connections))
+	for _, connection := range connections {
+		content, err := json.Marshal(connection.Record())
+		if err != nil {
+			continue
+		}
+		savedConnections = append(savedConnections, adapter.SavedConnection{
+			ID:       connection.ID,
+			ClosedAt: connection.ClosedAt,
+			Content:  content,
+		})
+	}
+	err := m.cacheFile.SaveConnectionHistory(savedConnections)
+	if err != nil {
+		m.logger.Warn("save connection history: ", err)
+	}
+}
+
 func (m *Manager) Connection(id uuid.UUID) Tracker {
 	connection, loaded := m.connections.Load(id)
 	if !loaded {
@@ -148,6 +242,9 @@ func (m *Manager) handle() {
 		downloadTemp = m.downloadTemp.Swap(0)
 		m.uploadBlip.Store(uploadTemp)
 		m.downloadBlip.Store(downloadTemp)
+		if m.cacheFile != nil {
+			m.saveConnectionHistory()
+		}
 	}
 }
 
@@ -0,0 +1,72 @@
+package trafficontrol
+
+import (
+	"net"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	E "github.com/sagernet/sing/common/exceptions"
+	N "github.com/sagernet/sing/common/network"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRouter struct {
+	adapter.Router
+}
+
+func (r *testRouter) DefaultOutbound(network string) (adapter.Outbound, error) {
+	return nil, E.New("missing default outbound")
+}
+
+func (r *testRouter) Outbound(tag string) (adapter.Outbound, bool) {
+	return nil, false
+}
+
+func newTestTracker(t *testing.T, manager *Manager, host string) *TCPConn {
+	conn, peer := net.Pipe()
+	t.Cleanup(func() {
+		peer.Close()
+	})
+	metadata := adapter.InboundContext{Network: N.NetworkTCP, Domain: host}
+	return NewTCPTracker(conn, manager, metadata, &testRouter{}, nil)
+}
+
+func TestTrackerLeave(t *testing.T) {
+	t.Parallel()
+	manager := NewManager()
+	defer manager.Close()
+
+	failed := newTestTracker(t, manager, "failed")
+	failed.Leave(E.New("dial failed"))
+	require.Nil(t, manager.Connection(failed.Metadata().ID))
+	failed.Close()
+
+	// closed while routing, the error returned by the outbound is recorded
+	closed := newTestTracker(t, manager, "closed")
+	closed.Close()
+	require.NotNil(t, manager.Connection(closed.Metadata().ID))
+	closed.Leave(E.Cause(net.ErrClosed, "read"))
+
+	// the outbound returned while keeping the connection
+	kept := newTestTracker(t, manager, "kept")
+	kept.Leave(nil)
+	require.NotNil(t, manager.Connection(kept.Metadata().ID))
+	kept.Kill()
+	require.Nil(t, manager.Connection(kept.Metadata().ID))
+
+	records := manager.ConnectionHistory(&ConnectionFilter{}, 0)
+	require.Len(t, records, 3)
+	require.Equal(t, "kept", records[0].Metadata.Host)
+	require.Equal(t, CloseReasonKilled, records[0].CloseReason)
+	require.Equal(t, "closed", records[1].Metadata.Host)
+	require.Equal(t, CloseReasonEOF, records[1].CloseReason)
+	require.Equal(t, "failed", records[2].Metadata.Host)
+	require.Equal(t, CloseReasonError, records[2].CloseReason)
+	require.Equal(t, "dial failed", records[2].Error)
+
+	records = manager.ConnectionHistory(&ConnectionFilter{Reason: CloseReasonError}, 0)
+	require.Len(t, records, 1)
+	require.Equal(t, "failed", records[0].Metadata.Host)
+	require.Len(t, manager.ConnectionHistory(&ConnectionFilter{}, 2), 2)
+}
@@ -0,0 +1,114 @@
+package trafficontrol
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	CloseReasonEOF     = "eof"
+	CloseReasonReset   = "reset"
+	CloseReasonTimeout = "timeout"
+	CloseReasonKilled  = "killed"
+	CloseReasonError   = "error"
+)
+
+func closeReason(err error, killed bool) string {
+	switch {
+	case killed:
+		return CloseReasonKilled
+	case err == nil:
+		return CloseReasonEOF
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return CloseReasonReset
+	case E.IsTimeout(err), errors.Is(err, os.ErrDeadlineExceeded), errors.Is(err, context.DeadlineExceeded):
+		return CloseReasonTimeout
+	case E.IsClosedOrCanceled(err):
+		return CloseReasonEOF
+	default:
+		return CloseReasonError
+	}
+}
+
+type ConnectionRecord struct {
+	ID          uuid.UUID                `json:"id"`
+	Metadata    ConnectionRecordMetadata `json:"metadata"`
+	Upload      int64                    `json:"upload"`
+	Download    int64                    `json:"download"`
+	Start       time.Time                `json:"start"`
+	End         *time.Time               `json:"end,omitempty"`
+	Chains      []string                 `json:"chains"`
+	Rule        string                   `json:"rule"`
+	RulePayload string                   `json:"rulePayload"`
+	CloseReason string                   `json:"closeReason,omitempty"`
+	Error       string                   `json:"error,omitempty"`
+	Failure     string                   `json:"failure,omitempty"`
+}
+
+type ConnectionRecordMetadata struct {
+	Network         string     `json:"network"`
+	Type            string     `json:"type"`
+	SourceIP        netip.Addr `json:"sourceIP"`
+	DestinationIP   netip.Addr `json:"destinationIP"`
+	SourcePort      string     `json:"sourcePort"`
+	DestinationPort string     `json:"destinationPort"`
+	Host            string     `json:"host"`
+	DNSMode         string     `json:"dnsMode"`
+	ProcessPath     string     `json:"processPath"`
+}
+
+// ConnectionFilter selects closed connections, empty fields match all.
+type ConnectionFilter struct {
+	Host     string
+	Rule     string
+	Outbound string
+	Process  string
+	Network  string
+	Reason   string
+	Failure  string
+	Since    time.Time
+	Until    time.Time
+}
+
+func (f *ConnectionFilter) Match(record *ConnectionRecord) bool {
+	if f.Host != "" && !strings.Contains(record.Metadata.Host, f.Host) && !(record.Metadata.DestinationIP.IsValid() && strings.Contains(record.Metadata.DestinationIP.String(), f.Host)) {
+		return false
+	}
+	if f.Rule != "" && !strings.Contains(record.Rule, f.Rule) {
+		return false
+	}
+	if f.Outbound != "" && !common.Contains(record.Chains, f.Outbound) {
+		return false
+	}
+	if f.Process != "" && !strings.Contains(record.Metadata.ProcessPath, f.Process) {
+		return false
+	}
+	if f.Network != "" && record.Metadata.Network != f.Network {
+		return false
+	}
+	if f.Reason != "" && record.CloseReason != f.Reason {
+		return false
+	}
+	if f.Failure != "" && record.Failure != f.Failure {
+		return false
+	}
+	if record.End != nil {
+		if !f.Since.IsZero() && record.End.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && record.End.After(f.Until) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package trafficontrol
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	E "github.com/sagernet/sing/common/exceptions"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseReason(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, CloseReasonEOF, closeReason(nil, false))
+	require.Equal(t, CloseReasonKilled, closeReason(syscall.ECONNRESET, true))
+	require.Equal(t, CloseReasonReset, closeReason(E.Cause(syscall.ECONNRESET, "read"), false))
+	require.Equal(t, CloseReasonReset, closeReason(syscall.EPIPE, false))
+	require.Equal(t, CloseReasonTimeout, closeReason(os.ErrDeadlineExceeded, false))
+	require.Equal(t, CloseReasonTimeout, closeReason(context.DeadlineExceeded, false))
+	require.Equal(t, CloseReasonEOF, closeReason(io.EOF, false))
+	require.Equal(t, CloseReasonEOF, closeReason(net.ErrClosed, false))
+	require.Equal(t, CloseReasonError, closeReason(E.New("dial failed"), false))
+}
+
+func TestConnectionFilter(t *testing.T) {
+	t.Parallel()
+	end := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	record := &ConnectionRecord{
+		Metadata: ConnectionRecordMetadata{
+			Network:       "tcp",
+			DestinationIP: netip.MustParseAddr("1.1.1.1"),
+			Host:          "www.example.com",
+			ProcessPath:   "/usr/bin/curl",
+		},
+		End:         &end,
+		Chains:      []string{"proxy", "select"},
+		Rule:        "domain_suffix=example.com => select",
+		CloseReason: CloseReasonReset,
+		Failure:     "refused",
+	}
+	for _, filter := range []ConnectionFilter{
+		{},
+		{Host: "example.com"},
+		{Host: "1.1.1"},
+		{Rule: "domain_suffix"},
+		{Outbound: "proxy"},
+		{Process: "curl"},
+		{Network: "tcp", Reason: CloseReasonReset, Failure: "refused"},
+		{Since: end.Add(-time.Minute), Until: end},
+	} {
+		require.True(t, filter.Match(record), "%+v", filter)
+	}
+	for _, filter := range []ConnectionFilter{
+		{Host: "example.org"},
+		{Rule: "geoip"},
+		{Outbound: "prox"},
+		{Process: "wget"},
+		{Network: "udp"},
+		{Reason: CloseReasonEOF},
+		{Failure: "timeout"},
+		{Since: end.Add(time.Second)},
+		{Until: end.Add(-time.Second)},
+	} {
+		require.False(t, filter.Match(record), "%+v", filter)
+	}
+}
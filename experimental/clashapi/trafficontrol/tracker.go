@@ -2,6 +2,7 @@ package trafficontrol
 
 import (
 	"net"
+	"sync"
 	"time"
 
 	"github.com/sagernet/sing-box/adapter"
@@ -20,6 +21,9 @@ type TrackerMetadata struct {
 	Metadata     adapter.InboundContext
 	CreatedAt    time.Time
 	ClosedAt     time.Time
+	CloseReason  string
+	CloseError   string
+	Failure      string
 	Upload       *atomic.Int64
 	Download     *atomic.Int64
 	Chain        []string
@@ -29,6 +33,11 @@ type TrackerMetadata struct {
 }
 
 func (t TrackerMetadata) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Record())
+}
+
+// Record returns the serializable form of the connection.
+func (t TrackerMetadata) Record() *ConnectionRecord {
 	var inbound string
 	if t.Metadata.Inbound != "" {
 		inbound = t.Metadata.InboundType + "/" + t.Metadata.Inbound
@@ -64,38 +73,70 @@ func (t TrackerMetadata) MarshalJSON() ([]byte, error) {
 	} else {
 		rule = "final"
 	}
-	return json.Marshal(map[string]any{
-		"id": t.ID,
-		"metadata": map[string]any{
-			"network":         t.Metadata.Network,
-			"type":            inbound,
-			"sourceIP":        t.Metadata.Source.Addr,
-			"destinationIP":   t.Metadata.Destination.Addr,
-			"sourcePort":      F.ToString(t.Metadata.Source.Port),
-			"destinationPort": F.ToString(t.Metadata.Destination.Port),
-			"host":            domain,
-			"dnsMode":         "normal",
-			"processPath":     processPath,
+	record := &ConnectionRecord{
+		ID: t.ID,
+		Metadata: ConnectionRecordMetadata{
+			Network:         t.Metadata.Network,
+			Type:            inbound,
+			SourceIP:        t.Metadata.Source.Addr,
+			DestinationIP:   t.Metadata.Destination.Addr,
+			SourcePort:      F.ToString(t.Metadata.Source.Port),
+			DestinationPort: F.ToString(t.Metadata.Destination.Port),
+			Host:            domain,
+			DNSMode:         "normal",
+			ProcessPath:     processPath,
 		},
-		"upload":      t.Upload.Load(),
-		"download":    t.Download.Load(),
-		"start":       t.CreatedAt,
-		"chains":      t.Chain,
-		"rule":        rule,
-		"rulePayload": "",
-	})
+		Upload:      t.Upload.Load(),
+		Download:    t.Download.Load(),
+		Start:       t.CreatedAt,
+		Chains:      t.Chain,
+		Rule:        rule,
+		CloseReason: t.CloseReason,
+		Error:       t.CloseError,
+		Failure:     t.Failure,
+	}
+	if !t.ClosedAt.IsZero() {
+		record.End = common.Ptr(t.ClosedAt)
+	}
+	return record
 }
 
 type Tracker interface {
 	adapter.Tracker
 	Metadata() TrackerMetadata
 	Close() error
+	// Kill closes the connection with the killed close reason.
+	Kill() error
+}
+
+// leaveState decides when a connection leaves the manager: when routing finished with an error,
+// or when routing finished and the connection is closed, since outbounds may keep connections after returning.
+type leaveState struct {
+	access sync.Mutex
+	routed bool
+	closed bool
+}
+
+func (s *leaveState) routeDone(err error) bool {
+	s.access.Lock()
+	defer s.access.Unlock()
+	s.routed = true
+	return err != nil || s.closed
+}
+
+func (s *leaveState) close() bool {
+	s.access.Lock()
+	defer s.access.Unlock()
+	s.closed = true
+	return s.routed
 }
 
 type TCPConn struct {
 	N.ExtendedConn
 	metadata TrackerMetadata
 	manager  *Manager
+	killed   atomic.Bool
+	state    leaveState
 }
 
 func (tt *TCPConn) Metadata() TrackerMetadata {
@@ -103,12 +144,22 @@ func (tt *TCPConn) Metadata() TrackerMetadata {
 }
 
 func (tt *TCPConn) Close() error {
-	tt.manager.Leave(tt)
-	return tt.ExtendedConn.Close()
+	err := tt.ExtendedConn.Close()
+	if tt.state.close() {
+		tt.manager.Leave(tt, closeReason(nil, tt.killed.Load()), nil)
+	}
+	return err
 }
 
-func (tt *TCPConn) Leave() {
-	tt.manager.Leave(tt)
+func (tt *TCPConn) Kill() error {
+	tt.killed.Store(true)
+	return tt.Close()
+}
+
+func (tt *TCPConn) Leave(err error) {
+	if tt.state.routeDone(err) {
+		tt.manager.Leave(tt, closeReason(err, tt.killed.Load()), err)
+	}
 }
 
 func (tt *TCPConn) Upstream() any {
@@ -183,6 +234,8 @@ type UDPConn struct {
 	N.PacketConn `json:"-"`
 	metadata     TrackerMetadata
 	manager      *Manager
+	killed       atomic.Bool
+	state        leaveState
 }
 
 func (ut *UDPConn) Metadata() TrackerMetadata {
@@ -190,12 +243,22 @@ func (ut *UDPConn) Metadata() TrackerMetadata {
 }
 
 func (ut *UDPConn) Close() error {
-	ut.manager.Leave(ut)
-	return ut.PacketConn.Close()
+	err := ut.PacketConn.Close()
+	if ut.state.close() {
+		ut.manager.Leave(ut, closeReason(nil, ut.killed.Load()), nil)
+	}
+	return err
+}
+
+func (ut *UDPConn) Kill() error {
+	ut.killed.Store(true)
+	return ut.Close()
 }
 
-func (ut *UDPConn) Leave() {
-	ut.manager.Leave(ut)
+func (ut *UDPConn) Leave(err error) {
+	if ut.state.routeDone(err) {
+		ut.manager.Leave(ut, closeReason(err, ut.killed.Load()), err)
+	}
 }
 
 func (ut *UDPConn) Upstream() any {
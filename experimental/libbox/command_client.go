@@ -4,7 +4,6 @@ import (
 	"encoding/binary"
 	"net"
 	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/sagernet/sing/common"
@@ -48,7 +47,7 @@ func NewCommandClient(handler CommandClientHandler, options *CommandClientOption
 func (c *CommandClient) directConnect() (net.Conn, error) {
 	if !sTVOS {
 		return net.DialUnix("unix", nil, &net.UnixAddr{
-			Name: filepath.Join(sBasePath, "command.sock"),
+			Name: commandSocketPath(),
 			Net:  "unix",
 		})
 	} else {
@@ -19,6 +19,10 @@ func (c *CommandClient) CloseConnection(connId string) error {
 	}
 	defer conn.Close()
 	writer := bufio.NewWriter(conn)
+	err = binary.Write(writer, binary.BigEndian, uint8(CommandCloseConnection))
+	if err != nil {
+		return err
+	}
 	err = varbin.Write(writer, binary.BigEndian, connId)
 	if err != nil {
 		return err
@@ -49,6 +53,6 @@ func (s *CommandServer) handleCloseConnection(conn net.Conn) error {
 	if targetConn == nil {
 		return writeError(conn, E.New("connection already closed"))
 	}
-	targetConn.Close()
+	targetConn.Kill()
 	return writeError(conn, nil)
 }
@@ -0,0 +1,86 @@
+package libbox
+
+import (
+	"bufio"
+	"net"
+	"sort"
+
+	"github.com/sagernet/sing-box/common/failure"
+	"github.com/sagernet/sing/common/binary"
+	"github.com/sagernet/sing/common/varbin"
+)
+
+type OutboundFailure struct {
+	Outbound            string
+	Success             int64
+	DNS                 int64
+	Refused             int64
+	Unreachable         int64
+	Reset               int64
+	Timeout             int64
+	TLS                 int64
+	Auth                int64
+	Other               int64
+	ConsecutiveFailures int64
+	LastFailure         string
+	LastError           string
+	LastFailureAt       int64
+}
+
+type OutboundFailureIterator interface {
+	Next() *OutboundFailure
+	HasNext() bool
+}
+
+func newOutboundFailure(outbound string, statistics *failure.OutboundStatistics) OutboundFailure {
+	outboundFailure := OutboundFailure{
+		Outbound:            outbound,
+		Success:             int64(statistics.Success),
+		DNS:                 int64(statistics.Failures[failure.CategoryDNS]),
+		Refused:             int64(statistics.Failures[failure.CategoryRefused]),
+		Unreachable:         int64(statistics.Failures[failure.CategoryUnreachable]),
+		Reset:               int64(statistics.Failures[failure.CategoryReset]),
+		Timeout:             int64(statistics.Failures[failure.CategoryTimeout]),
+		TLS:                 int64(statistics.Failures[failure.CategoryTLS]),
+		Auth:                int64(statistics.Failures[failure.CategoryAuth]),
+		Other:               int64(statistics.Failures[failure.CategoryOther]),
+		ConsecutiveFailures: int64(statistics.ConsecutiveFailures),
+		LastFailure:         statistics.LastFailure,
+		LastError:           statistics.LastError,
+	}
+	if !statistics.LastFailureAt.IsZero() {
+		outboundFailure.LastFailureAt = statistics.LastFailureAt.UnixMilli()
+	}
+	return outboundFailure
+}
+
+func (c *CommandClient) GetOutboundFailures() (OutboundFailureIterator, error) {
+	conn, err := c.directConnectWithRetry()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	err = binary.Write(conn, binary.BigEndian, uint8(CommandOutboundFailures))
+	if err != nil {
+		return nil, err
+	}
+	var outboundFailures []OutboundFailure
+	err = varbin.Read(bufio.NewReader(conn), binary.BigEndian, &outboundFailures)
+	if err != nil {
+		return nil, err
+	}
+	return newPtrIterator(outboundFailures), nil
+}
+
+func (s *CommandServer) handleOutboundFailures(conn net.Conn) error {
+	var outboundFailures []OutboundFailure
+	if s.service != nil {
+		for outbound, statistics := range s.service.failureStatistics.All() {
+			outboundFailures = append(outboundFailures, newOutboundFailure(outbound, statistics))
+		}
+		sort.Slice(outboundFailures, func(i, j int) bool {
+			return outboundFailures[i].Outbound < outboundFailures[j].Outbound
+		})
+	}
+	return varbin.Write(conn, binary.BigEndian, outboundFailures)
+}
@@ -0,0 +1,28 @@
+package libbox
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+func commandPeerUserID(conn *net.UnixConn) (int, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		credential *unix.Xucred
+		credErr    error
+	)
+	err = rawConn.Control(func(fd uintptr) {
+		credential, credErr = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return int(credential.Uid), nil
+}
@@ -0,0 +1,28 @@
+package libbox
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+func commandPeerUserID(conn *net.UnixConn) (int, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		credential *unix.Ucred
+		credErr    error
+	)
+	err = rawConn.Control(func(fd uintptr) {
+		credential, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return int(credential.Uid), nil
+}
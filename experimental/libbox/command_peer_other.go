@@ -0,0 +1,12 @@
+//go:build !(darwin || linux)
+
+package libbox
+
+import (
+	"net"
+	"os"
+)
+
+func commandPeerUserID(conn *net.UnixConn) (int, error) {
+	return 0, os.ErrInvalid
+}
@@ -1,12 +1,13 @@
 package libbox
 
 import (
+	"context"
 	"encoding/binary"
 	"net"
 	"os"
-	"path/filepath"
 	"sync"
 
+	"github.com/sagernet/sing-box/common/listener"
 	"github.com/sagernet/sing-box/common/urltest"
 	"github.com/sagernet/sing-box/experimental/clashapi"
 	"github.com/sagernet/sing-box/log"
@@ -82,23 +83,24 @@ func (s *CommandServer) Start() error {
 }
 
 func (s *CommandServer) listenUNIX() error {
-	sockPath := filepath.Join(sBasePath, "command.sock")
-	os.Remove(sockPath)
-	listener, err := net.ListenUnix("unix", &net.UnixAddr{
-		Name: sockPath,
-		Net:  "unix",
-	})
-	if err != nil {
-		return E.Cause(err, "listen ", sockPath)
+	sockPath := commandSocketPath()
+	var permissions os.FileMode
+	if sCommandSocketPath != "" {
+		permissions = 0o600
 	}
-	err = os.Chown(sockPath, sUserID, sGroupID)
+	commandListener, err := listener.ListenUnix(context.Background(), net.ListenConfig{}, sockPath, permissions)
 	if err != nil {
-		listener.Close()
-		os.Remove(sockPath)
-		return E.Cause(err, "chown")
+		return err
 	}
-	s.listener = listener
-	go s.loopConnection(listener)
+	if sCommandSocketPath == "" {
+		err = os.Chown(sockPath, sUserID, sGroupID)
+		if err != nil {
+			commandListener.Close()
+			return E.Cause(err, "chown")
+		}
+	}
+	s.listener = commandListener
+	go s.loopConnection(commandListener)
 	return nil
 }
 
@@ -126,6 +128,14 @@ func (s *CommandServer) loopConnection(listener net.Listener) {
 			return
 		}
 		go func() {
+			if sCommandSocketPath != "" {
+				err := checkCommandPeer(conn)
+				if err != nil {
+					conn.Close()
+					log.Warn("command server: refused connection: ", err)
+					return
+				}
+			}
 			hErr := s.handleConnection(conn)
 			if hErr != nil && !E.IsClosed(err) {
 				if debug.Enabled {
@@ -136,6 +146,27 @@ func (s *CommandServer) loopConnection(listener net.Listener) {
 	}
 }
 
+// checkCommandPeer only accepts clients running as root or as the user of this process,
+// in case the permissions of the socket directory are more open than the socket.
+func checkCommandPeer(conn net.Conn) error {
+	unixConn, isUnix := conn.(*net.UnixConn)
+	if !isUnix {
+		return nil
+	}
+	userID, err := commandPeerUserID(unixConn)
+	if err != nil {
+		if err == os.ErrInvalid {
+			// peer credentials are not available on this platform
+			return nil
+		}
+		return E.Cause(err, "read peer credentials")
+	}
+	if userID != 0 && userID != os.Geteuid() {
+		return E.New("unauthorized user: ", userID)
+	}
+	return nil
+}
+
 func (s *CommandServer) handleConnection(conn net.Conn) error {
 	defer conn.Close()
 	var command uint8
@@ -174,6 +205,8 @@ func (s *CommandServer) handleConnection(conn net.Conn) error {
 		return s.handleConnectionsConn(conn)
 	case CommandCloseConnection:
 		return s.handleCloseConnection(conn)
+	case CommandOutboundFailures:
+		return s.handleOutboundFailures(conn)
 	default:
 		return E.New("unknown command: ", command)
 	}
@@ -0,0 +1,233 @@
+//go:build with_clash_api
+
+package libbox
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sagernet/sing-box"
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/outbound"
+	M "github.com/sagernet/sing/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testCommandConfig = `{
+  "log": {
+    "disabled": true
+  },
+  "outbounds": [
+    {
+      "type": "selector",
+      "tag": "proxy",
+      "outbounds": ["direct-a", "direct-b"]
+    },
+    {
+      "type": "direct",
+      "tag": "direct-a"
+    },
+    {
+      "type": "direct",
+      "tag": "direct-b"
+    }
+  ],
+  "route": {
+    "rules": [
+      {
+        "clash_mode": "direct",
+        "outbound": "direct-a"
+      }
+    ],
+    "final": "proxy"
+  },
+  "experimental": {
+    "clash_api": {}
+  }
+}`
+
+type testCommandServerHandler struct{}
+
+func (h *testCommandServerHandler) ServiceReload() error {
+	return nil
+}
+
+func (h *testCommandServerHandler) PostServiceClose() {
+}
+
+func (h *testCommandServerHandler) GetSystemProxyStatus() *SystemProxyStatus {
+	return &SystemProxyStatus{}
+}
+
+func (h *testCommandServerHandler) SetSystemProxyEnabled(isEnabled bool) error {
+	return nil
+}
+
+type testCommandClientHandler struct {
+	modeList    chan []string
+	mode        chan string
+	connections chan []Connection
+}
+
+func newTestCommandClientHandler() *testCommandClientHandler {
+	return &testCommandClientHandler{
+		modeList:    make(chan []string, 1),
+		mode:        make(chan string, 8),
+		connections: make(chan []Connection, 8),
+	}
+}
+
+func (h *testCommandClientHandler) Connected() {
+}
+
+func (h *testCommandClientHandler) Disconnected(message string) {
+}
+
+func (h *testCommandClientHandler) ClearLogs() {
+}
+
+func (h *testCommandClientHandler) WriteLogs(messageList StringIterator) {
+}
+
+func (h *testCommandClientHandler) WriteStatus(message *StatusMessage) {
+}
+
+func (h *testCommandClientHandler) WriteGroups(message OutboundGroupIterator) {
+}
+
+func (h *testCommandClientHandler) InitializeClashMode(modeList StringIterator, currentMode string) {
+	var modes []string
+	for modeList.HasNext() {
+		modes = append(modes, modeList.Next())
+	}
+	h.modeList <- modes
+	h.mode <- currentMode
+}
+
+func (h *testCommandClientHandler) UpdateClashMode(newMode string) {
+	h.mode <- newMode
+}
+
+func (h *testCommandClientHandler) WriteConnections(message *Connections) {
+	message.FilterState(ConnectionStateAll)
+	select {
+	case h.connections <- append([]Connection(nil), message.filtered...):
+	default:
+	}
+}
+
+func receiveTest[T any](t *testing.T, channel chan T) T {
+	select {
+	case value := <-channel:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+		return *new(T)
+	}
+}
+
+func startTestEcho(t *testing.T) M.Socksaddr {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return M.SocksaddrFromNet(listener.Addr())
+}
+
+func TestCommandServer(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "command.sock")
+	SetupCommandSocket(socketPath)
+	defer SetupCommandSocket("")
+	options, err := parseConfig(testCommandConfig)
+	require.NoError(t, err)
+	service, err := NewServiceWithOptions(box.Options{Options: options})
+	require.NoError(t, err)
+	require.NoError(t, service.Start())
+	defer service.Close()
+	server := NewCommandServer(&testCommandServerHandler{}, 100)
+	require.NoError(t, server.Start())
+	defer server.Close()
+	server.SetService(service)
+	fileInfo, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+
+	client := NewStandaloneCommandClient()
+	require.NoError(t, client.SelectOutbound("proxy", "direct-b"))
+	proxy, loaded := service.instance.Router().Outbound("proxy")
+	require.True(t, loaded)
+	require.Equal(t, "direct-b", proxy.(*outbound.Selector).Now())
+	require.Error(t, client.SelectOutbound("proxy", "missing"))
+
+	handler := newTestCommandClientHandler()
+	modeClient := NewCommandClient(handler, &CommandClientOptions{Command: CommandClashMode})
+	require.NoError(t, modeClient.Connect())
+	defer modeClient.Disconnect()
+	require.Contains(t, receiveTest(t, handler.modeList), "direct")
+	require.NotEqual(t, "direct", receiveTest(t, handler.mode))
+	require.NoError(t, client.SetClashMode("direct"))
+	require.Equal(t, "direct", receiveTest(t, handler.mode))
+
+	destination := startTestEcho(t)
+	inboundConn, routedConn := net.Pipe()
+	defer inboundConn.Close()
+	go service.instance.Router().RouteConnection(context.Background(), routedConn, adapter.InboundContext{
+		Inbound:     "test-in",
+		Network:     "tcp",
+		Destination: destination,
+	})
+	_, err = inboundConn.Write([]byte("hello"))
+	require.NoError(t, err)
+	response := make([]byte, 5)
+	_, err = io.ReadFull(inboundConn, response)
+	require.NoError(t, err)
+
+	connectionsClient := NewCommandClient(handler, &CommandClientOptions{
+		Command:        CommandConnections,
+		StatusInterval: int64(10 * time.Millisecond),
+	})
+	require.NoError(t, connectionsClient.Connect())
+	defer connectionsClient.Disconnect()
+	connections := receiveTest(t, handler.connections)
+	require.Len(t, connections, 1)
+	require.Equal(t, "test-in", connections[0].Inbound)
+	require.Equal(t, "direct-a", connections[0].Outbound)
+	require.Zero(t, connections[0].ClosedAt)
+	require.NoError(t, client.CloseConnection(connections[0].ID))
+	require.Eventually(t, func() bool {
+		connections = receiveTest(t, handler.connections)
+		return len(connections) == 1 && connections[0].ClosedAt != 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Error(t, client.CloseConnection(connections[0].ID))
+}
+
+func TestCommandServerExistingFile(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "command.sock")
+	require.NoError(t, os.WriteFile(socketPath, []byte("data"), 0o644))
+	SetupCommandSocket(socketPath)
+	defer SetupCommandSocket("")
+	server := NewCommandServer(&testCommandServerHandler{}, 100)
+	require.ErrorContains(t, server.Start(), "not a socket")
+	content, err := os.ReadFile(socketPath)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(content))
+}
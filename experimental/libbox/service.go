@@ -11,6 +11,7 @@ import (
 
 	"github.com/sagernet/sing-box"
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/failure"
 	"github.com/sagernet/sing-box/common/process"
 	"github.com/sagernet/sing-box/common/urltest"
 	C "github.com/sagernet/sing-box/constant"
@@ -35,6 +36,7 @@ type BoxService struct {
 	instance              *box.Box
 	pauseManager          pause.Manager
 	urlTestHistoryStorage *urltest.HistoryStorage
+	failureStatistics     *failure.Statistics
 
 	servicePauseFields
 }
@@ -49,6 +51,8 @@ func NewService(configContent string, platformInterface PlatformInterface) (*Box
 	ctx = filemanager.WithDefault(ctx, sWorkingPath, sTempPath, sUserID, sGroupID)
 	urlTestHistoryStorage := urltest.NewHistoryStorage()
 	ctx = service.ContextWithPtr(ctx, urlTestHistoryStorage)
+	failureStatistics := failure.NewStatistics()
+	ctx = service.ContextWithPtr(ctx, failureStatistics)
 	platformWrapper := &platformInterfaceWrapper{iif: platformInterface, useProcFS: platformInterface.UseProcFS()}
 	instance, err := box.New(box.Options{
 		Context:           ctx,
@@ -66,10 +70,44 @@ func NewService(configContent string, platformInterface PlatformInterface) (*Box
 		cancel:                cancel,
 		instance:              instance,
 		urlTestHistoryStorage: urlTestHistoryStorage,
+		failureStatistics:     failureStatistics,
 		pauseManager:          service.FromContext[pause.Manager](ctx),
 	}, nil
 }
 
+// NewServiceWithOptions creates a service for processes without a platform interface,
+// such as the command server of a standalone sing-box.
+func NewServiceWithOptions(options box.Options) (*BoxService, error) {
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	ctx = service.ContextWithDefaultRegistry(ctx)
+	urlTestHistoryStorage := urltest.NewHistoryStorage()
+	ctx = service.ContextWithPtr(ctx, urlTestHistoryStorage)
+	failureStatistics := failure.NewStatistics()
+	ctx = service.ContextWithPtr(ctx, failureStatistics)
+	options.Context = ctx
+	instance, err := box.New(options)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &BoxService{
+		ctx:                   ctx,
+		cancel:                cancel,
+		instance:              instance,
+		urlTestHistoryStorage: urlTestHistoryStorage,
+		failureStatistics:     failureStatistics,
+		pauseManager:          service.FromContext[pause.Manager](ctx),
+	}, nil
+}
+
+func (s *BoxService) Instance() *box.Box {
+	return s.instance
+}
+
 func (s *BoxService) Start() error {
 	return s.instance.Start()
 }
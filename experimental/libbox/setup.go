@@ -3,6 +3,7 @@ package libbox
 import (
 	"os"
 	"os/user"
+	"path/filepath"
 	"runtime/debug"
 	"strconv"
 	"time"
@@ -20,6 +21,8 @@ var (
 	sUserID      int
 	sGroupID     int
 	sTVOS        bool
+
+	sCommandSocketPath string
 )
 
 func init() {
@@ -55,6 +58,20 @@ func SetupWithUsername(basePath string, workingPath string, tempPath string, use
 	return nil
 }
 
+// SetupCommandSocket overrides the path of the command server socket, which is command.sock in the base path by default.
+// The socket is created with mode 0600 instead of being chowned, and only clients running as root
+// or as the user of this process are accepted where peer credentials are available.
+func SetupCommandSocket(path string) {
+	sCommandSocketPath = path
+}
+
+func commandSocketPath() string {
+	if sCommandSocketPath != "" {
+		return sCommandSocketPath
+	}
+	return filepath.Join(sBasePath, "command.sock")
+}
+
 func Version() string {
 	return C.Version
 }
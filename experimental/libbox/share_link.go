@@ -0,0 +1,36 @@
+package libbox
+
+import (
+	"bytes"
+
+	"github.com/sagernet/sing-box/common/sharelink"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/json"
+)
+
+// ParseShareLinks parses share links or a Clash proxies list, such as the content of a scanned QR code,
+// and returns a configuration containing the outbounds.
+func ParseShareLinks(content string) (string, error) {
+	outbounds, err := sharelink.ParseContent(content)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(option.Options{Outbounds: outbounds})
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// GenerateShareLink generates a share link for an outbound, which can be displayed as a QR code.
+func GenerateShareLink(outboundContent string) (string, error) {
+	outbound, err := json.UnmarshalExtended[option.Outbound]([]byte(outboundContent))
+	if err != nil {
+		return "", E.Cause(err, "decode outbound")
+	}
+	return sharelink.Format(outbound)
+}
@@ -0,0 +1,280 @@
+package v2rayapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/json"
+	"github.com/sagernet/sing/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	HandlerService_ServiceDesc.ServiceName = "xray.app.proxyman.command.HandlerService"
+	v2rayHandlerServiceDesc = HandlerService_ServiceDesc
+	v2rayHandlerServiceDesc.ServiceName = "v2ray.core.app.proxyman.command.HandlerService"
+}
+
+// v2rayHandlerServiceDesc serves the handler service under the V2Ray service name, for V2Ray clients.
+var v2rayHandlerServiceDesc grpc.ServiceDesc
+
+// InboundOptionsType is the TypedMessage type of a sing-box inbound in JSON,
+// the only inbound configuration accepted by AddInbound.
+const InboundOptionsType = "sing-box.option.Inbound"
+
+// operation types with the Xray or V2Ray package prefix stripped
+const (
+	typeAddUserOperation    = "app.proxyman.command.AddUserOperation"
+	typeRemoveUserOperation = "app.proxyman.command.RemoveUserOperation"
+)
+
+var _ HandlerServiceServer = (*HandlerService)(nil)
+
+type HandlerService struct {
+	ctx context.Context
+}
+
+func NewHandlerService(ctx context.Context, options option.V2RayHandlerServiceOptions) *HandlerService {
+	if !options.Enabled {
+		return nil
+	}
+	return &HandlerService{ctx}
+}
+
+func (s *HandlerService) inboundManager() (adapter.InboundManager, error) {
+	manager := service.FromContext[adapter.InboundManager](s.ctx)
+	if manager == nil {
+		return nil, E.New("inbound manager not available")
+	}
+	return manager, nil
+}
+
+func (s *HandlerService) userManagedInbound(tag string) (adapter.UserManagedInbound, error) {
+	manager, err := s.inboundManager()
+	if err != nil {
+		return nil, err
+	}
+	inbound, loaded := manager.Inbound(tag)
+	if !loaded {
+		return nil, E.New("inbound not found: ", tag)
+	}
+	userManaged, isUserManaged := inbound.(adapter.UserManagedInbound)
+	if !isUserManaged {
+		return nil, E.New("inbound ", tag, " does not support user management")
+	}
+	return userManaged, nil
+}
+
+func (s *HandlerService) AddInbound(ctx context.Context, request *AddInboundRequest) (*AddInboundResponse, error) {
+	manager, err := s.inboundManager()
+	if err != nil {
+		return nil, err
+	}
+	config := request.Inbound
+	if config == nil || config.ProxySettings == nil {
+		return nil, E.New("missing inbound config")
+	}
+	if config.ProxySettings.Type != InboundOptionsType {
+		return nil, E.New("unsupported inbound config type: ", config.ProxySettings.Type, ", expected ", InboundOptionsType)
+	}
+	inboundOptions, err := json.UnmarshalExtended[option.Inbound](config.ProxySettings.Value)
+	if err != nil {
+		return nil, E.Cause(err, "decode inbound options")
+	}
+	if config.Tag != "" {
+		inboundOptions.Tag = config.Tag
+	}
+	err = manager.AddInbound(inboundOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &AddInboundResponse{}, nil
+}
+
+func (s *HandlerService) RemoveInbound(ctx context.Context, request *RemoveInboundRequest) (*RemoveInboundResponse, error) {
+	manager, err := s.inboundManager()
+	if err != nil {
+		return nil, err
+	}
+	err = manager.RemoveInbound(request.Tag)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoveInboundResponse{}, nil
+}
+
+func (s *HandlerService) AlterInbound(ctx context.Context, request *AlterInboundRequest) (*AlterInboundResponse, error) {
+	inbound, err := s.userManagedInbound(request.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if request.Operation == nil {
+		return nil, E.New("missing operation")
+	}
+	switch trimTypePrefix(request.Operation.Type) {
+	case typeAddUserOperation:
+		var operation AddUserOperation
+		err = proto.Unmarshal(request.Operation.Value, &operation)
+		if err != nil {
+			return nil, E.Cause(err, "decode operation")
+		}
+		if operation.User == nil {
+			return nil, E.New("missing user")
+		}
+		var user adapter.InboundUser
+		user, err = parseUser(operation.User)
+		if err != nil {
+			return nil, err
+		}
+		err = inbound.AddUsers([]adapter.InboundUser{user})
+	case typeRemoveUserOperation:
+		var operation RemoveUserOperation
+		err = proto.Unmarshal(request.Operation.Value, &operation)
+		if err != nil {
+			return nil, E.Cause(err, "decode operation")
+		}
+		err = inbound.RemoveUsers([]string{operation.Email})
+	default:
+		return nil, E.New("unsupported operation: ", request.Operation.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &AlterInboundResponse{}, nil
+}
+
+func (s *HandlerService) ListInbounds(ctx context.Context, request *ListInboundsRequest) (*ListInboundsResponse, error) {
+	manager, err := s.inboundManager()
+	if err != nil {
+		return nil, err
+	}
+	return &ListInboundsResponse{
+		Inbounds: common.Map(manager.Inbounds(), func(it adapter.Inbound) *InboundHandlerConfig {
+			config := &InboundHandlerConfig{Tag: it.Tag()}
+			if !request.IsOnlyTags {
+				config.ProxySettings = &TypedMessage{Type: it.Type()}
+			}
+			return config
+		}),
+	}, nil
+}
+
+func (s *HandlerService) GetInboundUsers(ctx context.Context, request *GetInboundUserRequest) (*GetInboundUserResponse, error) {
+	inbound, err := s.userManagedInbound(request.Tag)
+	if err != nil {
+		return nil, err
+	}
+	users := inbound.Users()
+	if request.Email != "" {
+		users = common.Filter(users, func(it adapter.InboundUser) bool {
+			return it.Name == request.Email
+		})
+	}
+	response := &GetInboundUserResponse{}
+	for _, user := range users {
+		var protoUser *User
+		protoUser, err = buildUser(inbound.Type(), user)
+		if err != nil {
+			return nil, err
+		}
+		response.Users = append(response.Users, protoUser)
+	}
+	return response, nil
+}
+
+func (s *HandlerService) GetInboundUsersCount(ctx context.Context, request *GetInboundUserRequest) (*GetInboundUsersCountResponse, error) {
+	inbound, err := s.userManagedInbound(request.Tag)
+	if err != nil {
+		return nil, err
+	}
+	return &GetInboundUsersCountResponse{Count: int64(len(inbound.Users()))}, nil
+}
+
+func (s *HandlerService) mustEmbedUnimplementedHandlerServiceServer() {
+}
+
+// trimTypePrefix strips the Xray or V2Ray package prefix from a TypedMessage type.
+func trimTypePrefix(typeName string) string {
+	typeName = strings.TrimPrefix(typeName, "xray.")
+	return strings.TrimPrefix(typeName, "v2ray.core.")
+}
+
+func parseUser(user *User) (adapter.InboundUser, error) {
+	inboundUser := adapter.InboundUser{
+		Name: user.Email,
+	}
+	if user.Account == nil {
+		return inboundUser, E.New("missing account")
+	}
+	var err error
+	switch trimTypePrefix(user.Account.Type) {
+	case "proxy.vmess.Account":
+		var account VMessAccount
+		err = proto.Unmarshal(user.Account.Value, &account)
+		inboundUser.UUID = account.Id
+	case "proxy.vless.Account":
+		var account VLESSAccount
+		err = proto.Unmarshal(user.Account.Value, &account)
+		inboundUser.UUID = account.Id
+		inboundUser.Flow = account.Flow
+	case "proxy.trojan.Account":
+		var account TrojanAccount
+		err = proto.Unmarshal(user.Account.Value, &account)
+		inboundUser.Password = account.Password
+	case "proxy.shadowsocks.Account":
+		var account ShadowsocksAccount
+		err = proto.Unmarshal(user.Account.Value, &account)
+		inboundUser.Password = account.Password
+	case "proxy.shadowsocks_2022.Account":
+		var account Shadowsocks2022Account
+		err = proto.Unmarshal(user.Account.Value, &account)
+		inboundUser.Password = account.Key
+	default:
+		return inboundUser, E.New("unsupported account type: ", user.Account.Type)
+	}
+	if err != nil {
+		return inboundUser, E.Cause(err, "decode account")
+	}
+	return inboundUser, nil
+}
+
+func buildUser(inboundType string, user adapter.InboundUser) (*User, error) {
+	var (
+		typeName string
+		account  proto.Message
+	)
+	switch inboundType {
+	case C.TypeVMess:
+		typeName = "xray.proxy.vmess.Account"
+		account = &VMessAccount{Id: user.UUID}
+	case C.TypeVLESS:
+		typeName = "xray.proxy.vless.Account"
+		account = &VLESSAccount{Id: user.UUID, Flow: user.Flow}
+	case C.TypeTrojan:
+		typeName = "xray.proxy.trojan.Account"
+		account = &TrojanAccount{Password: user.Password}
+	case C.TypeShadowsocks:
+		typeName = "xray.proxy.shadowsocks.Account"
+		account = &ShadowsocksAccount{Password: user.Password}
+	default:
+		return nil, E.New("unsupported inbound type: ", inboundType)
+	}
+	accountValue, err := proto.Marshal(account)
+	if err != nil {
+		return nil, err
+	}
+	return &User{
+		Email: user.Name,
+		Account: &TypedMessage{
+			Type:  typeName,
+			Value: accountValue,
+		},
+	}, nil
+}
@@ -1,11 +1,14 @@
 package v2rayapi
 
 import (
+	"context"
 	"errors"
 	"net"
 	"net/http"
+	"os"
 
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/listener"
 	"github.com/sagernet/sing-box/experimental"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
@@ -22,37 +25,48 @@ func init() {
 var _ adapter.V2RayServer = (*Server)(nil)
 
 type Server struct {
-	logger       log.Logger
-	listen       string
-	tcpListener  net.Listener
-	grpcServer   *grpc.Server
-	statsService *StatsService
+	logger         log.Logger
+	ctx            context.Context
+	listen         string
+	listenMode     os.FileMode
+	tcpListener    net.Listener
+	grpcServer     *grpc.Server
+	statsService   *StatsService
+	handlerService *HandlerService
 }
 
-func NewServer(logger log.Logger, options option.V2RayAPIOptions) (adapter.V2RayServer, error) {
+func NewServer(ctx context.Context, logger log.Logger, options option.V2RayAPIOptions) (adapter.V2RayServer, error) {
 	grpcServer := grpc.NewServer(grpc.Creds(insecure.NewCredentials()))
 	statsService := NewStatsService(common.PtrValueOrDefault(options.Stats))
 	if statsService != nil {
 		RegisterStatsServiceServer(grpcServer, statsService)
 	}
+	handlerService := NewHandlerService(ctx, common.PtrValueOrDefault(options.Handler))
+	if handlerService != nil {
+		RegisterHandlerServiceServer(grpcServer, handlerService)
+		grpcServer.RegisterService(&v2rayHandlerServiceDesc, handlerService)
+	}
 	server := &Server{
-		logger:       logger,
-		listen:       options.Listen,
-		grpcServer:   grpcServer,
-		statsService: statsService,
+		logger:         logger,
+		ctx:            ctx,
+		listen:         options.Listen,
+		listenMode:     options.UnixPermissions.Build(),
+		grpcServer:     grpcServer,
+		statsService:   statsService,
+		handlerService: handlerService,
 	}
 	return server, nil
 }
 
 func (s *Server) Start() error {
-	listener, err := net.Listen("tcp", s.listen)
+	tcpListener, err := listener.Listen(s.ctx, s.listen, s.listenMode)
 	if err != nil {
 		return err
 	}
-	s.logger.Info("grpc server started at ", listener.Addr())
-	s.tcpListener = listener
+	s.logger.Info("grpc server started at ", tcpListener.Addr())
+	s.tcpListener = tcpListener
 	go func() {
-		err = s.grpcServer.Serve(listener)
+		err = s.grpcServer.Serve(tcpListener)
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			s.logger.Error(err)
 		}
@@ -0,0 +1,119 @@
+package inbound
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/mux"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/bond"
+	E "github.com/sagernet/sing/common/exceptions"
+	N "github.com/sagernet/sing/common/network"
+)
+
+var (
+	_ adapter.Inbound           = (*Bond)(nil)
+	_ adapter.InjectableInbound = (*Bond)(nil)
+)
+
+type Bond struct {
+	myInboundAdapter
+	key         [bond.KeyLength]byte
+	linkTimeout time.Duration
+	access      sync.Mutex
+	sessions    map[[bond.SessionIDLength]byte]*bondSession
+}
+
+type bondSession struct {
+	*bond.Session
+	key bond.SessionKey
+}
+
+func NewBond(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.BondInboundOptions) (*Bond, error) {
+	inbound := &Bond{
+		myInboundAdapter: myInboundAdapter{
+			protocol:      C.TypeBond,
+			network:       []string{N.NetworkTCP},
+			ctx:           ctx,
+			router:        router,
+			logger:        logger,
+			tag:           tag,
+			listenOptions: options.ListenOptions,
+		},
+		key:         bond.Key(options.Password),
+		linkTimeout: time.Duration(options.LinkTimeout),
+		sessions:    make(map[[bond.SessionIDLength]byte]*bondSession),
+	}
+	if options.Password == "" {
+		return nil, E.New("missing password")
+	}
+	if inbound.linkTimeout == 0 {
+		inbound.linkTimeout = C.BondLinkTimeout
+	}
+	var err error
+	inbound.router, err = mux.NewRouterWithOptions(inbound.router, logger, option.InboundMultiplexOptions{Enabled: true})
+	if err != nil {
+		return nil, err
+	}
+	inbound.connHandler = inbound
+	return inbound, nil
+}
+
+func (h *Bond) NewConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
+	var (
+		session *bondSession
+		loaded  bool
+	)
+	link, _, err := bond.ServerHandshake(conn, h.key, func(sessionKey bond.SessionKey) bool {
+		h.access.Lock()
+		defer h.access.Unlock()
+		session, loaded = h.sessions[sessionKey.ID]
+		if loaded {
+			// links may only join the session created by the same client
+			return session.key.Equal(sessionKey)
+		}
+		session = &bondSession{
+			Session: bond.NewSession(h.linkTimeout, func(name string, err error) {
+				h.logger.DebugContext(ctx, "bond link from ", name, " lost: ", err)
+			}),
+			key: sessionKey,
+		}
+		h.sessions[sessionKey.ID] = session
+		return true
+	})
+	if session != nil && !loaded {
+		// the session is stored before the handshake completes, so it is
+		// removed on every return from the connection that created it
+		defer func() {
+			h.access.Lock()
+			delete(h.sessions, session.key.ID)
+			h.access.Unlock()
+			session.Close()
+		}()
+	}
+	if err != nil {
+		return E.Cause(err, "process bond handshake")
+	}
+	linkDone, err := session.AddLink(link, metadata.Source.String())
+	if err != nil {
+		return err
+	}
+	if loaded {
+		h.logger.DebugContext(ctx, "bond link joined, ", session.Links(), " link(s) active")
+		<-linkDone
+		return nil
+	}
+	metadata.Destination = mux.Destination
+	h.logger.InfoContext(ctx, "inbound bond session")
+	return h.router.RouteConnection(ctx, session.Session, metadata)
+}
+
+func (h *Bond) NewPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
+	return os.ErrInvalid
+}
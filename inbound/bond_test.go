@@ -0,0 +1,45 @@
+package inbound
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/bond"
+
+	"github.com/stretchr/testify/require"
+)
+
+// closeAfterWritesConn closes itself after the given number of writes,
+// so the peer fails to write the handshake response.
+type closeAfterWritesConn struct {
+	net.Conn
+	writes int
+}
+
+func (c *closeAfterWritesConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.writes--
+	if c.writes == 0 {
+		c.Conn.Close()
+	}
+	return n, err
+}
+
+func TestBondHandshakeFailure(t *testing.T) {
+	t.Parallel()
+	inbound, err := NewBond(context.Background(), nil, log.NewNOPFactory().Logger(), "bond-in", option.BondInboundOptions{
+		Password: "password",
+	})
+	require.NoError(t, err)
+	sessionKey, err := bond.NewSessionKey()
+	require.NoError(t, err)
+	clientConn, serverConn := net.Pipe()
+	defer serverConn.Close()
+	go bond.ClientHandshake(&closeAfterWritesConn{Conn: clientConn, writes: 2}, bond.Key("password"), sessionKey)
+	require.Error(t, inbound.NewConnection(context.Background(), serverConn, adapter.InboundContext{}))
+	require.Empty(t, inbound.sessions)
+}
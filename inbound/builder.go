@@ -19,9 +19,9 @@ func New(ctx context.Context, router adapter.Router, logger log.ContextLogger, t
 	case C.TypeTun:
 		return NewTun(ctx, router, logger, tag, options.TunOptions, platformInterface)
 	case C.TypeRedirect:
-		return NewRedirect(ctx, router, logger, tag, options.RedirectOptions), nil
+		return NewRedirect(ctx, router, logger, tag, options.RedirectOptions)
 	case C.TypeTProxy:
-		return NewTProxy(ctx, router, logger, tag, options.TProxyOptions), nil
+		return NewTProxy(ctx, router, logger, tag, options.TProxyOptions)
 	case C.TypeDirect:
 		return NewDirect(ctx, router, logger, tag, options.DirectOptions), nil
 	case C.TypeSOCKS:
@@ -48,6 +48,12 @@ func New(ctx context.Context, router adapter.Router, logger log.ContextLogger, t
 		return NewTUIC(ctx, router, logger, tag, options.TUICOptions)
 	case C.TypeHysteria2:
 		return NewHysteria2(ctx, router, logger, tag, options.Hysteria2Options)
+	case C.TypeBond:
+		return NewBond(ctx, router, logger, tag, options.BondOptions)
+	case C.TypeTor:
+		return NewTor(ctx, router, logger, tag, options.TorOptions)
+	case C.TypeSSH:
+		return NewSSH(ctx, router, logger, tag, options.SSHOptions)
 	default:
 		return nil, E.New("unknown inbound type: ", options.Type)
 	}
@@ -3,12 +3,14 @@ package inbound
 import (
 	"context"
 	"net"
+	"net/netip"
 
 	"github.com/sagernet/sing-box/adapter"
 	"github.com/sagernet/sing-box/common/settings"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/udphop"
 	"github.com/sagernet/sing/common"
 	"github.com/sagernet/sing/common/atomic"
 	E "github.com/sagernet/sing/common/exceptions"
@@ -41,6 +43,7 @@ type myInboundAdapter struct {
 	tcpListener          net.Listener
 	udpConn              *net.UDPConn
 	udpAddr              M.Socksaddr
+	udpPortConn          *udphop.ServerConn
 	packetOutboundClosed chan struct{}
 	packetOutbound       chan *myInboundPacket
 
@@ -91,6 +94,9 @@ func (a *myInboundAdapter) Start() error {
 		}
 	}
 	if a.setSystemProxy {
+		if !a.listenOptions.Listen.IsIP() {
+			return E.New("system proxy is not supported when listening on ", a.listenOptions.Listen)
+		}
 		listenPort := M.SocksaddrFromNet(a.tcpListener.Addr()).Port
 		var listenAddrString string
 		listenAddr := a.listenOptions.Listen.Build()
@@ -122,6 +128,7 @@ func (a *myInboundAdapter) Close() error {
 	return E.Errors(err, common.Close(
 		a.tcpListener,
 		common.PtrOrNil(a.udpConn),
+		common.PtrOrNil(a.udpPortConn),
 	))
 }
 
@@ -155,6 +162,13 @@ func (a *myInboundAdapter) createMetadata(conn net.Conn, metadata adapter.Inboun
 	metadata.InboundType = a.protocol
 	metadata.InboundDetour = a.listenOptions.Detour
 	metadata.InboundOptions = a.listenOptions.InboundOptions
+	if _, isUnix := conn.LocalAddr().(*net.UnixAddr); isUnix {
+		// unix socket peers are local processes without a network address
+		if !metadata.Source.IsValid() {
+			metadata.Source = M.SocksaddrFrom(netip.AddrFrom4([4]byte{127, 0, 0, 1}), 0)
+		}
+		return metadata
+	}
 	if !metadata.Source.IsValid() {
 		metadata.Source = M.SocksaddrFromNet(conn.RemoteAddr()).Unwrap()
 	}
@@ -5,6 +5,7 @@ import (
 	"net"
 
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/listener"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing/common/control"
@@ -15,6 +16,9 @@ import (
 
 func (a *myInboundAdapter) ListenTCP() (net.Listener, error) {
 	var err error
+	if !a.listenOptions.Listen.IsIP() {
+		return a.listenSocket()
+	}
 	bindAddr := M.SocksaddrFrom(a.listenOptions.Listen.Build(), a.listenOptions.ListenPort)
 	var tcpListener net.Listener
 	var listenConfig net.ListenConfig
@@ -45,6 +49,24 @@ func (a *myInboundAdapter) ListenTCP() (net.Listener, error) {
 	return tcpListener, err
 }
 
+func (a *myInboundAdapter) listenSocket() (net.Listener, error) {
+	var (
+		tcpListener net.Listener
+		err         error
+	)
+	if unixPath := a.listenOptions.Listen.UnixPath(); unixPath != "" {
+		tcpListener, err = listener.ListenUnix(a.ctx, net.ListenConfig{}, unixPath, a.listenOptions.UnixPermissions.Build())
+	} else {
+		tcpListener, err = listener.ListenSystemd(a.listenOptions.Listen.SystemdName())
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.logger.Info("tcp server started at ", a.listenOptions.Listen)
+	a.tcpListener = tcpListener
+	return tcpListener, nil
+}
+
 func (a *myInboundAdapter) loopTCPIn() {
 	tcpListener := a.tcpListener
 	for {
@@ -0,0 +1,40 @@
+package inbound
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMetadataUnix(t *testing.T) {
+	t.Parallel()
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "test.sock"))
+	require.NoError(t, err)
+	defer listener.Close()
+	clientConn, err := net.Dial("unix", listener.Addr().String())
+	require.NoError(t, err)
+	defer clientConn.Close()
+	serverConn, err := listener.Accept()
+	require.NoError(t, err)
+	defer serverConn.Close()
+	inbound := &myInboundAdapter{
+		protocol: C.TypeMixed,
+		tag:      "mixed-in",
+		listenOptions: option.ListenOptions{
+			Detour: "next",
+		},
+	}
+	metadata := inbound.createMetadata(serverConn, adapter.InboundContext{})
+	require.Equal(t, "mixed-in", metadata.Inbound)
+	require.Equal(t, C.TypeMixed, metadata.InboundType)
+	require.Equal(t, "next", metadata.InboundDetour)
+	require.True(t, metadata.Source.IsValid())
+	require.True(t, metadata.Source.Addr.IsLoopback())
+	require.False(t, metadata.Destination.IsValid())
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/transport/udphop"
 	"github.com/sagernet/sing/common"
 	"github.com/sagernet/sing/common/buf"
 	"github.com/sagernet/sing/common/control"
@@ -15,7 +16,50 @@ import (
 )
 
 func (a *myInboundAdapter) ListenUDP() (net.PacketConn, error) {
+	if !a.listenOptions.Listen.IsIP() {
+		return nil, E.New("UDP is not supported when listening on ", a.listenOptions.Listen)
+	}
 	bindAddr := M.SocksaddrFrom(a.listenOptions.Listen.Build(), a.listenOptions.ListenPort)
+	udpConn, err := a.listenUDP(bindAddr)
+	if err != nil {
+		return nil, err
+	}
+	a.udpConn = udpConn
+	a.udpAddr = bindAddr
+	a.logger.Info("udp server started at ", udpConn.LocalAddr())
+	return udpConn, err
+}
+
+// ListenUDPPorts listens on additional ports besides the listen port,
+// and serves all of them as one packet conn.
+func (a *myInboundAdapter) ListenUDPPorts(ports []uint16) (net.PacketConn, error) {
+	udpConn, err := a.ListenUDP()
+	if err != nil || len(ports) == 0 {
+		return udpConn, err
+	}
+	packetConns := []net.PacketConn{udpConn}
+	for _, port := range ports {
+		if port == a.listenOptions.ListenPort {
+			continue
+		}
+		var portConn *net.UDPConn
+		portConn, err = a.listenUDP(M.SocksaddrFrom(a.listenOptions.Listen.Build(), port))
+		if err != nil {
+			for _, packetConn := range packetConns[1:] {
+				packetConn.Close()
+			}
+			return nil, err
+		}
+		packetConns = append(packetConns, portConn)
+	}
+	// the port conn takes over the listen port socket
+	a.udpConn = nil
+	a.udpPortConn = udphop.NewServerConn(packetConns)
+	a.logger.Info("udp server listening on ", len(packetConns)-1, " additional ports")
+	return a.udpPortConn, nil
+}
+
+func (a *myInboundAdapter) listenUDP(bindAddr M.Socksaddr) (*net.UDPConn, error) {
 	var lc net.ListenConfig
 	var udpFragment bool
 	if a.listenOptions.UDPFragment != nil {
@@ -30,10 +74,7 @@ func (a *myInboundAdapter) ListenUDP() (net.PacketConn, error) {
 	if err != nil {
 		return nil, err
 	}
-	a.udpConn = udpConn.(*net.UDPConn)
-	a.udpAddr = bindAddr
-	a.logger.Info("udp server started at ", udpConn.LocalAddr())
-	return udpConn, err
+	return udpConn.(*net.UDPConn), nil
 }
 
 func (a *myInboundAdapter) loopUDPIn() {
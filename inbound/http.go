@@ -79,6 +79,7 @@ func (h *HTTP) NewConnection(ctx context.Context, conn net.Conn, metadata adapte
 		if err != nil {
 			return err
 		}
+		metadata.User = tls.ClientCertificateUser(conn)
 	}
 	return http.HandleConnection(ctx, conn, std_bufio.NewReader(conn), h.authenticator, h.upstreamUserHandler(metadata), adapter.UpstreamMetadata(metadata))
 }
@@ -97,7 +98,10 @@ func (a *myInboundAdapter) newUserConnection(ctx context.Context, conn net.Conn,
 		a.logger.InfoContext(ctx, "inbound connection to ", metadata.Destination)
 		return a.router.RouteConnection(ctx, conn, metadata)
 	}
-	metadata.User = user
+	if metadata.User == "" {
+		// the identity of a verified client certificate takes precedence
+		metadata.User = user
+	}
 	a.logger.InfoContext(ctx, "[", user, "] inbound connection to ", metadata.Destination)
 	return a.router.RouteConnection(ctx, conn, metadata)
 }
@@ -108,7 +112,9 @@ func (a *myInboundAdapter) streamUserPacketConnection(ctx context.Context, conn
 		a.logger.InfoContext(ctx, "inbound packet connection to ", metadata.Destination)
 		return a.router.RoutePacketConnection(ctx, conn, metadata)
 	}
-	metadata.User = user
+	if metadata.User == "" {
+		metadata.User = user
+	}
 	a.logger.InfoContext(ctx, "[", user, "] inbound packet connection to ", metadata.Destination)
 	return a.router.RoutePacketConnection(ctx, conn, metadata)
 }
@@ -13,6 +13,7 @@ import (
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/udphop"
 	"github.com/sagernet/sing-quic/hysteria"
 	"github.com/sagernet/sing/common"
 	"github.com/sagernet/sing/common/auth"
@@ -27,6 +28,7 @@ type Hysteria struct {
 	tlsConfig    tls.ServerConfig
 	service      *hysteria.Service[int]
 	userNameList []string
+	listenPorts  []uint16
 }
 
 func NewHysteria(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.HysteriaInboundOptions) (*Hysteria, error) {
@@ -38,6 +40,10 @@ func NewHysteria(ctx context.Context, router adapter.Router, logger log.ContextL
 	if err != nil {
 		return nil, err
 	}
+	listenPorts, err := udphop.ParsePorts(options.ListenPorts)
+	if err != nil {
+		return nil, E.Cause(err, "parse listen_ports")
+	}
 	inbound := &Hysteria{
 		myInboundAdapter: myInboundAdapter{
 			protocol:      C.TypeHysteria,
@@ -48,7 +54,8 @@ func NewHysteria(ctx context.Context, router adapter.Router, logger log.ContextL
 			tag:           tag,
 			listenOptions: options.ListenOptions,
 		},
-		tlsConfig: tlsConfig,
+		tlsConfig:   tlsConfig,
+		listenPorts: listenPorts,
 	}
 	var sendBps, receiveBps uint64
 	if len(options.Up) > 0 {
@@ -148,7 +155,7 @@ func (h *Hysteria) Start() error {
 			return err
 		}
 	}
-	packetConn, err := h.myInboundAdapter.ListenUDP()
+	packetConn, err := h.myInboundAdapter.ListenUDPPorts(h.listenPorts)
 	if err != nil {
 		return err
 	}
@@ -15,6 +15,7 @@ import (
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/udphop"
 	"github.com/sagernet/sing-quic/hysteria"
 	"github.com/sagernet/sing-quic/hysteria2"
 	"github.com/sagernet/sing/common"
@@ -30,6 +31,7 @@ type Hysteria2 struct {
 	tlsConfig    tls.ServerConfig
 	service      *hysteria2.Service[int]
 	userNameList []string
+	listenPorts  []uint16
 }
 
 func NewHysteria2(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.Hysteria2InboundOptions) (*Hysteria2, error) {
@@ -76,6 +78,10 @@ func NewHysteria2(ctx context.Context, router adapter.Router, logger log.Context
 			return nil, E.New("unknown masquerade URL scheme: ", masqueradeURL.Scheme)
 		}
 	}
+	listenPorts, err := udphop.ParsePorts(options.ListenPorts)
+	if err != nil {
+		return nil, E.Cause(err, "parse listen_ports")
+	}
 	inbound := &Hysteria2{
 		myInboundAdapter: myInboundAdapter{
 			protocol:      C.TypeHysteria2,
@@ -86,7 +92,8 @@ func NewHysteria2(ctx context.Context, router adapter.Router, logger log.Context
 			tag:           tag,
 			listenOptions: options.ListenOptions,
 		},
-		tlsConfig: tlsConfig,
+		tlsConfig:   tlsConfig,
+		listenPorts: listenPorts,
 	}
 	var udpTimeout time.Duration
 	if options.UDPTimeout != 0 {
@@ -159,7 +166,7 @@ func (h *Hysteria2) Start() error {
 			return err
 		}
 	}
-	packetConn, err := h.myInboundAdapter.ListenUDP()
+	packetConn, err := h.myInboundAdapter.ListenUDPPorts(h.listenPorts)
 	if err != nil {
 		return err
 	}
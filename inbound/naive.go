@@ -147,6 +147,12 @@ func (n *Naive) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		n.badRequest(ctx, request, E.New("authorization failed"))
 		return
 	}
+	if request.TLS != nil {
+		// the identity of a verified client certificate takes precedence
+		if certificateUser := tls.ClientCertificateUserFromState(*request.TLS); certificateUser != "" {
+			userName = certificateUser
+		}
+	}
 	writer.Header().Set("Padding", generateNaivePaddingHeader())
 	writer.WriteHeader(http.StatusOK)
 	writer.(http.Flusher).Flush()
@@ -0,0 +1,98 @@
+package inbound
+
+import (
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/redir"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/x/list"
+
+	"go4.org/netipx"
+)
+
+type redirAutoConfigure struct {
+	logger                      log.ContextLogger
+	autoConfigure               *redir.AutoConfigure
+	routeExcludeRuleSet         []adapter.RuleSet
+	routeExcludeRuleSetCallback []*list.Element[adapter.RuleSetUpdateCallback]
+	routeExcludeAddressSet      []*netipx.IPSet
+}
+
+func newRedirAutoConfigure(router adapter.Router, logger log.ContextLogger, listenOptions option.ListenOptions, options option.RedirAutoConfigureOptions, configureOptions redir.AutoConfigureOptions) (*redirAutoConfigure, error) {
+	if !listenOptions.Listen.IsIP() {
+		return nil, E.New("auto_configure: unsupported listen address: ", listenOptions.Listen)
+	}
+	listenAddress := listenOptions.Listen.Build()
+	if !listenAddress.IsUnspecified() {
+		return nil, E.New("auto_configure: listen address must be `::` or `0.0.0.0`")
+	}
+	if listenOptions.ListenPort == 0 {
+		return nil, E.New("auto_configure: missing listen port")
+	}
+	configure := &redirAutoConfigure{
+		logger: logger,
+	}
+	configureOptions.ListenPort = listenOptions.ListenPort
+	configureOptions.EnableIPv6 = listenAddress.Is6()
+	configureOptions.OutputMark = router.DefaultMark()
+	configureOptions.ExcludeAddress = options.RouteExcludeAddress
+	configureOptions.ExcludeAddressSet = &configure.routeExcludeAddressSet
+	autoConfigure, err := redir.NewAutoConfigure(configureOptions)
+	if err != nil {
+		return nil, E.Cause(err, "initialize auto_configure")
+	}
+	configure.autoConfigure = autoConfigure
+	for _, routeExcludeAddressSet := range options.RouteExcludeAddressSet {
+		ruleSet, loaded := router.RuleSet(routeExcludeAddressSet)
+		if !loaded {
+			return nil, E.New("parse route_exclude_address_set: rule-set not found: ", routeExcludeAddressSet)
+		}
+		ruleSet.IncRef()
+		configure.routeExcludeRuleSet = append(configure.routeExcludeRuleSet, ruleSet)
+	}
+	if configureOptions.OutputMark == 0 {
+		logger.Warn("auto_configure: local traffic is not proxied since `route.default_mark` is not set")
+	}
+	return configure, nil
+}
+
+func (c *redirAutoConfigure) Start() error {
+	for _, routeExcludeRuleSet := range c.routeExcludeRuleSet {
+		ipSets := routeExcludeRuleSet.ExtractIPSet()
+		if len(ipSets) == 0 {
+			c.logger.Warn("route_exclude_address_set: no destination IP CIDR rules found in rule-set: ", routeExcludeRuleSet.Name())
+		}
+		c.routeExcludeAddressSet = append(c.routeExcludeAddressSet, ipSets...)
+	}
+	err := c.autoConfigure.Start()
+	if err != nil {
+		return E.Cause(err, "auto_configure")
+	}
+	for _, routeExcludeRuleSet := range c.routeExcludeRuleSet {
+		c.routeExcludeRuleSetCallback = append(c.routeExcludeRuleSetCallback, routeExcludeRuleSet.RegisterCallback(c.updateRouteExcludeAddressSet))
+		routeExcludeRuleSet.DecRef()
+	}
+	c.routeExcludeAddressSet = nil
+	return nil
+}
+
+func (c *redirAutoConfigure) updateRouteExcludeAddressSet(it adapter.RuleSet) {
+	c.routeExcludeAddressSet = common.FlatMap(c.routeExcludeRuleSet, adapter.RuleSet.ExtractIPSet)
+	err := c.autoConfigure.UpdateExcludeAddressSet()
+	if err != nil {
+		c.logger.Error(E.Cause(err, "update route_exclude_address_set"))
+	}
+	c.routeExcludeAddressSet = nil
+}
+
+func (c *redirAutoConfigure) Close() error {
+	for i, routeExcludeRuleSet := range c.routeExcludeRuleSet {
+		if i < len(c.routeExcludeRuleSetCallback) {
+			routeExcludeRuleSet.UnregisterCallback(c.routeExcludeRuleSetCallback[i])
+		}
+	}
+	c.routeExcludeRuleSetCallback = nil
+	return c.autoConfigure.Close()
+}
@@ -9,6 +9,7 @@ import (
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
 	E "github.com/sagernet/sing/common/exceptions"
 	M "github.com/sagernet/sing/common/metadata"
 	N "github.com/sagernet/sing/common/network"
@@ -16,11 +17,12 @@ import (
 
 type Redirect struct {
 	myInboundAdapter
+	autoConfigure *redirAutoConfigure
 }
 
-func NewRedirect(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.RedirectInboundOptions) *Redirect {
+func NewRedirect(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.RedirectInboundOptions) (*Redirect, error) {
 	redirect := &Redirect{
-		myInboundAdapter{
+		myInboundAdapter: myInboundAdapter{
 			protocol:      C.TypeRedirect,
 			network:       []string{N.NetworkTCP},
 			ctx:           ctx,
@@ -31,7 +33,34 @@ func NewRedirect(ctx context.Context, router adapter.Router, logger log.ContextL
 		},
 	}
 	redirect.connHandler = redirect
-	return redirect
+	if options.AutoConfigure {
+		tableName := "sing-box-redirect"
+		if tag != "" {
+			tableName += "-" + tag
+		}
+		autoConfigure, err := newRedirAutoConfigure(router, logger, options.ListenOptions, options.RedirAutoConfigureOptions, redir.AutoConfigureOptions{
+			TableName: tableName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		redirect.autoConfigure = autoConfigure
+	}
+	return redirect, nil
+}
+
+func (r *Redirect) PostStart() error {
+	if r.autoConfigure == nil {
+		return nil
+	}
+	return r.autoConfigure.Start()
+}
+
+func (r *Redirect) Close() error {
+	return common.Close(
+		&r.myInboundAdapter,
+		common.PtrOrNil(r.autoConfigure),
+	)
 }
 
 func (r *Redirect) NewConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
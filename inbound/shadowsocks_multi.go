@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/sagernet/sing-box/adapter"
@@ -19,20 +20,21 @@ import (
 	"github.com/sagernet/sing/common/auth"
 	"github.com/sagernet/sing/common/buf"
 	E "github.com/sagernet/sing/common/exceptions"
-	F "github.com/sagernet/sing/common/format"
 	N "github.com/sagernet/sing/common/network"
 	"github.com/sagernet/sing/common/ntp"
 )
 
 var (
-	_ adapter.Inbound           = (*ShadowsocksMulti)(nil)
-	_ adapter.InjectableInbound = (*ShadowsocksMulti)(nil)
+	_ adapter.Inbound            = (*ShadowsocksMulti)(nil)
+	_ adapter.InjectableInbound  = (*ShadowsocksMulti)(nil)
+	_ adapter.UserManagedInbound = (*ShadowsocksMulti)(nil)
 )
 
 type ShadowsocksMulti struct {
 	myInboundAdapter
-	service shadowsocks.MultiService[int]
-	users   []option.ShadowsocksUser
+	service    shadowsocks.MultiService[serviceUser]
+	users      []option.ShadowsocksUser
+	userAccess sync.RWMutex
 }
 
 func newShadowsocksMulti(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.ShadowsocksInboundOptions) (*ShadowsocksMulti, error) {
@@ -60,9 +62,9 @@ func newShadowsocksMulti(ctx context.Context, router adapter.Router, logger log.
 	} else {
 		udpTimeout = C.UDPTimeout
 	}
-	var service shadowsocks.MultiService[int]
+	var service shadowsocks.MultiService[serviceUser]
 	if common.Contains(shadowaead_2022.List, options.Method) {
-		service, err = shadowaead_2022.NewMultiServiceWithPassword[int](
+		service, err = shadowaead_2022.NewMultiServiceWithPassword[serviceUser](
 			options.Method,
 			options.Password,
 			int64(udpTimeout.Seconds()),
@@ -70,7 +72,7 @@ func newShadowsocksMulti(ctx context.Context, router adapter.Router, logger log.
 			ntp.TimeFuncFromContext(ctx),
 		)
 	} else if common.Contains(shadowaead.List, options.Method) {
-		service, err = shadowaead.NewMultiService[int](
+		service, err = shadowaead.NewMultiService[serviceUser](
 			options.Method,
 			int64(udpTimeout.Seconds()),
 			adapter.NewUpstreamContextHandler(inbound.newConnection, inbound.newPacketConnection, inbound))
@@ -80,17 +82,12 @@ func newShadowsocksMulti(ctx context.Context, router adapter.Router, logger log.
 	if err != nil {
 		return nil, err
 	}
-	err = service.UpdateUsersWithPasswords(common.MapIndexed(options.Users, func(index int, user option.ShadowsocksUser) int {
-		return index
-	}), common.Map(options.Users, func(user option.ShadowsocksUser) string {
-		return user.Password
-	}))
+	inbound.service = service
+	inbound.packetUpstream = service
+	err = inbound.updateUsers(options.Users)
 	if err != nil {
 		return nil, err
 	}
-	inbound.service = service
-	inbound.packetUpstream = service
-	inbound.users = options.Users
 	return inbound, err
 }
 
@@ -107,33 +104,84 @@ func (h *ShadowsocksMulti) NewPacketConnection(ctx context.Context, conn N.Packe
 }
 
 func (h *ShadowsocksMulti) newConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" {
+		metadata.User = user.name
 	}
 	h.logger.InfoContext(ctx, "[", user, "] inbound connection to ", metadata.Destination)
 	return h.router.RouteConnection(ctx, conn, metadata)
 }
 
 func (h *ShadowsocksMulti) newPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" {
+		metadata.User = user.name
 	}
 	ctx = log.ContextWithNewID(ctx)
 	h.logger.InfoContext(ctx, "[", user, "] inbound packet connection from ", metadata.Source)
 	h.logger.InfoContext(ctx, "[", user, "] inbound packet connection to ", metadata.Destination)
 	return h.router.RoutePacketConnection(ctx, conn, metadata)
 }
+
+// updateUsers must be called with userAccess locked, or before the inbound is started.
+func (h *ShadowsocksMulti) updateUsers(users []option.ShadowsocksUser) error {
+	err := h.service.UpdateUsersWithPasswords(common.MapIndexed(users, func(index int, user option.ShadowsocksUser) serviceUser {
+		return serviceUser{index, user.Name}
+	}), common.Map(users, func(user option.ShadowsocksUser) string {
+		return user.Password
+	}))
+	if err != nil {
+		return err
+	}
+	h.users = users
+	return nil
+}
+
+func (h *ShadowsocksMulti) Users() []adapter.InboundUser {
+	h.userAccess.RLock()
+	defer h.userAccess.RUnlock()
+	return common.Map(h.users, func(it option.ShadowsocksUser) adapter.InboundUser {
+		return adapter.InboundUser{
+			Name:     it.Name,
+			Password: it.Password,
+		}
+	})
+}
+
+func (h *ShadowsocksMulti) AddUsers(users []adapter.InboundUser) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := appendUsers(h.users, users, func(it option.ShadowsocksUser) string {
+		return it.Name
+	}, func(user adapter.InboundUser) (option.ShadowsocksUser, error) {
+		if user.Password == "" {
+			return option.ShadowsocksUser{}, E.New("missing password")
+		}
+		return option.ShadowsocksUser{
+			Name:     user.Name,
+			Password: user.Password,
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
+
+func (h *ShadowsocksMulti) RemoveUsers(names []string) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := removeUsers(h.users, names, func(it option.ShadowsocksUser) string {
+		return it.Name
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
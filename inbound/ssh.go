@@ -0,0 +1,244 @@
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	_ adapter.Inbound           = (*SSH)(nil)
+	_ adapter.InjectableInbound = (*SSH)(nil)
+)
+
+const sshDefaultServerVersion = "SSH-2.0-OpenSSH_8.9p1"
+
+// SSH accepts port forwarding channels from SSH clients and routes them.
+// Shell, exec and subsystem requests are always refused.
+type SSH struct {
+	myInboundAdapter
+	config *ssh.ServerConfig
+	users  map[string]sshUser
+}
+
+type sshUser struct {
+	password       string
+	authorizedKeys [][]byte
+}
+
+func NewSSH(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.SSHInboundOptions) (*SSH, error) {
+	inbound := &SSH{
+		myInboundAdapter: myInboundAdapter{
+			protocol:      C.TypeSSH,
+			network:       []string{N.NetworkTCP},
+			ctx:           ctx,
+			router:        router,
+			logger:        logger,
+			tag:           tag,
+			listenOptions: options.ListenOptions,
+		},
+		users: make(map[string]sshUser),
+	}
+	if len(options.Users) == 0 {
+		return nil, E.New("missing users")
+	}
+	for _, user := range options.Users {
+		if user.Name == "" {
+			return nil, E.New("missing user name")
+		}
+		if user.Password == "" && len(user.AuthorizedKeys) == 0 {
+			return nil, E.New("missing password or authorized keys for user ", user.Name)
+		}
+		var authorizedKeys [][]byte
+		for _, authorizedKey := range user.AuthorizedKeys {
+			publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorizedKey))
+			if err != nil {
+				return nil, E.Cause(err, "parse authorized key for user ", user.Name)
+			}
+			authorizedKeys = append(authorizedKeys, publicKey.Marshal())
+		}
+		inbound.users[user.Name] = sshUser{
+			password:       user.Password,
+			authorizedKeys: authorizedKeys,
+		}
+	}
+	var hostKey []byte
+	if len(options.HostKey) > 0 {
+		hostKey = []byte(strings.Join(options.HostKey, "\n"))
+	} else if options.HostKeyPath != "" {
+		var err error
+		hostKey, err = os.ReadFile(os.ExpandEnv(options.HostKeyPath))
+		if err != nil {
+			return nil, E.Cause(err, "read host key")
+		}
+	} else {
+		return nil, E.New("missing host key")
+	}
+	signer, err := ssh.ParsePrivateKey(hostKey)
+	if err != nil {
+		return nil, E.Cause(err, "parse host key")
+	}
+	serverVersion := options.ServerVersion
+	if serverVersion == "" {
+		serverVersion = sshDefaultServerVersion
+	}
+	inbound.config = &ssh.ServerConfig{
+		PasswordCallback:  inbound.checkPassword,
+		PublicKeyCallback: inbound.checkPublicKey,
+		ServerVersion:     serverVersion,
+	}
+	inbound.config.AddHostKey(signer)
+	inbound.connHandler = inbound
+	return inbound, nil
+}
+
+func (h *SSH) checkPassword(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+	user, loaded := h.users[conn.User()]
+	if loaded && user.password != "" && subtle.ConstantTimeCompare([]byte(user.password), password) == 1 {
+		return nil, nil
+	}
+	return nil, E.New("ssh: authentication failed for user ", conn.User())
+}
+
+func (h *SSH) checkPublicKey(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	user, loaded := h.users[conn.User()]
+	if loaded {
+		keyBytes := key.Marshal()
+		for _, authorizedKey := range user.authorizedKeys {
+			if bytes.Equal(authorizedKey, keyBytes) {
+				return nil, nil
+			}
+		}
+	}
+	return nil, E.New("ssh: unknown public key for user ", conn.User())
+}
+
+func (h *SSH) NewConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
+	conn.SetDeadline(time.Now().Add(C.SSHLoginTimeout))
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, h.config)
+	if err != nil {
+		return E.Cause(err, "ssh handshake")
+	}
+	conn.SetDeadline(time.Time{})
+	defer serverConn.Close()
+	metadata.User = serverConn.User()
+	h.logger.InfoContext(ctx, "[", metadata.User, "] ssh session started")
+	// refuses remote forwarding and other global requests
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		switch newChannel.ChannelType() {
+		case "direct-tcpip":
+			go h.newForwardChannel(ctx, serverConn, newChannel, metadata)
+		case "session":
+			go h.newSessionChannel(ctx, newChannel, metadata)
+		default:
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+		}
+	}
+	return nil
+}
+
+func (h *SSH) NewPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
+	return os.ErrInvalid
+}
+
+// RFC 4254 7.2
+type sshForwardRequest struct {
+	Host       string
+	Port       uint32
+	OriginAddr string
+	OriginPort uint32
+}
+
+func (h *SSH) newForwardChannel(ctx context.Context, serverConn *ssh.ServerConn, newChannel ssh.NewChannel, metadata adapter.InboundContext) {
+	var request sshForwardRequest
+	err := ssh.Unmarshal(newChannel.ExtraData(), &request)
+	if err != nil || request.Port > 65535 {
+		newChannel.Reject(ssh.ConnectionFailed, "invalid forward request")
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	ctx = log.ContextWithNewID(ctx)
+	metadata.Destination = M.ParseSocksaddrHostPort(request.Host, uint16(request.Port))
+	h.logger.InfoContext(ctx, "[", metadata.User, "] inbound connection to ", metadata.Destination)
+	conn := &sshChannelConn{
+		Channel:    channel,
+		localAddr:  serverConn.LocalAddr(),
+		remoteAddr: serverConn.RemoteAddr(),
+	}
+	err = h.router.RouteConnection(ctx, conn, metadata)
+	if err != nil {
+		conn.Close()
+		h.NewError(ctx, E.Cause(err, "process connection from ", metadata.Source))
+	}
+}
+
+func (h *SSH) newSessionChannel(ctx context.Context, newChannel ssh.NewChannel, metadata adapter.InboundContext) {
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+	for request := range requests {
+		switch request.Type {
+		case "pty-req", "env", "window-change":
+			request.Reply(true, nil)
+		case "shell":
+			// keeps the session open for clients started without -N,
+			// so that their forwards keep working.
+			request.Reply(true, nil)
+			channel.Write([]byte("This service only supports port forwarding.\r\n"))
+		default:
+			h.logger.DebugContext(ctx, "[", metadata.User, "] refused session request: ", request.Type)
+			request.Reply(false, nil)
+		}
+	}
+}
+
+type sshChannelConn struct {
+	ssh.Channel
+	localAddr  net.Addr
+	remoteAddr net.Addr
+}
+
+func (c *sshChannelConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *sshChannelConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *sshChannelConn) SetDeadline(t time.Time) error {
+	return os.ErrInvalid
+}
+
+func (c *sshChannelConn) SetReadDeadline(t time.Time) error {
+	return os.ErrInvalid
+}
+
+func (c *sshChannelConn) SetWriteDeadline(t time.Time) error {
+	return os.ErrInvalid
+}
+
+func (c *sshChannelConn) NeedAdditionalReadDeadline() bool {
+	return true
+}
@@ -0,0 +1,153 @@
+package inbound
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+type testRouter struct {
+	adapter.Router
+	metadata chan adapter.InboundContext
+}
+
+func (r *testRouter) RouteConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
+	r.metadata <- metadata
+	go func() {
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+	return nil
+}
+
+func newTestSSHSigner(t *testing.T) (ssh.Signer, []byte) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.NoError(t, err)
+	return signer, pem.EncodeToMemory(block)
+}
+
+func newTestSSH(t *testing.T, clientSigner ssh.Signer) (*SSH, *testRouter) {
+	_, hostKey := newTestSSHSigner(t)
+	router := &testRouter{metadata: make(chan adapter.InboundContext, 1)}
+	inbound, err := NewSSH(context.Background(), router, log.NewNOPFactory().Logger(), "ssh-in", option.SSHInboundOptions{
+		Users: []option.SSHUser{
+			{Name: "password-user", Password: "password"},
+			{Name: "key-user", AuthorizedKeys: []string{string(ssh.MarshalAuthorizedKey(clientSigner.PublicKey()))}},
+		},
+		HostKey: []string{string(hostKey)},
+	})
+	require.NoError(t, err)
+	return inbound, router
+}
+
+func dialTestSSH(t *testing.T, inbound *SSH, user string, auth ssh.AuthMethod) (*ssh.Client, error) {
+	// net.Pipe is unbuffered and both sides send their version first
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	serverConn, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		clientConn.Close()
+		serverConn.Close()
+	})
+	go inbound.injectTCP(serverConn, adapter.InboundContext{})
+	conn, channels, requests, err := ssh.NewClientConn(clientConn, "ssh-in", &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	client := ssh.NewClient(conn, channels, requests)
+	t.Cleanup(func() {
+		client.Close()
+	})
+	return client, nil
+}
+
+func TestSSHAuthentication(t *testing.T) {
+	t.Parallel()
+	clientSigner, _ := newTestSSHSigner(t)
+	otherSigner, _ := newTestSSHSigner(t)
+	inbound, _ := newTestSSH(t, clientSigner)
+	_, err := dialTestSSH(t, inbound, "password-user", ssh.Password("password"))
+	require.NoError(t, err)
+	_, err = dialTestSSH(t, inbound, "key-user", ssh.PublicKeys(clientSigner))
+	require.NoError(t, err)
+	_, err = dialTestSSH(t, inbound, "password-user", ssh.Password("wrong"))
+	require.Error(t, err)
+	_, err = dialTestSSH(t, inbound, "key-user", ssh.PublicKeys(otherSigner))
+	require.Error(t, err)
+	_, err = dialTestSSH(t, inbound, "key-user", ssh.Password(""))
+	require.Error(t, err)
+}
+
+func TestSSHSession(t *testing.T) {
+	t.Parallel()
+	clientSigner, _ := newTestSSHSigner(t)
+	inbound, _ := newTestSSH(t, clientSigner)
+	client, err := dialTestSSH(t, inbound, "password-user", ssh.Password("password"))
+	require.NoError(t, err)
+
+	session, err := client.NewSession()
+	require.NoError(t, err)
+	require.Error(t, session.Run("id"))
+
+	session, err = client.NewSession()
+	require.NoError(t, err)
+	require.Error(t, session.RequestSubsystem("sftp"))
+
+	session, err = client.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	stdout, err := session.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, session.Shell())
+	message := make([]byte, len("This service only supports port forwarding.\r\n"))
+	_, err = io.ReadFull(stdout, message)
+	require.NoError(t, err)
+	require.Equal(t, "This service only supports port forwarding.\r\n", string(message))
+}
+
+func TestSSHForward(t *testing.T) {
+	t.Parallel()
+	clientSigner, _ := newTestSSHSigner(t)
+	inbound, router := newTestSSH(t, clientSigner)
+	client, err := dialTestSSH(t, inbound, "key-user", ssh.PublicKeys(clientSigner))
+	require.NoError(t, err)
+	conn, err := client.Dial("tcp", "example.com:443")
+	require.NoError(t, err)
+	defer conn.Close()
+	metadata := <-router.metadata
+	require.Equal(t, "ssh-in", metadata.Inbound)
+	require.Equal(t, "key-user", metadata.User)
+	require.Equal(t, "example.com:443", metadata.Destination.String())
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	response := make([]byte, 5)
+	_, err = io.ReadFull(conn, response)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(response))
+
+	_, err = client.Listen("tcp", "127.0.0.1:0")
+	require.Error(t, err)
+}
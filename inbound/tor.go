@@ -0,0 +1,164 @@
+package inbound
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+	"github.com/sagernet/sing/common/rw"
+
+	"github.com/cretz/bine/tor"
+)
+
+var _ adapter.Inbound = (*Tor)(nil)
+
+type torInstanceProvider interface {
+	TorInstance() *tor.Tor
+	DataDirectory() string
+}
+
+// Tor publishes a v3 onion service through the tor instance of a tor outbound.
+type Tor struct {
+	myInboundAdapter
+	outboundRouter adapter.Router
+	outbound       string
+	virtualPort    uint16
+	privateKeyPath string
+	destination    M.Socksaddr
+	onionAddress   M.Socksaddr
+	service        *tor.OnionService
+}
+
+func NewTor(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.TorInboundOptions) (*Tor, error) {
+	if options.Outbound == "" {
+		return nil, E.New("missing tor outbound")
+	}
+	inbound := &Tor{
+		myInboundAdapter: myInboundAdapter{
+			protocol:      C.TypeTor,
+			network:       []string{N.NetworkTCP},
+			ctx:           ctx,
+			router:        router,
+			logger:        logger,
+			tag:           tag,
+			listenOptions: option.ListenOptions{InboundOptions: options.InboundOptions},
+		},
+		outboundRouter: router,
+		outbound:       options.Outbound,
+		virtualPort:    options.VirtualPort,
+		privateKeyPath: options.PrivateKeyPath,
+	}
+	if inbound.virtualPort == 0 {
+		inbound.virtualPort = 80
+	}
+	// like HiddenServicePort of tor, connections go to the local virtual port by default,
+	// routing them to the onion address would loop back into the service
+	inbound.destination = M.SocksaddrFrom(netip.AddrFrom4([4]byte{127, 0, 0, 1}), inbound.virtualPort)
+	if options.OverrideAddress != "" {
+		overrideDestination := M.ParseSocksaddrHostPort(options.OverrideAddress, 0)
+		inbound.destination.Fqdn = overrideDestination.Fqdn
+		inbound.destination.Addr = overrideDestination.Addr
+	}
+	if options.OverridePort != 0 {
+		inbound.destination.Port = options.OverridePort
+	}
+	inbound.connHandler = inbound
+	return inbound, nil
+}
+
+func (t *Tor) Start() error {
+	outbound, loaded := t.outboundRouter.Outbound(t.outbound)
+	if !loaded {
+		return E.New("tor outbound not found: ", t.outbound)
+	}
+	provider, isTor := outbound.(torInstanceProvider)
+	if !isTor {
+		return E.New("outbound/", outbound.Type(), "[", t.outbound, "] is not a tor outbound")
+	}
+	instance := provider.TorInstance()
+	if instance == nil {
+		return E.New("tor outbound not started: ", t.outbound)
+	}
+	privateKeyPath := t.privateKeyPath
+	if privateKeyPath == "" {
+		if provider.DataDirectory() == "" {
+			return E.New("missing private_key_path or data_directory of the tor outbound to persist the onion service key")
+		}
+		privateKeyPath = filepath.Join(provider.DataDirectory(), "onion", t.tag+".pem")
+	}
+	privateKey, err := loadOnionKey(privateKeyPath)
+	if err != nil {
+		return err
+	}
+	service, err := instance.Listen(t.ctx, &tor.ListenConf{
+		Key:         privateKey,
+		RemotePorts: []int{int(t.virtualPort)},
+		NoWait:      true,
+	})
+	if err != nil {
+		return E.Cause(err, "create onion service")
+	}
+	t.service = service
+	t.tcpListener = service
+	t.onionAddress = M.Socksaddr{Fqdn: service.ID + ".onion", Port: t.virtualPort}
+	t.logger.Info("onion service published at ", t.onionAddress)
+	go t.loopTCPIn()
+	return nil
+}
+
+func (t *Tor) NewConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
+	metadata.Destination = t.destination
+	t.logger.InfoContext(ctx, "inbound connection to ", t.onionAddress, ", forwarding to ", metadata.Destination)
+	return t.router.RouteConnection(ctx, conn, metadata)
+}
+
+func loadOnionKey(path string) (ed25519.PrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		block, _ := pem.Decode(content)
+		if block == nil {
+			return nil, E.New("invalid onion service key: ", path)
+		}
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, E.Cause(err, "parse onion service key")
+		}
+		ed25519Key, isED25519 := privateKey.(ed25519.PrivateKey)
+		if !isED25519 {
+			return nil, E.New("onion service key is not an ed25519 key: ", path)
+		}
+		return ed25519Key, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	err = rw.MkdirParent(path)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), 0o600)
+	if err != nil {
+		return nil, E.Cause(err, "save onion service key")
+	}
+	return privateKey, nil
+}
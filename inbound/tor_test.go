@@ -0,0 +1,66 @@
+package inbound
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	M "github.com/sagernet/sing/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testTorRouter struct {
+	adapter.Router
+	metadata adapter.InboundContext
+}
+
+func (r *testTorRouter) RouteConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
+	r.metadata = metadata
+	return nil
+}
+
+func TestTorDestination(t *testing.T) {
+	t.Parallel()
+	for _, testCase := range []struct {
+		options     option.TorInboundOptions
+		destination string
+	}{
+		{option.TorInboundOptions{}, "127.0.0.1:80"},
+		{option.TorInboundOptions{VirtualPort: 8080}, "127.0.0.1:8080"},
+		{option.TorInboundOptions{OverrideAddress: "example.com"}, "example.com:80"},
+		{option.TorInboundOptions{VirtualPort: 443, OverridePort: 8443}, "127.0.0.1:8443"},
+		{option.TorInboundOptions{OverrideAddress: "::1", OverridePort: 22}, "[::1]:22"},
+	} {
+		router := &testTorRouter{}
+		testCase.options.Outbound = "tor-out"
+		inbound, err := NewTor(context.Background(), router, log.NewNOPFactory().Logger(), "onion-in", testCase.options)
+		require.NoError(t, err)
+		inbound.onionAddress = M.Socksaddr{Fqdn: "example.onion", Port: inbound.virtualPort}
+		require.NoError(t, inbound.NewConnection(context.Background(), nil, adapter.InboundContext{}))
+		require.Equal(t, testCase.destination, router.metadata.Destination.String())
+	}
+	_, err := NewTor(context.Background(), &testTorRouter{}, log.NewNOPFactory().Logger(), "onion-in", option.TorInboundOptions{})
+	require.ErrorContains(t, err, "missing tor outbound")
+}
+
+func TestLoadOnionKey(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "onion", "onion-in.pem")
+	privateKey, err := loadOnionKey(path)
+	require.NoError(t, err)
+	fileInfo, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+	loadedKey, err := loadOnionKey(path)
+	require.NoError(t, err)
+	require.True(t, privateKey.Equal(loadedKey))
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0o600))
+	_, err = loadOnionKey(path)
+	require.ErrorContains(t, err, "invalid onion service key")
+}
@@ -23,10 +23,11 @@ import (
 
 type TProxy struct {
 	myInboundAdapter
-	udpNat *udpnat.Service[netip.AddrPort]
+	udpNat        *udpnat.Service[netip.AddrPort]
+	autoConfigure *redirAutoConfigure
 }
 
-func NewTProxy(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.TProxyInboundOptions) *TProxy {
+func NewTProxy(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.TProxyInboundOptions) (*TProxy, error) {
 	tproxy := &TProxy{
 		myInboundAdapter: myInboundAdapter{
 			protocol:      C.TypeTProxy,
@@ -48,7 +49,25 @@ func NewTProxy(ctx context.Context, router adapter.Router, logger log.ContextLog
 	tproxy.oobPacketHandler = tproxy
 	tproxy.udpNat = udpnat.New[netip.AddrPort](int64(udpTimeout.Seconds()), tproxy.upstreamContextHandler())
 	tproxy.packetUpstream = tproxy.udpNat
-	return tproxy
+	if options.AutoConfigure {
+		tableName := "sing-box-tproxy"
+		if tag != "" {
+			tableName += "-" + tag
+		}
+		autoConfigure, err := newRedirAutoConfigure(router, logger, options.ListenOptions, options.RedirAutoConfigureOptions, redir.AutoConfigureOptions{
+			TableName:  tableName,
+			TProxy:     true,
+			Network:    tproxy.network,
+			TProxyMark: uint32(options.AutoConfigureMark),
+			TableIndex: options.IPRoute2TableIndex,
+			RuleIndex:  options.IPRoute2RuleIndex,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tproxy.autoConfigure = autoConfigure
+	}
+	return tproxy, nil
 }
 
 func (t *TProxy) Start() error {
@@ -75,6 +94,20 @@ func (t *TProxy) Start() error {
 	return nil
 }
 
+func (t *TProxy) PostStart() error {
+	if t.autoConfigure == nil {
+		return nil
+	}
+	return t.autoConfigure.Start()
+}
+
+func (t *TProxy) Close() error {
+	return common.Close(
+		&t.myInboundAdapter,
+		common.PtrOrNil(t.autoConfigure),
+	)
+}
+
 func (t *TProxy) NewConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
 	metadata.Destination = M.SocksaddrFromNet(conn.LocalAddr()).Unwrap()
 	return t.newConnection(ctx, conn, metadata)
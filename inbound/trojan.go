@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"os"
+	"sync"
 
 	"github.com/sagernet/sing-box/adapter"
 	"github.com/sagernet/sing-box/common/mux"
@@ -16,20 +17,21 @@ import (
 	"github.com/sagernet/sing/common"
 	"github.com/sagernet/sing/common/auth"
 	E "github.com/sagernet/sing/common/exceptions"
-	F "github.com/sagernet/sing/common/format"
 	M "github.com/sagernet/sing/common/metadata"
 	N "github.com/sagernet/sing/common/network"
 )
 
 var (
-	_ adapter.Inbound           = (*Trojan)(nil)
-	_ adapter.InjectableInbound = (*Trojan)(nil)
+	_ adapter.Inbound            = (*Trojan)(nil)
+	_ adapter.InjectableInbound  = (*Trojan)(nil)
+	_ adapter.UserManagedInbound = (*Trojan)(nil)
 )
 
 type Trojan struct {
 	myInboundAdapter
-	service                  *trojan.Service[int]
+	service                  *trojan.Service[serviceUser]
 	users                    []option.TrojanUser
+	userAccess               sync.RWMutex
 	tlsConfig                tls.ServerConfig
 	fallbackAddr             M.Socksaddr
 	fallbackAddrTLSNextProto map[string]M.Socksaddr
@@ -47,7 +49,6 @@ func NewTrojan(ctx context.Context, router adapter.Router, logger log.ContextLog
 			tag:           tag,
 			listenOptions: options.ListenOptions,
 		},
-		users: options.Users,
 	}
 	if options.TLS != nil {
 		tlsConfig, err := tls.NewServer(ctx, logger, common.PtrValueOrDefault(options.TLS))
@@ -80,12 +81,9 @@ func NewTrojan(ctx context.Context, router adapter.Router, logger log.ContextLog
 		}
 		fallbackHandler = adapter.NewUpstreamContextHandler(inbound.fallbackConnection, nil, nil)
 	}
-	service := trojan.NewService[int](adapter.NewUpstreamContextHandler(inbound.newConnection, inbound.newPacketConnection, inbound), fallbackHandler)
-	err := service.UpdateUsers(common.MapIndexed(options.Users, func(index int, it option.TrojanUser) int {
-		return index
-	}), common.Map(options.Users, func(it option.TrojanUser) string {
-		return it.Password
-	}))
+	service := trojan.NewService[serviceUser](adapter.NewUpstreamContextHandler(inbound.newConnection, inbound.newPacketConnection, inbound), fallbackHandler)
+	inbound.service = service
+	err := inbound.updateUsers(options.Users)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +97,6 @@ func NewTrojan(ctx context.Context, router adapter.Router, logger log.ContextLog
 	if err != nil {
 		return nil, err
 	}
-	inbound.service = service
 	inbound.connHandler = inbound
 	return inbound, nil
 }
@@ -161,6 +158,7 @@ func (h *Trojan) NewConnection(ctx context.Context, conn net.Conn, metadata adap
 		if err != nil {
 			return err
 		}
+		metadata.User = tls.ClientCertificateUser(conn)
 	}
 	return h.service.NewConnection(adapter.WithContext(ctx, &metadata), conn, adapter.UpstreamMetadata(metadata))
 }
@@ -170,15 +168,13 @@ func (h *Trojan) NewPacketConnection(ctx context.Context, conn N.PacketConn, met
 }
 
 func (h *Trojan) newConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" && metadata.User == "" {
+		// the identity of a verified client certificate takes precedence
+		metadata.User = user.name
 	}
 	h.logger.InfoContext(ctx, "[", user, "] inbound connection to ", metadata.Destination)
 	return h.router.RouteConnection(ctx, conn, metadata)
@@ -208,15 +204,13 @@ func (h *Trojan) fallbackConnection(ctx context.Context, conn net.Conn, metadata
 }
 
 func (h *Trojan) newPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" && metadata.User == "" {
+		// the identity of a verified client certificate takes precedence
+		metadata.User = user.name
 	}
 	h.logger.InfoContext(ctx, "[", user, "] inbound packet connection to ", metadata.Destination)
 	return h.router.RoutePacketConnection(ctx, conn, metadata)
@@ -232,3 +226,60 @@ func (t *trojanTransportHandler) NewConnection(ctx context.Context, conn net.Con
 		Destination: metadata.Destination,
 	})
 }
+
+// updateUsers must be called with userAccess locked, or before the inbound is started.
+func (h *Trojan) updateUsers(users []option.TrojanUser) error {
+	err := h.service.UpdateUsers(common.MapIndexed(users, func(index int, it option.TrojanUser) serviceUser {
+		return serviceUser{index, it.Name}
+	}), common.Map(users, func(it option.TrojanUser) string {
+		return it.Password
+	}))
+	if err != nil {
+		return err
+	}
+	h.users = users
+	return nil
+}
+
+func (h *Trojan) Users() []adapter.InboundUser {
+	h.userAccess.RLock()
+	defer h.userAccess.RUnlock()
+	return common.Map(h.users, func(it option.TrojanUser) adapter.InboundUser {
+		return adapter.InboundUser{
+			Name:     it.Name,
+			Password: it.Password,
+		}
+	})
+}
+
+func (h *Trojan) AddUsers(users []adapter.InboundUser) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := appendUsers(h.users, users, func(it option.TrojanUser) string {
+		return it.Name
+	}, func(user adapter.InboundUser) (option.TrojanUser, error) {
+		if user.Password == "" {
+			return option.TrojanUser{}, E.New("missing password")
+		}
+		return option.TrojanUser{
+			Name:     user.Name,
+			Password: user.Password,
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
+
+func (h *Trojan) RemoveUsers(names []string) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := removeUsers(h.users, names, func(it option.TrojanUser) string {
+		return it.Name
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
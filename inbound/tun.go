@@ -39,6 +39,11 @@ type Tun struct {
 	endpointIndependentNat      bool
 	udpTimeout                  int64
 	stack                       string
+	icmpForwarding              bool
+	icmpFallback                string
+	icmpCtx                     context.Context
+	icmpCancel                  context.CancelFunc
+	icmpQueue                   chan icmpEcho
 	tunIf                       tun.Tun
 	tunStack                    tun.Stack
 	platformInterface           platform.Interface
@@ -184,9 +189,16 @@ func NewTun(ctx context.Context, router adapter.Router, logger log.ContextLogger
 		endpointIndependentNat: options.EndpointIndependentNat,
 		udpTimeout:             int64(udpTimeout.Seconds()),
 		stack:                  options.Stack,
+		icmpForwarding:         options.ICMPForwarding,
+		icmpFallback:           options.ICMPFallback,
 		platformInterface:      platformInterface,
 		platformOptions:        common.PtrValueOrDefault(options.Platform),
 	}
+	switch options.ICMPFallback {
+	case "", C.ICMPFallbackReply, C.ICMPFallbackUnreachable:
+	default:
+		return nil, E.New("unknown icmp fallback: ", options.ICMPFallback)
+	}
 	if options.AutoRedirect {
 		if !options.AutoRoute {
 			return nil, E.New("`auto_route` is required by `auto_redirect`")
@@ -311,6 +323,10 @@ func (t *Tun) Start() error {
 		forwarderBindInterface = true
 		includeAllNetworks = t.platformInterface.IncludeAllNetworks()
 	}
+	if t.icmpForwarding {
+		t.startICMPWorkers()
+		tunInterface = newICMPTun(tunInterface, t.handleICMPPacket)
+	}
 	tunStack, err := tun.NewStack(t.stack, tun.StackOptions{
 		Context:                t.ctx,
 		Tun:                    tunInterface,
@@ -385,6 +401,9 @@ func (t *Tun) updateRouteAddressSet(it adapter.RuleSet) {
 }
 
 func (t *Tun) Close() error {
+	if t.icmpCancel != nil {
+		t.icmpCancel()
+	}
 	return common.Close(
 		t.tunStack,
 		t.tunIf,
@@ -0,0 +1,191 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"os"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/ping"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-tun"
+	"github.com/sagernet/sing/common"
+	"github.com/sagernet/sing/common/buf"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+)
+
+const (
+	icmpWorkers   = 64
+	icmpQueueSize = 256
+)
+
+type icmpEcho struct {
+	request *adapter.ICMPEchoRequest
+	packet  []byte
+}
+
+// startICMPWorkers starts a fixed number of workers routing echo requests,
+// so a flood of requests does not create a goroutine and a socket for each packet.
+func (t *Tun) startICMPWorkers() {
+	t.icmpCtx, t.icmpCancel = context.WithCancel(t.ctx)
+	t.icmpQueue = make(chan icmpEcho, icmpQueueSize)
+	for i := 0; i < icmpWorkers; i++ {
+		go t.loopICMPEcho()
+	}
+}
+
+func (t *Tun) loopICMPEcho() {
+	for {
+		select {
+		case <-t.icmpCtx.Done():
+			return
+		case echo := <-t.icmpQueue:
+			t.routeICMPEcho(echo.request, echo.packet)
+		}
+	}
+}
+
+// handleICMPPacket takes ICMP echo requests out of the packets read by the stack
+// and routes them, requests to the addresses of the interface are left to the stack.
+// Requests are dropped when all workers are busy and the queue is full, like on a congested network.
+func (t *Tun) handleICMPPacket(packet []byte) bool {
+	request, loaded := ping.ParseEchoRequest(packet)
+	if !loaded || !request.Destination.IsGlobalUnicast() {
+		return false
+	}
+	isLocal := func(it netip.Prefix) bool {
+		return it.Addr() == request.Destination
+	}
+	if common.Any(t.tunOptions.Inet4Address, isLocal) || common.Any(t.tunOptions.Inet6Address, isLocal) {
+		return false
+	}
+	select {
+	case t.icmpQueue <- icmpEcho{request, append([]byte(nil), packet...)}:
+	default:
+		t.logger.Trace("icmp echo queue full, dropping request to ", request.Destination)
+	}
+	return true
+}
+
+func (t *Tun) routeICMPEcho(request *adapter.ICMPEchoRequest, packet []byte) {
+	ctx, cancel := context.WithTimeout(log.ContextWithNewID(t.icmpCtx), C.ICMPTimeout)
+	defer cancel()
+	var metadata adapter.InboundContext
+	metadata.Inbound = t.tag
+	metadata.InboundType = C.TypeTun
+	metadata.Source = M.SocksaddrFrom(request.Source, 0)
+	metadata.InboundOptions = t.inboundOptions
+	t.logger.DebugContext(ctx, "inbound icmp echo from ", request.Source, " to ", request.Destination)
+	var reply []byte
+	err := t.router.RouteICMPEcho(ctx, request, metadata)
+	if err == nil {
+		reply = ping.EncodeEchoReply(request)
+	} else if errors.Is(err, adapter.ErrICMPUnsupported) {
+		if t.icmpFallback == C.ICMPFallbackUnreachable {
+			reply = ping.EncodeUnreachable(packet)
+		} else {
+			reply = ping.EncodeEchoReply(request)
+		}
+	} else if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		// lost requests are not answered, like on a real network
+		t.logger.DebugContext(ctx, "icmp echo to ", request.Destination, " timed out")
+		return
+	} else {
+		t.logger.DebugContext(ctx, E.Cause(err, "icmp echo to ", request.Destination))
+		reply = ping.EncodeUnreachable(packet)
+	}
+	err = t.tunIf.WriteVectorised([]*buf.Buffer{buf.As(reply)})
+	if err != nil {
+		t.logger.DebugContext(ctx, E.Cause(err, "write icmp reply"))
+	}
+}
+
+type icmpTun struct {
+	tun.Tun
+	handler func(packet []byte) bool
+}
+
+// newICMPTun returns a device passing the packets it reads to the handler first,
+// the read interfaces of the original device are kept for the stacks.
+func newICMPTun(tunInterface tun.Tun, handler func(packet []byte) bool) tun.Tun {
+	icmpTunInterface := &icmpTun{tunInterface, handler}
+	switch rawTun := tunInterface.(type) {
+	case tun.LinuxTUN:
+		return &icmpLinuxTUN{icmpTunInterface, rawTun}
+	case tun.WinTun:
+		return &icmpWinTun{icmpTunInterface, rawTun}
+	default:
+		return icmpTunInterface
+	}
+}
+
+func (t *icmpTun) Read(p []byte) (n int, err error) {
+	for {
+		n, err = t.Tun.Read(p)
+		if err != nil || n <= tun.PacketOffset || !t.handler(p[tun.PacketOffset:n]) {
+			return
+		}
+	}
+}
+
+type icmpLinuxTUN struct {
+	*icmpTun
+	linuxTUN tun.LinuxTUN
+}
+
+func (t *icmpLinuxTUN) FrontHeadroom() int {
+	return t.linuxTUN.FrontHeadroom()
+}
+
+func (t *icmpLinuxTUN) BatchSize() int {
+	return t.linuxTUN.BatchSize()
+}
+
+func (t *icmpLinuxTUN) BatchRead(buffers [][]byte, offset int, readN []int) (n int, err error) {
+	for {
+		n, err = t.linuxTUN.BatchRead(buffers, offset, readN)
+		if err != nil || n == 0 {
+			return
+		}
+		var kept int
+		for i := 0; i < n; i++ {
+			if t.handler(buffers[i][offset : offset+readN[i]]) {
+				continue
+			}
+			if kept != i {
+				buffers[kept], buffers[i] = buffers[i], buffers[kept]
+				readN[kept] = readN[i]
+			}
+			kept++
+		}
+		if kept > 0 {
+			return kept, nil
+		}
+	}
+}
+
+func (t *icmpLinuxTUN) BatchWrite(buffers [][]byte, offset int) error {
+	return t.linuxTUN.BatchWrite(buffers, offset)
+}
+
+func (t *icmpLinuxTUN) TXChecksumOffload() bool {
+	return t.linuxTUN.TXChecksumOffload()
+}
+
+type icmpWinTun struct {
+	*icmpTun
+	winTun tun.WinTun
+}
+
+func (t *icmpWinTun) ReadPacket() ([]byte, func(), error) {
+	for {
+		packet, release, err := t.winTun.ReadPacket()
+		if err != nil || !t.handler(packet) {
+			return packet, release, err
+		}
+		release()
+	}
+}
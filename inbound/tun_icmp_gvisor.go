@@ -0,0 +1,60 @@
+//go:build with_gvisor
+
+package inbound
+
+import (
+	"github.com/sagernet/gvisor/pkg/tcpip"
+	"github.com/sagernet/gvisor/pkg/tcpip/header"
+	"github.com/sagernet/gvisor/pkg/tcpip/stack"
+	"github.com/sagernet/sing-tun"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+var _ tun.GVisorTun = (*icmpTun)(nil)
+
+func (t *icmpTun) NewEndpoint() (stack.LinkEndpoint, error) {
+	gTun, isGTun := t.Tun.(tun.GVisorTun)
+	if !isGTun {
+		return nil, E.New("gVisor stack is unsupported on current platform")
+	}
+	endpoint, err := gTun.NewEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	return &icmpEndpoint{endpoint, t.handler}, nil
+}
+
+type icmpEndpoint struct {
+	stack.LinkEndpoint
+	handler func(packet []byte) bool
+}
+
+func (e *icmpEndpoint) Attach(dispatcher stack.NetworkDispatcher) {
+	if dispatcher != nil {
+		dispatcher = &icmpDispatcher{dispatcher, e.handler}
+	}
+	e.LinkEndpoint.Attach(dispatcher)
+}
+
+type icmpDispatcher struct {
+	stack.NetworkDispatcher
+	handler func(packet []byte) bool
+}
+
+func (d *icmpDispatcher) DeliverNetworkPacket(protocol tcpip.NetworkProtocolNumber, pkt *stack.PacketBuffer) {
+	var isICMP bool
+	switch protocol {
+	case header.IPv4ProtocolNumber:
+		if ipHeader, loaded := pkt.Data().PullUp(header.IPv4MinimumSize); loaded {
+			isICMP = header.IPv4(ipHeader).Protocol() == uint8(header.ICMPv4ProtocolNumber)
+		}
+	case header.IPv6ProtocolNumber:
+		if ipHeader, loaded := pkt.Data().PullUp(header.IPv6MinimumSize); loaded {
+			isICMP = header.IPv6(ipHeader).NextHeader() == uint8(header.ICMPv6ProtocolNumber)
+		}
+	}
+	if isICMP && d.handler(pkt.Data().AsRange().ToSlice()) {
+		return
+	}
+	d.NetworkDispatcher.DeliverNetworkPacket(protocol, pkt)
+}
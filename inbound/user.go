@@ -0,0 +1,67 @@
+package inbound
+
+import (
+	"github.com/sagernet/sing-box/adapter"
+	E "github.com/sagernet/sing/common/exceptions"
+	F "github.com/sagernet/sing/common/format"
+)
+
+// Multi-user services identify users by their index in the user list,
+// so changes build a new list and replace the service users as a whole.
+
+// serviceUser is the user key of multi-user services. The name is stored
+// with the index, so an authenticated connection keeps its user name
+// even if the user list is replaced concurrently.
+type serviceUser struct {
+	index int
+	name  string
+}
+
+func (u serviceUser) String() string {
+	if u.name == "" {
+		return F.ToString(u.index)
+	}
+	return u.name
+}
+
+func appendUsers[T any](users []T, newUsers []adapter.InboundUser, userName func(T) string, convert func(adapter.InboundUser) (T, error)) ([]T, error) {
+	names := make(map[string]bool)
+	for _, user := range users {
+		names[userName(user)] = true
+	}
+	result := append([]T(nil), users...)
+	for _, newUser := range newUsers {
+		if newUser.Name == "" {
+			return nil, E.New("missing user name")
+		}
+		if names[newUser.Name] {
+			return nil, E.New("user already exists: ", newUser.Name)
+		}
+		names[newUser.Name] = true
+		user, err := convert(newUser)
+		if err != nil {
+			return nil, E.Cause(err, "user ", newUser.Name)
+		}
+		result = append(result, user)
+	}
+	return result, nil
+}
+
+func removeUsers[T any](users []T, names []string, userName func(T) string) ([]T, error) {
+	removed := make(map[string]bool)
+	for _, name := range names {
+		removed[name] = true
+	}
+	result := make([]T, 0, len(users))
+	for _, user := range users {
+		if removed[userName(user)] {
+			delete(removed, userName(user))
+			continue
+		}
+		result = append(result, user)
+	}
+	for name := range removed {
+		return nil, E.New("user not found: ", name)
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package inbound
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/trojan"
+	F "github.com/sagernet/sing/common/format"
+	M "github.com/sagernet/sing/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testConcurrentUsers(t *testing.T, inbound adapter.UserManagedInbound, newUser func(index int) adapter.InboundUser) {
+	const count = 32
+	var group sync.WaitGroup
+	errors := make([]error, count)
+	for i := 0; i < count; i++ {
+		group.Add(1)
+		go func(index int) {
+			defer group.Done()
+			errors[index] = inbound.AddUsers([]adapter.InboundUser{newUser(index)})
+		}(i)
+	}
+	group.Wait()
+	for _, err := range errors {
+		require.NoError(t, err)
+	}
+	users := inbound.Users()
+	require.Len(t, users, count)
+	names := make(map[string]bool)
+	for _, user := range users {
+		names[user.Name] = true
+	}
+	require.Len(t, names, count)
+	for i := 0; i < count; i += 2 {
+		group.Add(1)
+		go func(index int) {
+			defer group.Done()
+			errors[index] = inbound.RemoveUsers([]string{F.ToString("user", index)})
+		}(i)
+	}
+	group.Wait()
+	for _, err := range errors {
+		require.NoError(t, err)
+	}
+	users = inbound.Users()
+	require.Len(t, users, count/2)
+	for _, user := range users {
+		require.NotContains(t, []string{"user0", "user2", "user30"}, user.Name)
+	}
+	require.Error(t, inbound.AddUsers([]adapter.InboundUser{newUser(1)}))
+	require.Error(t, inbound.RemoveUsers([]string{"user0"}))
+}
+
+func TestTrojanConcurrentUsers(t *testing.T) {
+	t.Parallel()
+	router := &testRouter{metadata: make(chan adapter.InboundContext, 1)}
+	inbound, err := NewTrojan(context.Background(), router, log.NewNOPFactory().Logger(), "trojan", option.TrojanInboundOptions{})
+	require.NoError(t, err)
+	testConcurrentUsers(t, inbound, func(index int) adapter.InboundUser {
+		return adapter.InboundUser{Name: F.ToString("user", index), Password: F.ToString("password", index)}
+	})
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go inbound.NewConnection(context.Background(), serverConn, adapter.InboundContext{})
+	_, err = trojan.NewClientConn(clientConn, trojan.Key("password5"), M.ParseSocksaddr("example.com:443")).Write([]byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, "user5", (<-router.metadata).User)
+}
+
+func TestVMessConcurrentUsers(t *testing.T) {
+	t.Parallel()
+	inbound, err := NewVMess(context.Background(), nil, log.NewNOPFactory().Logger(), "vmess", option.VMessInboundOptions{})
+	require.NoError(t, err)
+	testConcurrentUsers(t, inbound, func(index int) adapter.InboundUser {
+		return adapter.InboundUser{Name: F.ToString("user", index), UUID: fmt.Sprintf("b831381d-6324-4d53-ad4f-%012d", index)}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"os"
+	"sync"
 
 	"github.com/sagernet/sing-box/adapter"
 	"github.com/sagernet/sing-box/common/mux"
@@ -19,23 +20,24 @@ import (
 	"github.com/sagernet/sing/common"
 	"github.com/sagernet/sing/common/auth"
 	E "github.com/sagernet/sing/common/exceptions"
-	F "github.com/sagernet/sing/common/format"
 	M "github.com/sagernet/sing/common/metadata"
 	N "github.com/sagernet/sing/common/network"
 )
 
 var (
-	_ adapter.Inbound           = (*VLESS)(nil)
-	_ adapter.InjectableInbound = (*VLESS)(nil)
+	_ adapter.Inbound            = (*VLESS)(nil)
+	_ adapter.InjectableInbound  = (*VLESS)(nil)
+	_ adapter.UserManagedInbound = (*VLESS)(nil)
 )
 
 type VLESS struct {
 	myInboundAdapter
-	ctx       context.Context
-	users     []option.VLESSUser
-	service   *vless.Service[int]
-	tlsConfig tls.ServerConfig
-	transport adapter.V2RayServerTransport
+	ctx        context.Context
+	users      []option.VLESSUser
+	userAccess sync.RWMutex
+	service    *vless.Service[serviceUser]
+	tlsConfig  tls.ServerConfig
+	transport  adapter.V2RayServerTransport
 }
 
 func NewVLESS(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.VLESSInboundOptions) (*VLESS, error) {
@@ -49,23 +51,19 @@ func NewVLESS(ctx context.Context, router adapter.Router, logger log.ContextLogg
 			tag:           tag,
 			listenOptions: options.ListenOptions,
 		},
-		ctx:   ctx,
-		users: options.Users,
+		ctx: ctx,
 	}
 	var err error
 	inbound.router, err = mux.NewRouterWithOptions(inbound.router, logger, common.PtrValueOrDefault(options.Multiplex))
 	if err != nil {
 		return nil, err
 	}
-	service := vless.NewService[int](logger, adapter.NewUpstreamContextHandler(inbound.newConnection, inbound.newPacketConnection, inbound))
-	service.UpdateUsers(common.MapIndexed(inbound.users, func(index int, _ option.VLESSUser) int {
-		return index
-	}), common.Map(inbound.users, func(it option.VLESSUser) string {
-		return it.UUID
-	}), common.Map(inbound.users, func(it option.VLESSUser) string {
-		return it.Flow
-	}))
+	service := vless.NewService[serviceUser](logger, adapter.NewUpstreamContextHandler(inbound.newConnection, inbound.newPacketConnection, inbound))
 	inbound.service = service
+	err = inbound.updateUsers(options.Users)
+	if err != nil {
+		return nil, err
+	}
 	if options.TLS != nil {
 		inbound.tlsConfig, err = tls.NewServer(ctx, logger, common.PtrValueOrDefault(options.TLS))
 		if err != nil {
@@ -140,6 +138,7 @@ func (h *VLESS) NewConnection(ctx context.Context, conn net.Conn, metadata adapt
 		if err != nil {
 			return err
 		}
+		metadata.User = tls.ClientCertificateUser(conn)
 	}
 	return h.service.NewConnection(adapter.WithContext(log.ContextWithNewID(ctx), &metadata), conn, adapter.UpstreamMetadata(metadata))
 }
@@ -149,30 +148,26 @@ func (h *VLESS) NewPacketConnection(ctx context.Context, conn N.PacketConn, meta
 }
 
 func (h *VLESS) newConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" && metadata.User == "" {
+		// the identity of a verified client certificate takes precedence
+		metadata.User = user.name
 	}
 	h.logger.InfoContext(ctx, "[", user, "] inbound connection to ", metadata.Destination)
 	return h.router.RouteConnection(ctx, conn, metadata)
 }
 
 func (h *VLESS) newPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" && metadata.User == "" {
+		// the identity of a verified client certificate takes precedence
+		metadata.User = user.name
 	}
 	if metadata.Destination.Fqdn == packetaddr.SeqPacketMagicAddress {
 		metadata.Destination = M.Socksaddr{}
@@ -194,3 +189,61 @@ func (t *vlessTransportHandler) NewConnection(ctx context.Context, conn net.Conn
 		Destination: metadata.Destination,
 	})
 }
+
+// updateUsers must be called with userAccess locked, or before the inbound is started.
+func (h *VLESS) updateUsers(users []option.VLESSUser) error {
+	h.service.UpdateUsers(common.MapIndexed(users, func(index int, it option.VLESSUser) serviceUser {
+		return serviceUser{index, it.Name}
+	}), common.Map(users, func(it option.VLESSUser) string {
+		return it.UUID
+	}), common.Map(users, func(it option.VLESSUser) string {
+		return it.Flow
+	}))
+	h.users = users
+	return nil
+}
+
+func (h *VLESS) Users() []adapter.InboundUser {
+	h.userAccess.RLock()
+	defer h.userAccess.RUnlock()
+	return common.Map(h.users, func(it option.VLESSUser) adapter.InboundUser {
+		return adapter.InboundUser{
+			Name: it.Name,
+			UUID: it.UUID,
+			Flow: it.Flow,
+		}
+	})
+}
+
+func (h *VLESS) AddUsers(users []adapter.InboundUser) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := appendUsers(h.users, users, func(it option.VLESSUser) string {
+		return it.Name
+	}, func(user adapter.InboundUser) (option.VLESSUser, error) {
+		if user.UUID == "" {
+			return option.VLESSUser{}, E.New("missing uuid")
+		}
+		return option.VLESSUser{
+			Name: user.Name,
+			UUID: user.UUID,
+			Flow: user.Flow,
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
+
+func (h *VLESS) RemoveUsers(names []string) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := removeUsers(h.users, names, func(it option.VLESSUser) string {
+		return it.Name
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"os"
+	"sync"
 
 	"github.com/sagernet/sing-box/adapter"
 	"github.com/sagernet/sing-box/common/mux"
@@ -18,24 +19,25 @@ import (
 	"github.com/sagernet/sing/common"
 	"github.com/sagernet/sing/common/auth"
 	E "github.com/sagernet/sing/common/exceptions"
-	F "github.com/sagernet/sing/common/format"
 	M "github.com/sagernet/sing/common/metadata"
 	N "github.com/sagernet/sing/common/network"
 	"github.com/sagernet/sing/common/ntp"
 )
 
 var (
-	_ adapter.Inbound           = (*VMess)(nil)
-	_ adapter.InjectableInbound = (*VMess)(nil)
+	_ adapter.Inbound            = (*VMess)(nil)
+	_ adapter.InjectableInbound  = (*VMess)(nil)
+	_ adapter.UserManagedInbound = (*VMess)(nil)
 )
 
 type VMess struct {
 	myInboundAdapter
-	ctx       context.Context
-	service   *vmess.Service[int]
-	users     []option.VMessUser
-	tlsConfig tls.ServerConfig
-	transport adapter.V2RayServerTransport
+	ctx        context.Context
+	service    *vmess.Service[serviceUser]
+	users      []option.VMessUser
+	userAccess sync.RWMutex
+	tlsConfig  tls.ServerConfig
+	transport  adapter.V2RayServerTransport
 }
 
 func NewVMess(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.VMessInboundOptions) (*VMess, error) {
@@ -49,8 +51,7 @@ func NewVMess(ctx context.Context, router adapter.Router, logger log.ContextLogg
 			tag:           tag,
 			listenOptions: options.ListenOptions,
 		},
-		ctx:   ctx,
-		users: options.Users,
+		ctx: ctx,
 	}
 	var err error
 	inbound.router, err = mux.NewRouterWithOptions(inbound.router, logger, common.PtrValueOrDefault(options.Multiplex))
@@ -64,15 +65,9 @@ func NewVMess(ctx context.Context, router adapter.Router, logger log.ContextLogg
 	if options.Transport != nil && options.Transport.Type != "" {
 		serviceOptions = append(serviceOptions, vmess.ServiceWithDisableHeaderProtection())
 	}
-	service := vmess.NewService[int](adapter.NewUpstreamContextHandler(inbound.newConnection, inbound.newPacketConnection, inbound), serviceOptions...)
+	service := vmess.NewService[serviceUser](adapter.NewUpstreamContextHandler(inbound.newConnection, inbound.newPacketConnection, inbound), serviceOptions...)
 	inbound.service = service
-	err = service.UpdateUsers(common.MapIndexed(options.Users, func(index int, it option.VMessUser) int {
-		return index
-	}), common.Map(options.Users, func(it option.VMessUser) string {
-		return it.UUID
-	}), common.Map(options.Users, func(it option.VMessUser) int {
-		return it.AlterId
-	}))
+	err = inbound.updateUsers(options.Users)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +149,7 @@ func (h *VMess) NewConnection(ctx context.Context, conn net.Conn, metadata adapt
 		if err != nil {
 			return err
 		}
+		metadata.User = tls.ClientCertificateUser(conn)
 	}
 	return h.service.NewConnection(adapter.WithContext(log.ContextWithNewID(ctx), &metadata), conn, adapter.UpstreamMetadata(metadata))
 }
@@ -163,30 +159,26 @@ func (h *VMess) NewPacketConnection(ctx context.Context, conn N.PacketConn, meta
 }
 
 func (h *VMess) newConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" && metadata.User == "" {
+		// the identity of a verified client certificate takes precedence
+		metadata.User = user.name
 	}
 	h.logger.InfoContext(ctx, "[", user, "] inbound connection to ", metadata.Destination)
 	return h.router.RouteConnection(ctx, conn, metadata)
 }
 
 func (h *VMess) newPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
-	userIndex, loaded := auth.UserFromContext[int](ctx)
+	user, loaded := auth.UserFromContext[serviceUser](ctx)
 	if !loaded {
 		return os.ErrInvalid
 	}
-	user := h.users[userIndex].Name
-	if user == "" {
-		user = F.ToString(userIndex)
-	} else {
-		metadata.User = user
+	if user.name != "" && metadata.User == "" {
+		// the identity of a verified client certificate takes precedence
+		metadata.User = user.name
 	}
 	if metadata.Destination.Fqdn == packetaddr.SeqPacketMagicAddress {
 		metadata.Destination = M.Socksaddr{}
@@ -208,3 +200,62 @@ func (t *vmessTransportHandler) NewConnection(ctx context.Context, conn net.Conn
 		Destination: metadata.Destination,
 	})
 }
+
+// updateUsers must be called with userAccess locked, or before the inbound is started.
+func (h *VMess) updateUsers(users []option.VMessUser) error {
+	err := h.service.UpdateUsers(common.MapIndexed(users, func(index int, it option.VMessUser) serviceUser {
+		return serviceUser{index, it.Name}
+	}), common.Map(users, func(it option.VMessUser) string {
+		return it.UUID
+	}), common.Map(users, func(it option.VMessUser) int {
+		return it.AlterId
+	}))
+	if err != nil {
+		return err
+	}
+	h.users = users
+	return nil
+}
+
+func (h *VMess) Users() []adapter.InboundUser {
+	h.userAccess.RLock()
+	defer h.userAccess.RUnlock()
+	return common.Map(h.users, func(it option.VMessUser) adapter.InboundUser {
+		return adapter.InboundUser{
+			Name: it.Name,
+			UUID: it.UUID,
+		}
+	})
+}
+
+func (h *VMess) AddUsers(users []adapter.InboundUser) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := appendUsers(h.users, users, func(it option.VMessUser) string {
+		return it.Name
+	}, func(user adapter.InboundUser) (option.VMessUser, error) {
+		if user.UUID == "" {
+			return option.VMessUser{}, E.New("missing uuid")
+		}
+		return option.VMessUser{
+			Name: user.Name,
+			UUID: user.UUID,
+		}, nil
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
+
+func (h *VMess) RemoveUsers(names []string) error {
+	h.userAccess.Lock()
+	defer h.userAccess.Unlock()
+	newUsers, err := removeUsers(h.users, names, func(it option.VMessUser) string {
+		return it.Name
+	})
+	if err != nil {
+		return err
+	}
+	return h.updateUsers(newUsers)
+}
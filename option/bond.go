@@ -0,0 +1,14 @@
+package option
+
+type BondInboundOptions struct {
+	ListenOptions
+	Password    string   `json:"password,omitempty"`
+	LinkTimeout Duration `json:"link_timeout,omitempty"`
+}
+
+type BondOutboundOptions struct {
+	ServerOptions
+	Outbounds   []string `json:"outbounds"`
+	Password    string   `json:"password,omitempty"`
+	LinkTimeout Duration `json:"link_timeout,omitempty"`
+}
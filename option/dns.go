@@ -23,11 +23,14 @@ type DNSServerOptions struct {
 }
 
 type DNSClientOptions struct {
-	Strategy         DomainStrategy `json:"strategy,omitempty"`
-	DisableCache     bool           `json:"disable_cache,omitempty"`
-	DisableExpire    bool           `json:"disable_expire,omitempty"`
-	IndependentCache bool           `json:"independent_cache,omitempty"`
-	ClientSubnet     *AddrPrefix    `json:"client_subnet,omitempty"`
+	Strategy          DomainStrategy `json:"strategy,omitempty"`
+	DisableCache      bool           `json:"disable_cache,omitempty"`
+	DisableExpire     bool           `json:"disable_expire,omitempty"`
+	IndependentCache  bool           `json:"independent_cache,omitempty"`
+	ServeStale        bool           `json:"serve_stale,omitempty"`
+	ServeStaleTimeout Duration       `json:"serve_stale_timeout,omitempty"`
+	Prefetch          bool           `json:"prefetch,omitempty"`
+	ClientSubnet      *AddrPrefix    `json:"client_subnet,omitempty"`
 }
 
 type DNSFakeIPOptions struct {
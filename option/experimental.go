@@ -8,24 +8,28 @@ type ExperimentalOptions struct {
 }
 
 type CacheFileOptions struct {
-	Enabled     bool     `json:"enabled,omitempty"`
-	Path        string   `json:"path,omitempty"`
-	CacheID     string   `json:"cache_id,omitempty"`
-	StoreFakeIP bool     `json:"store_fakeip,omitempty"`
-	StoreRDRC   bool     `json:"store_rdrc,omitempty"`
-	RDRCTimeout Duration `json:"rdrc_timeout,omitempty"`
+	Enabled                  bool     `json:"enabled,omitempty"`
+	Path                     string   `json:"path,omitempty"`
+	CacheID                  string   `json:"cache_id,omitempty"`
+	StoreFakeIP              bool     `json:"store_fakeip,omitempty"`
+	StoreRDRC                bool     `json:"store_rdrc,omitempty"`
+	RDRCTimeout              Duration `json:"rdrc_timeout,omitempty"`
+	StoreDNS                 bool     `json:"store_dns,omitempty"`
+	StoreConnectionHistory   bool     `json:"store_connection_history,omitempty"`
+	ConnectionHistoryTimeout Duration `json:"connection_history_timeout,omitempty"`
 }
 
 type ClashAPIOptions struct {
-	ExternalController               string           `json:"external_controller,omitempty"`
-	ExternalUI                       string           `json:"external_ui,omitempty"`
-	ExternalUIDownloadURL            string           `json:"external_ui_download_url,omitempty"`
-	ExternalUIDownloadDetour         string           `json:"external_ui_download_detour,omitempty"`
-	Secret                           string           `json:"secret,omitempty"`
-	DefaultMode                      string           `json:"default_mode,omitempty"`
-	ModeList                         []string         `json:"-"`
-	AccessControlAllowOrigin         Listable[string] `json:"access_control_allow_origin,omitempty"`
-	AccessControlAllowPrivateNetwork bool             `json:"access_control_allow_private_network,omitempty"`
+	ExternalController                string           `json:"external_controller,omitempty"`
+	ExternalControllerUnixPermissions FileMode         `json:"external_controller_unix_permissions,omitempty"`
+	ExternalUI                        string           `json:"external_ui,omitempty"`
+	ExternalUIDownloadURL             string           `json:"external_ui_download_url,omitempty"`
+	ExternalUIDownloadDetour          string           `json:"external_ui_download_detour,omitempty"`
+	Secret                            string           `json:"secret,omitempty"`
+	DefaultMode                       string           `json:"default_mode,omitempty"`
+	ModeList                          []string         `json:"-"`
+	AccessControlAllowOrigin          Listable[string] `json:"access_control_allow_origin,omitempty"`
+	AccessControlAllowPrivateNetwork  bool             `json:"access_control_allow_private_network,omitempty"`
 
 	// Deprecated: migrated to global cache file
 	CacheFile string `json:"cache_file,omitempty"`
@@ -40,8 +44,14 @@ type ClashAPIOptions struct {
 }
 
 type V2RayAPIOptions struct {
-	Listen string                    `json:"listen,omitempty"`
-	Stats  *V2RayStatsServiceOptions `json:"stats,omitempty"`
+	Listen          string                      `json:"listen,omitempty"`
+	UnixPermissions FileMode                    `json:"unix_permissions,omitempty"`
+	Stats           *V2RayStatsServiceOptions   `json:"stats,omitempty"`
+	Handler         *V2RayHandlerServiceOptions `json:"handler,omitempty"`
+}
+
+type V2RayHandlerServiceOptions struct {
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 type V2RayStatsServiceOptions struct {
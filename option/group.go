@@ -13,4 +13,5 @@ type URLTestOutboundOptions struct {
 	Tolerance                 uint16   `json:"tolerance,omitempty"`
 	IdleTimeout               Duration `json:"idle_timeout,omitempty"`
 	InterruptExistConnections bool     `json:"interrupt_exist_connections,omitempty"`
+	FailureThreshold          uint32   `json:"failure_threshold,omitempty"`
 }
@@ -2,11 +2,12 @@ package option
 
 type Hysteria2InboundOptions struct {
 	ListenOptions
-	UpMbps                int             `json:"up_mbps,omitempty"`
-	DownMbps              int             `json:"down_mbps,omitempty"`
-	Obfs                  *Hysteria2Obfs  `json:"obfs,omitempty"`
-	Users                 []Hysteria2User `json:"users,omitempty"`
-	IgnoreClientBandwidth bool            `json:"ignore_client_bandwidth,omitempty"`
+	ListenPorts           Listable[string] `json:"listen_ports,omitempty"`
+	UpMbps                int              `json:"up_mbps,omitempty"`
+	DownMbps              int              `json:"down_mbps,omitempty"`
+	Obfs                  *Hysteria2Obfs   `json:"obfs,omitempty"`
+	Users                 []Hysteria2User  `json:"users,omitempty"`
+	IgnoreClientBandwidth bool             `json:"ignore_client_bandwidth,omitempty"`
 	InboundTLSOptionsContainer
 	Masquerade  string `json:"masquerade,omitempty"`
 	BrutalDebug bool   `json:"brutal_debug,omitempty"`
@@ -25,11 +26,13 @@ type Hysteria2User struct {
 type Hysteria2OutboundOptions struct {
 	DialerOptions
 	ServerOptions
-	UpMbps   int            `json:"up_mbps,omitempty"`
-	DownMbps int            `json:"down_mbps,omitempty"`
-	Obfs     *Hysteria2Obfs `json:"obfs,omitempty"`
-	Password string         `json:"password,omitempty"`
-	Network  NetworkList    `json:"network,omitempty"`
+	ServerPorts Listable[string] `json:"server_ports,omitempty"`
+	HopInterval Duration         `json:"hop_interval,omitempty"`
+	UpMbps      int              `json:"up_mbps,omitempty"`
+	DownMbps    int              `json:"down_mbps,omitempty"`
+	Obfs        *Hysteria2Obfs   `json:"obfs,omitempty"`
+	Password    string           `json:"password,omitempty"`
+	Network     NetworkList      `json:"network,omitempty"`
 	OutboundTLSOptionsContainer
 	BrutalDebug bool `json:"brutal_debug,omitempty"`
 }
@@ -27,6 +27,9 @@ type _Inbound struct {
 	VLESSOptions       VLESSInboundOptions       `json:"-"`
 	TUICOptions        TUICInboundOptions        `json:"-"`
 	Hysteria2Options   Hysteria2InboundOptions   `json:"-"`
+	BondOptions        BondInboundOptions        `json:"-"`
+	TorOptions         TorInboundOptions         `json:"-"`
+	SSHOptions         SSHInboundOptions         `json:"-"`
 }
 
 type Inbound _Inbound
@@ -66,6 +69,12 @@ func (h *Inbound) RawOptions() (any, error) {
 		rawOptionsPtr = &h.TUICOptions
 	case C.TypeHysteria2:
 		rawOptionsPtr = &h.Hysteria2Options
+	case C.TypeBond:
+		rawOptionsPtr = &h.BondOptions
+	case C.TypeTor:
+		rawOptionsPtr = &h.TorOptions
+	case C.TypeSSH:
+		rawOptionsPtr = &h.SSHOptions
 	case "":
 		return nil, E.New("missing inbound type")
 	default:
@@ -109,6 +118,7 @@ type InboundOptions struct {
 type ListenOptions struct {
 	Listen                      *ListenAddress   `json:"listen,omitempty"`
 	ListenPort                  uint16           `json:"listen_port,omitempty"`
+	UnixPermissions             FileMode         `json:"unix_permissions,omitempty"`
 	TCPFastOpen                 bool             `json:"tcp_fast_open,omitempty"`
 	TCPMultiPath                bool             `json:"tcp_multi_path,omitempty"`
 	UDPFragment                 *bool            `json:"udp_fragment,omitempty"`
@@ -25,6 +25,7 @@ type _Outbound struct {
 	VLESSOptions        VLESSOutboundOptions        `json:"-"`
 	TUICOptions         TUICOutboundOptions         `json:"-"`
 	Hysteria2Options    Hysteria2OutboundOptions    `json:"-"`
+	BondOptions         BondOutboundOptions         `json:"-"`
 	SelectorOptions     SelectorOutboundOptions     `json:"-"`
 	URLTestOptions      URLTestOutboundOptions      `json:"-"`
 }
@@ -66,6 +67,8 @@ func (h *Outbound) RawOptions() (any, error) {
 		rawOptionsPtr = &h.TUICOptions
 	case C.TypeHysteria2:
 		rawOptionsPtr = &h.Hysteria2Options
+	case C.TypeBond:
+		rawOptionsPtr = &h.BondOptions
 	case C.TypeSelector:
 		rawOptionsPtr = &h.SelectorOptions
 	case C.TypeURLTest:
@@ -139,8 +142,15 @@ type ServerOptionsWrapper interface {
 }
 
 type ServerOptions struct {
-	Server     string `json:"server"`
-	ServerPort uint16 `json:"server_port"`
+	Server          string                  `json:"server"`
+	ServerPort      uint16                  `json:"server_port"`
+	ServerAddresses Listable[string]        `json:"server_addresses,omitempty"`
+	ServerDiscovery *ServerDiscoveryOptions `json:"server_discovery,omitempty"`
+}
+
+type ServerDiscoveryOptions struct {
+	Type   string `json:"type,omitempty"`
+	Domain string `json:"domain,omitempty"`
 }
 
 func (o ServerOptions) Build() M.Socksaddr {
@@ -1,10 +1,23 @@
 package option
 
+import "net/netip"
+
 type RedirectInboundOptions struct {
 	ListenOptions
+	RedirAutoConfigureOptions
 }
 
 type TProxyInboundOptions struct {
 	ListenOptions
 	Network NetworkList `json:"network,omitempty"`
+	RedirAutoConfigureOptions
+	AutoConfigureMark  FwMark `json:"auto_configure_mark,omitempty"`
+	IPRoute2TableIndex int    `json:"iproute2_table_index,omitempty"`
+	IPRoute2RuleIndex  int    `json:"iproute2_rule_index,omitempty"`
+}
+
+type RedirAutoConfigureOptions struct {
+	AutoConfigure          bool                   `json:"auto_configure,omitempty"`
+	RouteExcludeAddress    Listable[netip.Prefix] `json:"route_exclude_address,omitempty"`
+	RouteExcludeAddressSet Listable[string]       `json:"route_exclude_address_set,omitempty"`
 }
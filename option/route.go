@@ -1,20 +1,38 @@
 package option
 
 type RouteOptions struct {
-	GeoIP               *GeoIPOptions   `json:"geoip,omitempty"`
-	Geosite             *GeositeOptions `json:"geosite,omitempty"`
-	Rules               []Rule          `json:"rules,omitempty"`
-	RuleSet             []RuleSet       `json:"rule_set,omitempty"`
-	Final               string          `json:"final,omitempty"`
-	FindProcess         bool            `json:"find_process,omitempty"`
-	AutoDetectInterface bool            `json:"auto_detect_interface,omitempty"`
-	OverrideAndroidVPN  bool            `json:"override_android_vpn,omitempty"`
-	DefaultInterface    string          `json:"default_interface,omitempty"`
-	DefaultMark         uint32          `json:"default_mark,omitempty"`
+	GeoIP               *GeoIPOptions         `json:"geoip,omitempty"`
+	Geosite             *GeositeOptions       `json:"geosite,omitempty"`
+	Rules               []Rule                `json:"rules,omitempty"`
+	RuleSet             []RuleSet             `json:"rule_set,omitempty"`
+	Final               string                `json:"final,omitempty"`
+	FindProcess         bool                  `json:"find_process,omitempty"`
+	AutoDetectInterface bool                  `json:"auto_detect_interface,omitempty"`
+	OverrideAndroidVPN  bool                  `json:"override_android_vpn,omitempty"`
+	DefaultInterface    string                `json:"default_interface,omitempty"`
+	DefaultMark         uint32                `json:"default_mark,omitempty"`
+	RateLimiters        []RateLimiterOptions  `json:"rate_limiters,omitempty"`
+	TrafficClasses      []TrafficClassOptions `json:"traffic_classes,omitempty"`
+
+	ScheduleInterruptExistConnections bool `json:"schedule_interrupt_exist_connections,omitempty"`
+}
+
+type RateLimiterOptions struct {
+	Tag      string           `json:"tag"`
+	UpMbps   int              `json:"up_mbps,omitempty"`
+	DownMbps int              `json:"down_mbps,omitempty"`
+	Inbound  Listable[string] `json:"inbound,omitempty"`
+	Outbound Listable[string] `json:"outbound,omitempty"`
+}
+
+type TrafficClassOptions struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority,omitempty"`
 }
 
 type GeoIPOptions struct {
 	Path           string `json:"path,omitempty"`
+	ASNPath        string `json:"asn_path,omitempty"`
 	DownloadURL    string `json:"download_url,omitempty"`
 	DownloadDetour string `json:"download_detour,omitempty"`
 }
@@ -144,25 +144,31 @@ func (r HeadlessRule) IsValid() bool {
 }
 
 type DefaultHeadlessRule struct {
-	QueryType        Listable[DNSQueryType] `json:"query_type,omitempty"`
-	Network          Listable[string]       `json:"network,omitempty"`
-	Domain           Listable[string]       `json:"domain,omitempty"`
-	DomainSuffix     Listable[string]       `json:"domain_suffix,omitempty"`
-	DomainKeyword    Listable[string]       `json:"domain_keyword,omitempty"`
-	DomainRegex      Listable[string]       `json:"domain_regex,omitempty"`
-	SourceIPCIDR     Listable[string]       `json:"source_ip_cidr,omitempty"`
-	IPCIDR           Listable[string]       `json:"ip_cidr,omitempty"`
-	SourcePort       Listable[uint16]       `json:"source_port,omitempty"`
-	SourcePortRange  Listable[string]       `json:"source_port_range,omitempty"`
-	Port             Listable[uint16]       `json:"port,omitempty"`
-	PortRange        Listable[string]       `json:"port_range,omitempty"`
-	ProcessName      Listable[string]       `json:"process_name,omitempty"`
-	ProcessPath      Listable[string]       `json:"process_path,omitempty"`
-	ProcessPathRegex Listable[string]       `json:"process_path_regex,omitempty"`
-	PackageName      Listable[string]       `json:"package_name,omitempty"`
-	WIFISSID         Listable[string]       `json:"wifi_ssid,omitempty"`
-	WIFIBSSID        Listable[string]       `json:"wifi_bssid,omitempty"`
-	Invert           bool                   `json:"invert,omitempty"`
+	QueryType        Listable[DNSQueryType]    `json:"query_type,omitempty"`
+	Network          Listable[string]          `json:"network,omitempty"`
+	Domain           Listable[string]          `json:"domain,omitempty"`
+	DomainSuffix     Listable[string]          `json:"domain_suffix,omitempty"`
+	DomainKeyword    Listable[string]          `json:"domain_keyword,omitempty"`
+	DomainRegex      Listable[string]          `json:"domain_regex,omitempty"`
+	SourceIPCIDR     Listable[string]          `json:"source_ip_cidr,omitempty"`
+	IPCIDR           Listable[string]          `json:"ip_cidr,omitempty"`
+	SourceIPASN      Listable[uint32]          `json:"source_ip_asn,omitempty"`
+	IPASN            Listable[uint32]          `json:"ip_asn,omitempty"`
+	SourcePort       Listable[uint16]          `json:"source_port,omitempty"`
+	SourcePortRange  Listable[string]          `json:"source_port_range,omitempty"`
+	Port             Listable[uint16]          `json:"port,omitempty"`
+	PortRange        Listable[string]          `json:"port_range,omitempty"`
+	ProcessName      Listable[string]          `json:"process_name,omitempty"`
+	ProcessPath      Listable[string]          `json:"process_path,omitempty"`
+	ProcessPathRegex Listable[string]          `json:"process_path_regex,omitempty"`
+	PackageName      Listable[string]          `json:"package_name,omitempty"`
+	CGroup           Listable[string]          `json:"cgroup,omitempty"`
+	SystemdUnit      Listable[string]          `json:"systemd_unit,omitempty"`
+	ContainerID      Listable[string]          `json:"container_id,omitempty"`
+	Schedule         Listable[ScheduleOptions] `json:"schedule,omitempty"`
+	WIFISSID         Listable[string]          `json:"wifi_ssid,omitempty"`
+	WIFIBSSID        Listable[string]          `json:"wifi_bssid,omitempty"`
+	Invert           bool                      `json:"invert,omitempty"`
 
 	DomainMatcher *domain.Matcher `json:"-"`
 	SourceIPSet   *netipx.IPSet   `json:"-"`
@@ -198,7 +204,7 @@ type PlainRuleSetCompat _PlainRuleSetCompat
 func (r PlainRuleSetCompat) MarshalJSON() ([]byte, error) {
 	var v any
 	switch r.Version {
-	case C.RuleSetVersion1, C.RuleSetVersion2:
+	case C.RuleSetVersion1, C.RuleSetVersion2, C.RuleSetVersion3:
 		v = r.Options
 	default:
 		return nil, E.New("unknown rule-set version: ", r.Version)
@@ -213,7 +219,7 @@ func (r *PlainRuleSetCompat) UnmarshalJSON(bytes []byte) error {
 	}
 	var v any
 	switch r.Version {
-	case C.RuleSetVersion1, C.RuleSetVersion2:
+	case C.RuleSetVersion1, C.RuleSetVersion2, C.RuleSetVersion3:
 		v = &r.Options
 	case 0:
 		return E.New("missing rule-set version")
@@ -229,7 +235,7 @@ func (r *PlainRuleSetCompat) UnmarshalJSON(bytes []byte) error {
 
 func (r PlainRuleSetCompat) Upgrade() (PlainRuleSet, error) {
 	switch r.Version {
-	case C.RuleSetVersion1, C.RuleSetVersion2:
+	case C.RuleSetVersion1, C.RuleSetVersion2, C.RuleSetVersion3:
 	default:
 		return PlainRuleSet{}, E.New("unknown rule-set version: " + F.ToString(r.Version))
 	}
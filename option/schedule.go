@@ -0,0 +1,7 @@
+package option
+
+type ScheduleOptions struct {
+	Weekday  Listable[string] `json:"weekday,omitempty"`
+	Time     Listable[string] `json:"time,omitempty"`
+	Timezone string           `json:"timezone,omitempty"`
+}
@@ -7,3 +7,12 @@ type TorOutboundOptions struct {
 	DataDirectory  string            `json:"data_directory,omitempty"`
 	Options        map[string]string `json:"torrc,omitempty"`
 }
+
+type TorInboundOptions struct {
+	InboundOptions
+	Outbound        string `json:"outbound"`
+	VirtualPort     uint16 `json:"virtual_port,omitempty"`
+	PrivateKeyPath  string `json:"private_key_path,omitempty"`
+	OverrideAddress string `json:"override_address,omitempty"`
+	OverridePort    uint16 `json:"override_port,omitempty"`
+}
@@ -37,6 +37,8 @@ type TunInboundOptions struct {
 	EndpointIndependentNat bool                   `json:"endpoint_independent_nat,omitempty"`
 	UDPTimeout             UDPTimeoutCompat       `json:"udp_timeout,omitempty"`
 	Stack                  string                 `json:"stack,omitempty"`
+	ICMPForwarding         bool                   `json:"icmp_forwarding,omitempty"`
+	ICMPFallback           string                 `json:"icmp_fallback,omitempty"`
 	Platform               *TunPlatformOptions    `json:"platform,omitempty"`
 	InboundOptions
 
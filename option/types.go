@@ -3,6 +3,8 @@ package option
 import (
 	"net/http"
 	"net/netip"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,19 +17,30 @@ import (
 	mDNS "github.com/miekg/dns"
 )
 
-type ListenAddress netip.Addr
+// ListenAddress is an IP address, a unix socket path in the form of
+// `unix:/path/to/socket`, or a socket passed by systemd in the form of
+// `systemd:name`.
+type ListenAddress struct {
+	addr        netip.Addr
+	unixPath    string
+	systemdName string
+}
 
 func NewListenAddress(addr netip.Addr) *ListenAddress {
-	address := ListenAddress(addr)
-	return &address
+	return &ListenAddress{addr: addr}
 }
 
 func (a ListenAddress) MarshalJSON() ([]byte, error) {
-	addr := netip.Addr(a)
-	if !addr.IsValid() {
-		return nil, nil
+	switch {
+	case a.unixPath != "":
+		return json.Marshal(listenPrefixUnix + a.unixPath)
+	case a.systemdName != "":
+		return json.Marshal(listenPrefixSystemd + a.systemdName)
+	case !a.addr.IsValid():
+		return []byte("null"), nil
+	default:
+		return json.Marshal(a.addr.String())
 	}
-	return json.Marshal(addr.String())
 }
 
 func (a *ListenAddress) UnmarshalJSON(content []byte) error {
@@ -36,11 +49,26 @@ func (a *ListenAddress) UnmarshalJSON(content []byte) error {
 	if err != nil {
 		return err
 	}
-	addr, err := netip.ParseAddr(value)
-	if err != nil {
-		return err
+	switch {
+	case strings.HasPrefix(value, listenPrefixUnix):
+		unixPath := strings.TrimPrefix(value, listenPrefixUnix)
+		if unixPath == "" {
+			return E.New("missing unix socket path")
+		}
+		*a = ListenAddress{unixPath: unixPath}
+	case strings.HasPrefix(value, listenPrefixSystemd):
+		systemdName := strings.TrimPrefix(value, listenPrefixSystemd)
+		if systemdName == "" {
+			return E.New("missing systemd socket name")
+		}
+		*a = ListenAddress{systemdName: systemdName}
+	default:
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return err
+		}
+		*a = ListenAddress{addr: addr}
 	}
-	*a = ListenAddress(addr)
 	return nil
 }
 
@@ -48,7 +76,71 @@ func (a *ListenAddress) Build() netip.Addr {
 	if a == nil {
 		return netip.AddrFrom4([4]byte{127, 0, 0, 1})
 	}
-	return (netip.Addr)(*a)
+	return a.addr
+}
+
+// UnixPath returns the unix socket path, or an empty string if not a unix socket.
+func (a *ListenAddress) UnixPath() string {
+	if a == nil {
+		return ""
+	}
+	return a.unixPath
+}
+
+// SystemdName returns the name of the socket passed by systemd, or an empty string.
+func (a *ListenAddress) SystemdName() string {
+	if a == nil {
+		return ""
+	}
+	return a.systemdName
+}
+
+// IsIP reports whether the address is an IP address rather than a socket path or name.
+func (a *ListenAddress) IsIP() bool {
+	return a == nil || a.unixPath == "" && a.systemdName == ""
+}
+
+func (a *ListenAddress) String() string {
+	switch {
+	case a.UnixPath() != "":
+		return listenPrefixUnix + a.unixPath
+	case a.SystemdName() != "":
+		return listenPrefixSystemd + a.systemdName
+	default:
+		return a.Build().String()
+	}
+}
+
+const (
+	listenPrefixUnix    = "unix:"
+	listenPrefixSystemd = "systemd:"
+)
+
+type FileMode os.FileMode
+
+func (m FileMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0" + strconv.FormatUint(uint64(m), 8))
+}
+
+func (m *FileMode) UnmarshalJSON(content []byte) error {
+	var value string
+	err := json.Unmarshal(content, &value)
+	if err != nil {
+		return err
+	}
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return E.Cause(err, "parse file mode")
+	}
+	if mode > 0o777 {
+		return E.New("invalid file mode: ", value)
+	}
+	*m = FileMode(mode)
+	return nil
+}
+
+func (m FileMode) Build() os.FileMode {
+	return os.FileMode(m)
 }
 
 type AddrPrefix netip.Prefix
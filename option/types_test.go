@@ -0,0 +1,34 @@
+package option
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/sagernet/sing/common/json"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenAddress(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{`"::"`, `"127.0.0.1"`, `"unix:/run/sing-box.sock"`, `"systemd:mixed"`} {
+		var address ListenAddress
+		require.NoError(t, json.Unmarshal([]byte(value), &address))
+		content, err := json.Marshal(address)
+		require.NoError(t, err)
+		require.Equal(t, value, string(content))
+	}
+	var address ListenAddress
+	require.Error(t, json.Unmarshal([]byte(`"unix:"`), &address))
+	require.Error(t, json.Unmarshal([]byte(`"systemd:"`), &address))
+	content, err := json.Marshal(ListenAddress{})
+	require.NoError(t, err)
+	require.Equal(t, "null", string(content))
+	content, err = json.Marshal(struct {
+		Listen ListenAddress `json:"listen"`
+	}{})
+	require.NoError(t, err)
+	require.Equal(t, `{"listen":null}`, string(content))
+	require.Equal(t, netip.AddrFrom4([4]byte{127, 0, 0, 1}), (*ListenAddress)(nil).Build())
+	require.True(t, (*ListenAddress)(nil).IsIP())
+}
@@ -8,11 +8,15 @@ import (
 	"github.com/sagernet/sing-box/adapter"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
+	E "github.com/sagernet/sing/common/exceptions"
 	M "github.com/sagernet/sing/common/metadata"
 	N "github.com/sagernet/sing/common/network"
 )
 
-var _ adapter.Outbound = (*Block)(nil)
+var (
+	_ adapter.Outbound     = (*Block)(nil)
+	_ adapter.ICMPOutbound = (*Block)(nil)
+)
 
 type Block struct {
 	myOutboundAdapter
@@ -50,3 +54,8 @@ func (h *Block) NewPacketConnection(ctx context.Context, conn N.PacketConn, meta
 	h.logger.InfoContext(ctx, "blocked packet connection to ", metadata.Destination)
 	return nil
 }
+
+func (h *Block) NewICMPEcho(ctx context.Context, request *adapter.ICMPEchoRequest, metadata adapter.InboundContext) error {
+	h.logger.InfoContext(ctx, "blocked icmp echo to ", request.Destination)
+	return E.New("blocked")
+}
@@ -0,0 +1,245 @@
+package outbound
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/dialer"
+	"github.com/sagernet/sing-box/common/mux"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/bond"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+)
+
+var (
+	_ adapter.Outbound                = (*Bond)(nil)
+	_ adapter.InterfaceUpdateListener = (*Bond)(nil)
+)
+
+type Bond struct {
+	myOutboundAdapter
+	ctx           context.Context
+	tags          []string
+	serverOptions option.ServerOptions
+	serverAddr    M.Socksaddr
+	key           [bond.KeyLength]byte
+	linkTimeout   time.Duration
+	links         []bondLink
+	multiplexer   *mux.Client
+}
+
+type bondLink struct {
+	tag    string
+	dialer N.Dialer
+}
+
+func NewBond(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.BondOutboundOptions) (*Bond, error) {
+	outbound := &Bond{
+		myOutboundAdapter: myOutboundAdapter{
+			protocol:     C.TypeBond,
+			network:      []string{N.NetworkTCP, N.NetworkUDP},
+			router:       router,
+			logger:       logger,
+			tag:          tag,
+			dependencies: options.Outbounds,
+		},
+		ctx:           ctx,
+		tags:          options.Outbounds,
+		serverOptions: options.ServerOptions,
+		serverAddr:    options.ServerOptions.Build(),
+		key:           bond.Key(options.Password),
+		linkTimeout:   time.Duration(options.LinkTimeout),
+	}
+	if len(outbound.tags) == 0 {
+		return nil, E.New("missing outbounds")
+	}
+	if !outbound.serverAddr.IsValid() {
+		return nil, E.New("missing server address")
+	}
+	if options.Password == "" {
+		return nil, E.New("missing password")
+	}
+	if outbound.linkTimeout == 0 {
+		outbound.linkTimeout = C.BondLinkTimeout
+	}
+	var err error
+	outbound.multiplexer, err = mux.NewClientWithOptions((*bondSessionDialer)(outbound), logger, option.OutboundMultiplexOptions{
+		Enabled:        true,
+		MaxConnections: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outbound, nil
+}
+
+func (h *Bond) Start() error {
+	for i, tag := range h.tags {
+		detour, loaded := h.router.Outbound(tag)
+		if !loaded {
+			return E.New("outbound ", i, " not found: ", tag)
+		}
+		if !common.Contains(detour.Network(), N.NetworkTCP) {
+			return E.New("outbound ", i, " does not support TCP: ", tag)
+		}
+		var linkDialer N.Dialer = detour
+		if len(h.serverOptions.ServerAddresses) > 0 || h.serverOptions.ServerDiscovery != nil {
+			serverDialer, err := dialer.NewServerDialer(h.router, detour, h.serverOptions, 0)
+			if err != nil {
+				return err
+			}
+			linkDialer = serverDialer
+		}
+		h.links = append(h.links, bondLink{tag, linkDialer})
+	}
+	return nil
+}
+
+func (h *Bond) InterfaceUpdated() {
+	h.multiplexer.Reset()
+}
+
+func (h *Bond) Close() error {
+	return h.multiplexer.Close()
+}
+
+func (h *Bond) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
+	switch N.NetworkName(network) {
+	case N.NetworkTCP:
+		h.logger.InfoContext(ctx, "outbound bond connection to ", destination)
+	case N.NetworkUDP:
+		h.logger.InfoContext(ctx, "outbound bond packet connection to ", destination)
+	}
+	return h.multiplexer.DialContext(ctx, network, destination)
+}
+
+func (h *Bond) ListenPacket(ctx context.Context, destination M.Socksaddr) (net.PacketConn, error) {
+	h.logger.InfoContext(ctx, "outbound bond packet connection to ", destination)
+	return h.multiplexer.ListenPacket(ctx, destination)
+}
+
+func (h *Bond) NewConnection(ctx context.Context, conn net.Conn, metadata adapter.InboundContext) error {
+	return NewConnection(ctx, h, conn, metadata)
+}
+
+func (h *Bond) NewPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
+	return NewPacketConnection(ctx, h, conn, metadata)
+}
+
+// bondSessionDialer opens the bonded session carrying the multiplexed connections.
+type bondSessionDialer Bond
+
+func (h *bondSessionDialer) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
+	sessionKey, err := bond.NewSessionKey()
+	if err != nil {
+		return nil, err
+	}
+	var session *bond.Session
+	session = bond.NewSession(h.linkTimeout, func(name string, err error) {
+		h.logger.DebugContext(ctx, "bond link ", name, " lost: ", err)
+		for _, link := range h.links {
+			if link.tag == name {
+				go h.redialLink(ctx, session, link, sessionKey)
+			}
+		}
+	})
+	results := make(chan bondDialResult, len(h.links))
+	for _, link := range h.links {
+		go func(link bondLink) {
+			// links that come up after the session is returned join it in the background
+			results <- bondDialResult{link, h.dialLink(h.ctx, session, link, sessionKey)}
+		}(link)
+	}
+	var failed []bondDialResult
+	for i := range h.links {
+		var result bondDialResult
+		select {
+		case result = <-results:
+		case <-ctx.Done():
+			session.Close()
+			return nil, ctx.Err()
+		}
+		if result.err == nil {
+			go h.retryFailedLinks(ctx, session, sessionKey, failed, results, len(h.links)-i-1)
+			return session, nil
+		}
+		failed = append(failed, result)
+	}
+	session.Close()
+	return nil, E.Errors(common.Map(failed, func(it bondDialResult) error {
+		return it.err
+	})...)
+}
+
+func (h *bondSessionDialer) ListenPacket(ctx context.Context, destination M.Socksaddr) (net.PacketConn, error) {
+	return nil, os.ErrInvalid
+}
+
+type bondDialResult struct {
+	link bondLink
+	err  error
+}
+
+func (h *bondSessionDialer) retryFailedLinks(ctx context.Context, session *bond.Session, sessionKey bond.SessionKey, failed []bondDialResult, results <-chan bondDialResult, remaining int) {
+	for ; remaining > 0; remaining-- {
+		result := <-results
+		if result.err != nil {
+			failed = append(failed, result)
+		}
+	}
+	for _, result := range failed {
+		h.logger.DebugContext(ctx, "bond link failed: ", result.err)
+		go h.redialLink(ctx, session, result.link, sessionKey)
+	}
+}
+
+func (h *bondSessionDialer) dialLink(ctx context.Context, session *bond.Session, link bondLink, sessionKey bond.SessionKey) error {
+	ctx, cancel := context.WithTimeout(ctx, C.TCPTimeout)
+	defer cancel()
+	conn, err := link.dialer.DialContext(ctx, N.NetworkTCP, h.serverAddr)
+	if err != nil {
+		return E.Cause(err, "dial link ", link.tag)
+	}
+	bondLink, err := bond.ClientHandshake(conn, h.key, sessionKey)
+	if err != nil {
+		conn.Close()
+		return E.Cause(err, "handshake link ", link.tag)
+	}
+	_, err = session.AddLink(bondLink, link.tag)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	return nil
+}
+
+func (h *bondSessionDialer) redialLink(ctx context.Context, session *bond.Session, link bondLink, sessionKey bond.SessionKey) {
+	delay := time.Second
+	for !session.IsClosed() {
+		select {
+		case <-time.After(delay):
+		case <-h.ctx.Done():
+			return
+		}
+		if session.IsClosed() {
+			return
+		}
+		err := h.dialLink(h.ctx, session, link, sessionKey)
+		if err == nil {
+			h.logger.DebugContext(ctx, "bond link ", link.tag, " restored")
+			return
+		}
+		h.logger.DebugContext(ctx, "redial bond link: ", err)
+		if delay < h.linkTimeout {
+			delay *= 2
+		}
+	}
+}
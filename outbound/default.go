@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/failure"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
@@ -66,6 +67,7 @@ func NewConnection(ctx context.Context, this N.Dialer, conn net.Conn, metadata a
 	} else {
 		outConn, err = this.DialContext(ctx, N.NetworkTCP, metadata.Destination)
 	}
+	recordDial(ctx, this, err)
 	if err != nil {
 		return N.ReportHandshakeFailure(conn, err)
 	}
@@ -87,12 +89,14 @@ func NewDirectConnection(ctx context.Context, router adapter.Router, this N.Dial
 		var destinationAddresses []netip.Addr
 		destinationAddresses, err = router.Lookup(ctx, metadata.Destination.Fqdn, domainStrategy)
 		if err != nil {
+			recordDial(ctx, this, err)
 			return N.ReportHandshakeFailure(conn, err)
 		}
 		outConn, err = N.DialSerial(ctx, this, N.NetworkTCP, metadata.Destination, destinationAddresses)
 	} else {
 		outConn, err = this.DialContext(ctx, N.NetworkTCP, metadata.Destination)
 	}
+	recordDial(ctx, this, err)
 	if err != nil {
 		return N.ReportHandshakeFailure(conn, err)
 	}
@@ -114,6 +118,7 @@ func NewPacketConnection(ctx context.Context, this N.Dialer, conn N.PacketConn,
 	} else {
 		outConn, err = this.ListenPacket(ctx, metadata.Destination)
 	}
+	recordDial(ctx, this, err)
 	if err != nil {
 		return N.ReportHandshakeFailure(conn, err)
 	}
@@ -156,12 +161,14 @@ func NewDirectPacketConnection(ctx context.Context, router adapter.Router, this
 		var destinationAddresses []netip.Addr
 		destinationAddresses, err = router.Lookup(ctx, metadata.Destination.Fqdn, domainStrategy)
 		if err != nil {
+			recordDial(ctx, this, err)
 			return N.ReportHandshakeFailure(conn, err)
 		}
 		outConn, destinationAddress, err = N.ListenSerial(ctx, this, metadata.Destination, destinationAddresses)
 	} else {
 		outConn, err = this.ListenPacket(ctx, metadata.Destination)
 	}
+	recordDial(ctx, this, err)
 	if err != nil {
 		return N.ReportHandshakeFailure(conn, err)
 	}
@@ -234,6 +241,24 @@ func CopyEarlyConn(ctx context.Context, conn net.Conn, serverConn net.Conn) erro
 	return bufio.CopyConn(ctx, conn, serverConn)
 }
 
+// recordDial counts the dial and handshake result of a routed connection,
+// failures of established connections are not counted.
+// Groups count the result for the outbound they dialed instead.
+func recordDial(ctx context.Context, this N.Dialer, err error) {
+	if _, isGroup := this.(adapter.OutboundGroup); isGroup {
+		return
+	}
+	if outbound, isOutbound := this.(adapter.Outbound); isOutbound {
+		failure.RecordDial(ctx, outbound.Tag(), err)
+	}
+}
+
+func recordGroupDial(ctx context.Context, outbound adapter.Outbound, err error) {
+	if _, isGroup := outbound.(adapter.OutboundGroup); !isGroup {
+		failure.RecordDial(ctx, outbound.Tag(), err)
+	}
+}
+
 func NewError(logger log.ContextLogger, ctx context.Context, err error) {
 	common.Close(err)
 	if E.IsClosedOrCanceled(err) {
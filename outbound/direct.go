@@ -8,6 +8,7 @@ import (
 
 	"github.com/sagernet/sing-box/adapter"
 	"github.com/sagernet/sing-box/common/dialer"
+	"github.com/sagernet/sing-box/common/ping"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/log"
 	"github.com/sagernet/sing-box/option"
@@ -19,13 +20,16 @@ import (
 )
 
 var (
-	_ adapter.Outbound = (*Direct)(nil)
-	_ N.ParallelDialer = (*Direct)(nil)
+	_ adapter.Outbound     = (*Direct)(nil)
+	_ adapter.ICMPOutbound = (*Direct)(nil)
+	_ N.ParallelDialer     = (*Direct)(nil)
 )
 
 type Direct struct {
 	myOutboundAdapter
 	dialer              N.Dialer
+	icmpDialer          *dialer.DefaultDialer
+	detour              string
 	domainStrategy      dns.DomainStrategy
 	fallbackDelay       time.Duration
 	overrideOption      int
@@ -56,6 +60,14 @@ func NewDirect(router adapter.Router, logger log.ContextLogger, tag string, opti
 	if options.ProxyProtocol != 0 {
 		return nil, E.New("Proxy Protocol is deprecated and removed in sing-box 1.6.0")
 	}
+	if options.Detour != "" {
+		outbound.detour = options.Detour
+	} else {
+		outbound.icmpDialer, err = dialer.NewDefault(router, options.DialerOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if options.OverrideAddress != "" && options.OverridePort != 0 {
 		outbound.overrideOption = 1
 		outbound.overrideDestination = M.ParseSocksaddrHostPort(options.OverrideAddress, options.OverridePort)
@@ -168,3 +180,23 @@ func (h *Direct) NewPacketConnection(ctx context.Context, conn N.PacketConn, met
 	}
 	return NewPacketConnection(ctx, h, conn, metadata)
 }
+
+func (h *Direct) NewICMPEcho(ctx context.Context, request *adapter.ICMPEchoRequest, metadata adapter.InboundContext) error {
+	if h.detour != "" {
+		detour, loaded := h.router.Outbound(h.detour)
+		if !loaded {
+			return E.New("outbound detour not found: ", h.detour)
+		}
+		detour, err := RealOutbound(h.router, detour)
+		if err != nil {
+			return err
+		}
+		icmpOutbound, isICMP := detour.(adapter.ICMPOutbound)
+		if !isICMP {
+			return adapter.ErrICMPUnsupported
+		}
+		return icmpOutbound.NewICMPEcho(ctx, request, metadata)
+	}
+	h.logger.DebugContext(ctx, "outbound icmp echo to ", request.Destination)
+	return ping.Exchange(ctx, h.icmpDialer.ListenControl(), request)
+}
@@ -37,11 +37,15 @@ func NewHysteria(ctx context.Context, router adapter.Router, logger log.ContextL
 	if options.TLS == nil || !options.TLS.Enabled {
 		return nil, C.ErrTLSRequired
 	}
-	tlsConfig, err := tls.NewClient(ctx, options.Server, common.PtrValueOrDefault(options.TLS))
+	tlsConfig, err := tls.NewClient(ctx, logger, options.Server, common.PtrValueOrDefault(options.TLS))
 	if err != nil {
 		return nil, err
 	}
-	outboundDialer, err := dialer.New(router, options.DialerOptions)
+	outboundDialer, err := dialer.NewServer(router, options.DialerOptions, options.ServerOptions)
+	if err != nil {
+		return nil, err
+	}
+	outboundDialer, err = newPortHoppingDialer(outboundDialer, options.ServerOptions, options.ServerPorts, options.HopInterval)
 	if err != nil {
 		return nil, err
 	}
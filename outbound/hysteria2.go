@@ -37,7 +37,7 @@ func NewHysteria2(ctx context.Context, router adapter.Router, logger log.Context
 	if options.TLS == nil || !options.TLS.Enabled {
 		return nil, C.ErrTLSRequired
 	}
-	tlsConfig, err := tls.NewClient(ctx, options.Server, common.PtrValueOrDefault(options.TLS))
+	tlsConfig, err := tls.NewClient(ctx, logger, options.Server, common.PtrValueOrDefault(options.TLS))
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +53,11 @@ func NewHysteria2(ctx context.Context, router adapter.Router, logger log.Context
 			return nil, E.New("unknown obfs type: ", options.Obfs.Type)
 		}
 	}
-	outboundDialer, err := dialer.New(router, options.DialerOptions)
+	outboundDialer, err := dialer.NewServer(router, options.DialerOptions, options.ServerOptions)
+	if err != nil {
+		return nil, err
+	}
+	outboundDialer, err = newPortHoppingDialer(outboundDialer, options.ServerOptions, options.ServerPorts, options.HopInterval)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,36 @@
+//go:build with_quic
+
+package outbound
+
+import (
+	"time"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing-box/transport/udphop"
+	E "github.com/sagernet/sing/common/exceptions"
+	N "github.com/sagernet/sing/common/network"
+)
+
+func newPortHoppingDialer(outboundDialer N.Dialer, serverOptions option.ServerOptions, serverPorts []string, hopInterval option.Duration) (N.Dialer, error) {
+	if len(serverPorts) == 0 {
+		return outboundDialer, nil
+	}
+	if serverOptions.ServerPort != 0 {
+		return nil, E.New("server_port and server_ports can not be set at the same time")
+	}
+	if len(serverOptions.ServerAddresses) > 0 || serverOptions.ServerDiscovery != nil {
+		return nil, E.New("server_ports is not supported with server_addresses or server_discovery")
+	}
+	ports, err := udphop.ParsePorts(serverPorts)
+	if err != nil {
+		return nil, E.Cause(err, "parse server_ports")
+	}
+	interval := time.Duration(hopInterval)
+	if interval == 0 {
+		interval = C.DefaultHopInterval
+	} else if interval < 5*time.Second {
+		return nil, E.New("hop_interval must be at least 5s")
+	}
+	return udphop.NewDialer(outboundDialer, ports, interval), nil
+}
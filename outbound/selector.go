@@ -128,6 +128,7 @@ func (s *Selector) SelectOutbound(tag string) bool {
 
 func (s *Selector) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
 	conn, err := s.selected.DialContext(ctx, network, destination)
+	recordGroupDial(ctx, s.selected, err)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +137,7 @@ func (s *Selector) DialContext(ctx context.Context, network string, destination
 
 func (s *Selector) ListenPacket(ctx context.Context, destination M.Socksaddr) (net.PacketConn, error) {
 	conn, err := s.selected.ListenPacket(ctx, destination)
+	recordGroupDial(ctx, s.selected, err)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +154,22 @@ func (s *Selector) NewPacketConnection(ctx context.Context, conn N.PacketConn, m
 	return s.selected.NewPacketConnection(ctx, conn, metadata)
 }
 
+// RealOutbound returns the outbound currently selected by the group, following nested groups.
+func RealOutbound(router adapter.Router, detour adapter.Outbound) (adapter.Outbound, error) {
+	for i := 0; i < 8; i++ {
+		group, isGroup := detour.(adapter.OutboundGroup)
+		if !isGroup {
+			break
+		}
+		selected, loaded := router.Outbound(group.Now())
+		if !loaded {
+			return nil, E.New("outbound not found: ", group.Now())
+		}
+		detour = selected
+	}
+	return detour, nil
+}
+
 func RealTag(detour adapter.Outbound) string {
 	if group, isGroup := detour.(adapter.OutboundGroup); isGroup {
 		return group.Now()
@@ -45,7 +45,10 @@ type SSH struct {
 }
 
 func NewSSH(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.SSHOutboundOptions) (*SSH, error) {
-	outboundDialer, err := dialer.New(router, options.DialerOptions)
+	if options.ServerPort == 0 {
+		options.ServerPort = 22
+	}
+	outboundDialer, err := dialer.NewServer(router, options.DialerOptions, options.ServerOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -65,9 +68,6 @@ func NewSSH(ctx context.Context, router adapter.Router, logger log.ContextLogger
 		hostKeyAlgorithms: options.HostKeyAlgorithms,
 		clientVersion:     options.ClientVersion,
 	}
-	if outbound.serverAddr.Port == 0 {
-		outbound.serverAddr.Port = 22
-	}
 	if outbound.user == "" {
 		outbound.user = "root"
 	}
@@ -203,6 +203,17 @@ func (t *Tor) Close() error {
 	return err
 }
 
+// TorInstance returns the running tor instance, or nil if not started.
+func (t *Tor) TorInstance() *tor.Tor {
+	return t.instance
+}
+
+// DataDirectory returns the configured data directory, or an empty string
+// if a temporary directory is used.
+func (t *Tor) DataDirectory() string {
+	return t.startConf.DataDir
+}
+
 func (t *Tor) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
 	t.logger.InfoContext(ctx, "outbound connection to ", destination)
 	return t.socksClient.DialContext(ctx, network, destination)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/failure"
 	"github.com/sagernet/sing-box/common/interrupt"
 	"github.com/sagernet/sing-box/common/urltest"
 	C "github.com/sagernet/sing-box/constant"
@@ -18,6 +19,7 @@ import (
 	E "github.com/sagernet/sing/common/exceptions"
 	M "github.com/sagernet/sing/common/metadata"
 	N "github.com/sagernet/sing/common/network"
+	"github.com/sagernet/sing/common/x/list"
 	"github.com/sagernet/sing/service"
 	"github.com/sagernet/sing/service/pause"
 )
@@ -38,6 +40,7 @@ type URLTest struct {
 	idleTimeout                  time.Duration
 	group                        *URLTestGroup
 	interruptExternalConnections bool
+	failureThreshold             uint32
 }
 
 func NewURLTest(ctx context.Context, router adapter.Router, logger log.ContextLogger, tag string, options option.URLTestOutboundOptions) (*URLTest, error) {
@@ -57,6 +60,7 @@ func NewURLTest(ctx context.Context, router adapter.Router, logger log.ContextLo
 		tolerance:                    options.Tolerance,
 		idleTimeout:                  time.Duration(options.IdleTimeout),
 		interruptExternalConnections: options.InterruptExistConnections,
+		failureThreshold:             options.FailureThreshold,
 	}
 	if len(outbound.tags) == 0 {
 		return nil, E.New("missing tags")
@@ -83,6 +87,7 @@ func (s *URLTest) Start() error {
 		s.tolerance,
 		s.idleTimeout,
 		s.interruptExternalConnections,
+		s.failureThreshold,
 	)
 	if err != nil {
 		return err
@@ -141,6 +146,7 @@ func (s *URLTest) DialContext(ctx context.Context, network string, destination M
 		return nil, E.New("missing supported outbound")
 	}
 	conn, err := outbound.DialContext(ctx, network, destination)
+	recordGroupDial(ctx, outbound, err)
 	if err == nil {
 		return s.group.interruptGroup.NewConn(conn, interrupt.IsExternalConnectionFromContext(ctx)), nil
 	}
@@ -159,6 +165,7 @@ func (s *URLTest) ListenPacket(ctx context.Context, destination M.Socksaddr) (ne
 		return nil, E.New("missing supported outbound")
 	}
 	conn, err := outbound.ListenPacket(ctx, destination)
+	recordGroupDial(ctx, outbound, err)
 	if err == nil {
 		return s.group.interruptGroup.NewPacketConn(conn, interrupt.IsExternalConnectionFromContext(ctx)), nil
 	}
@@ -198,6 +205,9 @@ type URLTestGroup struct {
 	selectedOutboundUDP          adapter.Outbound
 	interruptGroup               *interrupt.Group
 	interruptExternalConnections bool
+	failureThreshold             uint32
+	failureStatistics            *failure.Statistics
+	failureCallback              *list.Element[failure.FailureCallback]
 
 	access     sync.Mutex
 	ticker     *time.Ticker
@@ -216,6 +226,7 @@ func NewURLTestGroup(
 	tolerance uint16,
 	idleTimeout time.Duration,
 	interruptExternalConnections bool,
+	failureThreshold uint32,
 ) (*URLTestGroup, error) {
 	if interval == 0 {
 		interval = C.DefaultURLTestInterval
@@ -250,15 +261,39 @@ func NewURLTestGroup(
 		pauseManager:                 service.FromContext[pause.Manager](ctx),
 		interruptGroup:               interrupt.NewGroup(),
 		interruptExternalConnections: interruptExternalConnections,
+		failureThreshold:             failureThreshold,
+		failureStatistics:            service.PtrFromContext[failure.Statistics](ctx),
 	}, nil
 }
 
 func (g *URLTestGroup) PostStart() {
 	g.started = true
 	g.lastActive.Store(time.Now())
+	if g.failureThreshold > 0 && g.failureStatistics != nil {
+		g.failureCallback = g.failureStatistics.RegisterCallback(g.onFailure)
+	}
 	go g.CheckOutbounds(false)
 }
 
+func (g *URLTestGroup) onFailure(outbound string, consecutiveFailures uint64) {
+	if consecutiveFailures != uint64(g.failureThreshold) {
+		return
+	}
+	var selected bool
+	for _, detour := range []adapter.Outbound{g.selectedOutboundTCP, g.selectedOutboundUDP} {
+		if detour != nil && RealTag(detour) == outbound {
+			selected = true
+			break
+		}
+	}
+	if !selected {
+		return
+	}
+	g.logger.Info("outbound ", outbound, " failed ", consecutiveFailures, " times in a row, re-testing")
+	g.history.DeleteURLTestHistory(outbound)
+	go g.CheckOutbounds(true)
+}
+
 func (g *URLTestGroup) Touch() {
 	if !g.started {
 		return
@@ -277,6 +312,10 @@ func (g *URLTestGroup) Touch() {
 }
 
 func (g *URLTestGroup) Close() error {
+	if g.failureCallback != nil {
+		g.failureStatistics.UnregisterCallback(g.failureCallback)
+		g.failureCallback = nil
+	}
 	if g.ticker == nil {
 		return nil
 	}
@@ -32,6 +32,7 @@ import (
 
 var (
 	_ adapter.Outbound                = (*WireGuard)(nil)
+	_ adapter.ICMPOutbound            = (*WireGuard)(nil)
 	_ adapter.InterfaceUpdateListener = (*WireGuard)(nil)
 )
 
@@ -241,3 +242,8 @@ func (w *WireGuard) NewConnection(ctx context.Context, conn net.Conn, metadata a
 func (w *WireGuard) NewPacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
 	return NewDirectPacketConnection(ctx, w.router, w, conn, metadata, dns.DomainStrategyAsIS)
 }
+
+func (w *WireGuard) NewICMPEcho(ctx context.Context, request *adapter.ICMPEchoRequest, metadata adapter.InboundContext) error {
+	w.logger.DebugContext(ctx, "outbound icmp echo to ", request.Destination)
+	return w.tunDevice.NewICMPEcho(ctx, request)
+}
@@ -0,0 +1,400 @@
+package route
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-dns"
+	"github.com/sagernet/sing/common"
+	"github.com/sagernet/sing/common/cache"
+	"github.com/sagernet/sing/common/logger"
+	"github.com/sagernet/sing/service"
+
+	mDNS "github.com/miekg/dns"
+)
+
+const (
+	dnsCacheSize = 8192
+	// dnsStaleTTL is the TTL of stale answers, as recommended by RFC 8767.
+	dnsStaleTTL = 30
+	// An entry is prefetched when it is hit at least dnsPrefetchMinHits times
+	// and less than 1/dnsPrefetchRatio of its TTL remains.
+	dnsPrefetchMinHits = 2
+	dnsPrefetchRatio   = 10
+)
+
+type dnsRefreshKey struct{}
+
+func contextWithDNSRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, (*dnsRefreshKey)(nil), true)
+}
+
+func isDNSRefresh(ctx context.Context) bool {
+	return ctx.Value((*dnsRefreshKey)(nil)) != nil
+}
+
+type dnsCacheEntry struct {
+	message  *mDNS.Msg
+	ttl      uint32
+	expireAt time.Time
+	hits     atomic.Int32
+}
+
+// dnsCache is the router level DNS cache used when serve-stale, prefetch
+// or persistence is enabled. It is checked after the client cache.
+type dnsCache struct {
+	ctx           context.Context
+	logger        logger.ContextLogger
+	disableExpire bool
+	serveStale    bool
+	staleTimeout  time.Duration
+	prefetch      bool
+	refresh       func(ctx context.Context, question mDNS.Question, allowFakeIP bool)
+	cacheFile     adapter.CacheFile
+	entries       *cache.LruCache[mDNS.Question, *dnsCacheEntry]
+	refreshAccess sync.Mutex
+	refreshing    map[mDNS.Question]bool
+}
+
+func newDNSCache(ctx context.Context, logger logger.ContextLogger, disableExpire bool, serveStale bool, staleTimeout time.Duration, prefetch bool, refresh func(ctx context.Context, question mDNS.Question, allowFakeIP bool)) *dnsCache {
+	return &dnsCache{
+		ctx:           ctx,
+		logger:        logger,
+		disableExpire: disableExpire,
+		serveStale:    serveStale,
+		staleTimeout:  staleTimeout,
+		prefetch:      prefetch,
+		refresh:       refresh,
+		entries:       cache.New(cache.WithSize[mDNS.Question, *dnsCacheEntry](dnsCacheSize)),
+		refreshing:    make(map[mDNS.Question]bool),
+	}
+}
+
+// Start loads the persisted cache and reports whether the cache is in use.
+func (c *dnsCache) Start() bool {
+	cacheFile := service.FromContext[adapter.CacheFile](c.ctx)
+	if cacheFile == nil || !cacheFile.StoreDNS() {
+		return c.serveStale || c.prefetch
+	}
+	c.cacheFile = cacheFile
+	var staleTimeout time.Duration
+	if c.serveStale {
+		staleTimeout = c.staleTimeout
+	}
+	savedCache := cacheFile.LoadDNSCache(staleTimeout)
+	for _, saved := range savedCache {
+		removeOPT(saved.Message)
+		c.entries.Store(saved.Message.Question[0], &dnsCacheEntry{
+			message:  saved.Message,
+			ttl:      messageTTL(saved.Message),
+			expireAt: saved.ExpireAt,
+		})
+	}
+	if len(savedCache) > 0 {
+		c.logger.Debug("loaded ", len(savedCache), " DNS cache entries")
+	}
+	return true
+}
+
+func (c *dnsCache) Clear() {
+	c.entries.Clear()
+}
+
+// isCacheableRequest reports whether the response can be shared with other clients,
+// EDNS0 options are ignored except client subnet.
+func isCacheableRequest(ctx context.Context, message *mDNS.Msg) bool {
+	if len(message.Question) != 1 || len(message.Ns) > 0 {
+		return false
+	}
+	for _, record := range message.Extra {
+		opt, isOPT := record.(*mDNS.OPT)
+		if !isOPT {
+			return false
+		}
+		for _, option := range opt.Option {
+			if option.Option() == mDNS.EDNS0SUBNET {
+				return false
+			}
+		}
+	}
+	if _, clientSubnetLoaded := dns.ClientSubnetFromContext(ctx); clientSubnetLoaded {
+		return false
+	}
+	return !dns.DisableCacheFromContext(ctx)
+}
+
+func (c *dnsCache) LoadExchange(ctx context.Context, message *mDNS.Msg) (*mDNS.Msg, bool) {
+	if isDNSRefresh(ctx) || !isCacheableRequest(ctx, message) {
+		return nil, false
+	}
+	response, loaded := c.load(ctx, message.Question[0], true)
+	if !loaded {
+		return nil, false
+	}
+	response.Id = message.Id
+	if opt := message.IsEdns0(); opt != nil {
+		response.SetEdns0(opt.UDPSize(), opt.Do())
+	}
+	return response, true
+}
+
+// TouchExchange counts a hit of the client cache, so the entry is still prefetched.
+func (c *dnsCache) TouchExchange(ctx context.Context, message *mDNS.Msg) {
+	if !c.prefetch || isDNSRefresh(ctx) || !isCacheableRequest(ctx, message) {
+		return
+	}
+	c.touch(ctx, message.Question[0], true)
+}
+
+// TouchLookup counts a hit of the client cache, so the entries are still prefetched.
+func (c *dnsCache) TouchLookup(ctx context.Context, domain string, strategy dns.DomainStrategy) {
+	if !c.prefetch || isDNSRefresh(ctx) || dns.DisableCacheFromContext(ctx) {
+		return
+	}
+	dnsName := mDNS.Fqdn(domain)
+	if strategy != dns.DomainStrategyUseIPv6 {
+		c.touch(ctx, mDNS.Question{Name: dnsName, Qtype: mDNS.TypeA, Qclass: mDNS.ClassINET}, false)
+	}
+	if strategy != dns.DomainStrategyUseIPv4 {
+		c.touch(ctx, mDNS.Question{Name: dnsName, Qtype: mDNS.TypeAAAA, Qclass: mDNS.ClassINET}, false)
+	}
+}
+
+func (c *dnsCache) touch(ctx context.Context, question mDNS.Question, allowFakeIP bool) {
+	entry, loaded := c.entries.Load(question)
+	if !loaded || c.disableExpire {
+		return
+	}
+	timeNow := time.Now()
+	if timeNow.Before(entry.expireAt) {
+		c.countHit(ctx, question, entry, uint32(entry.expireAt.Sub(timeNow)/time.Second), allowFakeIP)
+	}
+}
+
+func (c *dnsCache) countHit(ctx context.Context, question mDNS.Question, entry *dnsCacheEntry, timeToLive uint32, allowFakeIP bool) {
+	hits := entry.hits.Add(1)
+	if c.prefetch && hits >= dnsPrefetchMinHits && timeToLive*dnsPrefetchRatio <= entry.ttl {
+		c.refreshAsync(ctx, question, allowFakeIP)
+	}
+}
+
+func (c *dnsCache) LoadLookup(ctx context.Context, domain string, strategy dns.DomainStrategy) ([]netip.Addr, bool) {
+	if isDNSRefresh(ctx) || dns.DisableCacheFromContext(ctx) {
+		return nil, false
+	}
+	dnsName := mDNS.Fqdn(domain)
+	loadAddresses := func(qType uint16) ([]netip.Addr, bool) {
+		response, loaded := c.load(ctx, mDNS.Question{
+			Name:   dnsName,
+			Qtype:  qType,
+			Qclass: mDNS.ClassINET,
+		}, false)
+		if !loaded {
+			return nil, false
+		}
+		addresses, _ := dns.MessageToAddresses(response)
+		return addresses, true
+	}
+	switch strategy {
+	case dns.DomainStrategyUseIPv4:
+		return loadAddresses(mDNS.TypeA)
+	case dns.DomainStrategyUseIPv6:
+		return loadAddresses(mDNS.TypeAAAA)
+	default:
+		response4, _ := loadAddresses(mDNS.TypeA)
+		response6, _ := loadAddresses(mDNS.TypeAAAA)
+		if len(response4) == 0 && len(response6) == 0 {
+			return nil, false
+		}
+		if strategy == dns.DomainStrategyPreferIPv6 {
+			return append(response6, response4...), true
+		} else {
+			return append(response4, response6...), true
+		}
+	}
+}
+
+func (c *dnsCache) load(ctx context.Context, question mDNS.Question, allowFakeIP bool) (*mDNS.Msg, bool) {
+	entry, loaded := c.entries.Load(question)
+	if !loaded {
+		return nil, false
+	}
+	response := entry.message.Copy()
+	if c.disableExpire {
+		logCachedResponse(c.logger, ctx, "cached ", response, int(entry.ttl))
+		return response, true
+	}
+	var timeToLive uint32
+	timeNow := time.Now()
+	if timeNow.Before(entry.expireAt) {
+		timeToLive = uint32(entry.expireAt.Sub(timeNow) / time.Second)
+		c.countHit(ctx, question, entry, timeToLive, allowFakeIP)
+	} else if c.serveStale && timeNow.Before(entry.expireAt.Add(c.staleTimeout)) {
+		timeToLive = dnsStaleTTL
+		c.refreshAsync(ctx, question, allowFakeIP)
+	} else {
+		c.entries.Delete(question)
+		return nil, false
+	}
+	for _, recordList := range [][]mDNS.RR{response.Answer, response.Ns, response.Extra} {
+		for _, record := range recordList {
+			record.Header().Ttl = timeToLive
+		}
+	}
+	if timeNow.Before(entry.expireAt) {
+		logCachedResponse(c.logger, ctx, "cached ", response, int(timeToLive))
+	} else {
+		logCachedResponse(c.logger, ctx, "stale cached ", response, int(timeToLive))
+	}
+	return response, true
+}
+
+func (c *dnsCache) refreshAsync(ctx context.Context, question mDNS.Question, allowFakeIP bool) {
+	c.refreshAccess.Lock()
+	if c.refreshing[question] {
+		c.refreshAccess.Unlock()
+		return
+	}
+	c.refreshing[question] = true
+	c.refreshAccess.Unlock()
+	var metadata adapter.InboundContext
+	if originMetadata := adapter.ContextFrom(ctx); originMetadata != nil {
+		metadata = *originMetadata
+	}
+	refreshCtx := adapter.WithContext(log.ContextWithNewID(c.ctx), &metadata)
+	refreshCtx = dns.ContextWithDisableCache(contextWithDNSRefresh(refreshCtx), true)
+	go func() {
+		c.refresh(refreshCtx, question, allowFakeIP)
+		c.refreshAccess.Lock()
+		delete(c.refreshing, question)
+		c.refreshAccess.Unlock()
+	}()
+}
+
+func (c *dnsCache) Store(response *mDNS.Msg) {
+	if len(response.Question) != 1 {
+		return
+	}
+	if response.Rcode != mDNS.RcodeSuccess && response.Rcode != mDNS.RcodeNameError {
+		return
+	}
+	message := response.Copy()
+	removeOPT(message)
+	timeToLive := messageTTL(message)
+	if timeToLive == 0 {
+		return
+	}
+	entry := &dnsCacheEntry{
+		message:  message,
+		ttl:      timeToLive,
+		expireAt: time.Now().Add(time.Duration(timeToLive) * time.Second),
+	}
+	c.entries.Store(response.Question[0], entry)
+	if c.cacheFile != nil {
+		c.cacheFile.SaveDNSCacheAsync(entry.message, entry.expireAt, c.logger)
+	}
+}
+
+// StoreLookup caches lookup results of transports without raw query support,
+// which return no records, with the default TTL as the DNS client does.
+// Responses of raw transports are cached with their record TTLs by Store.
+func (c *dnsCache) StoreLookup(ctx context.Context, domain string, strategy dns.DomainStrategy, addresses []netip.Addr) {
+	timeToLive, loaded := dns.RewriteTTLFromContext(ctx)
+	if !loaded {
+		timeToLive = dns.DefaultTTL
+	}
+	dnsName := mDNS.Fqdn(domain)
+	storeAddresses := func(qType uint16, addresses []netip.Addr) {
+		response := &mDNS.Msg{
+			MsgHdr: mDNS.MsgHdr{
+				Response: true,
+				Rcode:    mDNS.RcodeSuccess,
+			},
+			Question: []mDNS.Question{{
+				Name:   dnsName,
+				Qtype:  qType,
+				Qclass: mDNS.ClassINET,
+			}},
+		}
+		for _, address := range addresses {
+			address = address.Unmap()
+			header := mDNS.RR_Header{
+				Name:   dnsName,
+				Rrtype: qType,
+				Class:  mDNS.ClassINET,
+				Ttl:    timeToLive,
+			}
+			if qType == mDNS.TypeA {
+				response.Answer = append(response.Answer, &mDNS.A{Hdr: header, A: address.AsSlice()})
+			} else {
+				response.Answer = append(response.Answer, &mDNS.AAAA{Hdr: header, AAAA: address.AsSlice()})
+			}
+		}
+		if len(response.Answer) == 0 {
+			return
+		}
+		c.Store(response)
+	}
+	if strategy != dns.DomainStrategyUseIPv6 {
+		storeAddresses(mDNS.TypeA, common.Filter(addresses, func(it netip.Addr) bool {
+			return it.Is4() || it.Is4In6()
+		}))
+	}
+	if strategy != dns.DomainStrategyUseIPv4 {
+		storeAddresses(mDNS.TypeAAAA, common.Filter(addresses, func(it netip.Addr) bool {
+			return it.Is6() && !it.Is4In6()
+		}))
+	}
+}
+
+// removeOPT removes the EDNS0 record, which belongs to the request and not to the cached answer.
+func removeOPT(message *mDNS.Msg) {
+	message.Extra = common.Filter(message.Extra, func(it mDNS.RR) bool {
+		_, isOPT := it.(*mDNS.OPT)
+		return !isOPT
+	})
+}
+
+// dnsLookupTransport records the responses of a lookup, so they can be cached with their record TTLs.
+type dnsLookupTransport struct {
+	dns.Transport
+	access    sync.Mutex
+	responses []*mDNS.Msg
+}
+
+func (t *dnsLookupTransport) Exchange(ctx context.Context, message *mDNS.Msg) (*mDNS.Msg, error) {
+	response, err := t.Transport.Exchange(ctx, message)
+	if err == nil {
+		t.access.Lock()
+		t.responses = append(t.responses, response)
+		t.access.Unlock()
+	}
+	return response, err
+}
+
+func messageTTL(message *mDNS.Msg) uint32 {
+	var timeToLive uint32
+	for _, recordList := range [][]mDNS.RR{message.Answer, message.Ns, message.Extra} {
+		for _, record := range recordList {
+			if timeToLive == 0 || record.Header().Ttl > 0 && record.Header().Ttl < timeToLive {
+				timeToLive = record.Header().Ttl
+			}
+		}
+	}
+	return timeToLive
+}
+
+func logCachedResponse(logger logger.ContextLogger, ctx context.Context, prefix string, response *mDNS.Msg, ttl int) {
+	domain := fqdnToDomain(response.Question[0].Name)
+	logger.DebugContext(ctx, prefix, domain, " ", mDNS.RcodeToString[response.Rcode], " ", ttl)
+	for _, recordList := range [][]mDNS.RR{response.Answer, response.Ns, response.Extra} {
+		for _, record := range recordList {
+			logger.InfoContext(ctx, prefix, domain, " ", mDNS.Type(record.Header().Rrtype).String(), " ", formatQuestion(record.String()))
+		}
+	}
+}
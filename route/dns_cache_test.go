@@ -0,0 +1,133 @@
+package route
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sagernet/sing-box/log"
+	"github.com/sagernet/sing-dns"
+
+	mDNS "github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSCacheServeStale(t *testing.T) {
+	t.Parallel()
+	refreshed := make(chan mDNS.Question, 1)
+	cache := newDNSCache(context.Background(), log.NewNOPFactory().NewLogger("dns"), false, true, time.Hour, true, func(ctx context.Context, question mDNS.Question, allowFakeIP bool) {
+		refreshed <- question
+	})
+	question := mDNS.Question{Name: "example.com.", Qtype: mDNS.TypeA, Qclass: mDNS.ClassINET}
+	cache.Store(&mDNS.Msg{
+		MsgHdr:   mDNS.MsgHdr{Response: true},
+		Question: []mDNS.Question{question},
+		Answer: []mDNS.RR{&mDNS.A{
+			Hdr: mDNS.RR_Header{Name: question.Name, Rrtype: mDNS.TypeA, Class: mDNS.ClassINET, Ttl: 60},
+			A:   net.IPv4(1, 1, 1, 1),
+		}},
+	})
+	query := &mDNS.Msg{MsgHdr: mDNS.MsgHdr{Id: 1}, Question: []mDNS.Question{question}}
+
+	response, loaded := cache.LoadExchange(context.Background(), query)
+	require.True(t, loaded)
+	require.Equal(t, uint16(1), response.Id)
+	require.Len(t, refreshed, 0)
+
+	entry, _ := cache.entries.Load(question)
+	entry.expireAt = time.Now().Add(-time.Minute)
+	response, loaded = cache.LoadExchange(context.Background(), query)
+	require.True(t, loaded)
+	require.Equal(t, uint32(dnsStaleTTL), response.Answer[0].Header().Ttl)
+	require.Equal(t, question, <-refreshed)
+
+	entry.expireAt = time.Now().Add(-2 * time.Hour)
+	_, loaded = cache.LoadExchange(context.Background(), query)
+	require.False(t, loaded)
+}
+
+func TestDNSCachePrefetch(t *testing.T) {
+	t.Parallel()
+	refreshed := make(chan mDNS.Question, 1)
+	cache := newDNSCache(context.Background(), log.NewNOPFactory().NewLogger("dns"), false, false, 0, true, func(ctx context.Context, question mDNS.Question, allowFakeIP bool) {
+		refreshed <- question
+	})
+	question := mDNS.Question{Name: "example.com.", Qtype: mDNS.TypeA, Qclass: mDNS.ClassINET}
+	cache.Store(&mDNS.Msg{
+		MsgHdr:   mDNS.MsgHdr{Response: true},
+		Question: []mDNS.Question{question},
+		Answer: []mDNS.RR{&mDNS.A{
+			Hdr: mDNS.RR_Header{Name: question.Name, Rrtype: mDNS.TypeA, Class: mDNS.ClassINET, Ttl: 100},
+			A:   net.IPv4(1, 1, 1, 1),
+		}},
+	})
+	entry, _ := cache.entries.Load(question)
+	entry.expireAt = time.Now().Add(5 * time.Second)
+	addresses, loaded := cache.LoadLookup(context.Background(), "example.com", dns.DomainStrategyUseIPv4)
+	require.True(t, loaded)
+	require.Len(t, addresses, 1)
+	require.Len(t, refreshed, 0)
+	_, loaded = cache.LoadLookup(context.Background(), "example.com", dns.DomainStrategyUseIPv4)
+	require.True(t, loaded)
+	require.Equal(t, question, <-refreshed)
+}
+
+func TestDNSCacheEDNS0(t *testing.T) {
+	t.Parallel()
+	cache := newDNSCache(context.Background(), log.NewNOPFactory().NewLogger("dns"), false, true, time.Hour, false, nil)
+	question := mDNS.Question{Name: "example.com.", Qtype: mDNS.TypeA, Qclass: mDNS.ClassINET}
+	query := &mDNS.Msg{MsgHdr: mDNS.MsgHdr{Id: 1}, Question: []mDNS.Question{question}}
+	query.SetEdns0(1232, false)
+	require.True(t, isCacheableRequest(context.Background(), query))
+
+	subnetQuery := query.Copy()
+	subnetQuery.IsEdns0().Option = append(subnetQuery.IsEdns0().Option, &mDNS.EDNS0_SUBNET{Code: mDNS.EDNS0SUBNET, Family: 1})
+	require.False(t, isCacheableRequest(context.Background(), subnetQuery))
+
+	response := &mDNS.Msg{
+		MsgHdr:   mDNS.MsgHdr{Response: true},
+		Question: []mDNS.Question{question},
+		Answer: []mDNS.RR{&mDNS.A{
+			Hdr: mDNS.RR_Header{Name: question.Name, Rrtype: mDNS.TypeA, Class: mDNS.ClassINET, Ttl: 60},
+			A:   net.IPv4(1, 1, 1, 1),
+		}},
+	}
+	response.SetEdns0(4096, true)
+	cache.Store(response)
+	entry, loaded := cache.entries.Load(question)
+	require.True(t, loaded)
+	require.Nil(t, entry.message.IsEdns0())
+	require.Equal(t, uint32(60), entry.ttl)
+
+	cached, loaded := cache.LoadExchange(context.Background(), query)
+	require.True(t, loaded)
+	require.NotNil(t, cached.IsEdns0())
+	require.Equal(t, uint16(1232), cached.IsEdns0().UDPSize())
+	cached, loaded = cache.LoadExchange(context.Background(), &mDNS.Msg{Question: []mDNS.Question{question}})
+	require.True(t, loaded)
+	require.Nil(t, cached.IsEdns0())
+}
+
+func TestDNSCacheTouch(t *testing.T) {
+	t.Parallel()
+	refreshed := make(chan mDNS.Question, 1)
+	cache := newDNSCache(context.Background(), log.NewNOPFactory().NewLogger("dns"), false, false, 0, true, func(ctx context.Context, question mDNS.Question, allowFakeIP bool) {
+		refreshed <- question
+	})
+	question := mDNS.Question{Name: "example.com.", Qtype: mDNS.TypeA, Qclass: mDNS.ClassINET}
+	cache.Store(&mDNS.Msg{
+		MsgHdr:   mDNS.MsgHdr{Response: true},
+		Question: []mDNS.Question{question},
+		Answer: []mDNS.RR{&mDNS.A{
+			Hdr: mDNS.RR_Header{Name: question.Name, Rrtype: mDNS.TypeA, Class: mDNS.ClassINET, Ttl: 100},
+			A:   net.IPv4(1, 1, 1, 1),
+		}},
+	})
+	entry, _ := cache.entries.Load(question)
+	entry.expireAt = time.Now().Add(5 * time.Second)
+	cache.TouchLookup(context.Background(), "example.com", dns.DomainStrategyUseIPv4)
+	require.Len(t, refreshed, 0)
+	cache.TouchExchange(context.Background(), &mDNS.Msg{Question: []mDNS.Question{question}})
+	require.Equal(t, question, <-refreshed)
+}
@@ -10,14 +10,17 @@ import (
 	"os/user"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sagernet/sing-box/adapter"
 	"github.com/sagernet/sing-box/common/conntrack"
 	"github.com/sagernet/sing-box/common/dialer"
+	"github.com/sagernet/sing-box/common/failure"
 	"github.com/sagernet/sing-box/common/geoip"
 	"github.com/sagernet/sing-box/common/geosite"
 	"github.com/sagernet/sing-box/common/process"
+	"github.com/sagernet/sing-box/common/ratelimit"
 	"github.com/sagernet/sing-box/common/sniff"
 	"github.com/sagernet/sing-box/common/taskmonitor"
 	C "github.com/sagernet/sing-box/constant"
@@ -31,6 +34,7 @@ import (
 	"github.com/sagernet/sing-tun"
 	"github.com/sagernet/sing-vmess"
 	"github.com/sagernet/sing/common"
+	"github.com/sagernet/sing/common/atomic"
 	"github.com/sagernet/sing/common/buf"
 	"github.com/sagernet/sing/common/bufio"
 	"github.com/sagernet/sing/common/bufio/deadline"
@@ -43,6 +47,7 @@ import (
 	"github.com/sagernet/sing/common/task"
 	"github.com/sagernet/sing/common/uot"
 	"github.com/sagernet/sing/common/winpowrprof"
+	"github.com/sagernet/sing/common/x/list"
 	"github.com/sagernet/sing/service"
 	"github.com/sagernet/sing/service/pause"
 )
@@ -53,6 +58,7 @@ type Router struct {
 	ctx                                context.Context
 	logger                             log.ContextLogger
 	dnsLogger                          log.ContextLogger
+	inboundAccess                      sync.RWMutex
 	inboundByTag                       map[string]adapter.Inbound
 	outbounds                          []adapter.Outbound
 	outboundByTag                      map[string]adapter.Outbound
@@ -61,14 +67,17 @@ type Router struct {
 	defaultOutboundForConnection       adapter.Outbound
 	defaultOutboundForPacketConnection adapter.Outbound
 	needGeoIPDatabase                  bool
+	needASNDatabase                    bool
 	needGeositeDatabase                bool
 	geoIPOptions                       option.GeoIPOptions
 	geositeOptions                     option.GeositeOptions
 	geoIPReader                        *geoip.Reader
+	asnReader                          atomic.Pointer[geoip.Reader]
 	geositeReader                      *geosite.Reader
 	geositeCache                       map[string]adapter.Rule
 	needFindProcess                    bool
 	dnsClient                          *dns.Client
+	dnsCache                           *dnsCache
 	defaultDomainStrategy              dns.DomainStrategy
 	dnsRules                           []adapter.DNSRule
 	ruleSets                           []adapter.RuleSet
@@ -91,12 +100,22 @@ type Router struct {
 	processSearcher                    process.Searcher
 	timeService                        *ntp.Service
 	pauseManager                       pause.Manager
+	failureStatistics                  *failure.Statistics
 	clashServer                        adapter.ClashServer
 	v2rayServer                        adapter.V2RayServer
 	platformInterface                  platform.Interface
 	needWIFIState                      bool
 	needPackageManager                 bool
 	wifiState                          adapter.WIFIState
+	schedules                          []adapter.ScheduleMatcher
+	scheduleEnabled                    atomic.Bool
+	scheduleInterrupt                  bool
+	scheduleAccess                     sync.Mutex
+	scheduleConnections                list.List[*scheduleConnection]
+	rateLimiters                       map[string]*rateLimiter
+	inboundLimiters                    map[string][]*rateLimiter
+	outboundLimiters                   map[string][]*rateLimiter
+	ruleShaping                        map[adapter.Rule]ruleShaping
 	started                            bool
 }
 
@@ -118,6 +137,7 @@ func NewRouter(
 		dnsRules:              make([]adapter.DNSRule, 0, len(dnsOptions.Rules)),
 		ruleSetMap:            make(map[string]adapter.RuleSet),
 		needGeoIPDatabase:     hasRule(options.Rules, isGeoIPRule) || hasDNSRule(dnsOptions.Rules, isGeoIPDNSRule),
+		needASNDatabase:       hasRule(options.Rules, isASNRule) || hasDNSRule(dnsOptions.Rules, isASNDNSRule) || common.PtrValueOrDefault(options.GeoIP).ASNPath != "",
 		needGeositeDatabase:   hasRule(options.Rules, isGeositeRule) || hasDNSRule(dnsOptions.Rules, isGeositeDNSRule),
 		geoIPOptions:          common.PtrValueOrDefault(options.GeoIP),
 		geositeOptions:        common.PtrValueOrDefault(options.Geosite),
@@ -130,8 +150,11 @@ func NewRouter(
 		defaultInterface:      options.DefaultInterface,
 		defaultMark:           options.DefaultMark,
 		pauseManager:          service.FromContext[pause.Manager](ctx),
+		failureStatistics:     service.PtrFromContext[failure.Statistics](ctx),
 		platformInterface:     platformInterface,
 		needWIFIState:         hasRule(options.Rules, isWIFIRule) || hasDNSRule(dnsOptions.Rules, isWIFIDNSRule),
+		schedules:             append(collectSchedules(options.Rules), collectDNSSchedules(dnsOptions.Rules)...),
+		scheduleInterrupt:     options.ScheduleInterruptExistConnections,
 		needPackageManager: common.Any(inbounds, func(inbound option.Inbound) bool {
 			return len(inbound.TunOptions.IncludePackage) > 0 || len(inbound.TunOptions.ExcludePackage) > 0
 		}),
@@ -152,6 +175,22 @@ func NewRouter(
 		},
 		Logger: router.dnsLogger,
 	})
+	clientOptions := dnsOptions.DNSClientOptions
+	if clientOptions.ServeStale || clientOptions.Prefetch {
+		if clientOptions.DisableCache {
+			return nil, E.New("serve_stale and prefetch require DNS cache")
+		}
+		if clientOptions.IndependentCache {
+			return nil, E.New("serve_stale and prefetch are not supported with independent_cache")
+		}
+	}
+	if !clientOptions.DisableCache && !clientOptions.IndependentCache {
+		staleTimeout := time.Duration(clientOptions.ServeStaleTimeout)
+		if staleTimeout == 0 {
+			staleTimeout = C.DNSServeStaleTimeout
+		}
+		router.dnsCache = newDNSCache(ctx, router.dnsLogger, clientOptions.DisableExpire, clientOptions.ServeStale, staleTimeout, clientOptions.Prefetch, router.refreshDNS)
+	}
 	for i, ruleOptions := range options.Rules {
 		routeRule, err := NewRule(router, router.logger, ruleOptions, true)
 		if err != nil {
@@ -159,6 +198,10 @@ func NewRouter(
 		}
 		router.rules = append(router.rules, routeRule)
 	}
+	err := router.initializeShaping(options)
+	if err != nil {
+		return nil, err
+	}
 	for i, dnsRuleOptions := range dnsOptions.Rules {
 		dnsRule, err := NewDNSRule(router, router.logger, dnsRuleOptions, true)
 		if err != nil {
@@ -423,7 +466,9 @@ func (r *Router) Initialize(inbounds []adapter.Inbound, outbounds []adapter.Outb
 		outbounds = append(outbounds, detour)
 		outboundByTag[detour.Tag()] = detour
 	}
+	r.inboundAccess.Lock()
 	r.inboundByTag = inboundByTag
+	r.inboundAccess.Unlock()
 	r.outbounds = outbounds
 	r.defaultOutboundForConnection = defaultOutboundForConnection
 	r.defaultOutboundForPacketConnection = defaultOutboundForPacketConnection
@@ -482,6 +527,14 @@ func (r *Router) Start() error {
 			return err
 		}
 	}
+	if r.needASNDatabase {
+		monitor.Start("initialize asn database")
+		err := r.prepareASNDatabase()
+		monitor.Finish()
+		if err != nil {
+			return err
+		}
+	}
 	if r.needGeositeDatabase {
 		monitor.Start("initialize geosite database")
 		err := r.prepareGeositeDatabase()
@@ -531,6 +584,9 @@ func (r *Router) Start() error {
 
 	monitor.Start("initialize DNS client")
 	r.dnsClient.Start()
+	if r.dnsCache != nil && !r.dnsCache.Start() {
+		r.dnsCache = nil
+	}
 	monitor.Finish()
 
 	if C.IsAndroid && r.platformInterface == nil {
@@ -612,6 +668,13 @@ func (r *Router) Close() error {
 		})
 		monitor.Finish()
 	}
+	if asnReader := r.asnReader.Load(); asnReader != nil && asnReader != r.geoIPReader {
+		monitor.Start("close asn reader")
+		err = E.Append(err, asnReader.Close(), func(err error) error {
+			return E.Cause(err, "close asn reader")
+		})
+		monitor.Finish()
+	}
 	if r.interfaceMonitor != nil {
 		monitor.Start("close interface monitor")
 		err = E.Append(err, r.interfaceMonitor.Close(), func(err error) error {
@@ -684,8 +747,14 @@ func (r *Router) PostStart() error {
 	}
 	needFindProcess := r.needFindProcess
 	needWIFIState := r.needWIFIState
+	// remote rule sets may contain schedules after an update
+	needSchedule := len(r.schedules) > 0 || len(r.ruleSets) > 0
+	needASNDatabase := r.needASNDatabase
 	for _, ruleSet := range r.ruleSets {
 		metadata := ruleSet.Metadata()
+		if metadata.ContainsASNRule {
+			needASNDatabase = true
+		}
 		if metadata.ContainsProcessRule {
 			needFindProcess = true
 		}
@@ -693,6 +762,18 @@ func (r *Router) PostStart() error {
 			needWIFIState = true
 		}
 	}
+	if needASNDatabase && !r.needASNDatabase {
+		monitor.Start("initialize asn database")
+		err := r.prepareASNDatabase()
+		monitor.Finish()
+		if err != nil {
+			return err
+		}
+	}
+	if needSchedule {
+		r.scheduleEnabled.Store(len(r.scheduleState(time.Now())) > 0)
+		go r.loopSchedule()
+	}
 	if C.IsAndroid && r.platformInterface == nil && !r.needPackageManager {
 		if needFindProcess {
 			monitor.Start("start package manager")
@@ -761,6 +842,28 @@ func (r *Router) Cleanup() error {
 	return nil
 }
 
+func (r *Router) Inbound(tag string) (adapter.Inbound, bool) {
+	r.inboundAccess.RLock()
+	defer r.inboundAccess.RUnlock()
+	inbound, loaded := r.inboundByTag[tag]
+	return inbound, loaded
+}
+
+func (r *Router) RegisterInbound(inbound adapter.Inbound) {
+	r.inboundAccess.Lock()
+	defer r.inboundAccess.Unlock()
+	if r.inboundByTag == nil {
+		r.inboundByTag = make(map[string]adapter.Inbound)
+	}
+	r.inboundByTag[inbound.Tag()] = inbound
+}
+
+func (r *Router) UnregisterInbound(tag string) {
+	r.inboundAccess.Lock()
+	defer r.inboundAccess.Unlock()
+	delete(r.inboundByTag, tag)
+}
+
 func (r *Router) Outbound(tag string) (adapter.Outbound, bool) {
 	outbound, loaded := r.outboundByTag[tag]
 	return outbound, loaded
@@ -802,8 +905,8 @@ func (r *Router) RouteConnection(ctx context.Context, conn net.Conn, metadata ad
 		if metadata.LastInbound == metadata.InboundDetour {
 			return E.New("routing loop on detour: ", metadata.InboundDetour)
 		}
-		detour := r.inboundByTag[metadata.InboundDetour]
-		if detour == nil {
+		detour, loaded := r.Inbound(metadata.InboundDetour)
+		if !loaded {
 			return E.New("inbound detour not found: ", metadata.InboundDetour)
 		}
 		injectable, isInjectable := detour.(adapter.InjectableInbound)
@@ -915,17 +1018,33 @@ func (r *Router) RouteConnection(ctx context.Context, conn net.Conn, metadata ad
 	if !common.Contains(detour.Network(), N.NetworkTCP) {
 		return E.New("missing supported outbound, closing connection")
 	}
+	var tracker adapter.Tracker
 	if r.clashServer != nil {
-		trackerConn, tracker := r.clashServer.RoutedConnection(ctx, conn, metadata, matchedRule)
-		defer tracker.Leave()
+		var trackerConn net.Conn
+		trackerConn, tracker = r.clashServer.RoutedConnection(ctx, conn, metadata, matchedRule)
 		conn = trackerConn
+		defer func() {
+			tracker.Leave(err)
+		}()
 	}
 	if r.v2rayServer != nil {
 		if statsService := r.v2rayServer.StatsService(); statsService != nil {
 			conn = statsService.RoutedConnection(metadata.Inbound, detour.Tag(), metadata.User, conn)
 		}
 	}
-	return detour.NewConnection(ctx, conn, metadata)
+	if shaper := r.connectionShaper(metadata.Inbound, detour.Tag(), matchedRule); !shaper.IsEmpty() {
+		conn = ratelimit.NewConn(ctx, conn, shaper)
+	}
+	if r.scheduleInterrupt && r.scheduleEnabled.Load() {
+		scheduleConn := r.newScheduleConn(conn, metadata, detour.Tag())
+		conn = scheduleConn
+		defer scheduleConn.untrack()
+	}
+	if r.failureStatistics != nil {
+		ctx = failure.ContextWithStatistics(ctx, r.failureStatistics)
+	}
+	err = detour.NewConnection(ctx, conn, metadata)
+	return err
 }
 
 func (r *Router) RoutePacketConnection(ctx context.Context, conn N.PacketConn, metadata adapter.InboundContext) error {
@@ -936,8 +1055,8 @@ func (r *Router) RoutePacketConnection(ctx context.Context, conn N.PacketConn, m
 		if metadata.LastInbound == metadata.InboundDetour {
 			return E.New("routing loop on detour: ", metadata.InboundDetour)
 		}
-		detour := r.inboundByTag[metadata.InboundDetour]
-		if detour == nil {
+		detour, loaded := r.Inbound(metadata.InboundDetour)
+		if !loaded {
 			return E.New("inbound detour not found: ", metadata.InboundDetour)
 		}
 		injectable, isInjectable := detour.(adapter.InjectableInbound)
@@ -1089,20 +1208,36 @@ func (r *Router) RoutePacketConnection(ctx context.Context, conn N.PacketConn, m
 	if !common.Contains(detour.Network(), N.NetworkUDP) {
 		return E.New("missing supported outbound, closing packet connection")
 	}
+	var tracker adapter.Tracker
 	if r.clashServer != nil {
-		trackerConn, tracker := r.clashServer.RoutedPacketConnection(ctx, conn, metadata, matchedRule)
-		defer tracker.Leave()
+		var trackerConn N.PacketConn
+		trackerConn, tracker = r.clashServer.RoutedPacketConnection(ctx, conn, metadata, matchedRule)
 		conn = trackerConn
+		defer func() {
+			tracker.Leave(err)
+		}()
 	}
 	if r.v2rayServer != nil {
 		if statsService := r.v2rayServer.StatsService(); statsService != nil {
 			conn = statsService.RoutedPacketConnection(metadata.Inbound, detour.Tag(), metadata.User, conn)
 		}
 	}
+	if shaper := r.connectionShaper(metadata.Inbound, detour.Tag(), matchedRule); !shaper.IsEmpty() {
+		conn = ratelimit.NewPacketConn(ctx, conn, shaper)
+	}
+	if r.scheduleInterrupt && r.scheduleEnabled.Load() {
+		schedulePacketConn := r.newSchedulePacketConn(conn, metadata, detour.Tag())
+		conn = schedulePacketConn
+		defer schedulePacketConn.untrack()
+	}
 	if metadata.FakeIP {
 		conn = bufio.NewNATPacketConn(bufio.NewNetPacketConn(conn), metadata.OriginDestination, metadata.Destination)
 	}
-	return detour.NewPacketConnection(ctx, conn, metadata)
+	if r.failureStatistics != nil {
+		ctx = failure.ContextWithStatistics(ctx, r.failureStatistics)
+	}
+	err = detour.NewPacketConnection(ctx, conn, metadata)
+	return err
 }
 
 func (r *Router) match(ctx context.Context, metadata *adapter.InboundContext, defaultOutbound adapter.Outbound) (context.Context, adapter.Rule, adapter.Outbound, error) {
@@ -1145,9 +1280,18 @@ func (r *Router) match0(ctx context.Context, metadata *adapter.InboundContext, d
 					r.logger.InfoContext(ctx, "found user id: ", processInfo.UserId)
 				}
 			}
+			if processInfo.ContainerID != "" {
+				r.logger.InfoContext(ctx, "found container id: ", processInfo.ContainerID)
+			} else if processInfo.SystemdUnit != "" {
+				r.logger.InfoContext(ctx, "found systemd unit: ", processInfo.SystemdUnit)
+			}
 			metadata.ProcessInfo = processInfo
 		}
 	}
+	return r.matchRules(ctx, metadata, defaultOutbound)
+}
+
+func (r *Router) matchRules(ctx context.Context, metadata *adapter.InboundContext, defaultOutbound adapter.Outbound) (adapter.Rule, adapter.Outbound) {
 	for i, rule := range r.rules {
 		metadata.ResetRuleCache()
 		if rule.Match(metadata) {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/failure"
 	"github.com/sagernet/sing-dns"
 	"github.com/sagernet/sing/common/cache"
 	E "github.com/sagernet/sing/common/exceptions"
@@ -102,67 +103,16 @@ func (r *Router) Exchange(ctx context.Context, message *mDNS.Msg) (*mDNS.Msg, er
 		err       error
 	)
 	response, cached = r.dnsClient.ExchangeCache(ctx, message)
-	if !cached {
-		var metadata *adapter.InboundContext
-		ctx, metadata = adapter.ExtendContext(ctx)
-		metadata.Destination = M.Socksaddr{}
-		if len(message.Question) > 0 {
-			metadata.QueryType = message.Question[0].Qtype
-			switch metadata.QueryType {
-			case mDNS.TypeA:
-				metadata.IPVersion = 4
-			case mDNS.TypeAAAA:
-				metadata.IPVersion = 6
-			}
-			metadata.Domain = fqdnToDomain(message.Question[0].Name)
-		}
-		var (
-			strategy  dns.DomainStrategy
-			rule      adapter.DNSRule
-			ruleIndex int
-		)
-		ruleIndex = -1
-		for {
-			var (
-				dnsCtx       context.Context
-				addressLimit bool
-			)
-			dnsCtx, transport, strategy, rule, ruleIndex = r.matchDNS(ctx, true, ruleIndex, isAddressQuery(message))
-			dnsCtx = adapter.OverrideContext(dnsCtx)
-			if rule != nil && rule.WithAddressLimit() {
-				addressLimit = true
-				response, err = r.dnsClient.ExchangeWithResponseCheck(dnsCtx, transport, message, strategy, func(response *mDNS.Msg) bool {
-					addresses, addrErr := dns.MessageToAddresses(response)
-					if addrErr != nil {
-						return false
-					}
-					metadata.DestinationAddresses = addresses
-					return rule.MatchAddressLimit(metadata)
-				})
-			} else {
-				addressLimit = false
-				response, err = r.dnsClient.Exchange(dnsCtx, transport, message, strategy)
-			}
-			var rejected bool
-			if err != nil {
-				if errors.Is(err, dns.ErrResponseRejectedCached) {
-					rejected = true
-					r.dnsLogger.DebugContext(ctx, E.Cause(err, "response rejected for ", formatQuestion(message.Question[0].String())), " (cached)")
-				} else if errors.Is(err, dns.ErrResponseRejected) {
-					rejected = true
-					r.dnsLogger.DebugContext(ctx, E.Cause(err, "response rejected for ", formatQuestion(message.Question[0].String())))
-				} else if len(message.Question) > 0 {
-					r.dnsLogger.ErrorContext(ctx, E.Cause(err, "exchange failed for ", formatQuestion(message.Question[0].String())))
-				} else {
-					r.dnsLogger.ErrorContext(ctx, E.Cause(err, "exchange failed for <empty query>"))
-				}
-			}
-			if addressLimit && rejected {
-				continue
-			}
-			break
+	if r.dnsCache != nil {
+		if cached {
+			r.dnsCache.TouchExchange(ctx, message)
+		} else {
+			response, cached = r.dnsCache.LoadExchange(ctx, message)
 		}
 	}
+	if !cached {
+		response, transport, err = r.exchange(ctx, message, true)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +131,93 @@ func (r *Router) Exchange(ctx context.Context, message *mDNS.Msg) (*mDNS.Msg, er
 	return response, nil
 }
 
+func (r *Router) exchange(ctx context.Context, message *mDNS.Msg, allowFakeIP bool) (*mDNS.Msg, dns.Transport, error) {
+	var (
+		response  *mDNS.Msg
+		transport dns.Transport
+		err       error
+	)
+	cacheable := r.dnsCache != nil && (isDNSRefresh(ctx) || isCacheableRequest(ctx, message))
+	var metadata *adapter.InboundContext
+	ctx, metadata = adapter.ExtendContext(ctx)
+	metadata.Destination = M.Socksaddr{}
+	if len(message.Question) > 0 {
+		metadata.QueryType = message.Question[0].Qtype
+		switch metadata.QueryType {
+		case mDNS.TypeA:
+			metadata.IPVersion = 4
+		case mDNS.TypeAAAA:
+			metadata.IPVersion = 6
+		}
+		metadata.Domain = fqdnToDomain(message.Question[0].Name)
+	}
+	var (
+		strategy  dns.DomainStrategy
+		rule      adapter.DNSRule
+		ruleIndex int
+	)
+	ruleIndex = -1
+	for {
+		var (
+			dnsCtx       context.Context
+			addressLimit bool
+		)
+		dnsCtx, transport, strategy, rule, ruleIndex = r.matchDNS(ctx, allowFakeIP, ruleIndex, isAddressQuery(message))
+		dnsCtx = adapter.OverrideContext(dnsCtx)
+		if rule != nil && rule.WithAddressLimit() {
+			addressLimit = true
+			response, err = r.dnsClient.ExchangeWithResponseCheck(dnsCtx, transport, message, strategy, func(response *mDNS.Msg) bool {
+				addresses, addrErr := dns.MessageToAddresses(response)
+				if addrErr != nil {
+					return false
+				}
+				metadata.DestinationAddresses = addresses
+				return rule.MatchAddressLimit(metadata)
+			})
+		} else {
+			addressLimit = false
+			response, err = r.dnsClient.Exchange(dnsCtx, transport, message, strategy)
+		}
+		var rejected bool
+		if err != nil {
+			if errors.Is(err, dns.ErrResponseRejectedCached) {
+				rejected = true
+				r.dnsLogger.DebugContext(ctx, E.Cause(err, "response rejected for ", formatQuestion(message.Question[0].String())), " (cached)")
+			} else if errors.Is(err, dns.ErrResponseRejected) {
+				rejected = true
+				r.dnsLogger.DebugContext(ctx, E.Cause(err, "response rejected for ", formatQuestion(message.Question[0].String())))
+			} else if len(message.Question) > 0 {
+				r.dnsLogger.ErrorContext(ctx, E.Cause(err, "exchange failed for ", formatQuestion(message.Question[0].String())))
+			} else {
+				r.dnsLogger.ErrorContext(ctx, E.Cause(err, "exchange failed for <empty query>"))
+			}
+		}
+		if addressLimit && rejected {
+			continue
+		}
+		break
+	}
+	if err == nil && cacheable {
+		_, isFakeIP := transport.(adapter.FakeIPTransport)
+		if !isFakeIP && (rule == nil || !rule.DisableCache() && rule.ClientSubnet() == nil) {
+			r.dnsCache.Store(response)
+		}
+	}
+	return response, transport, err
+}
+
+func (r *Router) refreshDNS(ctx context.Context, question mDNS.Question, allowFakeIP bool) {
+	r.dnsLogger.DebugContext(ctx, "refresh ", formatQuestion(question.String()))
+	message := &mDNS.Msg{
+		MsgHdr: mDNS.MsgHdr{
+			Id:               mDNS.Id(),
+			RecursionDesired: true,
+		},
+		Question: []mDNS.Question{question},
+	}
+	_, _, _ = r.exchange(ctx, message, allowFakeIP)
+}
+
 func (r *Router) Lookup(ctx context.Context, domain string, strategy dns.DomainStrategy) ([]netip.Addr, error) {
 	var (
 		responseAddrs []netip.Addr
@@ -188,6 +225,13 @@ func (r *Router) Lookup(ctx context.Context, domain string, strategy dns.DomainS
 		err           error
 	)
 	responseAddrs, cached = r.dnsClient.LookupCache(ctx, domain, strategy)
+	if r.dnsCache != nil {
+		if cached {
+			r.dnsCache.TouchLookup(ctx, domain, strategy)
+		} else {
+			responseAddrs, cached = r.dnsCache.LoadLookup(ctx, domain, strategy)
+		}
+	}
 	if cached {
 		if len(responseAddrs) == 0 {
 			return nil, dns.RCodeNameError
@@ -203,18 +247,22 @@ func (r *Router) Lookup(ctx context.Context, domain string, strategy dns.DomainS
 		transportStrategy dns.DomainStrategy
 		rule              adapter.DNSRule
 		ruleIndex         int
+		lookupTransport   *dnsLookupTransport
 	)
 	ruleIndex = -1
+	var dnsCtx context.Context
 	for {
-		var (
-			dnsCtx       context.Context
-			addressLimit bool
-		)
+		var addressLimit bool
 		dnsCtx, transport, transportStrategy, rule, ruleIndex = r.matchDNS(ctx, false, ruleIndex, true)
 		dnsCtx = adapter.OverrideContext(dnsCtx)
 		if strategy == dns.DomainStrategyAsIS {
 			strategy = transportStrategy
 		}
+		lookupTransport = nil
+		if r.dnsCache != nil && transport.Raw() && (rule == nil || !rule.WithAddressLimit()) {
+			lookupTransport = &dnsLookupTransport{Transport: transport}
+			transport = lookupTransport
+		}
 		if rule != nil && rule.WithAddressLimit() {
 			addressLimit = true
 			responseAddrs, err = r.dnsClient.LookupWithResponseCheck(dnsCtx, transport, domain, strategy, func(responseAddrs []netip.Addr) bool {
@@ -243,8 +291,17 @@ func (r *Router) Lookup(ctx context.Context, domain string, strategy dns.DomainS
 	}
 	if len(responseAddrs) > 0 {
 		r.dnsLogger.InfoContext(ctx, "lookup succeed for ", domain, ": ", strings.Join(F.MapToString(responseAddrs), " "))
+		if r.dnsCache != nil && !dns.DisableCacheFromContext(dnsCtx) && (rule == nil || rule.ClientSubnet() == nil) {
+			if lookupTransport != nil {
+				for _, response := range lookupTransport.responses {
+					r.dnsCache.Store(response)
+				}
+			} else if !transport.Raw() {
+				r.dnsCache.StoreLookup(dnsCtx, domain, strategy, responseAddrs)
+			}
+		}
 	}
-	return responseAddrs, err
+	return responseAddrs, failure.DNSError(err)
 }
 
 func (r *Router) LookupDefault(ctx context.Context, domain string) ([]netip.Addr, error) {
@@ -253,6 +310,9 @@ func (r *Router) LookupDefault(ctx context.Context, domain string) ([]netip.Addr
 
 func (r *Router) ClearDNSCache() {
 	r.dnsClient.ClearCache()
+	if r.dnsCache != nil {
+		r.dnsCache.Clear()
+	}
 	if r.platformInterface != nil {
 		r.platformInterface.ClearDNSCache()
 	}
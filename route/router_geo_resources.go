@@ -23,6 +23,10 @@ func (r *Router) GeoIPReader() *geoip.Reader {
 	return r.geoIPReader
 }
 
+func (r *Router) ASNReader() *geoip.Reader {
+	return r.asnReader.Load()
+}
+
 func (r *Router) LoadGeosite(code string) (adapter.Rule, error) {
 	rule, cached := r.geositeCache[code]
 	if cached {
@@ -81,11 +85,49 @@ func (r *Router) prepareGeoIPDatabase() error {
 	if err != nil {
 		return E.Cause(err, "open geoip database")
 	}
-	r.logger.Info("loaded geoip database: ", len(codes), " codes")
+	if len(codes) > 0 {
+		r.logger.Info("loaded geoip database: ", len(codes), " codes")
+	} else {
+		r.logger.Info("loaded geoip database: ", geoReader.DatabaseType())
+	}
+	if !geoReader.HasCountry() {
+		r.logger.Warn("geoip database has no country data: ", geoPath)
+	}
 	r.geoIPReader = geoReader
 	return nil
 }
 
+func (r *Router) prepareASNDatabase() error {
+	if r.geoIPOptions.ASNPath == "" {
+		if r.geoIPReader == nil && !r.needGeoIPDatabase {
+			err := r.prepareGeoIPDatabase()
+			if err != nil {
+				return err
+			}
+		}
+		if r.geoIPReader == nil || !r.geoIPReader.HasASN() {
+			return E.New("ASN rules require `route.geoip.asn_path` or a geoip database with ASN data")
+		}
+		r.asnReader.Store(r.geoIPReader)
+		return nil
+	}
+	asnPath := r.geoIPOptions.ASNPath
+	if !rw.IsFile(asnPath) {
+		asnPath = filemanager.BasePath(r.ctx, asnPath)
+	}
+	asnReader, _, err := geoip.Open(asnPath)
+	if err != nil {
+		return E.Cause(err, "open asn database")
+	}
+	if !asnReader.HasASN() {
+		asnReader.Close()
+		return E.New("asn database has no ASN data: ", asnPath)
+	}
+	r.logger.Info("loaded asn database: ", asnReader.DatabaseType())
+	r.asnReader.Store(asnReader)
+	return nil
+}
+
 func (r *Router) prepareGeositeDatabase() error {
 	var geoPath string
 	if r.geositeOptions.Path != "" {
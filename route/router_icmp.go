@@ -0,0 +1,69 @@
+package route
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/outbound"
+	"github.com/sagernet/sing-dns"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+	M "github.com/sagernet/sing/common/metadata"
+)
+
+func (r *Router) RouteICMPEcho(ctx context.Context, request *adapter.ICMPEchoRequest, metadata adapter.InboundContext) error {
+	if r.pauseManager.IsDevicePaused() {
+		return E.New("reject icmp echo to ", request.Destination, " while device paused")
+	}
+	metadata.Network = C.NetworkICMP
+	metadata.Destination = M.SocksaddrFrom(request.Destination, 0)
+	if request.Destination.Is4() {
+		metadata.IPVersion = 4
+	} else {
+		metadata.IPVersion = 6
+	}
+	if r.fakeIPStore != nil && r.fakeIPStore.Contains(request.Destination) {
+		domain, loaded := r.fakeIPStore.Lookup(request.Destination)
+		if !loaded {
+			return E.New("missing fakeip context")
+		}
+		metadata.OriginDestination = metadata.Destination
+		metadata.Destination = M.Socksaddr{Fqdn: domain}
+		metadata.FakeIP = true
+		r.logger.DebugContext(ctx, "found fakeip domain: ", domain)
+	} else if r.dnsReverseMapping != nil {
+		domain, loaded := r.dnsReverseMapping.Query(request.Destination)
+		if loaded {
+			metadata.Domain = domain
+			r.logger.DebugContext(ctx, "found reserve mapped domain: ", metadata.Domain)
+		}
+	}
+	// process searching is skipped, since no socket table covers ICMP
+	_, detour := r.matchRules(ctx, &metadata, r.defaultOutboundForPacketConnection)
+	detour, err := outbound.RealOutbound(r, detour)
+	if err != nil {
+		return err
+	}
+	icmpOutbound, isICMP := detour.(adapter.ICMPOutbound)
+	if !isICMP {
+		return adapter.ErrICMPUnsupported
+	}
+	if metadata.FakeIP {
+		addresses, err := r.Lookup(adapter.WithContext(ctx, &metadata), metadata.Destination.Fqdn, dns.DomainStrategy(metadata.InboundOptions.DomainStrategy))
+		if err != nil {
+			return err
+		}
+		address := common.Find(addresses, func(it netip.Addr) bool {
+			return it.Is4() == request.Destination.Is4()
+		})
+		if !address.IsValid() {
+			return E.New("no address of the same family found for ", metadata.Destination.Fqdn)
+		}
+		newRequest := *request
+		newRequest.Destination = address
+		request = &newRequest
+	}
+	return icmpOutbound.NewICMPEcho(ctx, request, metadata)
+}
@@ -1,6 +1,7 @@
 package route
 
 import (
+	"github.com/sagernet/sing-box/adapter"
 	C "github.com/sagernet/sing-box/constant"
 	"github.com/sagernet/sing-box/option"
 	"github.com/sagernet/sing/common"
@@ -62,6 +63,14 @@ func isGeoIPDNSRule(rule option.DefaultDNSRule) bool {
 	return len(rule.SourceGeoIP) > 0 && common.Any(rule.SourceGeoIP, notPrivateNode) || len(rule.GeoIP) > 0 && common.Any(rule.GeoIP, notPrivateNode)
 }
 
+func isASNRule(rule option.DefaultRule) bool {
+	return len(rule.SourceIPASN) > 0 || len(rule.IPASN) > 0
+}
+
+func isASNDNSRule(rule option.DefaultDNSRule) bool {
+	return len(rule.SourceIPASN) > 0 || len(rule.IPASN) > 0
+}
+
 func isGeositeRule(rule option.DefaultRule) bool {
 	return len(rule.Geosite) > 0
 }
@@ -71,15 +80,18 @@ func isGeositeDNSRule(rule option.DefaultDNSRule) bool {
 }
 
 func isProcessRule(rule option.DefaultRule) bool {
-	return len(rule.ProcessName) > 0 || len(rule.ProcessPath) > 0 || len(rule.PackageName) > 0 || len(rule.User) > 0 || len(rule.UserID) > 0
+	return len(rule.ProcessName) > 0 || len(rule.ProcessPath) > 0 || len(rule.PackageName) > 0 || len(rule.User) > 0 || len(rule.UserID) > 0 ||
+		len(rule.CGroup) > 0 || len(rule.SystemdUnit) > 0 || len(rule.ContainerID) > 0
 }
 
 func isProcessDNSRule(rule option.DefaultDNSRule) bool {
-	return len(rule.ProcessName) > 0 || len(rule.ProcessPath) > 0 || len(rule.PackageName) > 0 || len(rule.User) > 0 || len(rule.UserID) > 0
+	return len(rule.ProcessName) > 0 || len(rule.ProcessPath) > 0 || len(rule.PackageName) > 0 || len(rule.User) > 0 || len(rule.UserID) > 0 ||
+		len(rule.CGroup) > 0 || len(rule.SystemdUnit) > 0 || len(rule.ContainerID) > 0
 }
 
 func isProcessHeadlessRule(rule option.DefaultHeadlessRule) bool {
-	return len(rule.ProcessName) > 0 || len(rule.ProcessPath) > 0 || len(rule.PackageName) > 0
+	return len(rule.ProcessName) > 0 || len(rule.ProcessPath) > 0 || len(rule.PackageName) > 0 ||
+		len(rule.CGroup) > 0 || len(rule.SystemdUnit) > 0 || len(rule.ContainerID) > 0
 }
 
 func notPrivateNode(code string) bool {
@@ -99,5 +111,60 @@ func isWIFIHeadlessRule(rule option.DefaultHeadlessRule) bool {
 }
 
 func isIPCIDRHeadlessRule(rule option.DefaultHeadlessRule) bool {
-	return len(rule.IPCIDR) > 0 || rule.IPSet != nil
+	return len(rule.IPCIDR) > 0 || rule.IPSet != nil || len(rule.IPASN) > 0
+}
+
+func isASNHeadlessRule(rule option.DefaultHeadlessRule) bool {
+	return len(rule.SourceIPASN) > 0 || len(rule.IPASN) > 0
+}
+
+// appendSchedule parses the schedule of a rule once, invalid schedules are reported when the rule is created.
+func appendSchedule(schedules []adapter.ScheduleMatcher, scheduleList []option.ScheduleOptions) []adapter.ScheduleMatcher {
+	if len(scheduleList) == 0 {
+		return schedules
+	}
+	item, err := NewScheduleItem(scheduleList)
+	if err != nil {
+		return schedules
+	}
+	return append(schedules, item)
+}
+
+func collectSchedules(rules []option.Rule) []adapter.ScheduleMatcher {
+	var schedules []adapter.ScheduleMatcher
+	for _, rule := range rules {
+		switch rule.Type {
+		case C.RuleTypeDefault:
+			schedules = appendSchedule(schedules, rule.DefaultOptions.Schedule)
+		case C.RuleTypeLogical:
+			schedules = append(schedules, collectSchedules(rule.LogicalOptions.Rules)...)
+		}
+	}
+	return schedules
+}
+
+func collectDNSSchedules(rules []option.DNSRule) []adapter.ScheduleMatcher {
+	var schedules []adapter.ScheduleMatcher
+	for _, rule := range rules {
+		switch rule.Type {
+		case C.RuleTypeDefault:
+			schedules = appendSchedule(schedules, rule.DefaultOptions.Schedule)
+		case C.RuleTypeLogical:
+			schedules = append(schedules, collectDNSSchedules(rule.LogicalOptions.Rules)...)
+		}
+	}
+	return schedules
+}
+
+func collectHeadlessSchedules(rules []option.HeadlessRule) []adapter.ScheduleMatcher {
+	var schedules []adapter.ScheduleMatcher
+	for _, rule := range rules {
+		switch rule.Type {
+		case C.RuleTypeDefault:
+			schedules = appendSchedule(schedules, rule.DefaultOptions.Schedule)
+		case C.RuleTypeLogical:
+			schedules = append(schedules, collectHeadlessSchedules(rule.LogicalOptions.Rules)...)
+		}
+	}
+	return schedules
 }
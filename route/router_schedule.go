@@ -0,0 +1,178 @@
+package route
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	N "github.com/sagernet/sing/common/network"
+	"github.com/sagernet/sing/common/x/list"
+)
+
+type scheduleConnection struct {
+	metadata adapter.InboundContext
+	outbound string
+	closer   io.Closer
+}
+
+func (r *Router) loopSchedule() {
+	var lastState []bool
+	for {
+		now := time.Now()
+		state := r.scheduleState(now)
+		// rule sets may gain or lose schedules when updated
+		r.scheduleEnabled.Store(len(state) > 0)
+		if lastState != nil && !scheduleStateEqual(lastState, state) {
+			r.logger.Info("schedule changed")
+			r.ClearDNSCache()
+			if r.scheduleInterrupt {
+				r.interruptScheduleConnections()
+			}
+		}
+		lastState = state
+		// windows are minute-granular, so checking at each minute boundary is sufficient
+		timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		select {
+		case <-r.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *Router) scheduleState(now time.Time) []bool {
+	schedules := append([]adapter.ScheduleMatcher(nil), r.schedules...)
+	for _, ruleSet := range r.ruleSets {
+		schedules = append(schedules, ruleSet.Metadata().Schedules...)
+	}
+	state := make([]bool, 0, len(schedules))
+	for _, schedule := range schedules {
+		state = append(state, schedule.MatchTime(now))
+	}
+	return state
+}
+
+func scheduleStateEqual(a, b []bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Router) interruptScheduleConnections() {
+	r.scheduleAccess.Lock()
+	connections := r.scheduleConnections.Array()
+	r.scheduleAccess.Unlock()
+	var interrupted int
+	for _, connection := range connections {
+		metadata := connection.metadata
+		var defaultOutbound adapter.Outbound
+		if metadata.Network == N.NetworkUDP {
+			defaultOutbound = r.defaultOutboundForPacketConnection
+		} else {
+			defaultOutbound = r.defaultOutboundForConnection
+		}
+		_, matchOutbound := r.matchRules(r.ctx, &metadata, defaultOutbound)
+		if matchOutbound.Tag() != connection.outbound {
+			connection.closer.Close()
+			interrupted++
+		}
+	}
+	if interrupted > 0 {
+		r.logger.Info("interrupted ", interrupted, " connection(s) no longer matching the schedule")
+	}
+}
+
+func (r *Router) trackScheduleConnection(metadata adapter.InboundContext, outbound string, closer io.Closer) *list.Element[*scheduleConnection] {
+	r.scheduleAccess.Lock()
+	defer r.scheduleAccess.Unlock()
+	return r.scheduleConnections.PushBack(&scheduleConnection{metadata, outbound, closer})
+}
+
+func (r *Router) untrackScheduleConnection(element *list.Element[*scheduleConnection]) {
+	r.scheduleAccess.Lock()
+	defer r.scheduleAccess.Unlock()
+	r.scheduleConnections.Remove(element)
+}
+
+func (r *Router) newScheduleConn(conn net.Conn, metadata adapter.InboundContext, outbound string) *scheduleConn {
+	scheduleConn := &scheduleConn{Conn: conn, router: r}
+	scheduleConn.element = r.trackScheduleConnection(metadata, outbound, scheduleConn)
+	return scheduleConn
+}
+
+func (r *Router) newSchedulePacketConn(conn N.PacketConn, metadata adapter.InboundContext, outbound string) *schedulePacketConn {
+	schedulePacketConn := &schedulePacketConn{PacketConn: conn, router: r}
+	schedulePacketConn.element = r.trackScheduleConnection(metadata, outbound, schedulePacketConn)
+	return schedulePacketConn
+}
+
+type scheduleConn struct {
+	net.Conn
+	router      *Router
+	element     *list.Element[*scheduleConnection]
+	untrackOnce sync.Once
+}
+
+// untrack is deferred by the router as well, since the wrapper is not
+// closed when the outbound fails to dial.
+func (c *scheduleConn) untrack() {
+	c.untrackOnce.Do(func() {
+		c.router.untrackScheduleConnection(c.element)
+	})
+}
+
+func (c *scheduleConn) Close() error {
+	c.untrack()
+	return c.Conn.Close()
+}
+
+func (c *scheduleConn) ReaderReplaceable() bool {
+	return true
+}
+
+func (c *scheduleConn) WriterReplaceable() bool {
+	return true
+}
+
+func (c *scheduleConn) Upstream() any {
+	return c.Conn
+}
+
+type schedulePacketConn struct {
+	N.PacketConn
+	router      *Router
+	element     *list.Element[*scheduleConnection]
+	untrackOnce sync.Once
+}
+
+func (c *schedulePacketConn) untrack() {
+	c.untrackOnce.Do(func() {
+		c.router.untrackScheduleConnection(c.element)
+	})
+}
+
+func (c *schedulePacketConn) Close() error {
+	c.untrack()
+	return c.PacketConn.Close()
+}
+
+func (c *schedulePacketConn) ReaderReplaceable() bool {
+	return true
+}
+
+func (c *schedulePacketConn) WriterReplaceable() bool {
+	return true
+}
+
+func (c *schedulePacketConn) Upstream() any {
+	return c.PacketConn
+}
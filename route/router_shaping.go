@@ -0,0 +1,126 @@
+package route
+
+import (
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/ratelimit"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+	"github.com/sagernet/sing/common"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+type rateLimiter struct {
+	upload   *ratelimit.Limiter
+	download *ratelimit.Limiter
+}
+
+type ruleShaping struct {
+	limiters []*rateLimiter
+	priority int
+}
+
+// initializeShaping also runs without rate limiters,
+// so that rules referencing unknown rate limiters or traffic classes are rejected.
+func (r *Router) initializeShaping(options option.RouteOptions) error {
+	r.rateLimiters = make(map[string]*rateLimiter)
+	r.inboundLimiters = make(map[string][]*rateLimiter)
+	r.outboundLimiters = make(map[string][]*rateLimiter)
+	r.ruleShaping = make(map[adapter.Rule]ruleShaping)
+	for i, limiterOptions := range options.RateLimiters {
+		if limiterOptions.Tag == "" {
+			return E.New("parse rate_limiters[", i, "]: missing tag")
+		}
+		if _, exists := r.rateLimiters[limiterOptions.Tag]; exists {
+			return E.New("duplicate rate limiter tag: ", limiterOptions.Tag)
+		}
+		if limiterOptions.UpMbps <= 0 && limiterOptions.DownMbps <= 0 {
+			return E.New("parse rate_limiters[", i, "]: missing up_mbps or down_mbps")
+		}
+		limiter := &rateLimiter{}
+		if limiterOptions.UpMbps > 0 {
+			limiter.upload = ratelimit.NewLimiter(uint64(limiterOptions.UpMbps) * C.MbpsToBps)
+		}
+		if limiterOptions.DownMbps > 0 {
+			limiter.download = ratelimit.NewLimiter(uint64(limiterOptions.DownMbps) * C.MbpsToBps)
+		}
+		r.rateLimiters[limiterOptions.Tag] = limiter
+		for _, inbound := range limiterOptions.Inbound {
+			r.inboundLimiters[inbound] = append(r.inboundLimiters[inbound], limiter)
+		}
+		for _, outbound := range limiterOptions.Outbound {
+			r.outboundLimiters[outbound] = append(r.outboundLimiters[outbound], limiter)
+		}
+	}
+	trafficClasses := make(map[string]int)
+	for i, classOptions := range options.TrafficClasses {
+		if classOptions.Name == "" {
+			return E.New("parse traffic_classes[", i, "]: missing name")
+		}
+		if _, exists := trafficClasses[classOptions.Name]; exists {
+			return E.New("duplicate traffic class: ", classOptions.Name)
+		}
+		trafficClasses[classOptions.Name] = classOptions.Priority
+	}
+	for i, ruleOptions := range options.Rules {
+		var (
+			limiterTags  []string
+			trafficClass string
+		)
+		switch ruleOptions.Type {
+		case "", C.RuleTypeDefault:
+			limiterTags = ruleOptions.DefaultOptions.RateLimiter
+			trafficClass = ruleOptions.DefaultOptions.TrafficClass
+		case C.RuleTypeLogical:
+			limiterTags = ruleOptions.LogicalOptions.RateLimiter
+			trafficClass = ruleOptions.LogicalOptions.TrafficClass
+		}
+		if len(limiterTags) == 0 && trafficClass == "" {
+			continue
+		}
+		var shaping ruleShaping
+		for _, tag := range limiterTags {
+			limiter, loaded := r.rateLimiters[tag]
+			if !loaded {
+				return E.New("parse rule[", i, "]: rate limiter not found: ", tag)
+			}
+			shaping.limiters = append(shaping.limiters, limiter)
+		}
+		if trafficClass != "" {
+			priority, loaded := trafficClasses[trafficClass]
+			if !loaded {
+				return E.New("parse rule[", i, "]: traffic class not found: ", trafficClass)
+			}
+			shaping.priority = priority
+		}
+		r.ruleShaping[r.rules[i]] = shaping
+	}
+	return nil
+}
+
+func (r *Router) connectionShaper(inbound string, outbound string, matchedRule adapter.Rule) ratelimit.Shaper {
+	var (
+		shaper   ratelimit.Shaper
+		limiters []*rateLimiter
+	)
+	if len(r.rateLimiters) == 0 && len(r.ruleShaping) == 0 {
+		return shaper
+	}
+	limiters = append(limiters, r.inboundLimiters[inbound]...)
+	limiters = append(limiters, r.outboundLimiters[outbound]...)
+	if matchedRule != nil {
+		if shaping, loaded := r.ruleShaping[matchedRule]; loaded {
+			limiters = append(limiters, shaping.limiters...)
+			shaper.Priority = shaping.priority
+		}
+	}
+	// a limiter attached to the inbound, the outbound and the rule is only waited once
+	for _, limiter := range common.Uniq(limiters) {
+		if limiter.upload != nil {
+			shaper.Upload = append(shaper.Upload, limiter.upload)
+		}
+		if limiter.download != nil {
+			shaper.Download = append(shaper.Download, limiter.download)
+		}
+	}
+	return shaper
+}
@@ -0,0 +1,78 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testShapingRule struct {
+	adapter.Rule
+}
+
+func newTestShapingRouter(rules int) *Router {
+	router := &Router{}
+	for i := 0; i < rules; i++ {
+		router.rules = append(router.rules, &testShapingRule{})
+	}
+	return router
+}
+
+func shapingRule(limiters []string, trafficClass string) option.Rule {
+	return option.Rule{
+		Type: C.RuleTypeDefault,
+		DefaultOptions: option.DefaultRule{
+			RateLimiter:  limiters,
+			TrafficClass: trafficClass,
+		},
+	}
+}
+
+func TestShapingValidation(t *testing.T) {
+	t.Parallel()
+	err := newTestShapingRouter(1).initializeShaping(option.RouteOptions{
+		Rules: []option.Rule{shapingRule([]string{"missing"}, "")},
+	})
+	require.ErrorContains(t, err, "rate limiter not found: missing")
+	err = newTestShapingRouter(1).initializeShaping(option.RouteOptions{
+		Rules: []option.Rule{shapingRule(nil, "missing")},
+	})
+	require.ErrorContains(t, err, "traffic class not found: missing")
+	err = newTestShapingRouter(0).initializeShaping(option.RouteOptions{
+		RateLimiters: []option.RateLimiterOptions{{Tag: "a", UpMbps: 1}, {Tag: "a", UpMbps: 1}},
+	})
+	require.ErrorContains(t, err, "duplicate rate limiter tag")
+}
+
+func TestShapingTrafficClassOnly(t *testing.T) {
+	t.Parallel()
+	router := newTestShapingRouter(1)
+	require.NoError(t, router.initializeShaping(option.RouteOptions{
+		TrafficClasses: []option.TrafficClassOptions{{Name: "interactive", Priority: 10}},
+		Rules:          []option.Rule{shapingRule(nil, "interactive")},
+	}))
+	shaper := router.connectionShaper("in", "out", router.rules[0])
+	require.True(t, shaper.IsEmpty())
+	require.Equal(t, 10, shaper.Priority)
+}
+
+func TestShapingDeduplicate(t *testing.T) {
+	t.Parallel()
+	router := newTestShapingRouter(1)
+	require.NoError(t, router.initializeShaping(option.RouteOptions{
+		RateLimiters: []option.RateLimiterOptions{
+			{Tag: "shared", UpMbps: 10, DownMbps: 10, Inbound: []string{"in"}, Outbound: []string{"out"}},
+			{Tag: "upload", UpMbps: 1, Inbound: []string{"in"}},
+		},
+		Rules: []option.Rule{shapingRule([]string{"shared"}, "")},
+	}))
+	shaper := router.connectionShaper("in", "out", router.rules[0])
+	require.Len(t, shaper.Upload, 2)
+	require.Len(t, shaper.Download, 1)
+	shaper = router.connectionShaper("other", "other", nil)
+	require.True(t, shaper.IsEmpty())
+}
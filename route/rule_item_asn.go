@@ -0,0 +1,80 @@
+package route
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/sagernet/sing-box/adapter"
+	F "github.com/sagernet/sing/common/format"
+)
+
+var _ RuleItem = (*ASNItem)(nil)
+
+type ASNItem struct {
+	router   adapter.Router
+	isSource bool
+	asns     []uint32
+	asnMap   map[uint32]bool
+}
+
+func NewASNItem(router adapter.Router, isSource bool, asns []uint32) *ASNItem {
+	asnMap := make(map[uint32]bool)
+	for _, asn := range asns {
+		asnMap[asn] = true
+	}
+	return &ASNItem{
+		router:   router,
+		isSource: isSource,
+		asns:     asns,
+		asnMap:   asnMap,
+	}
+}
+
+func (r *ASNItem) Match(metadata *adapter.InboundContext) bool {
+	if r.isSource || metadata.IPCIDRMatchSource {
+		return r.match(metadata.Source.Addr)
+	}
+	if metadata.Destination.IsIP() {
+		return r.match(metadata.Destination.Addr)
+	}
+	if len(metadata.DestinationAddresses) > 0 {
+		for _, address := range metadata.DestinationAddresses {
+			if r.match(address) {
+				return true
+			}
+		}
+		return false
+	}
+	return metadata.IPCIDRAcceptEmpty
+}
+
+func (r *ASNItem) match(address netip.Addr) bool {
+	// headless rules are built without a router by rule-set match
+	if r.router == nil {
+		return false
+	}
+	asnReader := r.router.ASNReader()
+	if asnReader == nil || !address.IsValid() {
+		return false
+	}
+	asn, _ := asnReader.LookupASN(address)
+	return asn != 0 && r.asnMap[asn]
+}
+
+func (r *ASNItem) String() string {
+	var description string
+	if r.isSource {
+		description = "source_ip_asn="
+	} else {
+		description = "ip_asn="
+	}
+	aLen := len(r.asns)
+	if aLen == 1 {
+		description += F.ToString(r.asns[0])
+	} else if aLen > 3 {
+		description += "[" + strings.Join(F.MapToString(r.asns[:3]), " ") + "...]"
+	} else {
+		description += "[" + strings.Join(F.MapToString(r.asns), " ") + "]"
+	}
+	return description
+}
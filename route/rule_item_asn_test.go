@@ -0,0 +1,76 @@
+package route
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/geoip"
+	"github.com/sagernet/sing-box/option"
+	M "github.com/sagernet/sing/common/metadata"
+
+	"github.com/stretchr/testify/require"
+)
+
+type asnTestRouter struct {
+	adapter.Router
+	reader *geoip.Reader
+}
+
+func (r *asnTestRouter) ASNReader() *geoip.Reader {
+	return r.reader
+}
+
+func newASNTestRouter(t *testing.T) *asnTestRouter {
+	path := filepath.Join(t.TempDir(), "asn.mmdb")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, geoip.WriteASN(file, map[uint32][]netip.Prefix{
+		13335: {netip.MustParsePrefix("1.1.1.0/24")},
+		15169: {netip.MustParsePrefix("8.8.8.0/24")},
+	}))
+	require.NoError(t, file.Close())
+	reader, _, err := geoip.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		reader.Close()
+	})
+	return &asnTestRouter{reader: reader}
+}
+
+func TestASNItem(t *testing.T) {
+	t.Parallel()
+	router := newASNTestRouter(t)
+	item := NewASNItem(router, false, []uint32{13335})
+	require.True(t, item.Match(&adapter.InboundContext{Destination: M.ParseSocksaddrHostPort("1.1.1.1", 443)}))
+	require.False(t, item.Match(&adapter.InboundContext{Destination: M.ParseSocksaddrHostPort("8.8.8.8", 443)}))
+	require.True(t, item.Match(&adapter.InboundContext{
+		Destination:          M.ParseSocksaddrHostPort("example.com", 443),
+		DestinationAddresses: []netip.Addr{netip.MustParseAddr("8.8.8.8"), netip.MustParseAddr("1.1.1.1")},
+	}))
+	require.False(t, item.Match(&adapter.InboundContext{Destination: M.ParseSocksaddrHostPort("example.com", 443)}))
+	require.True(t, item.Match(&adapter.InboundContext{
+		Destination:       M.ParseSocksaddrHostPort("example.com", 443),
+		IPCIDRAcceptEmpty: true,
+	}))
+	sourceItem := NewASNItem(router, true, []uint32{15169})
+	require.True(t, sourceItem.Match(&adapter.InboundContext{Source: M.ParseSocksaddrHostPort("8.8.8.8", 1234)}))
+	require.False(t, sourceItem.Match(&adapter.InboundContext{Source: M.ParseSocksaddrHostPort("1.1.1.1", 1234)}))
+	require.Equal(t, "ip_asn=13335", item.String())
+}
+
+func TestASNItemWithoutReader(t *testing.T) {
+	t.Parallel()
+	metadata := &adapter.InboundContext{Destination: M.ParseSocksaddrHostPort("1.1.1.1", 443)}
+	require.False(t, NewASNItem(&asnTestRouter{}, false, []uint32{13335}).Match(metadata))
+	rule, err := NewHeadlessRule(nil, option.HeadlessRule{
+		Type: "default",
+		DefaultOptions: option.DefaultHeadlessRule{
+			IPASN: []uint32{13335},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, rule.Match(metadata))
+}
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"strings"
+
+	"github.com/sagernet/sing-box/adapter"
+)
+
+var _ RuleItem = (*CGroupItem)(nil)
+
+type CGroupItem struct {
+	cgroups []string
+}
+
+func NewCGroupItem(cgroupList []string) *CGroupItem {
+	cgroups := make([]string, 0, len(cgroupList))
+	for _, cgroup := range cgroupList {
+		cgroups = append(cgroups, strings.TrimSuffix(cgroup, "/"))
+	}
+	return &CGroupItem{cgroups}
+}
+
+func (r *CGroupItem) Match(metadata *adapter.InboundContext) bool {
+	if metadata.ProcessInfo == nil || metadata.ProcessInfo.CGroup == "" {
+		return false
+	}
+	cgroupPath := metadata.ProcessInfo.CGroup
+	for _, cgroup := range r.cgroups {
+		if cgroupPath == cgroup || strings.HasPrefix(cgroupPath, cgroup+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *CGroupItem) String() string {
+	var description string
+	pLen := len(r.cgroups)
+	if pLen == 1 {
+		description = "cgroup=" + r.cgroups[0]
+	} else {
+		description = "cgroup=[" + strings.Join(r.cgroups, " ") + "]"
+	}
+	return description
+}
@@ -0,0 +1,53 @@
+package route
+
+import (
+	"strings"
+
+	"github.com/sagernet/sing-box/adapter"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// minContainerIDLength is the length of short IDs as printed by `docker ps`,
+// so a shorter prefix cannot match unrelated containers.
+const minContainerIDLength = 12
+
+var _ RuleItem = (*ContainerIDItem)(nil)
+
+type ContainerIDItem struct {
+	containerIDs []string
+}
+
+func NewContainerIDItem(containerIDList []string) (*ContainerIDItem, error) {
+	containerIDs := make([]string, 0, len(containerIDList))
+	for _, containerID := range containerIDList {
+		if len(containerID) < minContainerIDLength {
+			return nil, E.New("container ID too short: ", containerID, ", at least ", minContainerIDLength, " characters required")
+		}
+		containerIDs = append(containerIDs, strings.ToLower(containerID))
+	}
+	return &ContainerIDItem{containerIDs}, nil
+}
+
+func (r *ContainerIDItem) Match(metadata *adapter.InboundContext) bool {
+	if metadata.ProcessInfo == nil || metadata.ProcessInfo.ContainerID == "" {
+		return false
+	}
+	for _, containerID := range r.containerIDs {
+		// short IDs as printed by `docker ps` are prefixes of the full ID
+		if strings.HasPrefix(metadata.ProcessInfo.ContainerID, containerID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ContainerIDItem) String() string {
+	var description string
+	pLen := len(r.containerIDs)
+	if pLen == 1 {
+		description = "container_id=" + r.containerIDs[0]
+	} else {
+		description = "container_id=[" + strings.Join(r.containerIDs, " ") + "]"
+	}
+	return description
+}
@@ -0,0 +1,27 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/process"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerIDItem(t *testing.T) {
+	t.Parallel()
+	_, err := NewContainerIDItem([]string{""})
+	require.Error(t, err)
+	_, err = NewContainerIDItem([]string{"4d5b"})
+	require.Error(t, err)
+	item, err := NewContainerIDItem([]string{"4D5B9A3C7E21"})
+	require.NoError(t, err)
+	require.True(t, item.Match(&adapter.InboundContext{ProcessInfo: &process.Info{
+		ContainerID: "4d5b9a3c7e21f0a8b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4",
+	}}))
+	require.False(t, item.Match(&adapter.InboundContext{ProcessInfo: &process.Info{
+		ContainerID: "9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e",
+	}}))
+	require.False(t, item.Match(&adapter.InboundContext{ProcessInfo: &process.Info{}}))
+}
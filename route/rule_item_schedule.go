@@ -0,0 +1,191 @@
+package route
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/option"
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+var _ RuleItem = (*ScheduleItem)(nil)
+
+type ScheduleItem struct {
+	schedules   []schedule
+	description string
+}
+
+type schedule struct {
+	weekdays [7]bool
+	windows  []scheduleWindow
+	location *time.Location
+}
+
+// scheduleWindow is a time-of-day range in minutes, end exclusive.
+// A window with end <= start crosses midnight.
+type scheduleWindow struct {
+	start int
+	end   int
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+func NewScheduleItem(scheduleList []option.ScheduleOptions) (*ScheduleItem, error) {
+	item := &ScheduleItem{
+		schedules: make([]schedule, 0, len(scheduleList)),
+	}
+	var descriptions []string
+	for i, options := range scheduleList {
+		parsed, err := parseSchedule(options)
+		if err != nil {
+			return nil, E.Cause(err, "schedule[", i, "]")
+		}
+		item.schedules = append(item.schedules, parsed)
+		descriptions = append(descriptions, scheduleString(options))
+	}
+	if len(descriptions) == 1 {
+		item.description = "schedule=" + descriptions[0]
+	} else {
+		item.description = "schedule=[" + strings.Join(descriptions, " ") + "]"
+	}
+	return item, nil
+}
+
+func parseSchedule(options option.ScheduleOptions) (schedule, error) {
+	var parsed schedule
+	if options.Timezone == "" {
+		parsed.location = time.Local
+	} else {
+		location, err := time.LoadLocation(options.Timezone)
+		if err != nil {
+			return schedule{}, E.Cause(err, "load timezone")
+		}
+		parsed.location = location
+	}
+	if len(options.Weekday) == 0 {
+		for i := range parsed.weekdays {
+			parsed.weekdays[i] = true
+		}
+	}
+	for _, weekdayString := range options.Weekday {
+		startString, endString, isRange := strings.Cut(strings.ToLower(weekdayString), "-")
+		start, loaded := weekdayNames[strings.TrimSpace(startString)]
+		if !loaded {
+			return schedule{}, E.New("unknown weekday: ", weekdayString)
+		}
+		end := start
+		if isRange {
+			end, loaded = weekdayNames[strings.TrimSpace(endString)]
+			if !loaded {
+				return schedule{}, E.New("unknown weekday: ", weekdayString)
+			}
+		}
+		for day := start; ; day = (day + 1) % 7 {
+			parsed.weekdays[day] = true
+			if day == end {
+				break
+			}
+		}
+	}
+	for _, timeString := range options.Time {
+		startString, endString, isRange := strings.Cut(timeString, "-")
+		if !isRange {
+			return schedule{}, E.New("invalid time range: ", timeString)
+		}
+		start, err := parseTimeOfDay(strings.TrimSpace(startString))
+		if err != nil {
+			return schedule{}, E.Cause(err, "parse time range: ", timeString)
+		}
+		end, err := parseTimeOfDay(strings.TrimSpace(endString))
+		if err != nil {
+			return schedule{}, E.Cause(err, "parse time range: ", timeString)
+		}
+		parsed.windows = append(parsed.windows, scheduleWindow{start, end})
+	}
+	return parsed, nil
+}
+
+func parseTimeOfDay(timeString string) (int, error) {
+	hourString, minuteString, loaded := strings.Cut(timeString, ":")
+	if !loaded {
+		return 0, E.New("missing minute in ", timeString)
+	}
+	hour, err := strconv.ParseUint(hourString, 10, 8)
+	if err != nil {
+		return 0, err
+	}
+	minute, err := strconv.ParseUint(minuteString, 10, 8)
+	if err != nil {
+		return 0, err
+	}
+	if hour > 24 || minute > 59 || hour == 24 && minute != 0 {
+		return 0, E.New("invalid time of day: ", timeString)
+	}
+	return int(hour*60 + minute), nil
+}
+
+func scheduleString(options option.ScheduleOptions) string {
+	var parts []string
+	if len(options.Weekday) > 0 {
+		parts = append(parts, strings.Join(options.Weekday, ","))
+	}
+	if len(options.Time) > 0 {
+		parts = append(parts, strings.Join(options.Time, ","))
+	}
+	if options.Timezone != "" {
+		parts = append(parts, options.Timezone)
+	}
+	if len(parts) == 0 {
+		return "always"
+	}
+	return strings.Join(parts, "/")
+}
+
+func (s schedule) match(now time.Time) bool {
+	now = now.In(s.location)
+	weekday := now.Weekday()
+	if len(s.windows) == 0 {
+		return s.weekdays[weekday]
+	}
+	minute := now.Hour()*60 + now.Minute()
+	yesterday := (weekday + 6) % 7
+	for _, window := range s.windows {
+		if window.start < window.end {
+			if s.weekdays[weekday] && minute >= window.start && minute < window.end {
+				return true
+			}
+		} else {
+			if s.weekdays[weekday] && minute >= window.start || s.weekdays[yesterday] && minute < window.end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *ScheduleItem) MatchTime(now time.Time) bool {
+	for _, it := range r.schedules {
+		if it.match(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ScheduleItem) Match(metadata *adapter.InboundContext) bool {
+	return r.MatchTime(time.Now())
+}
+
+func (r *ScheduleItem) String() string {
+	return r.description
+}
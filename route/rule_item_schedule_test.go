@@ -0,0 +1,67 @@
+package route
+
+import (
+	"testing"
+	"time"
+
+	C "github.com/sagernet/sing-box/constant"
+	"github.com/sagernet/sing-box/option"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleItem(t *testing.T) {
+	t.Parallel()
+	item, err := NewScheduleItem([]option.ScheduleOptions{{
+		Weekday:  []string{"mon-fri"},
+		Time:     []string{"09:00-18:00"},
+		Timezone: "UTC",
+	}})
+	require.NoError(t, err)
+	// 2024-01-01 is a Monday
+	require.True(t, item.MatchTime(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)))
+	require.True(t, item.MatchTime(time.Date(2024, 1, 5, 17, 59, 0, 0, time.UTC)))
+	require.False(t, item.MatchTime(time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)))
+	require.False(t, item.MatchTime(time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)))
+
+	overnight, err := NewScheduleItem([]option.ScheduleOptions{{
+		Weekday:  []string{"fri"},
+		Time:     []string{"22:00-06:00"},
+		Timezone: "UTC",
+	}})
+	require.NoError(t, err)
+	require.True(t, overnight.MatchTime(time.Date(2024, 1, 5, 23, 0, 0, 0, time.UTC)))
+	require.True(t, overnight.MatchTime(time.Date(2024, 1, 6, 5, 59, 0, 0, time.UTC)))
+	require.False(t, overnight.MatchTime(time.Date(2024, 1, 5, 5, 0, 0, 0, time.UTC)))
+	require.False(t, overnight.MatchTime(time.Date(2024, 1, 6, 22, 0, 0, 0, time.UTC)))
+
+	weekend, err := NewScheduleItem([]option.ScheduleOptions{{
+		Weekday:  []string{"sat-sun"},
+		Timezone: "UTC",
+	}})
+	require.NoError(t, err)
+	require.True(t, weekend.MatchTime(time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)))
+	require.False(t, weekend.MatchTime(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)))
+
+	_, err = NewScheduleItem([]option.ScheduleOptions{{Time: []string{"25:00-26:00"}}})
+	require.Error(t, err)
+}
+
+func TestCollectSchedules(t *testing.T) {
+	t.Parallel()
+	weekday := option.ScheduleOptions{Weekday: []string{"mon-fri"}, Timezone: "UTC"}
+	weekend := option.ScheduleOptions{Weekday: []string{"sat-sun"}, Timezone: "UTC"}
+	schedules := collectSchedules([]option.Rule{
+		{Type: C.RuleTypeDefault, DefaultOptions: option.DefaultRule{Schedule: []option.ScheduleOptions{weekday}}},
+		{Type: C.RuleTypeDefault, DefaultOptions: option.DefaultRule{Outbound: "direct"}},
+		{Type: C.RuleTypeLogical, LogicalOptions: option.LogicalRule{Rules: []option.Rule{
+			{Type: C.RuleTypeDefault, DefaultOptions: option.DefaultRule{Schedule: []option.ScheduleOptions{weekend}}},
+		}}},
+		{Type: C.RuleTypeDefault, DefaultOptions: option.DefaultRule{Schedule: []option.ScheduleOptions{{Time: []string{"25:00-26:00"}}}}},
+	})
+	require.Len(t, schedules, 2)
+	// 2024-01-01 is a Monday
+	monday := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.True(t, schedules[0].MatchTime(monday))
+	require.False(t, schedules[1].MatchTime(monday))
+}
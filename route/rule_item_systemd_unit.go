@@ -0,0 +1,43 @@
+package route
+
+import (
+	"strings"
+
+	"github.com/sagernet/sing-box/adapter"
+)
+
+var _ RuleItem = (*SystemdUnitItem)(nil)
+
+type SystemdUnitItem struct {
+	units   []string
+	unitMap map[string]bool
+}
+
+func NewSystemdUnitItem(unitList []string) *SystemdUnitItem {
+	rule := &SystemdUnitItem{
+		units:   unitList,
+		unitMap: make(map[string]bool),
+	}
+	for _, unit := range unitList {
+		rule.unitMap[unit] = true
+	}
+	return rule
+}
+
+func (r *SystemdUnitItem) Match(metadata *adapter.InboundContext) bool {
+	if metadata.ProcessInfo == nil || metadata.ProcessInfo.SystemdUnit == "" {
+		return false
+	}
+	return r.unitMap[metadata.ProcessInfo.SystemdUnit]
+}
+
+func (r *SystemdUnitItem) String() string {
+	var description string
+	pLen := len(r.units)
+	if pLen == 1 {
+		description = "systemd_unit=" + r.units[0]
+	} else {
+		description = "systemd_unit=[" + strings.Join(r.units, " ") + "]"
+	}
+	return description
+}
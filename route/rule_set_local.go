@@ -116,10 +116,11 @@ func (s *LocalRuleSet) reloadFile(path string) error {
 		if err != nil {
 			return err
 		}
-		plainRuleSet, err = srs.Read(setFile, false)
+		compat, err := srs.Read(setFile, false)
 		if err != nil {
 			return err
 		}
+		plainRuleSet = compat.Options
 	default:
 		return E.New("unknown rule-set format: ", s.fileFormat)
 	}
@@ -139,6 +140,8 @@ func (s *LocalRuleSet) reloadRules(headlessRules []option.HeadlessRule) error {
 	metadata.ContainsProcessRule = hasHeadlessRule(headlessRules, isProcessHeadlessRule)
 	metadata.ContainsWIFIRule = hasHeadlessRule(headlessRules, isWIFIHeadlessRule)
 	metadata.ContainsIPCIDRRule = hasHeadlessRule(headlessRules, isIPCIDRHeadlessRule)
+	metadata.ContainsASNRule = hasHeadlessRule(headlessRules, isASNHeadlessRule)
+	metadata.Schedules = collectHeadlessSchedules(headlessRules)
 	s.rules = rules
 	s.metadata = metadata
 	return nil
@@ -173,10 +173,12 @@ func (s *RemoteRuleSet) loadBytes(content []byte) error {
 			return err
 		}
 	case C.RuleSetFormatBinary:
-		plainRuleSet, err = srs.Read(bytes.NewReader(content), false)
+		var compat option.PlainRuleSetCompat
+		compat, err = srs.Read(bytes.NewReader(content), false)
 		if err != nil {
 			return err
 		}
+		plainRuleSet = compat.Options
 	default:
 		return E.New("unknown rule-set format: ", s.options.Format)
 	}
@@ -190,6 +192,8 @@ func (s *RemoteRuleSet) loadBytes(content []byte) error {
 	s.metadata.ContainsProcessRule = hasHeadlessRule(plainRuleSet.Rules, isProcessHeadlessRule)
 	s.metadata.ContainsWIFIRule = hasHeadlessRule(plainRuleSet.Rules, isWIFIHeadlessRule)
 	s.metadata.ContainsIPCIDRRule = hasHeadlessRule(plainRuleSet.Rules, isIPCIDRHeadlessRule)
+	s.metadata.ContainsASNRule = hasHeadlessRule(plainRuleSet.Rules, isASNHeadlessRule)
+	s.metadata.Schedules = collectHeadlessSchedules(plainRuleSet.Rules)
 	s.rules = rules
 	s.callbackAccess.Lock()
 	callbacks := s.callbacks.Array()
@@ -0,0 +1,296 @@
+package bond
+
+import (
+	std_bufio "bufio"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"io"
+	"net"
+
+	"github.com/sagernet/sing-box/common/failure"
+	E "github.com/sagernet/sing/common/exceptions"
+	"github.com/sagernet/sing/common/rw"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	Version         = 1
+	KeyLength       = sha256.Size
+	SessionIDLength = 16
+	secretLength    = 32
+	publicKeyLength = 32
+)
+
+const (
+	StatusOK = iota
+	StatusSessionMismatch
+)
+
+const (
+	frameData = iota
+	frameAck
+	frameFin
+)
+
+const (
+	maxPayloadSize = 16 * 1024
+	frameHeaderLen = 9
+	sendWindow     = 256
+)
+
+var keyInfo = []byte("sing-box bond link")
+
+// SessionKey identifies a session and authorizes links to join it.
+// The secret is only sent over encrypted links, so a peer knowing the password but not the secret cannot join.
+type SessionKey struct {
+	ID     [SessionIDLength]byte
+	Secret [secretLength]byte
+}
+
+func NewSessionKey() (SessionKey, error) {
+	var key SessionKey
+	_, err := rand.Read(key.ID[:])
+	if err != nil {
+		return key, err
+	}
+	_, err = rand.Read(key.Secret[:])
+	return key, err
+}
+
+func (k SessionKey) Equal(other SessionKey) bool {
+	return subtle.ConstantTimeCompare(k.Secret[:], other.Secret[:]) == 1
+}
+
+func Key(password string) [KeyLength]byte {
+	return sha256.Sum256([]byte(password))
+}
+
+// Link is an authenticated and encrypted connection carrying frames of a session.
+type Link struct {
+	net.Conn
+	bufferedReader *std_bufio.Reader
+	reader         *linkCipher
+	writer         *linkCipher
+}
+
+func (l *Link) Upstream() any {
+	return l.Conn
+}
+
+type linkCipher struct {
+	aead  cipher.AEAD
+	nonce [chacha20poly1305.NonceSize]byte
+}
+
+func (c *linkCipher) increaseNonce() {
+	for i := range c.nonce {
+		c.nonce[i]++
+		if c.nonce[i] != 0 {
+			return
+		}
+	}
+}
+
+// handshake exchanges ephemeral X25519 keys and derives the link keys from the shared secret and the password,
+// so each link has fresh keys and a recorded handshake cannot be replayed.
+func handshake(conn net.Conn, key [KeyLength]byte, isClient bool) (*Link, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	localPublicKey := privateKey.PublicKey().Bytes()
+	var remotePublicKey [publicKeyLength]byte
+	if isClient {
+		err = rw.WriteBytes(conn, append([]byte{Version}, localPublicKey...))
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.ReadFull(conn, remotePublicKey[:])
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		version, err := rw.ReadByte(conn)
+		if err != nil {
+			return nil, err
+		}
+		if version != Version {
+			return nil, E.New("bond: unknown version: ", version)
+		}
+		_, err = io.ReadFull(conn, remotePublicKey[:])
+		if err != nil {
+			return nil, err
+		}
+		err = rw.WriteBytes(conn, localPublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	publicKey, err := ecdh.X25519().NewPublicKey(remotePublicKey[:])
+	if err != nil {
+		return nil, err
+	}
+	sharedKey, err := privateKey.ECDH(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	clientPublicKey, serverPublicKey := localPublicKey, remotePublicKey[:]
+	if !isClient {
+		clientPublicKey, serverPublicKey = serverPublicKey, clientPublicKey
+	}
+	info := append(append(append([]byte(nil), keyInfo...), clientPublicKey...), serverPublicKey...)
+	var keys [2 * chacha20poly1305.KeySize]byte
+	_, err = io.ReadFull(hkdf.New(sha256.New, sharedKey, key[:], info), keys[:])
+	if err != nil {
+		return nil, err
+	}
+	clientCipher, err := chacha20poly1305.New(keys[:chacha20poly1305.KeySize])
+	if err != nil {
+		return nil, err
+	}
+	serverCipher, err := chacha20poly1305.New(keys[chacha20poly1305.KeySize:])
+	if err != nil {
+		return nil, err
+	}
+	link := &Link{Conn: conn, bufferedReader: std_bufio.NewReader(conn)}
+	if isClient {
+		link.writer, link.reader = &linkCipher{aead: clientCipher}, &linkCipher{aead: serverCipher}
+	} else {
+		link.writer, link.reader = &linkCipher{aead: serverCipher}, &linkCipher{aead: clientCipher}
+	}
+	return link, nil
+}
+
+// ClientHandshake authenticates a new link of the session.
+func ClientHandshake(conn net.Conn, key [KeyLength]byte, sessionKey SessionKey) (*Link, error) {
+	link, err := handshake(conn, key, true)
+	if err != nil {
+		return nil, err
+	}
+	err = link.writeRecord(append(sessionKey.ID[:], sessionKey.Secret[:]...))
+	if err != nil {
+		return nil, err
+	}
+	response, err := link.readRecord()
+	if err != nil {
+		return nil, failure.AuthError(E.Cause(err, "bond: authentication failed"))
+	}
+	if len(response) != 1 {
+		return nil, E.New("bond: invalid response")
+	}
+	switch response[0] {
+	case StatusOK:
+		return link, nil
+	case StatusSessionMismatch:
+		return nil, E.New("bond: session mismatch")
+	default:
+		return nil, E.New("bond: unknown status: ", response[0])
+	}
+}
+
+// ServerHandshake authenticates a link, the session key is checked by accept before the response is sent.
+func ServerHandshake(conn net.Conn, key [KeyLength]byte, accept func(sessionKey SessionKey) bool) (*Link, SessionKey, error) {
+	var sessionKey SessionKey
+	link, err := handshake(conn, key, false)
+	if err != nil {
+		return nil, sessionKey, err
+	}
+	request, err := link.readRecord()
+	if err != nil {
+		return nil, sessionKey, E.Cause(err, "bond: authentication failed")
+	}
+	if len(request) != SessionIDLength+secretLength {
+		return nil, sessionKey, E.New("bond: invalid request")
+	}
+	copy(sessionKey.ID[:], request)
+	copy(sessionKey.Secret[:], request[SessionIDLength:])
+	if !accept(sessionKey) {
+		link.writeRecord([]byte{StatusSessionMismatch})
+		return nil, sessionKey, E.New("bond: session mismatch")
+	}
+	err = link.writeRecord([]byte{StatusOK})
+	if err != nil {
+		return nil, sessionKey, err
+	}
+	return link, sessionKey, nil
+}
+
+// record layout: length(2) sealed(content)
+func (l *Link) writeRecord(content []byte) error {
+	record := make([]byte, 2, 2+len(content)+l.writer.aead.Overhead())
+	record = l.writer.aead.Seal(record, l.writer.nonce[:], content, nil)
+	binary.BigEndian.PutUint16(record, uint16(len(record)-2))
+	l.writer.increaseNonce()
+	return rw.WriteBytes(l.Conn, record)
+}
+
+func (l *Link) readRecord() ([]byte, error) {
+	var length uint16
+	err := binary.Read(l.bufferedReader, binary.BigEndian, &length)
+	if err != nil {
+		return nil, err
+	}
+	if int(length) < l.reader.aead.Overhead() {
+		return nil, E.New("bond: invalid record length: ", length)
+	}
+	record := make([]byte, length)
+	_, err = io.ReadFull(l.bufferedReader, record)
+	if err != nil {
+		return nil, err
+	}
+	content, err := l.reader.aead.Open(record[:0], l.reader.nonce[:], record, nil)
+	if err != nil {
+		return nil, err
+	}
+	l.reader.increaseNonce()
+	return content, nil
+}
+
+type frame struct {
+	frameType byte
+	seq       uint64
+	payload   []byte
+}
+
+// frame layout: type(1) seq(8) [payload] for data frames, sealed in one record
+func (l *Link) writeFrame(frame frame) error {
+	content := make([]byte, frameHeaderLen, frameHeaderLen+len(frame.payload))
+	content[0] = frame.frameType
+	binary.BigEndian.PutUint64(content[1:], frame.seq)
+	if frame.frameType == frameData {
+		content = append(content, frame.payload...)
+	}
+	return l.writeRecord(content)
+}
+
+func (l *Link) readFrame() (frame, error) {
+	content, err := l.readRecord()
+	if err != nil {
+		return frame{}, err
+	}
+	if len(content) < frameHeaderLen {
+		return frame{}, E.New("bond: invalid frame length: ", len(content))
+	}
+	result := frame{
+		frameType: content[0],
+		seq:       binary.BigEndian.Uint64(content[1:]),
+	}
+	switch result.frameType {
+	case frameData:
+		length := len(content) - frameHeaderLen
+		if length == 0 || length > maxPayloadSize {
+			return frame{}, E.New("bond: invalid frame length: ", length)
+		}
+		result.payload = content[frameHeaderLen:]
+	case frameAck, frameFin:
+	default:
+		return frame{}, E.New("bond: unknown frame type: ", result.frameType)
+	}
+	return result, nil
+}
@@ -0,0 +1,489 @@
+package bond
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	E "github.com/sagernet/sing/common/exceptions"
+	N "github.com/sagernet/sing/common/network"
+)
+
+const (
+	ackInterval  = 16
+	closeTimeout = 5 * time.Second
+)
+
+var _ net.Conn = (*Session)(nil)
+
+// Session is one logical stream striped across several links.
+//
+// Data frames carry a per-session sequence number and are kept until the peer acknowledges them,
+// so frames written to a link that fails are sent again over the remaining links.
+// The peer acknowledges frames only after the application has read them, which bounds the reorder buffer
+// on both sides by the send window.
+type Session struct {
+	access      sync.Mutex
+	cond        *sync.Cond
+	linkTimeout time.Duration
+	onLinkLost  func(name string, err error)
+	links       []*link
+	linkTimer   *time.Timer
+	stalled     bool
+
+	sendSeq uint64
+	unacked []frame
+
+	recvSeq     uint64
+	consumedSeq uint64
+	ackedSeq    uint64
+	pending     map[uint64][]byte
+	readQueue   [][]byte
+	readOffset  int
+
+	finSeq      uint64
+	finReceived bool
+	closed      bool
+	err         error
+
+	readDeadline  time.Time
+	writeDeadline time.Time
+	localAddr     net.Addr
+	remoteAddr    net.Addr
+}
+
+// NewSession creates an empty session. The session is closed if it has no links for linkTimeout,
+// onLinkLost is called for each link lost before the session is closed.
+func NewSession(linkTimeout time.Duration, onLinkLost func(name string, err error)) *Session {
+	session := &Session{
+		linkTimeout: linkTimeout,
+		onLinkLost:  onLinkLost,
+		pending:     make(map[uint64][]byte),
+	}
+	session.cond = sync.NewCond(&session.access)
+	return session
+}
+
+type link struct {
+	session   *Session
+	conn      *Link
+	name      string
+	frames    chan frame
+	ackSignal chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *link) enqueue(frame frame) {
+	select {
+	case l.frames <- frame:
+	case <-l.done:
+	}
+}
+
+func (l *link) signalAck() {
+	select {
+	case l.ackSignal <- struct{}{}:
+	default:
+	}
+}
+
+func (l *link) close() {
+	l.closeOnce.Do(func() {
+		l.conn.Close()
+		close(l.done)
+	})
+}
+
+// AddLink attaches an authenticated link to the session.
+// The returned channel is closed when the link is removed from the session.
+func (s *Session) AddLink(conn *Link, name string) (<-chan struct{}, error) {
+	newLink := &link{
+		session:   s,
+		conn:      conn,
+		name:      name,
+		frames:    make(chan frame, sendWindow*2),
+		ackSignal: make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	s.access.Lock()
+	if s.closed || s.finReceived {
+		s.access.Unlock()
+		return nil, net.ErrClosed
+	}
+	if s.localAddr == nil {
+		s.localAddr = conn.LocalAddr()
+		s.remoteAddr = conn.RemoteAddr()
+	}
+	s.links = append(s.links, newLink)
+	if s.linkTimer != nil {
+		s.linkTimer.Stop()
+		s.linkTimer = nil
+	}
+	var retransmit []frame
+	if s.stalled {
+		retransmit = append(retransmit, s.unacked...)
+		s.stalled = false
+	}
+	s.cond.Broadcast()
+	s.access.Unlock()
+	go s.loopRead(newLink)
+	go s.loopWrite(newLink)
+	for _, it := range retransmit {
+		newLink.enqueue(it)
+	}
+	newLink.signalAck()
+	return newLink.done, nil
+}
+
+func (s *Session) Links() int {
+	s.access.Lock()
+	defer s.access.Unlock()
+	return len(s.links)
+}
+
+func (s *Session) IsClosed() bool {
+	s.access.Lock()
+	defer s.access.Unlock()
+	return s.closed || s.finReceived
+}
+
+func (s *Session) loopRead(l *link) {
+	for {
+		received, err := l.conn.readFrame()
+		if err != nil {
+			s.linkFailed(l, err)
+			return
+		}
+		switch received.frameType {
+		case frameData:
+			err = s.receiveData(l, received)
+		case frameAck:
+			s.receiveAck(received.seq)
+		case frameFin:
+			s.receiveFin(received.seq)
+		}
+		if err != nil {
+			s.linkFailed(l, err)
+			return
+		}
+	}
+}
+
+func (s *Session) loopWrite(l *link) {
+	for {
+		select {
+		case pending := <-l.frames:
+			err := l.conn.writeFrame(pending)
+			if err != nil {
+				s.linkFailed(l, err)
+				return
+			}
+			if pending.frameType == frameFin {
+				// keep reading until the peer closes, closing with unread acks would reset the link
+				N.CloseWrite(l.conn)
+				l.conn.SetReadDeadline(time.Now().Add(closeTimeout))
+				return
+			}
+		case <-l.ackSignal:
+			s.access.Lock()
+			ack := s.consumedSeq
+			s.access.Unlock()
+			err := l.conn.writeFrame(frame{frameType: frameAck, seq: ack})
+			if err != nil {
+				s.linkFailed(l, err)
+				return
+			}
+		case <-l.done:
+			return
+		}
+	}
+}
+
+func (s *Session) receiveData(l *link, received frame) error {
+	s.access.Lock()
+	defer s.access.Unlock()
+	if s.closed {
+		return nil
+	}
+	if received.seq >= s.consumedSeq+sendWindow {
+		return E.New("bond: frame out of window: ", received.seq)
+	}
+	if received.seq < s.recvSeq {
+		// duplicate from a retransmission, the ack for it may have been lost with a failed link
+		l.signalAck()
+		return nil
+	}
+	if _, loaded := s.pending[received.seq]; loaded {
+		return nil
+	}
+	s.pending[received.seq] = received.payload
+	for {
+		payload, loaded := s.pending[s.recvSeq]
+		if !loaded {
+			break
+		}
+		delete(s.pending, s.recvSeq)
+		s.readQueue = append(s.readQueue, payload)
+		s.recvSeq++
+	}
+	s.cond.Broadcast()
+	return nil
+}
+
+func (s *Session) receiveAck(ack uint64) {
+	s.access.Lock()
+	defer s.access.Unlock()
+	var acked int
+	for acked < len(s.unacked) && s.unacked[acked].seq < ack {
+		acked++
+	}
+	if acked > 0 {
+		s.unacked = s.unacked[acked:]
+		s.cond.Broadcast()
+	}
+}
+
+func (s *Session) receiveFin(seq uint64) {
+	s.access.Lock()
+	defer s.access.Unlock()
+	s.finReceived = true
+	s.finSeq = seq
+	s.cond.Broadcast()
+}
+
+func (s *Session) linkFailed(l *link, err error) {
+	s.access.Lock()
+	index := -1
+	for i, it := range s.links {
+		if it == l {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		s.access.Unlock()
+		l.close()
+		return
+	}
+	s.links = append(s.links[:index], s.links[index+1:]...)
+	finished := s.closed || s.finReceived
+	var (
+		retransmit []frame
+		targets    []*link
+	)
+	if !finished {
+		if len(s.links) == 0 {
+			s.stalled = true
+			if s.linkTimeout > 0 && s.linkTimer == nil {
+				s.linkTimer = time.AfterFunc(s.linkTimeout, s.checkLinkTimeout)
+			}
+		} else {
+			retransmit = append(retransmit, s.unacked...)
+			targets = append(targets, s.links...)
+		}
+	}
+	s.cond.Broadcast()
+	s.access.Unlock()
+	l.close()
+	if finished {
+		return
+	}
+	if s.onLinkLost != nil {
+		s.onLinkLost(l.name, err)
+	}
+	for i, it := range retransmit {
+		targets[i%len(targets)].enqueue(it)
+	}
+	if len(targets) > 0 {
+		targets[0].signalAck()
+	}
+}
+
+func (s *Session) checkLinkTimeout() {
+	s.access.Lock()
+	defer s.access.Unlock()
+	s.linkTimer = nil
+	if len(s.links) == 0 && !s.closed {
+		s.closeLocked(E.New("bond: all links lost"))
+	}
+}
+
+func (s *Session) closeLocked(err error) {
+	s.closed = true
+	s.err = err
+	for _, it := range s.links {
+		it.close()
+	}
+	s.links = nil
+	s.cond.Broadcast()
+}
+
+// selectLink returns the link with the fewest queued frames, so slower links receive less data.
+func (s *Session) selectLink() *link {
+	selected := s.links[0]
+	for _, it := range s.links[1:] {
+		if len(it.frames) < len(selected.frames) {
+			selected = it
+		}
+	}
+	return selected
+}
+
+func (s *Session) Read(p []byte) (n int, err error) {
+	s.access.Lock()
+	for len(s.readQueue) == 0 {
+		if s.finReceived && s.recvSeq >= s.finSeq {
+			s.access.Unlock()
+			return 0, io.EOF
+		}
+		if s.closed {
+			s.access.Unlock()
+			return 0, s.closeError()
+		}
+		if !s.readDeadline.IsZero() && !time.Now().Before(s.readDeadline) {
+			s.access.Unlock()
+			return 0, os.ErrDeadlineExceeded
+		}
+		s.cond.Wait()
+	}
+	n = copy(p, s.readQueue[0][s.readOffset:])
+	s.readOffset += n
+	var ackLink *link
+	if s.readOffset == len(s.readQueue[0]) {
+		s.readQueue = s.readQueue[1:]
+		s.readOffset = 0
+		s.consumedSeq++
+		if len(s.links) > 0 && (s.consumedSeq-s.ackedSeq >= ackInterval || len(s.readQueue) == 0) {
+			s.ackedSeq = s.consumedSeq
+			ackLink = s.selectLink()
+		}
+	}
+	s.access.Unlock()
+	if ackLink != nil {
+		ackLink.signalAck()
+	}
+	return
+}
+
+func (s *Session) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		s.access.Lock()
+		for {
+			if s.closed {
+				s.access.Unlock()
+				return n, s.closeError()
+			}
+			if s.finReceived {
+				s.access.Unlock()
+				return n, io.ErrClosedPipe
+			}
+			if !s.writeDeadline.IsZero() && !time.Now().Before(s.writeDeadline) {
+				s.access.Unlock()
+				return n, os.ErrDeadlineExceeded
+			}
+			if len(s.unacked) < sendWindow && len(s.links) > 0 {
+				break
+			}
+			s.cond.Wait()
+		}
+		size := len(p)
+		if size > maxPayloadSize {
+			size = maxPayloadSize
+		}
+		pending := frame{
+			frameType: frameData,
+			seq:       s.sendSeq,
+			payload:   append([]byte(nil), p[:size]...),
+		}
+		s.sendSeq++
+		s.unacked = append(s.unacked, pending)
+		selected := s.selectLink()
+		s.access.Unlock()
+		selected.enqueue(pending)
+		n += size
+		p = p[size:]
+	}
+	return
+}
+
+func (s *Session) closeError() error {
+	if s.err != nil {
+		return s.err
+	}
+	return net.ErrClosed
+}
+
+func (s *Session) Close() error {
+	s.access.Lock()
+	if s.closed {
+		s.access.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.linkTimer != nil {
+		s.linkTimer.Stop()
+		s.linkTimer = nil
+	}
+	fin := frame{frameType: frameFin, seq: s.sendSeq}
+	links := append([]*link(nil), s.links...)
+	s.cond.Broadcast()
+	s.access.Unlock()
+	for _, it := range links {
+		// queued frames are flushed before the fin, but a stuck link must not hold the session open
+		it.conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+		it.enqueue(fin)
+	}
+	return nil
+}
+
+func (s *Session) LocalAddr() net.Addr {
+	s.access.Lock()
+	defer s.access.Unlock()
+	return s.localAddr
+}
+
+func (s *Session) RemoteAddr() net.Addr {
+	s.access.Lock()
+	defer s.access.Unlock()
+	return s.remoteAddr
+}
+
+func (s *Session) SetDeadline(t time.Time) error {
+	s.access.Lock()
+	s.readDeadline = t
+	s.writeDeadline = t
+	s.access.Unlock()
+	s.wakeAt(t)
+	return nil
+}
+
+func (s *Session) SetReadDeadline(t time.Time) error {
+	s.access.Lock()
+	s.readDeadline = t
+	s.access.Unlock()
+	s.wakeAt(t)
+	return nil
+}
+
+func (s *Session) SetWriteDeadline(t time.Time) error {
+	s.access.Lock()
+	s.writeDeadline = t
+	s.access.Unlock()
+	s.wakeAt(t)
+	return nil
+}
+
+func (s *Session) wakeAt(t time.Time) {
+	s.cond.Broadcast()
+	if t.IsZero() {
+		return
+	}
+	time.AfterFunc(time.Until(t), func() {
+		s.access.Lock()
+		s.cond.Broadcast()
+		s.access.Unlock()
+	})
+}
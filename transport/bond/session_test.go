@@ -0,0 +1,236 @@
+package bond
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLink(t *testing.T, sessionKey SessionKey) (*Link, *Link) {
+	key := Key("password")
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() {
+		clientConn.Close()
+		serverConn.Close()
+	})
+	type serverResult struct {
+		link *Link
+		err  error
+	}
+	serverDone := make(chan serverResult, 1)
+	go func() {
+		link, _, err := ServerHandshake(serverConn, key, func(accepted SessionKey) bool {
+			return accepted.ID == sessionKey.ID && accepted.Equal(sessionKey)
+		})
+		serverDone <- serverResult{link, err}
+	}()
+	clientLink, err := ClientHandshake(clientConn, key, sessionKey)
+	require.NoError(t, err)
+	result := <-serverDone
+	require.NoError(t, result.err)
+	return clientLink, result.link
+}
+
+func newTestSessionKey(t *testing.T) SessionKey {
+	sessionKey, err := NewSessionKey()
+	require.NoError(t, err)
+	return sessionKey
+}
+
+func newTestSessions(t *testing.T) (*Session, *Session) {
+	client := NewSession(time.Minute, nil)
+	server := NewSession(time.Minute, nil)
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return client, server
+}
+
+func addTestLink(t *testing.T, sessionKey SessionKey, client *Session, server *Session, name string) {
+	clientLink, serverLink := newTestLink(t, sessionKey)
+	_, err := client.AddLink(clientLink, name)
+	require.NoError(t, err)
+	_, err = server.AddLink(serverLink, name)
+	require.NoError(t, err)
+}
+
+func readTestData(t *testing.T, session *Session, length int) []byte {
+	require.NoError(t, session.SetReadDeadline(time.Now().Add(5*time.Second)))
+	data := make([]byte, length)
+	_, err := io.ReadFull(session, data)
+	require.NoError(t, err)
+	return data
+}
+
+func TestHandshake(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	clientLink, serverLink := newTestLink(t, sessionKey)
+	go clientLink.writeFrame(frame{frameType: frameData, seq: 1, payload: []byte("hello")})
+	received, err := serverLink.readFrame()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), received.seq)
+	require.Equal(t, []byte("hello"), received.payload)
+}
+
+func TestHandshakeWrongPassword(t *testing.T) {
+	t.Parallel()
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+	go func() {
+		ServerHandshake(serverConn, Key("password"), func(SessionKey) bool {
+			return true
+		})
+		serverConn.Close()
+	}()
+	_, err := ClientHandshake(clientConn, Key("wrong"), newTestSessionKey(t))
+	require.Error(t, err)
+}
+
+func TestHandshakeSessionMismatch(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	forged := sessionKey
+	forged.Secret[0]++
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+	go ServerHandshake(serverConn, Key("password"), func(accepted SessionKey) bool {
+		return accepted.Equal(sessionKey)
+	})
+	_, err := ClientHandshake(clientConn, Key("password"), forged)
+	require.ErrorContains(t, err, "session mismatch")
+}
+
+func TestSessionStriping(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	client, server := newTestSessions(t)
+	addTestLink(t, sessionKey, client, server, "a")
+	addTestLink(t, sessionKey, client, server, "b")
+	data := make([]byte, 1024*1024)
+	rand.Read(data)
+	go client.Write(data)
+	require.True(t, bytes.Equal(data, readTestData(t, server, len(data))))
+}
+
+func TestSessionReorder(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	server := NewSession(time.Minute, nil)
+	defer server.Close()
+	clientLink, serverLink := newTestLink(t, sessionKey)
+	_, err := server.AddLink(serverLink, "a")
+	require.NoError(t, err)
+	go func() {
+		clientLink.writeFrame(frame{frameType: frameData, seq: 2, payload: []byte("c")})
+		clientLink.writeFrame(frame{frameType: frameData, seq: 1, payload: []byte("b")})
+		clientLink.writeFrame(frame{frameType: frameData, seq: 1, payload: []byte("b")})
+		clientLink.writeFrame(frame{frameType: frameData, seq: 0, payload: []byte("a")})
+		for {
+			_, err := clientLink.readFrame()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	require.Equal(t, []byte("abc"), readTestData(t, server, 3))
+}
+
+func TestSessionLinkFailure(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	client, server := newTestSessions(t)
+	lostLink, peerLink := newTestLink(t, sessionKey)
+	_, err := client.AddLink(lostLink, "lost")
+	require.NoError(t, err)
+	// the peer of the lost link drops everything it receives
+	dropped := make(chan struct{}, sendWindow)
+	go func() {
+		for {
+			_, err := peerLink.readFrame()
+			if err != nil {
+				return
+			}
+			dropped <- struct{}{}
+		}
+	}()
+	_, err = client.Write([]byte("hello"))
+	require.NoError(t, err)
+	<-dropped
+	addTestLink(t, sessionKey, client, server, "a")
+	peerLink.Close()
+	require.Equal(t, []byte("hello"), readTestData(t, server, 5))
+	_, err = client.Write([]byte("world"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("world"), readTestData(t, server, 5))
+}
+
+func TestSessionLinkRestore(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	client, server := newTestSessions(t)
+	lostLink, peerLink := newTestLink(t, sessionKey)
+	_, err := client.AddLink(lostLink, "lost")
+	require.NoError(t, err)
+	go func() {
+		for {
+			_, err := peerLink.readFrame()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	_, err = client.Write([]byte("hello"))
+	require.NoError(t, err)
+	peerLink.Close()
+	require.Eventually(t, func() bool {
+		return client.Links() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	addTestLink(t, sessionKey, client, server, "a")
+	require.Equal(t, []byte("hello"), readTestData(t, server, 5))
+}
+
+func TestSessionLinkTimeout(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	lost := make(chan string, 1)
+	client := NewSession(100*time.Millisecond, func(name string, err error) {
+		lost <- name
+	})
+	defer client.Close()
+	clientLink, serverLink := newTestLink(t, sessionKey)
+	_, err := client.AddLink(clientLink, "a")
+	require.NoError(t, err)
+	serverLink.Close()
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = client.Read(make([]byte, 1))
+	require.ErrorContains(t, err, "all links lost")
+	require.Equal(t, "a", <-lost)
+	_, err = client.AddLink(clientLink, "a")
+	require.ErrorIs(t, err, net.ErrClosed)
+}
+
+func TestSessionClose(t *testing.T) {
+	t.Parallel()
+	sessionKey := newTestSessionKey(t)
+	client, server := newTestSessions(t)
+	addTestLink(t, sessionKey, client, server, "a")
+	addTestLink(t, sessionKey, client, server, "b")
+	_, err := client.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+	require.Equal(t, []byte("hello"), readTestData(t, server, 5))
+	_, err = server.Read(make([]byte, 1))
+	require.ErrorIs(t, err, io.EOF)
+	require.True(t, server.IsClosed())
+	_, err = client.Write([]byte("world"))
+	require.ErrorIs(t, err, net.ErrClosed)
+}
@@ -5,12 +5,13 @@ import (
 	"net"
 
 	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/log"
 	E "github.com/sagernet/sing/common/exceptions"
 	M "github.com/sagernet/sing/common/metadata"
 	N "github.com/sagernet/sing/common/network"
 )
 
-type PluginConstructor func(ctx context.Context, pluginArgs Args, router adapter.Router, dialer N.Dialer, serverAddr M.Socksaddr) (Plugin, error)
+type PluginConstructor func(ctx context.Context, logger log.Logger, pluginArgs Args, router adapter.Router, dialer N.Dialer, serverAddr M.Socksaddr) (Plugin, error)
 
 type Plugin interface {
 	DialContext(ctx context.Context) (net.Conn, error)
@@ -25,7 +26,7 @@ func RegisterPlugin(name string, constructor PluginConstructor) {
 	plugins[name] = constructor
 }
 
-func CreatePlugin(ctx context.Context, name string, pluginArgs string, router adapter.Router, dialer N.Dialer, serverAddr M.Socksaddr) (Plugin, error) {
+func CreatePlugin(ctx context.Context, logger log.Logger, name string, pluginArgs string, router adapter.Router, dialer N.Dialer, serverAddr M.Socksaddr) (Plugin, error) {
 	pluginOptions, err := ParsePluginOptions(pluginArgs)
 	if err != nil {
 		return nil, E.Cause(err, "parse plugin_opts")
@@ -34,5 +35,5 @@ func CreatePlugin(ctx context.Context, name string, pluginArgs string, router ad
 	if !loaded {
 		return nil, E.New("plugin not found: ", name)
 	}
-	return constructor(ctx, pluginOptions, router, dialer, serverAddr)
+	return constructor(ctx, logger, pluginOptions, router, dialer, serverAddr)
 }
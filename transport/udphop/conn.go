@@ -0,0 +1,200 @@
+package udphop
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing/common/buf"
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+	"github.com/sagernet/sing/common/pipe"
+)
+
+var _ N.Dialer = (*Dialer)(nil)
+
+// Dialer dials UDP connections that hop between the server ports.
+// TCP connections and packet listeners are passed to the upstream dialer.
+type Dialer struct {
+	dialer   N.Dialer
+	ports    []uint16
+	interval time.Duration
+}
+
+func NewDialer(dialer N.Dialer, ports []uint16, interval time.Duration) *Dialer {
+	return &Dialer{dialer, ports, interval}
+}
+
+func (d *Dialer) DialContext(ctx context.Context, network string, destination M.Socksaddr) (net.Conn, error) {
+	if N.NetworkName(network) != N.NetworkUDP {
+		return d.dialer.DialContext(ctx, network, destination)
+	}
+	return NewConn(ctx, d.dialer, destination, d.ports, d.interval)
+}
+
+func (d *Dialer) ListenPacket(ctx context.Context, destination M.Socksaddr) (net.PacketConn, error) {
+	return d.dialer.ListenPacket(ctx, destination)
+}
+
+// Conn is a UDP connection that switches to a random server port every
+// hop interval. Packets arriving on the previous port are still received
+// until the next hop, so in-flight packets are not lost.
+type Conn struct {
+	ctx         context.Context
+	dialer      N.Dialer
+	destination M.Socksaddr
+	ports       []uint16
+	remoteAddr  net.Addr
+	access      sync.Mutex
+	current     net.Conn
+	previous    net.Conn
+	packets     chan *buf.Buffer
+	deadline    pipe.Deadline
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewConn(ctx context.Context, dialer N.Dialer, destination M.Socksaddr, ports []uint16, interval time.Duration) (*Conn, error) {
+	conn := &Conn{
+		ctx:         ctx,
+		dialer:      dialer,
+		destination: destination,
+		ports:       ports,
+		packets:     make(chan *buf.Buffer, 64),
+		deadline:    pipe.MakeDeadline(),
+		done:        make(chan struct{}),
+	}
+	udpConn, err := conn.dial(0)
+	if err != nil {
+		return nil, err
+	}
+	conn.current = udpConn
+	conn.remoteAddr = udpConn.RemoteAddr()
+	go conn.loopRead(udpConn)
+	go conn.loopHop(interval)
+	return conn, nil
+}
+
+func (c *Conn) dial(excludePort uint16) (net.Conn, error) {
+	index := rand.Intn(len(c.ports))
+	if c.ports[index] == excludePort && len(c.ports) > 1 {
+		index = (index + 1 + rand.Intn(len(c.ports)-1)) % len(c.ports)
+	}
+	destination := c.destination
+	destination.Port = c.ports[index]
+	return c.dialer.DialContext(c.ctx, N.NetworkUDP, destination)
+}
+
+func (c *Conn) loopHop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.done:
+			return
+		}
+		c.access.Lock()
+		currentPort := M.SocksaddrFromNet(c.current.RemoteAddr()).Port
+		c.access.Unlock()
+		udpConn, err := c.dial(currentPort)
+		if err != nil {
+			// keep the current port and retry on the next hop
+			continue
+		}
+		c.access.Lock()
+		select {
+		case <-c.done:
+			c.access.Unlock()
+			udpConn.Close()
+			return
+		default:
+		}
+		if c.previous != nil {
+			c.previous.Close()
+		}
+		c.previous = c.current
+		c.current = udpConn
+		c.access.Unlock()
+		go c.loopRead(udpConn)
+	}
+}
+
+func (c *Conn) loopRead(conn net.Conn) {
+	for {
+		buffer := buf.NewPacket()
+		_, err := buffer.ReadOnceFrom(conn)
+		if err != nil {
+			buffer.Release()
+			return
+		}
+		select {
+		case c.packets <- buffer:
+		case <-c.done:
+			buffer.Release()
+			return
+		}
+	}
+}
+
+func (c *Conn) Read(b []byte) (n int, err error) {
+	select {
+	case buffer := <-c.packets:
+		n = copy(b, buffer.Bytes())
+		buffer.Release()
+		return
+	case <-c.deadline.Wait():
+		return 0, os.ErrDeadlineExceeded
+	case <-c.done:
+		return 0, net.ErrClosed
+	}
+}
+
+func (c *Conn) Write(b []byte) (n int, err error) {
+	c.access.Lock()
+	conn := c.current
+	c.access.Unlock()
+	return conn.Write(b)
+}
+
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		c.access.Lock()
+		close(c.done)
+		if c.previous != nil {
+			c.previous.Close()
+		}
+		c.current.Close()
+		c.access.Unlock()
+	})
+	return nil
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	c.access.Lock()
+	defer c.access.Unlock()
+	return c.current.LocalAddr()
+}
+
+// RemoteAddr returns the address of the first dialed port,
+// which stays the same after hops.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// SetDeadline only sets the read deadline, writes to UDP sockets do not block.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.deadline.Set(t)
+	return nil
+}
+
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
@@ -0,0 +1,62 @@
+package udphop
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	M "github.com/sagernet/sing/common/metadata"
+	N "github.com/sagernet/sing/common/network"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHop(t *testing.T) {
+	t.Parallel()
+	var (
+		packetConns []net.PacketConn
+		ports       []uint16
+	)
+	for i := 0; i < 3; i++ {
+		packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		packetConns = append(packetConns, packetConn)
+		ports = append(ports, M.SocksaddrFromNet(packetConn.LocalAddr()).Port)
+	}
+	server := NewServerConn(packetConns)
+	defer server.Close()
+	go func() {
+		buffer := make([]byte, 64)
+		for {
+			n, addr, err := server.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+			server.WriteTo(buffer[:n], addr)
+		}
+	}()
+	client, err := NewConn(context.Background(), N.SystemDialer, M.ParseSocksaddrHostPort("127.0.0.1", 0), ports, 20*time.Millisecond)
+	require.NoError(t, err)
+	defer client.Close()
+	remoteAddr := client.RemoteAddr()
+	dialedPorts := make(map[uint16]bool)
+	buffer := make([]byte, 64)
+	for i := 0; i < 20; i++ {
+		client.access.Lock()
+		dialedPorts[M.SocksaddrFromNet(client.current.RemoteAddr()).Port] = true
+		client.access.Unlock()
+		_, err = client.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, client.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, err := client.Read(buffer)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(buffer[:n]))
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Greater(t, len(dialedPorts), 1)
+	require.Equal(t, remoteAddr, client.RemoteAddr())
+	require.NoError(t, client.Close())
+	_, err = client.Read(buffer)
+	require.ErrorIs(t, err, net.ErrClosed)
+}
@@ -0,0 +1,47 @@
+package udphop
+
+import (
+	"strconv"
+	"strings"
+
+	E "github.com/sagernet/sing/common/exceptions"
+)
+
+// ParsePorts parses a list of ports and port ranges in the form of `start-end` or `start:end`.
+func ParsePorts(portList []string) ([]uint16, error) {
+	var ports []uint16
+	portMap := make(map[uint16]bool)
+	for _, portRange := range portList {
+		var (
+			start uint64
+			end   uint64
+			err   error
+		)
+		startString, endString, isRange := strings.Cut(portRange, "-")
+		if !isRange {
+			startString, endString, isRange = strings.Cut(portRange, ":")
+		}
+		start, err = strconv.ParseUint(startString, 10, 16)
+		if err != nil {
+			return nil, E.Cause(err, "parse port range: ", portRange)
+		}
+		if isRange {
+			end, err = strconv.ParseUint(endString, 10, 16)
+			if err != nil {
+				return nil, E.Cause(err, "parse port range: ", portRange)
+			}
+		} else {
+			end = start
+		}
+		if start == 0 || start > end {
+			return nil, E.New("invalid port range: ", portRange)
+		}
+		for port := start; port <= end; port++ {
+			if !portMap[uint16(port)] {
+				portMap[uint16(port)] = true
+				ports = append(ports, uint16(port))
+			}
+		}
+	}
+	return ports, nil
+}
@@ -0,0 +1,18 @@
+package udphop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePorts(t *testing.T) {
+	t.Parallel()
+	ports, err := ParsePorts([]string{"1000-1002", "1001:1003", "2000"})
+	require.NoError(t, err)
+	require.Equal(t, []uint16{1000, 1001, 1002, 1003, 2000}, ports)
+	for _, portRange := range []string{"0", "2-1", "1-", "-1", "1:2:3", "65536", "a-b"} {
+		_, err = ParsePorts([]string{portRange})
+		require.Error(t, err, portRange)
+	}
+}
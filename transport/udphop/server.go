@@ -0,0 +1,114 @@
+package udphop
+
+import (
+	"net"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sagernet/sing/common"
+	"github.com/sagernet/sing/common/buf"
+	"github.com/sagernet/sing/common/cache"
+	M "github.com/sagernet/sing/common/metadata"
+	"github.com/sagernet/sing/common/pipe"
+)
+
+const routeTimeout = 5 * time.Minute
+
+type serverPacket struct {
+	buffer *buf.Buffer
+	source netip.AddrPort
+	index  int
+}
+
+// ServerConn serves multiple UDP sockets as one packet conn. Replies are
+// sent from the socket the peer was last seen on, which is the server port
+// that the peer currently hops to.
+type ServerConn struct {
+	conns     []net.PacketConn
+	routes    *cache.LruCache[netip.AddrPort, int]
+	packets   chan serverPacket
+	deadline  pipe.Deadline
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewServerConn(conns []net.PacketConn) *ServerConn {
+	conn := &ServerConn{
+		conns:    conns,
+		routes:   cache.New(cache.WithAge[netip.AddrPort, int](int64(routeTimeout.Seconds())), cache.WithUpdateAgeOnGet[netip.AddrPort, int]()),
+		packets:  make(chan serverPacket, 64),
+		deadline: pipe.MakeDeadline(),
+		done:     make(chan struct{}),
+	}
+	for index, packetConn := range conns {
+		go conn.loopRead(index, packetConn)
+	}
+	return conn
+}
+
+func (c *ServerConn) loopRead(index int, conn net.PacketConn) {
+	for {
+		buffer := buf.NewPacket()
+		_, addr, err := buffer.ReadPacketFrom(conn)
+		if err != nil {
+			buffer.Release()
+			return
+		}
+		select {
+		case c.packets <- serverPacket{buffer, M.SocksaddrFromNet(addr).Unwrap().AddrPort(), index}:
+		case <-c.done:
+			buffer.Release()
+			return
+		}
+	}
+}
+
+func (c *ServerConn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
+	select {
+	case packet := <-c.packets:
+		n = copy(p, packet.buffer.Bytes())
+		packet.buffer.Release()
+		c.routes.Store(packet.source, packet.index)
+		return n, net.UDPAddrFromAddrPort(packet.source), nil
+	case <-c.deadline.Wait():
+		return 0, nil, os.ErrDeadlineExceeded
+	case <-c.done:
+		return 0, nil, net.ErrClosed
+	}
+}
+
+func (c *ServerConn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	index, _ := c.routes.Load(M.SocksaddrFromNet(addr).Unwrap().AddrPort())
+	return c.conns[index].WriteTo(p, addr)
+}
+
+func (c *ServerConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = common.Close(common.Map(c.conns, func(it net.PacketConn) any {
+			return it
+		})...)
+	})
+	return err
+}
+
+func (c *ServerConn) LocalAddr() net.Addr {
+	return c.conns[0].LocalAddr()
+}
+
+// SetDeadline only sets the read deadline, writes to UDP sockets do not block.
+func (c *ServerConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *ServerConn) SetReadDeadline(t time.Time) error {
+	c.deadline.Set(t)
+	return nil
+}
+
+func (c *ServerConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
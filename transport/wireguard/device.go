@@ -1,6 +1,9 @@
 package wireguard
 
 import (
+	"context"
+
+	"github.com/sagernet/sing-box/adapter"
 	N "github.com/sagernet/sing/common/network"
 	"github.com/sagernet/wireguard-go/tun"
 )
@@ -9,5 +12,6 @@ type Device interface {
 	tun.Device
 	N.Dialer
 	Start() error
+	NewICMPEcho(ctx context.Context, request *adapter.ICMPEchoRequest) error
 	// NewEndpoint() (stack.LinkEndpoint, error)
 }
@@ -4,9 +4,11 @@ package wireguard
 
 import (
 	"context"
+	"math/rand"
 	"net"
 	"net/netip"
 	"os"
+	"sync"
 
 	"github.com/sagernet/gvisor/pkg/buffer"
 	"github.com/sagernet/gvisor/pkg/tcpip"
@@ -18,6 +20,8 @@ import (
 	"github.com/sagernet/gvisor/pkg/tcpip/transport/icmp"
 	"github.com/sagernet/gvisor/pkg/tcpip/transport/tcp"
 	"github.com/sagernet/gvisor/pkg/tcpip/transport/udp"
+	"github.com/sagernet/sing-box/adapter"
+	"github.com/sagernet/sing-box/common/ping"
 	"github.com/sagernet/sing-tun"
 	"github.com/sagernet/sing/common/buf"
 	E "github.com/sagernet/sing/common/exceptions"
@@ -40,6 +44,14 @@ type StackDevice struct {
 	dispatcher     stack.NetworkDispatcher
 	addr4          tcpip.Address
 	addr6          tcpip.Address
+	icmpAccess     sync.Mutex
+	icmpRequests   map[icmpRequestKey]chan struct{}
+}
+
+type icmpRequestKey struct {
+	destination netip.Addr
+	identifier  uint16
+	sequence    uint16
 }
 
 func NewStackDevice(localAddresses []netip.Prefix, mtu uint32) (*StackDevice, error) {
@@ -55,6 +67,7 @@ func NewStackDevice(localAddresses []netip.Prefix, mtu uint32) (*StackDevice, er
 		outbound:       make(chan *stack.PacketBuffer, 256),
 		packetOutbound: make(chan *buf.Buffer, 256),
 		done:           make(chan struct{}),
+		icmpRequests:   make(map[icmpRequestKey]chan struct{}),
 	}
 	err := ipStack.CreateNIC(defaultNIC, (*wireEndpoint)(tunDevice))
 	if err != nil {
@@ -147,6 +160,75 @@ func (w *StackDevice) ListenPacket(ctx context.Context, destination M.Socksaddr)
 	return udpConn, nil
 }
 
+// NewICMPEcho writes the echo request to the peer as a raw packet from the local address,
+// the reply is taken out of the inbound packets before they reach the stack.
+func (w *StackDevice) NewICMPEcho(ctx context.Context, request *adapter.ICMPEchoRequest) error {
+	newRequest := *request
+	if request.Destination.Is4() {
+		newRequest.Source = w.Inet4Address()
+	} else {
+		newRequest.Source = w.Inet6Address()
+	}
+	if !newRequest.Source.IsValid() {
+		return E.New("missing local address for ", request.Destination)
+	}
+	newRequest.Identifier = uint16(rand.Uint32())
+	requestKey := icmpRequestKey{newRequest.Destination, newRequest.Identifier, newRequest.Sequence}
+	replyCh := make(chan struct{})
+	w.icmpAccess.Lock()
+	w.icmpRequests[requestKey] = replyCh
+	w.icmpAccess.Unlock()
+	defer func() {
+		w.icmpAccess.Lock()
+		delete(w.icmpRequests, requestKey)
+		w.icmpAccess.Unlock()
+	}()
+	select {
+	case w.packetOutbound <- buf.As(ping.EncodeEchoRequest(&newRequest)):
+	case <-w.done:
+		return os.ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-replyCh:
+		return nil
+	case <-w.done:
+		return os.ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *StackDevice) handleICMPReply(packet []byte) bool {
+	switch header.IPVersion(packet) {
+	case header.IPv4Version:
+		if len(packet) < header.IPv4MinimumSize || packet[9] != uint8(header.ICMPv4ProtocolNumber) {
+			return false
+		}
+	case header.IPv6Version:
+		if len(packet) < header.IPv6MinimumSize || packet[6] != uint8(header.ICMPv6ProtocolNumber) {
+			return false
+		}
+	default:
+		return false
+	}
+	reply, loaded := ping.ParseEchoReply(packet)
+	if !loaded {
+		return false
+	}
+	w.icmpAccess.Lock()
+	replyCh, loaded := w.icmpRequests[icmpRequestKey{reply.Source, reply.Identifier, reply.Sequence}]
+	if loaded {
+		delete(w.icmpRequests, icmpRequestKey{reply.Source, reply.Identifier, reply.Sequence})
+	}
+	w.icmpAccess.Unlock()
+	if loaded {
+		close(replyCh)
+	}
+	return loaded
+}
+
 func (w *StackDevice) Inet4Address() netip.Addr {
 	return tun.AddrFromAddress(w.addr4)
 }
@@ -196,6 +278,10 @@ func (w *StackDevice) Write(bufs [][]byte, offset int) (count int, err error) {
 		if len(b) == 0 {
 			continue
 		}
+		if w.handleICMPReply(b) {
+			count++
+			continue
+		}
 		var networkProtocol tcpip.NetworkProtocolNumber
 		switch header.IPVersion(b) {
 		case header.IPv4Version:
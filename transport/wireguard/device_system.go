@@ -10,19 +10,19 @@ import (
 
 	"github.com/sagernet/sing-box/adapter"
 	"github.com/sagernet/sing-box/common/dialer"
+	"github.com/sagernet/sing-box/common/ping"
 	"github.com/sagernet/sing-box/option"
 	"github.com/sagernet/sing-tun"
 	"github.com/sagernet/sing/common"
 	E "github.com/sagernet/sing/common/exceptions"
 	M "github.com/sagernet/sing/common/metadata"
-	N "github.com/sagernet/sing/common/network"
 	wgTun "github.com/sagernet/wireguard-go/tun"
 )
 
 var _ Device = (*SystemDevice)(nil)
 
 type SystemDevice struct {
-	dialer         N.Dialer
+	dialer         *dialer.DefaultDialer
 	device         tun.Tun
 	batchDevice    tun.LinuxTUN
 	name           string
@@ -69,6 +69,10 @@ func (w *SystemDevice) ListenPacket(ctx context.Context, destination M.Socksaddr
 	return w.dialer.ListenPacket(ctx, destination)
 }
 
+func (w *SystemDevice) NewICMPEcho(ctx context.Context, request *adapter.ICMPEchoRequest) error {
+	return ping.Exchange(ctx, w.dialer.ListenControl(), request)
+}
+
 func (w *SystemDevice) Inet4Address() netip.Addr {
 	if len(w.inet4Addresses) == 0 {
 		return netip.Addr{}